@@ -0,0 +1,108 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v2"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
+	appbuilder "git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils/app_builder"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/db"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/manager"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/revdeps"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/utils"
+)
+
+func AutoremoveCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "autoremove",
+		Usage: gotext.Get("Remove ALR packages installed as dependencies that are no longer needed"),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "dry-run",
+				Aliases: []string{"n"},
+				Usage:   gotext.Get("Only list the packages that would be removed"),
+			},
+		},
+		Action: utils.RootNeededAction(func(c *cli.Context) error {
+			ctx := c.Context
+
+			deps, err := appbuilder.
+				New(ctx).
+				WithConfig().
+				WithDB().
+				WithDistroInfo().
+				WithManager().
+				Build()
+			if err != nil {
+				return err
+			}
+			defer deps.Defer()
+
+			graph, err := revdeps.Load(ctx, deps.Manager, deps.DB, deps.Info)
+			if err != nil {
+				return cliutils.FormatCliExit(gotext.Get("Error checking reverse dependencies"), err)
+			}
+
+			if unknown := graph.UnknownDepends(); len(unknown) > 0 {
+				slog.Warn(gotext.Get("Dependencies of some installed packages are unknown, packages they need may be removed"), "packages", strings.Join(unknown, ", "))
+			}
+
+			orphans := graph.Orphans()
+			if len(orphans) == 0 {
+				slog.Info(gotext.Get("No unneeded packages to remove"))
+				return nil
+			}
+
+			if c.Bool("dry-run") {
+				for _, pkg := range orphans {
+					fmt.Println(pkg)
+				}
+				return nil
+			}
+
+			slog.Info(gotext.Get("Removing packages that are no longer needed"), "count", len(orphans))
+			if err := deps.Manager.Remove(&manager.Opts{
+				NoConfirm: !c.Bool("interactive"),
+			}, orphans...); err != nil {
+				return cliutils.FormatCliExit(gotext.Get("Error removing packages"), err)
+			}
+
+			forgetInstallReasons(ctx, deps.DB, orphans)
+			return nil
+		}),
+	}
+}
+
+// forgetInstallReasons удаляет из базы причины установки удалённых пакетов ALR
+func forgetInstallReasons(ctx context.Context, database *db.Database, pkgs []string) {
+	for _, pkg := range pkgs {
+		name, repo, ok := revdeps.ParseName(pkg)
+		if !ok {
+			continue
+		}
+		if err := database.DeleteInstallReason(ctx, name, repo); err != nil {
+			slog.Warn(gotext.Get("Failed to record install reason"), "name", pkg, "error", err)
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/leonelquinteros/gotext"
 	"github.com/urfave/cli/v2"
@@ -30,6 +31,7 @@ import (
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/build"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
 	appbuilder "git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils/app_builder"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cpu"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/osutils"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/utils"
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
@@ -61,6 +63,23 @@ func BuildCmd() *cli.Command {
 				Aliases: []string{"c"},
 				Usage:   gotext.Get("Build package from scratch even if there's an already built package available"),
 			},
+			&cli.StringFlag{
+				Name:  "target-arch",
+				Usage: gotext.Get("Build the package for another CPU architecture (example: arm64)"),
+			},
+			&cli.StringFlag{
+				Name:  "emulate-root",
+				Usage: gotext.Get("Build scripts without cross compilation support inside this target architecture root filesystem under qemu-user binfmt (build dependencies must already be installed in it)"),
+			},
+			&cli.BoolFlag{
+				Name:    "multilib",
+				Aliases: []string{"lib32"},
+				Usage:   gotext.Get("Build the 32-bit (lib32) variant of the package on an x86_64 system"),
+			},
+			&cli.StringSliceFlag{
+				Name:  "formats",
+				Usage: gotext.Get("Package the build result into several formats (example: deb,rpm,apk,archlinux)"),
+			},
 		},
 		Action: func(c *cli.Context) error {
 			if err := utils.CheckUserPrivileges(); err != nil {
@@ -87,6 +106,23 @@ func BuildCmd() *cli.Command {
 			}
 			defer deps.Defer()
 
+			targetArch := ""
+			if c.IsSet("target-arch") {
+				arch, ok := cpu.Canonical(c.String("target-arch"))
+				if !ok {
+					return cliutils.FormatCliExit(gotext.Get("Unknown architecture: %s", c.String("target-arch")), nil)
+				}
+				targetArch = arch
+			}
+
+			emulateRoot := ""
+			if c.IsSet("emulate-root") {
+				emulateRoot, err = filepath.Abs(c.String("emulate-root"))
+				if err != nil {
+					return cliutils.FormatCliExit(gotext.Get("Cannot get absolute emulation root path"), err)
+				}
+			}
+
 			var script string
 			var packages []string
 
@@ -99,6 +135,10 @@ func BuildCmd() *cli.Command {
 				Opts: &types.BuildOpts{
 					Clean:       c.Bool("clean"),
 					Interactive: c.Bool("interactive"),
+					TargetArch:  targetArch,
+					EmulateRoot: emulateRoot,
+					Multilib:    c.Bool("multilib"),
+					Formats:     c.StringSlice("formats"),
 				},
 				PkgFormat_: build.GetPkgFormat(deps.Manager),
 				Info:       deps.Info,
@@ -168,6 +208,7 @@ func BuildCmd() *cli.Command {
 				deps.Repos,
 				scripter,
 				installer,
+				deps.DB,
 			)
 			if err != nil {
 				return err
@@ -189,18 +230,31 @@ func BuildCmd() *cli.Command {
 				return cliutils.FormatCliExit(gotext.Get("Error building package"), err)
 			}
 
-			for _, pkg := range res {
-				name := filepath.Base(pkg.Path)
+			// Пакет в формате системы собирается всегда, но в текущий каталог
+			// попадает, только если этот формат запрошен
+			formats := buildArgs.Opts.Formats
+			primary := len(formats) == 0 || slices.Contains(formats, buildArgs.PkgFormat_)
 
-				// Проверяем, существует ли файл перед перемещением
-				if _, err := os.Stat(pkg.Path); os.IsNotExist(err) {
-					slog.Info(gotext.Get("Package file already moved or removed, skipping"), "path", pkg.Path)
-					continue
+			for _, pkg := range res {
+				paths := pkg.ExtraPaths
+				if primary {
+					paths = append([]string{pkg.Path, pkg.DebugPath}, paths...)
 				}
+				for _, path := range paths {
+					if path == "" {
+						continue
+					}
 
-				err = osutils.Move(pkg.Path, filepath.Join(wd, name))
-				if err != nil {
-					return cliutils.FormatCliExit(gotext.Get("Error moving the package"), err)
+					// Проверяем, существует ли файл перед перемещением
+					if _, err := os.Stat(path); os.IsNotExist(err) {
+						slog.Info(gotext.Get("Package file already moved or removed, skipping"), "path", path)
+						continue
+					}
+
+					err = osutils.Move(path, filepath.Join(wd, filepath.Base(path)))
+					if err != nil {
+						return cliutils.FormatCliExit(gotext.Get("Error moving the package"), err)
+					}
 				}
 			}
 
@@ -96,6 +96,11 @@ var configKeys = []string{
 	"ignorePkgUpdates",
 	"updateSystemOnUpgrade",
 	"preferALRDeps",
+	"signing.keyFile",
+	"signing.keyId",
+	"signing.apkKeyFile",
+	"signing.apkKeyName",
+	"signing.passphrase",
 }
 
 func SetConfig() *cli.Command {
@@ -169,6 +174,8 @@ func SetConfig() *cli.Command {
 					return cliutils.FormatCliExit(gotext.Get("invalid boolean value for %s: %s", key, value), err)
 				}
 				deps.Cfg.System.SetPreferALRDeps(boolValue)
+			case "signing.keyFile", "signing.keyId", "signing.apkKeyFile", "signing.apkKeyName", "signing.passphrase":
+				deps.Cfg.System.SetSigning(strings.TrimPrefix(key, "signing."), value)
 			case "repo", "repos":
 				return cliutils.FormatCliExit(gotext.Get("use 'repo add/remove' commands to manage repositories"), nil)
 			default:
@@ -242,6 +249,16 @@ func GetConfig() *cli.Command {
 				fmt.Println(deps.Cfg.UpdateSystemOnUpgrade())
 			case "preferALRDeps":
 				fmt.Println(deps.Cfg.PreferALRDeps())
+			case "signing.keyFile":
+				fmt.Println(deps.Cfg.Signing().KeyFile)
+			case "signing.keyId":
+				fmt.Println(deps.Cfg.Signing().KeyID)
+			case "signing.apkKeyFile":
+				fmt.Println(deps.Cfg.Signing().APKKeyFile)
+			case "signing.apkKeyName":
+				fmt.Println(deps.Cfg.Signing().APKKeyName)
+			case "signing.passphrase":
+				fmt.Println(deps.Cfg.Signing().Passphrase)
 			case "repo", "repos":
 				repos := deps.Cfg.Repos()
 				if len(repos) == 0 {
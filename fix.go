@@ -20,17 +20,21 @@
 package main
 
 import (
+	"context"
 	"io/fs"
 	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/leonelquinteros/gotext"
 	"github.com/urfave/cli/v2"
 
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
 	appbuilder "git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils/app_builder"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/config"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/db"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/utils"
 )
 
@@ -72,6 +76,15 @@ func FixCmd() *cli.Command {
 
 			paths := cfg.GetPaths()
 
+			// Причины установки пакетов нельзя восстановить из репозиториев,
+			// поэтому из базы удаляются только данные репозиториев. Базу,
+			// которую не удалось открыть, удаляем вместе с остальным кешем.
+			keepDB := true
+			if err := resetRepoData(ctx, cfg); err != nil {
+				slog.Warn(gotext.Get("Unable to reset database, it will be removed with install reasons"), "error", err)
+				keepDB = false
+			}
+
 			slog.Info(gotext.Get("Clearing cache and temporary directories"))
 
 			// Проверяем, существует ли директория кэша
@@ -93,6 +106,10 @@ func FixCmd() *cli.Command {
 
 				for _, entry := range entries {
 					fullPath := filepath.Join(paths.CacheDir, entry)
+					// Вместе с базой сохраняются её служебные файлы (-journal, -wal)
+					if keepDB && strings.HasPrefix(fullPath, paths.DBPath) {
+						continue
+					}
 
 					// Пробуем сделать файлы доступными для записи
 					if err := makeWritableRecursive(fullPath); err != nil {
@@ -198,6 +215,17 @@ func FixCmd() *cli.Command {
 	}
 }
 
+// resetRepoData удаляет из базы данные репозиториев, которые затем
+// загружаются заново
+func resetRepoData(ctx context.Context, cfg *config.ALRConfig) error {
+	database := db.New(cfg)
+	if err := database.Init(ctx); err != nil {
+		return err
+	}
+	defer database.Close()
+	return database.ResetRepoData(ctx)
+}
+
 func makeWritableRecursive(path string) error {
 	return filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -0,0 +1,98 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v2"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/shutils/formatter"
+)
+
+func FmtCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "fmt",
+		Usage:     gotext.Get("Format alr.sh scripts"),
+		ArgsUsage: gotext.Get("[path...]"),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "write",
+				Aliases: []string{"w"},
+				Usage:   gotext.Get("Write the result to the file instead of stdout"),
+			},
+			&cli.BoolFlag{
+				Name:  "check",
+				Usage: gotext.Get("List files that are not formatted and exit with an error code"),
+			},
+		},
+		Action: func(c *cli.Context) error {
+			paths := c.Args().Slice()
+			if len(paths) == 0 {
+				paths = []string{"alr.sh"}
+			}
+
+			scripts, err := findScripts(paths)
+			if err != nil {
+				return cliutils.FormatCliExitWithCode(gotext.Get("Error finding scripts"), err, 2)
+			}
+
+			unformatted := 0
+			for _, script := range scripts {
+				src, err := os.ReadFile(script)
+				if err != nil {
+					return cliutils.FormatCliExitWithCode(gotext.Get("Error reading script"), err, 2)
+				}
+
+				out, err := formatter.Format(src, script)
+				if err != nil {
+					return cliutils.FormatCliExitWithCode(gotext.Get("Error formatting script"), err, 2)
+				}
+
+				switch {
+				case c.Bool("check"):
+					if !bytes.Equal(src, out) {
+						fmt.Println(script)
+						unformatted++
+					}
+				case c.Bool("write"):
+					if bytes.Equal(src, out) {
+						continue
+					}
+					fi, err := os.Stat(script)
+					if err != nil {
+						return cliutils.FormatCliExitWithCode(gotext.Get("Error writing script"), err, 2)
+					}
+					if err := os.WriteFile(script, out, fi.Mode().Perm()); err != nil {
+						return cliutils.FormatCliExitWithCode(gotext.Get("Error writing script"), err, 2)
+					}
+				default:
+					os.Stdout.Write(out)
+				}
+			}
+
+			if unformatted > 0 {
+				return cli.Exit("", 1)
+			}
+			return nil
+		},
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/leonelquinteros/gotext"
 	"github.com/urfave/cli/v2"
 
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/gen"
 )
 
@@ -84,6 +85,168 @@ func GenCmd() *cli.Command {
 					})
 				},
 			},
+			{
+				Name:  "crate",
+				Usage: gotext.Get("Generate a ALR script for a Rust crate from crates.io"),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "name",
+						Aliases:  []string{"n"},
+						Required: true,
+						Usage:    gotext.Get("Name of the crate"),
+					},
+					&cli.StringFlag{
+						Name:    "version",
+						Aliases: []string{"v"},
+						Usage:   gotext.Get("Version of the crate (optional, uses latest stable if not specified)"),
+					},
+					&cli.StringFlag{
+						Name:    "description",
+						Aliases: []string{"d"},
+					},
+					&cli.StringFlag{
+						Name:  "registry",
+						Value: gen.CratesIOURL,
+						Usage: gotext.Get("Base URL of the crates.io API"),
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return gen.Crate(os.Stdout, gen.CrateOptions{
+						Name:        c.String("name"),
+						Version:     c.String("version"),
+						Description: c.String("description"),
+						BaseURL:     c.String("registry"),
+					})
+				},
+			},
+			{
+				Name:  "npm",
+				Usage: gotext.Get("Generate a ALR script for an npm package"),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "name",
+						Aliases:  []string{"n"},
+						Required: true,
+						Usage:    gotext.Get("Name of the npm package"),
+					},
+					&cli.StringFlag{
+						Name:    "version",
+						Aliases: []string{"v"},
+						Usage:   gotext.Get("Version of the package (optional, uses latest if not specified)"),
+					},
+					&cli.StringFlag{
+						Name:    "description",
+						Aliases: []string{"d"},
+					},
+					&cli.StringFlag{
+						Name:  "registry",
+						Value: gen.NPMRegistryURL,
+						Usage: gotext.Get("Base URL of the npm registry"),
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return gen.NPM(os.Stdout, gen.NPMOptions{
+						Name:        c.String("name"),
+						Version:     c.String("version"),
+						Description: c.String("description"),
+						BaseURL:     c.String("registry"),
+					})
+				},
+			},
+			{
+				Name:  "gomod",
+				Usage: gotext.Get("Generate a ALR script for a Go module"),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "module",
+						Aliases:  []string{"m"},
+						Required: true,
+						Usage:    gotext.Get("Path of the Go module"),
+					},
+					&cli.StringFlag{
+						Name:    "version",
+						Aliases: []string{"v"},
+						Usage:   gotext.Get("Version of the module (optional, uses latest if not specified)"),
+					},
+					&cli.StringFlag{
+						Name:    "description",
+						Aliases: []string{"d"},
+					},
+					&cli.StringFlag{
+						Name:  "proxy",
+						Value: gen.GoProxyURL,
+						Usage: gotext.Get("Base URL of the Go module proxy"),
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return gen.GoMod(os.Stdout, gen.GoModOptions{
+						Module:      c.String("module"),
+						Version:     c.String("version"),
+						Description: c.String("description"),
+						BaseURL:     c.String("proxy"),
+					})
+				},
+			},
+			{
+				Name:  "release",
+				Usage: gotext.Get("Generate a ALR script for release binaries from GitHub or Gitea"),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "repo",
+						Aliases:  []string{"r"},
+						Required: true,
+						Usage:    gotext.Get("Repository in owner/name format"),
+					},
+					&cli.StringFlag{
+						Name:  "forge",
+						Value: gen.ForgeGitHub,
+						Usage: gotext.Get("Forge hosting the repository (github or gitea)"),
+					},
+					&cli.StringFlag{
+						Name:    "tag",
+						Aliases: []string{"t"},
+						Usage:   gotext.Get("Release tag (optional, uses latest release if not specified)"),
+					},
+					&cli.StringFlag{
+						Name:  "url",
+						Usage: gotext.Get("Base URL of the forge API (required for self-hosted Gitea)"),
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return gen.Release(os.Stdout, gen.ReleaseOptions{
+						Repo:    c.String("repo"),
+						Forge:   c.String("forge"),
+						Tag:     c.String("tag"),
+						BaseURL: c.String("url"),
+					})
+				},
+			},
+			{
+				Name:      "spec",
+				Usage:     gotext.Get("Convert an RPM spec file into a ALR script"),
+				ArgsUsage: gotext.Get("<file.spec>"),
+				Action: func(c *cli.Context) error {
+					if c.Args().Len() < 1 {
+						return cliutils.FormatCliExit("missing args", nil)
+					}
+					return gen.Spec(os.Stdout, gen.SpecOptions{
+						Path: c.Args().First(),
+					})
+				},
+			},
+			{
+				Name:      "debian",
+				Usage:     gotext.Get("Convert a Debian source package into a ALR script"),
+				ArgsUsage: gotext.Get("<dir|file.dsc>"),
+				Action: func(c *cli.Context) error {
+					if c.Args().Len() < 1 {
+						return cliutils.FormatCliExit("missing args", nil)
+					}
+					return gen.Debian(os.Stdout, gen.DebianOptions{
+						Path: c.Args().First(),
+					})
+				},
+			},
 		},
 	}
 }
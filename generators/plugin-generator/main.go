@@ -350,6 +350,8 @@ func typeToString(expr ast.Expr) string {
 			return "context.Context"
 		}
 		return xStr + "." + t.Sel.Name
+	case *ast.MapType:
+		return "map[" + typeToString(t.Key) + "]" + typeToString(t.Value)
 	case *ast.InterfaceType:
 		return "interface{}"
 	default:
@@ -0,0 +1,121 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v2"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
+	appbuilder "git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils/app_builder"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/pkginspect"
+)
+
+func InspectCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "inspect",
+		Usage:     gotext.Get("Show metadata, dependencies, scripts and files of a built package"),
+		ArgsUsage: gotext.Get("<artifact|package>"),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Value:   pkginspect.FormatText,
+				Usage:   gotext.Get("Output format: text or json"),
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.Args().Len() != 1 {
+				return cliutils.FormatCliExit(gotext.Get("Expected one package or artifact"), nil)
+			}
+
+			pkg, err := openArtifact(c, c.Args().First())
+			if err != nil {
+				return err
+			}
+
+			if err := pkginspect.WritePackage(os.Stdout, c.String("output"), pkg); err != nil {
+				return cliutils.FormatCliExit(gotext.Get("Error writing results"), err)
+			}
+			return nil
+		},
+	}
+}
+
+func DiffCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "diff",
+		Usage:     gotext.Get("Compare two builds of a package"),
+		ArgsUsage: gotext.Get("<old> <new>"),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Value:   pkginspect.FormatText,
+				Usage:   gotext.Get("Output format: text or json"),
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.Args().Len() != 2 {
+				return cliutils.FormatCliExit(gotext.Get("Expected two packages or artifacts"), nil)
+			}
+
+			oldPkg, err := openArtifact(c, c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+			newPkg, err := openArtifact(c, c.Args().Get(1))
+			if err != nil {
+				return err
+			}
+
+			if err := pkginspect.WriteDiff(os.Stdout, c.String("output"), pkginspect.Compare(oldPkg, newPkg)); err != nil {
+				return cliutils.FormatCliExit(gotext.Get("Error writing results"), err)
+			}
+			return nil
+		},
+	}
+}
+
+// openArtifact открывает файл пакета arg. Если такого файла нет, arg
+// считается именем пакета ALR и ищется последняя его сборка в кэше.
+func openArtifact(c *cli.Context, arg string) (*pkginspect.Package, error) {
+	path := arg
+	if _, err := os.Stat(arg); err != nil {
+		deps, err := appbuilder.
+			New(c.Context).
+			WithConfig().
+			Build()
+		if err != nil {
+			return nil, err
+		}
+		defer deps.Defer()
+
+		path, err = pkginspect.FindArtifact(deps.Cfg.GetPaths().PkgsDir, arg)
+		if err != nil {
+			return nil, cliutils.FormatCliExit(gotext.Get("Error finding built package"), err)
+		}
+	}
+
+	pkg, err := pkginspect.Open(path)
+	if err != nil {
+		return nil, cliutils.FormatCliExit(gotext.Get("Error reading package"), err)
+	}
+	return pkg, nil
+}
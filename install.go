@@ -31,6 +31,7 @@ import (
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
 	appbuilder "git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils/app_builder"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/manager"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/revdeps"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/utils"
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
 )
@@ -86,6 +87,7 @@ func InstallCmd() *cli.Command {
 				deps.Repos,
 				scripter,
 				installer,
+				deps.DB,
 			)
 			if err != nil {
 				return err
@@ -211,14 +213,25 @@ func RemoveCmd() *cli.Command {
 
 			return nil
 		}),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "cascade",
+				Usage: gotext.Get("Also remove ALR packages that depend on the removed packages"),
+			},
+		},
 		Action: utils.RootNeededAction(func(c *cli.Context) error {
 			args := c.Args()
 			if args.Len() < 1 {
 				return cliutils.FormatCliExit(gotext.Get("Command remove expected at least 1 argument, got %d", args.Len()), nil)
 			}
 
+			ctx := c.Context
+
 			deps, err := appbuilder.
-				New(c.Context).
+				New(ctx).
+				WithConfig().
+				WithDB().
+				WithDistroInfo().
 				WithManager().
 				Build()
 			if err != nil {
@@ -232,12 +245,40 @@ func RemoveCmd() *cli.Command {
 				return cliutils.FormatCliExit(gotext.Get("Error removing packages"), err)
 			}
 
+			graph, err := revdeps.Load(ctx, deps.Manager, deps.DB, deps.Info)
+			if err != nil {
+				return cliutils.FormatCliExit(gotext.Get("Error checking reverse dependencies"), err)
+			}
+
+			var alrPkgs []string
+			for _, pkg := range resolvedPkgs {
+				if name, repo, ok := revdeps.ParseName(pkg); ok {
+					alrPkgs = append(alrPkgs, name+"+"+repo)
+				}
+			}
+
+			if c.Bool("cascade") {
+				if dependents := graph.Cascade(alrPkgs)[len(alrPkgs):]; len(dependents) > 0 {
+					slog.Info(gotext.Get("Also removing dependent packages"), "packages", strings.Join(dependents, " "))
+					resolvedPkgs = append(resolvedPkgs, dependents...)
+					alrPkgs = append(alrPkgs, dependents...)
+				}
+			} else if blockers := graph.Blockers(alrPkgs); len(blockers) > 0 {
+				for _, pkg := range alrPkgs {
+					if dependents, ok := blockers[pkg]; ok {
+						slog.Error(gotext.Get("Package %s is required by %s", pkg, strings.Join(dependents, ", ")))
+					}
+				}
+				return cliutils.FormatCliExit(gotext.Get("Refusing to remove packages other ALR packages depend on, use --cascade to remove them too"), nil)
+			}
+
 			if err := deps.Manager.Remove(&manager.Opts{
 				NoConfirm: !c.Bool("interactive"),
 			}, resolvedPkgs...); err != nil {
 				return cliutils.FormatCliExit(gotext.Get("Error removing packages"), err)
 			}
 
+			forgetInstallReasons(ctx, deps.DB, alrPkgs)
 			return nil
 		}),
 	}
@@ -167,6 +167,10 @@ func InternalDBusServiceCmd() *cli.Command {
 				Name:  "session",
 				Usage: "Use session bus instead of system bus",
 			},
+			&cli.BoolFlag{
+				Name:  "packagekit",
+				Usage: "Also provide org.freedesktop.PackageKit for software centres",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			logger.SetupForGoPlugin()
@@ -175,6 +179,9 @@ func InternalDBusServiceCmd() *cli.Command {
 			slog.Info("Starting ALR D-Bus service", "session_bus", c.Bool("session"))
 
 			service := alrdbus.NewService()
+			if c.Bool("packagekit") {
+				service.EnablePackageKit()
+			}
 			if err := service.Run(c.Bool("session")); err != nil {
 				slog.Error("D-Bus service failed", "err", err)
 				return cliutils.FormatCliExit(gotext.Get("D-Bus service failed"), err)
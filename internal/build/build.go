@@ -34,7 +34,9 @@ import (
 
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/config"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/db"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/manager"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/signing"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/stats"
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
@@ -50,6 +52,9 @@ type BuildInput struct {
 	packages          []string
 	skipDepsBuilding  bool // Пропустить сборку зависимостей (используется при вызове из BuildALRDeps)
 	skipBuildDeps     bool // Пропустить установку build_deps (используется при единой установке)
+	// signing - ключи подписи с прочитанным паролем, передаются процессу
+	// сборки, т.к. его окружение очищается (см. setCommonCmdEnv)
+	signing signing.Key
 }
 
 func (bi *BuildInput) GobEncode() ([]byte, error) {
@@ -74,6 +79,9 @@ func (bi *BuildInput) GobEncode() ([]byte, error) {
 	if err := encoder.Encode(bi.packages); err != nil {
 		return nil, err
 	}
+	if err := encoder.Encode(bi.signing); err != nil {
+		return nil, err
+	}
 
 	return w.Bytes(), nil
 }
@@ -100,6 +108,9 @@ func (bi *BuildInput) GobDecode(data []byte) error {
 	if err := decoder.Decode(&bi.packages); err != nil {
 		return err
 	}
+	if err := decoder.Decode(&bi.signing); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -141,6 +152,10 @@ type RepositoryProvider interface {
 type BuiltDep struct {
 	Name string
 	Path string
+	// DebugPath - путь к пакету с отладочной информацией (может быть пустым)
+	DebugPath string
+	// ExtraPaths - пути к пакетам в дополнительных форматах (см. BuildOpts.Formats)
+	ExtraPaths []string
 }
 
 func Map[T, R any](items []T, f func(T) R) []R {
@@ -167,10 +182,18 @@ type PackageFinder interface {
 	FindPkgs(ctx context.Context, pkgs []string) (map[string][]alrsh.Package, []string, error)
 }
 
+// InstallReasonRecorder сохраняет причины установки пакетов ALR
+// (см. db.InstallReasonExplicit и db.InstallReasonDependency)
+type InstallReasonRecorder interface {
+	SetInstallReason(ctx context.Context, name, repo, reason string) error
+	AddInstallReason(ctx context.Context, name, repo, reason string) error
+}
+
 type Config interface {
 	GetPaths() *config.Paths
 	PagerStyle() string
 	PreferALRDeps() bool
+	Signing() types.SigningConfig
 }
 
 type FunctionsOutput struct {
@@ -184,7 +207,7 @@ type ScriptResolverExecutor interface {
 }
 
 type CacheExecutor interface {
-	CheckForBuiltPackage(ctx context.Context, input *BuildInput, vars *alrsh.Package) (string, bool, error)
+	CheckForBuiltPackage(ctx context.Context, input *BuildInput, vars *alrsh.Package) (*BuiltDep, bool, error)
 }
 
 type ScriptViewerExecutor interface {
@@ -246,6 +269,7 @@ type Builder struct {
 	repos                PackageFinder
 	mgr                  manager.Manager
 	cfg                  Config
+	reasons              InstallReasonRecorder
 }
 
 type BuildArgs struct {
@@ -331,20 +355,38 @@ func (b *Builder) BuildPackage(
 		return nil, fmt.Errorf("failed ExecuteFirstPass: %w", err)
 	}
 
+	if err := checkMultilib(input.opts, input.pkgFormat); err != nil {
+		return nil, err
+	}
+
+	if err := checkCrossBuild(input.opts, varsOfPackages); err != nil {
+		return nil, err
+	}
+
 	var builtDeps []*BuiltDep
 	var remainingVars []*alrsh.Package
 
-	if !input.opts.Clean {
+	if err := checkFormats(input.opts); err != nil {
+		return nil, err
+	}
+
+	if b.cfg != nil {
+		input.signing, err = signing.Resolve(b.cfg.Signing())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Кеш хранит пакеты только основного формата, поэтому при упаковке
+	// в несколько форматов пакет всегда собирается заново
+	if !input.opts.Clean && len(extraFormats(input.opts, input.pkgFormat)) == 0 {
 		for _, vars := range varsOfPackages {
-			builtPkgPath, ok, err := b.cacheExecutor.CheckForBuiltPackage(ctx, input, vars)
+			builtDep, ok, err := b.cacheExecutor.CheckForBuiltPackage(ctx, input, vars)
 			if err != nil {
 				return nil, err
 			}
 			if ok {
-				builtDeps = append(builtDeps, &BuiltDep{
-					Path: builtPkgPath,
-					Name: vars.Name,
-				})
+				builtDeps = append(builtDeps, builtDep)
 			} else {
 				remainingVars = append(remainingVars, vars)
 			}
@@ -397,16 +439,38 @@ func (b *Builder) BuildPackage(
 	
 	// Устанавливаем build_deps только если не в режиме единой установки
 	if !input.skipBuildDeps {
-		slog.Debug("installBuildDeps")
-		alrBuildDeps, _, err = b.installBuildDeps(ctx, input, buildDepends)
-		if err != nil {
-			return nil, err
+		if needsEmulation(input.opts, varsOfPackages) {
+			// Скрипт выполняется в корневой файловой системе --emulate-root,
+			// пакеты системы сборки в ней не видны, поэтому build_deps
+			// должны быть установлены в неё заранее
+			if len(buildDepends) > 0 {
+				slog.Warn(gotext.Get("Build dependencies are not installed into the emulation root, it must already provide them"), "root", input.opts.EmulateRoot, "build_deps", buildDepends)
+			}
+		} else {
+			slog.Debug("installBuildDeps")
+			alrBuildDeps, _, err = b.installBuildDeps(ctx, newHostBuildInput(input), buildDepends)
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		slog.Debug("installOptDeps")
-		_, err = b.installOptDeps(ctx, input, optDepends)
-		if err != nil {
-			return nil, err
+		// 32-битные библиотеки для multilib-сборки ставятся из репозиториев
+		// дистрибутива под именами вида lib32-foo, foo:i386 или foo.i686
+		if isMultilib(input.opts) {
+			slog.Debug("installMultilibBuildDeps")
+			_, _, err = b.installBuildDeps(ctx, newHostBuildInput(input), multilibBuildDepends(input.pkgFormat, varsOfPackages))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Опциональные зависимости нужны целевой системе, а не системе сборки
+		if !isCrossBuild(input.opts) {
+			slog.Debug("installOptDeps")
+			_, err = b.installOptDeps(ctx, input, optDepends)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -666,17 +730,15 @@ func (b *Builder) checkCacheForAllSubpackages(
 		}
 
 		if pkgForCheck != nil {
-			pkgPath, found, err := b.cacheExecutor.CheckForBuiltPackage(ctx, buildInput, pkgForCheck)
+			cached, found, err := b.cacheExecutor.CheckForBuiltPackage(ctx, buildInput, pkgForCheck)
 			if err != nil {
 				return nil, false, fmt.Errorf("failed to check cache: %w", err)
 			}
 
 			if found {
-				slog.Info(gotext.Get("Using cached package"), "name", pkgName, "path", pkgPath)
-				cachedDeps = append(cachedDeps, &BuiltDep{
-					Name: pkgName,
-					Path: pkgPath,
-				})
+				slog.Info(gotext.Get("Using cached package"), "name", pkgName, "path", cached.Path)
+				cached.Name = pkgName
+				cachedDeps = append(cachedDeps, cached)
 			} else {
 				allInCache = false
 				break
@@ -965,6 +1027,8 @@ func (i *Builder) InstallPkgs(
 
 	var allBuiltDeps []*BuiltDep
 	var targetDeps []*BuiltDep
+	var targetArtifacts []conflictArtifact
+	var targetPkgs []*alrsh.Package
 	var installedBuildDeps []string
 
 	// Шаг 2: Устанавливаем ВСЕ системные зависимости одним вызовом
@@ -1058,16 +1122,22 @@ func (i *Builder) InstallPkgs(
 			// Целевые пакеты откладываем для финальной установки
 			if node.IsTarget {
 				targetDeps = append(targetDeps, cachedDeps...)
+				targetArtifacts = append(targetArtifacts, newConflictArtifacts(cachedDeps, pkg)...)
+				targetPkgs = append(targetPkgs, pkg)
 			} else {
 				allBuiltDeps = append(allBuiltDeps, cachedDeps...)
 				// Устанавливаем кешированный пакет сразу
 				if len(cachedDeps) > 0 {
+					if err := i.checkFileConflicts(ctx, input, newConflictArtifacts(cachedDeps, pkg)); err != nil {
+						return nil, err
+					}
 					err = i.installerExecutor.InstallLocal(ctx, GetBuiltPaths(cachedDeps), &manager.Opts{
 						NoConfirm: userConfirmed, // true после подтверждения
 					})
 					if err != nil {
 						return nil, fmt.Errorf("failed to install cached %s: %w", pkgName, err)
 					}
+					i.recordDependency(ctx, pkg)
 				}
 			}
 			continue
@@ -1102,18 +1172,24 @@ func (i *Builder) InstallPkgs(
 		// для зависимостей устанавливаем сразу
 		if node.IsTarget {
 			targetDeps = append(targetDeps, res...)
+			targetArtifacts = append(targetArtifacts, newConflictArtifacts(res, pkg)...)
+			targetPkgs = append(targetPkgs, pkg)
 		} else {
 			allBuiltDeps = append(allBuiltDeps, res...)
 		}
 
 		// Устанавливаем собранный пакет сразу, чтобы он был доступен для следующих
 		if len(res) > 0 && !node.IsTarget {
+			if err := i.checkFileConflicts(ctx, input, newConflictArtifacts(res, pkg)); err != nil {
+				return nil, err
+			}
 			err = i.installerExecutor.InstallLocal(ctx, GetBuiltPaths(res), &manager.Opts{
 				NoConfirm: userConfirmed, // true после подтверждения
 			})
 			if err != nil {
 				return nil, fmt.Errorf("failed to install %s: %w", pkgName, err)
 			}
+			i.recordDependency(ctx, pkg)
 		}
 
 		// Собираем установленные build deps для удаления в конце
@@ -1125,6 +1201,9 @@ func (i *Builder) InstallPkgs(
 	// Шаг 6: Устанавливаем целевые пакеты
 	if len(targetDeps) > 0 {
 		slog.Info(gotext.Get("Installing target packages"))
+		if err := i.checkFileConflicts(ctx, input, targetArtifacts); err != nil {
+			return nil, err
+		}
 		err = i.installerExecutor.InstallLocal(ctx, GetBuiltPaths(targetDeps), &manager.Opts{
 			NoConfirm: userConfirmed, // true после подтверждения
 		})
@@ -1132,6 +1211,11 @@ func (i *Builder) InstallPkgs(
 			return nil, err
 		}
 
+		// Причина записывается для тех кандидатов, которые были установлены
+		for _, pkg := range targetPkgs {
+			i.recordExplicit(ctx, pkg)
+		}
+
 		// Отслеживание установки
 		for _, dep := range targetDeps {
 			if stats.ShouldTrackPackage(dep.Name) {
@@ -1162,3 +1246,24 @@ func (i *Builder) InstallPkgs(
 	return append(allBuiltDeps, targetDeps...), nil
 }
 
+// recordDependency отмечает пакет pkg как установленный в качестве зависимости.
+// Причина уже установленного явно пакета не меняется.
+func (i *Builder) recordDependency(ctx context.Context, pkg *alrsh.Package) {
+	if i.reasons == nil {
+		return
+	}
+	if err := i.reasons.AddInstallReason(ctx, pkg.Name, pkg.Repository, db.InstallReasonDependency); err != nil {
+		slog.Warn(gotext.Get("Failed to record install reason"), "name", pkg.Name, "error", err)
+	}
+}
+
+// recordExplicit отмечает пакет pkg как установленный пользователем явно,
+// чтобы alr autoremove не удалял его
+func (i *Builder) recordExplicit(ctx context.Context, pkg *alrsh.Package) {
+	if i.reasons == nil {
+		return
+	}
+	if err := i.reasons.SetInstallReason(ctx, pkg.Name, pkg.Repository, db.InstallReasonExplicit); err != nil {
+		slog.Warn(gotext.Get("Failed to record install reason"), "name", pkg.Name, "error", err)
+	}
+}
@@ -20,9 +20,11 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/goreleaser/nfpm/v2"
 
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/signing"
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
 )
 
@@ -30,14 +32,17 @@ type Cache struct {
 	cfg Config
 }
 
+// CheckForBuiltPackage ищет в кеше собранный ранее пакет vars вместе
+// с пакетом его отладочной информации
 func (c *Cache) CheckForBuiltPackage(
 	ctx context.Context,
 	input *BuildInput,
 	vars *alrsh.Package,
-) (string, bool, error) {
-	filename, err := pkgFileName(input, vars)
-	if err != nil {
-		return "", false, err
+) (*BuiltDep, bool, error) {
+	// Имя файла в кеше не зависит от подписи, поэтому подписанный пакет
+	// всегда собирается заново, чтобы не установить неподписанный
+	if signing.Enabled(c.cfg.Signing(), input.PkgFormat()) {
+		return nil, false, nil
 	}
 
 	// Для подпакетов используем BasePkgName, чтобы искать в правильной директории
@@ -45,19 +50,46 @@ func (c *Cache) CheckForBuiltPackage(
 	if baseName == "" {
 		baseName = vars.Name
 	}
-	pkgPath := filepath.Join(getBaseDir(c.cfg, baseName), filename)
 
-	_, err = os.Stat(pkgPath)
+	pkgPath, ok, err := c.findPackage(input, vars, baseName)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	dep := &BuiltDep{Name: vars.Name, Path: pkgPath}
+
+	if debugEnabled(vars) {
+		debugPath, ok, err := c.findPackage(input, debugPackageVars(vars), baseName)
+		if err != nil {
+			return nil, false, err
+		}
+		// Пакет без ELF-файлов собирается без -debug, что заранее известно
+		// только для пакетов, не зависящих от архитектуры
+		if !ok && !slices.Contains(vars.Architectures, "all") {
+			return nil, false, nil
+		}
+		dep.DebugPath = debugPath
+	}
+
+	return dep, true, nil
+}
+
+func (c *Cache) findPackage(input *BuildInput, vars *alrsh.Package, baseName string) (string, bool, error) {
+	filename, err := pkgFileName(input, vars)
 	if err != nil {
-		return "", false, nil
+		return "", false, err
 	}
 
+	pkgPath := filepath.Join(getBaseDir(c.cfg, baseName), filename)
+	if _, err := os.Stat(pkgPath); err != nil {
+		return "", false, nil
+	}
 	return pkgPath, true, nil
 }
 
 func pkgFileName(
 	input interface {
 		OsInfoProvider
+		BuildOptsProvider
 		PkgFormatProvider
 		RepositoryProvider
 	},
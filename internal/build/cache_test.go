@@ -0,0 +1,94 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/config"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+type cacheConfig struct {
+	testConfig
+	pkgsDir string
+	signing types.SigningConfig
+}
+
+func (c cacheConfig) GetPaths() *config.Paths      { return &config.Paths{PkgsDir: c.pkgsDir} }
+func (c cacheConfig) Signing() types.SigningConfig { return c.signing }
+
+func TestCheckForBuiltPackage(t *testing.T) {
+	ctx := context.Background()
+	cfg := cacheConfig{pkgsDir: t.TempDir()}
+	cache := &Cache{cfg}
+	input := &BuildInput{
+		opts:       &types.BuildOpts{},
+		info:       &distro.OSRelease{ID: "debian"},
+		pkgFormat:  "deb",
+		repository: "default",
+	}
+	vars := &alrsh.Package{Name: "foo", Version: "1.0", Release: 1}
+
+	touch := func(vars *alrsh.Package) string {
+		t.Helper()
+		name, err := pkgFileName(input, vars)
+		require.NoError(t, err)
+		path := filepath.Join(cfg.pkgsDir, "foo", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, nil, 0o644))
+		return path
+	}
+
+	_, ok, err := cache.CheckForBuiltPackage(ctx, input, vars)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// Без пакета -debug кеш не используется, иначе он не попадёт в результат сборки
+	pkgPath := touch(vars)
+	_, ok, err = cache.CheckForBuiltPackage(ctx, input, vars)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	debugPath := touch(debugPackageVars(vars))
+	dep, ok, err := cache.CheckForBuiltPackage(ctx, input, vars)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, &BuiltDep{Name: "foo", Path: pkgPath, DebugPath: debugPath}, dep)
+
+	// С отключённым -debug достаточно основного пакета
+	noDebug := *vars
+	noDebug.Options.SetResolved([]string{"!debug"})
+	dep, ok, err = cache.CheckForBuiltPackage(ctx, input, &noDebug)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, &BuiltDep{Name: "foo", Path: pkgPath}, dep)
+
+	// Пакеты в кеше могут быть не подписаны, поэтому с подписью кеш не используется
+	cfg.signing = types.SigningConfig{KeyFile: "/etc/alr/key.asc"}
+	_, ok, err = (&Cache{cfg}).CheckForBuiltPackage(ctx, input, vars)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
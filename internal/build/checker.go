@@ -37,10 +37,14 @@ func (c *Checker) PerformChecks(
 	input *BuildInput,
 	vars *alrsh.Package,
 ) (bool, error) {
-	if !cpu.IsCompatibleWith(cpu.Arch(), vars.Architectures) { // Проверяем совместимость архитектуры
+	if !cpu.IsCompatibleWith(targetArch(input.opts), vars.Architectures) { // Проверяем совместимость архитектуры
+		msg := gotext.Get("Your system's CPU architecture doesn't match this package. Do you want to build anyway?")
+		if isCrossBuild(input.opts) {
+			msg = gotext.Get("Target architecture %s doesn't match this package. Do you want to build anyway?", targetArch(input.opts))
+		}
 		cont, err := cliutils.YesNoPrompt(
 			ctx,
-			gotext.Get("Your system's CPU architecture doesn't match this package. Do you want to build anyway?"),
+			msg,
 			input.opts.Interactive,
 			true,
 		)
@@ -0,0 +1,188 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/manager"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/depver"
+)
+
+// FileConflict описывает файл устанавливаемого пакета, который уже
+// принадлежит другим пакетам
+type FileConflict struct {
+	Path string
+	// Package - пакет ALR, содержащий файл
+	Package string
+	// Owners - установленные пакеты или другие пакеты той же транзакции
+	Owners []string
+}
+
+// conflictArtifact - собранный пакет, который будет установлен
+type conflictArtifact struct {
+	dep *BuiltDep
+	pkg *alrsh.Package
+}
+
+// installedName возвращает имя, под которым пакет будет установлен ("name+repo")
+func (a conflictArtifact) installedName() string {
+	if a.pkg == nil || a.pkg.Repository == "" {
+		return a.dep.Name
+	}
+	return a.dep.Name + "+" + a.pkg.Repository
+}
+
+// explains сообщает, объявляет ли пакет pkg замену или конфликт с name,
+// то есть ожидается ли пересечение их файлов. Для установленных пакетов
+// ALR ("name+repo") сравнивается имя пакета без репозитория.
+func explains(pkg *alrsh.Package, name string) bool {
+	if pkg == nil {
+		return false
+	}
+	if matches := RegexpALRPackageName.FindStringSubmatch(name); matches != nil {
+		name = matches[RegexpALRPackageName.SubexpIndex("package")]
+	}
+	for _, dep := range slices.Concat(pkg.Replaces, pkg.Conflicts) {
+		if depver.Parse(dep).Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// findFileConflicts сравнивает файлы пакетов artifacts с файлами
+// установленных пакетов и друг с другом. Пересечения с предыдущей версией
+// того же пакета и объяснённые через replaces/conflicts не считаются конфликтами.
+func findFileConflicts(fq manager.FileQuerier, artifacts []conflictArtifact) ([]FileConflict, error) {
+	providers := map[string][]conflictArtifact{}
+	var paths []string
+	for _, a := range artifacts {
+		files, err := fq.PackageFiles(a.dep.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files of %s: %w", a.dep.Path, err)
+		}
+		for _, file := range files {
+			if len(providers[file]) == 0 {
+				paths = append(paths, file)
+			}
+			providers[file] = append(providers[file], a)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	installed, err := fq.FileOwners(paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file owners: %w", err)
+	}
+
+	var conflicts []FileConflict
+	for _, file := range paths {
+		for _, a := range providers[file] {
+			self := a.installedName()
+			var owners []string
+			addOwner := func(name string) {
+				if name == self || explains(a.pkg, name) || slices.Contains(owners, name) {
+					return
+				}
+				owners = append(owners, name)
+			}
+
+			for _, owner := range installed[file] {
+				addOwner(owner)
+			}
+			for _, other := range providers[file] {
+				if other.dep != a.dep && !explains(other.pkg, a.dep.Name) {
+					addOwner(other.dep.Name)
+				}
+			}
+
+			if len(owners) > 0 {
+				sort.Strings(owners)
+				conflicts = append(conflicts, FileConflict{
+					Path:    file,
+					Package: a.dep.Name,
+					Owners:  owners,
+				})
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// checkFileConflicts проверяет собранные пакеты перед установкой.
+// Если менеджер не умеет искать владельцев файлов, проверка пропускается.
+// При найденных конфликтах в интерактивном режиме пользователю
+// предлагается продолжить, иначе возвращается ошибка.
+func (i *Builder) checkFileConflicts(
+	ctx context.Context,
+	input BuildOptsProvider,
+	artifacts []conflictArtifact,
+) error {
+	if i.mgr == nil || len(artifacts) == 0 {
+		return nil
+	}
+	fq, ok := manager.AsFileQuerier(i.mgr)
+	if !ok {
+		slog.Debug("file conflict check is not supported by package manager", "manager", i.mgr.Name())
+		return nil
+	}
+
+	conflicts, err := findFileConflicts(fq, artifacts)
+	if err != nil {
+		slog.Warn(gotext.Get("Unable to check file conflicts"), "error", err)
+		return nil
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	for _, c := range conflicts {
+		slog.Error(gotext.Get("File conflict"), "file", c.Path, "package", c.Package, "owners", strings.Join(c.Owners, ", "))
+	}
+	slog.Info(gotext.Get("Add the conflicting packages to replaces or conflicts in the build script if the overlap is intended"))
+
+	cont, err := cliutils.YesNoPrompt(ctx, gotext.Get("Install anyway?"), input.BuildOpts().Interactive, false)
+	if err != nil {
+		return err
+	}
+	if !cont {
+		return errors.New(gotext.Get("%d file conflicts found", len(conflicts)))
+	}
+	return nil
+}
+
+// newConflictArtifacts связывает собранные из пакета pkg файлы deps с ним самим
+func newConflictArtifacts(deps []*BuiltDep, pkg *alrsh.Package) []conflictArtifact {
+	artifacts := make([]conflictArtifact, 0, len(deps))
+	for _, dep := range deps {
+		artifacts = append(artifacts, conflictArtifact{dep: dep, pkg: pkg})
+	}
+	return artifacts
+}
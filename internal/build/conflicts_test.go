@@ -0,0 +1,104 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+)
+
+type fakeFileQuerier struct {
+	files  map[string][]string
+	owners map[string][]string
+}
+
+func (f *fakeFileQuerier) PackageFiles(path string) ([]string, error) {
+	return f.files[path], nil
+}
+
+func (f *fakeFileQuerier) FileOwners(paths []string) (map[string][]string, error) {
+	res := map[string][]string{}
+	for _, p := range paths {
+		if owners, ok := f.owners[p]; ok {
+			res[p] = owners
+		}
+	}
+	return res, nil
+}
+
+func TestFindFileConflicts(t *testing.T) {
+	fq := &fakeFileQuerier{
+		files: map[string][]string{
+			"foo.deb":    {"/usr/bin/foo", "/usr/bin/common", "/usr/share/foo/data"},
+			"bar.deb":    {"/usr/bin/bar", "/usr/bin/common", "/etc/legacy.conf"},
+			"foo-ng.deb": {"/usr/bin/foo"},
+		},
+		owners: map[string][]string{
+			// Предыдущая версия самого пакета
+			"/usr/share/foo/data": {"foo+repo"},
+			"/usr/bin/common":     {"coreutils"},
+			"/etc/legacy.conf":    {"legacy+repo"},
+		},
+	}
+
+	foo := &alrsh.Package{Name: "foo", Repository: "repo"}
+	bar := &alrsh.Package{Name: "bar", Repository: "repo", Replaces: []string{"legacy>=1.0"}}
+	fooNG := &alrsh.Package{Name: "foo-ng", Repository: "repo", Conflicts: []string{"foo"}}
+
+	conflicts, err := findFileConflicts(fq, []conflictArtifact{
+		{dep: &BuiltDep{Name: "foo", Path: "foo.deb"}, pkg: foo},
+		{dep: &BuiltDep{Name: "bar", Path: "bar.deb"}, pkg: bar},
+		{dep: &BuiltDep{Name: "foo-ng", Path: "foo-ng.deb"}, pkg: fooNG},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []FileConflict{
+		{Path: "/usr/bin/common", Package: "foo", Owners: []string{"bar", "coreutils"}},
+		{Path: "/usr/bin/common", Package: "bar", Owners: []string{"coreutils", "foo"}},
+	}, conflicts)
+}
+
+func TestFindFileConflictsNone(t *testing.T) {
+	fq := &fakeFileQuerier{
+		files: map[string][]string{"foo.deb": {"/usr/bin/foo"}},
+	}
+	conflicts, err := findFileConflicts(fq, newConflictArtifacts(
+		[]*BuiltDep{{Name: "foo", Path: "foo.deb"}},
+		&alrsh.Package{Name: "foo"},
+	))
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+}
+
+func TestFindFileConflictsOtherRepo(t *testing.T) {
+	// Пакет с тем же именем из другого репозитория - не предыдущая версия
+	fq := &fakeFileQuerier{
+		files:  map[string][]string{"foo.deb": {"/usr/bin/foo"}},
+		owners: map[string][]string{"/usr/bin/foo": {"foo+other"}},
+	}
+	conflicts, err := findFileConflicts(fq, newConflictArtifacts(
+		[]*BuiltDep{{Name: "foo", Path: "foo.deb"}},
+		&alrsh.Package{Name: "foo", Repository: "repo"},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, []FileConflict{
+		{Path: "/usr/bin/foo", Package: "foo", Owners: []string{"foo+other"}},
+	}, conflicts)
+}
@@ -0,0 +1,108 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/leonelquinteros/gotext"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cpu"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+// targetArch возвращает архитектуру, для которой собирается пакет
+func targetArch(opts *types.BuildOpts) string {
+	if isMultilib(opts) {
+		return multilibArch
+	}
+	if opts != nil && opts.TargetArch != "" {
+		return opts.TargetArch
+	}
+	return cpu.Arch()
+}
+
+// isCrossBuild сообщает, отличается ли целевая архитектура от архитектуры системы
+func isCrossBuild(opts *types.BuildOpts) bool {
+	return targetArch(opts) != cpu.Arch()
+}
+
+// checkCrossBuild проверяет, что пакеты можно собрать для целевой архитектуры:
+// скрипт с options=('!cross') собирается только в режиме эмуляции
+// в корневой файловой системе целевой архитектуры (--emulate-root)
+func checkCrossBuild(opts *types.BuildOpts, varsOfPackages []*alrsh.Package) error {
+	// 32-битные программы multilib-сборки собираются системным компилятором с -m32
+	if !isCrossBuild(opts) || isMultilib(opts) {
+		return nil
+	}
+
+	if needsEmulation(opts, varsOfPackages) {
+		_, err := checkEmulation(opts)
+		return err
+	}
+
+	for _, vars := range varsOfPackages {
+		if !crossEnabled(vars) {
+			return errors.New(gotext.Get("Package %s does not support cross compilation, use --emulate-root to build it in a %s root filesystem under qemu-user", vars.Name, targetArch(opts)))
+		}
+	}
+	return nil
+}
+
+// crossEnabled сообщает, поддерживает ли скрипт кросс-компиляцию.
+// Отключается через options=('!cross').
+func crossEnabled(vars *alrsh.Package) bool {
+	return !slices.Contains(vars.Options.Resolved(), "!cross")
+}
+
+// hostBuildInput - параметры сборки для архитектуры системы. Зависимости
+// для сборки устанавливаются в систему, поэтому собираются не для целевой
+// архитектуры, а для архитектуры системы.
+type hostBuildInput struct {
+	OsInfoProvider
+	PkgFormatProvider
+	opts *types.BuildOpts
+}
+
+func newHostBuildInput(input interface {
+	OsInfoProvider
+	BuildOptsProvider
+	PkgFormatProvider
+},
+) *hostBuildInput {
+	opts := *input.BuildOpts()
+	opts.TargetArch = ""
+	opts.EmulateRoot = ""
+	opts.Multilib = false
+	return &hostBuildInput{input, input, &opts}
+}
+
+func (h *hostBuildInput) BuildOpts() *types.BuildOpts {
+	return h.opts
+}
+
+// crossToolPrefix возвращает префикс инструментов (strip, objcopy) для целевой
+// архитектуры или пустую строку, если пакет собирается для архитектуры системы
+func crossToolPrefix(info *distro.OSRelease, opts *types.BuildOpts) string {
+	if !isCrossBuild(opts) || isMultilib(opts) {
+		return ""
+	}
+	return cpu.Triplet(targetArch(opts), info.IsMusl()) + "-"
+}
@@ -0,0 +1,157 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mvdan.cc/sh/v3/expand"
+	"mvdan.cc/sh/v3/interp"
+	"mvdan.cc/sh/v3/syntax"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cpu"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+// foreignArch возвращает архитектуру, отличную от архитектуры системы
+func foreignArch() string {
+	if cpu.Arch() == "riscv64" {
+		return "s390x"
+	}
+	return "riscv64"
+}
+
+func TestCheckCrossBuild(t *testing.T) {
+	cross := &alrsh.Package{Name: "cross"}
+	native := &alrsh.Package{Name: "native"}
+	native.Options.SetResolved([]string{"!cross"})
+
+	assert.NoError(t, checkCrossBuild(&types.BuildOpts{}, []*alrsh.Package{native}))
+	assert.NoError(t, checkCrossBuild(&types.BuildOpts{TargetArch: cpu.Arch()}, []*alrsh.Package{native}))
+
+	opts := &types.BuildOpts{TargetArch: foreignArch()}
+	assert.NoError(t, checkCrossBuild(opts, []*alrsh.Package{cross}))
+	assert.Error(t, checkCrossBuild(opts, []*alrsh.Package{cross, native}))
+
+	// Каталог без /bin/sh не является корневой файловой системой
+	opts.EmulateRoot = t.TempDir()
+	assert.NoError(t, checkCrossBuild(opts, []*alrsh.Package{cross}))
+	assert.Error(t, checkCrossBuild(opts, []*alrsh.Package{native}))
+}
+
+func TestNeedsEmulation(t *testing.T) {
+	cross := &alrsh.Package{Name: "cross"}
+	native := &alrsh.Package{Name: "native"}
+	native.Options.SetResolved([]string{"!cross"})
+	root := t.TempDir()
+
+	assert.False(t, needsEmulation(&types.BuildOpts{TargetArch: foreignArch()}, []*alrsh.Package{native}))
+	assert.False(t, needsEmulation(&types.BuildOpts{EmulateRoot: root}, []*alrsh.Package{native}))
+	assert.False(t, needsEmulation(&types.BuildOpts{TargetArch: foreignArch(), EmulateRoot: root}, []*alrsh.Package{cross}))
+	assert.True(t, needsEmulation(&types.BuildOpts{TargetArch: foreignArch(), EmulateRoot: root}, []*alrsh.Package{cross, native}))
+	assert.Empty(t, newHostBuildInput(&BuildInput{opts: &types.BuildOpts{EmulateRoot: root}}).BuildOpts().EmulateRoot)
+}
+
+func TestEmulationCommand(t *testing.T) {
+	dirs := types.Directories{BaseDir: "/var/cache/alr/pkgs/foo", ScriptDir: "/repo/foo"}
+	e := &emulation{
+		root:    "/srv/rootfs-arm64",
+		handler: cpu.BinfmtHandler{Interpreter: "/usr/bin/qemu-aarch64-static", Flags: "OCF"},
+		dirs:    dirs,
+	}
+
+	cmd := e.command("/var/cache/alr/pkgs/foo/src", []string{"make", "-j4"})
+	assert.Equal(t, []string{"bwrap", "--bind", "/srv/rootfs-arm64", "/"}, cmd[:4])
+	assert.Equal(t, []string{"--chdir", "/var/cache/alr/pkgs/foo/src", "--", "make", "-j4"}, cmd[len(cmd)-5:])
+	assert.Subset(t, cmd, []string{"--bind", dirs.BaseDir, "--ro-bind", dirs.ScriptDir})
+	assert.NotContains(t, cmd, e.handler.Interpreter)
+
+	// Без флага F интерпретатор монтируется в корневую файловую систему
+	e.handler.Flags = "P"
+	assert.Contains(t, e.command("/", []string{"true"}), e.handler.Interpreter)
+
+	// Внешние команды скрипта запускаются через bwrap в текущем каталоге
+	e.dirs.ScriptDir = t.TempDir()
+	var got []string
+	runner, err := interp.New(
+		interp.Dir(e.dirs.ScriptDir),
+		interp.ExecHandler(e.ExecHandler(func(ctx context.Context, args []string) error {
+			got = args
+			return nil
+		})),
+	)
+	require.NoError(t, err)
+	script, err := syntax.NewParser().Parse(strings.NewReader("./configure --host=$CHOST"), "")
+	require.NoError(t, err)
+	require.NoError(t, runner.Run(t.Context(), script))
+	assert.Equal(t, "bwrap", got[0])
+	assert.Equal(t, []string{"--chdir", e.dirs.ScriptDir, "--", "./configure", "--host="}, got[len(got)-5:])
+}
+
+func TestCreateBuildEnvVarsCross(t *testing.T) {
+	info := &distro.OSRelease{ID: "debian"}
+	arch := foreignArch()
+
+	env := createBuildEnvVars(info, &types.BuildOpts{TargetArch: arch}, types.Directories{})
+	assert.Contains(t, env, "ARCH="+arch)
+	assert.Contains(t, env, "CHOST="+cpu.Triplet(arch, false))
+	assert.Contains(t, env, "CROSS_COMPILE="+cpu.Triplet(arch, false)+"-")
+	assert.Equal(t, cpu.Triplet(arch, false)+"-", crossToolPrefix(info, &types.BuildOpts{TargetArch: arch}))
+
+	// При эмуляции программы собираются компилятором целевой архитектуры
+	env = append(env, emulationEnv()...)
+	assert.Equal(t, "", expand.ListEnviron(env...).Get("CROSS_COMPILE").String())
+
+	env = createBuildEnvVars(info, &types.BuildOpts{}, types.Directories{})
+	assert.Contains(t, env, "ARCH="+cpu.Arch())
+	assert.Contains(t, env, "CROSS_COMPILE=")
+	assert.Empty(t, crossToolPrefix(info, &types.BuildOpts{}))
+}
+
+func TestCrossParseBuildVars(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alr.sh")
+	require.NoError(t, os.WriteFile(path, []byte(`name=foo
+version=1.0
+release=1
+desc="foo"
+architectures=('amd64' 'riscv64' 's390x')
+deps=('base')
+deps_riscv64=('riscv64-only')
+deps_s390x=('s390x-only')
+`), 0o644))
+
+	sf, err := alrsh.ReadFromLocal(path)
+	require.NoError(t, err)
+
+	arch := foreignArch()
+	input := &BuildInput{
+		opts: &types.BuildOpts{TargetArch: arch},
+		info: &distro.OSRelease{ID: "debian"},
+	}
+	_, pkgs, err := NewLocalScriptExecutor(nil).ExecuteFirstPass(t.Context(), input, sf)
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+	assert.Equal(t, []string{arch + "-only"}, pkgs[0].Depends.Resolved())
+}
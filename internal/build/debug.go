@@ -0,0 +1,287 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"debug/elf"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/leonelquinteros/gotext"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/xbps"
+)
+
+// debugRoot - каталог отладочной информации в пакете
+const debugRoot = "/usr/lib/debug"
+
+// stripEnabled сообщает, нужно ли удалять отладочную информацию из ELF-файлов.
+// Отключается через options=('!strip').
+func stripEnabled(vars *alrsh.Package) bool {
+	return !slices.Contains(vars.Options.Resolved(), "!strip")
+}
+
+// debugEnabled сообщает, нужно ли собирать пакет <имя>-debug.
+// Отключается через options=('!debug') или options=('!strip').
+func debugEnabled(vars *alrsh.Package) bool {
+	return stripEnabled(vars) && !slices.Contains(vars.Options.Resolved(), "!debug")
+}
+
+// debugPackageVars возвращает переменные пакета с отладочной информацией для vars
+func debugPackageVars(vars *alrsh.Package) *alrsh.Package {
+	dbg := &alrsh.Package{
+		Repository:  vars.Repository,
+		Name:        vars.Name + "-debug",
+		BasePkgName: vars.BasePkgName,
+		Version:     vars.Version,
+		Release:     vars.Release,
+		Epoch:       vars.Epoch,
+		Licenses:    vars.Licenses,
+	}
+	dbg.Description.SetResolved(gotext.Get("Debug symbols for %s", vars.Name))
+	dbg.Homepage.SetResolved(vars.Homepage.Resolved())
+	dbg.Maintainer.SetResolved(vars.Maintainer.Resolved())
+	return dbg
+}
+
+// debugDepends возвращает зависимость пакета с отладочной информацией от точной
+// версии основного пакета pkgInfo ([эпоха:]версия-релиз) в синтаксисе формата pkgFormat,
+// иначе символы могли бы установиться к файлам другой сборки
+func debugDepends(pkgInfo *nfpm.Info, pkgFormat string) []string {
+	version := pkgInfo.Version
+	if pkgInfo.Release != "" {
+		version += "-" + pkgInfo.Release
+	}
+	if pkgInfo.Epoch != "" {
+		version = pkgInfo.Epoch + ":" + version
+	}
+
+	switch pkgFormat {
+	case "deb", "ipk":
+		return []string{fmt.Sprintf("%s (= %s)", pkgInfo.Name, version)}
+	case "rpm":
+		return []string{fmt.Sprintf("%s = %s", pkgInfo.Name, version)}
+	case "apk":
+		// В Alpine нет эпохи, а релиз записывается как -rN
+		version = pkgInfo.Version
+		if pkgInfo.Release != "" {
+			version += "-r" + strings.TrimPrefix(pkgInfo.Release, "r")
+		}
+		return []string{pkgInfo.Name + "=" + version}
+	case "xbps":
+		// Точная версия в xbps задаётся строкой pkgver: name-version_revision
+		return []string{pkgInfo.Name + "-" + xbps.Version(pkgInfo)}
+	default:
+		return []string{pkgInfo.Name + "=" + version}
+	}
+}
+
+// elfTarget - ELF-файл, из которого можно удалить отладочную информацию
+type elfTarget struct {
+	path    string
+	buildID string
+	debug   bool
+}
+
+// splitDebugInfo удаляет отладочную информацию из ELF-файлов в pkgDir.
+// Если debugDir не пуст, отладочная информация сохраняется в нём по пути
+// /usr/lib/debug/.build-id/xx/yyyy.debug. Возвращает количество сохранённых файлов.
+// toolPrefix - префикс инструментов кросс-компиляции (например, "aarch64-linux-gnu-"),
+// если таких инструментов нет, файлы не изменяются.
+func splitDebugInfo(ctx context.Context, pkgDir, debugDir, toolPrefix string) (int, error) {
+	targets, err := findELFTargets(pkgDir)
+	if err != nil || len(targets) == 0 {
+		return 0, err
+	}
+
+	// Инструменты системы не обрабатывают ELF-файлы другой архитектуры,
+	// поэтому при кросс-сборке используются только инструменты с префиксом
+	strip, err := exec.LookPath(toolPrefix + "strip")
+	if err != nil {
+		slog.Warn(gotext.Get("strip not found, debug information is kept"), "tool", toolPrefix+"strip")
+		return 0, nil
+	}
+	objcopy, err := exec.LookPath(toolPrefix + "objcopy")
+	if err != nil {
+		debugDir = ""
+	}
+
+	saved := 0
+	for _, t := range targets {
+		fi, err := os.Stat(t.path)
+		if err != nil {
+			return saved, err
+		}
+		// strip и objcopy перезаписывают файл, поэтому временно разрешаем запись
+		mode := fi.Mode().Perm()
+		if mode&0o200 == 0 {
+			if err := os.Chmod(t.path, mode|0o200); err != nil {
+				return saved, err
+			}
+		}
+
+		if debugDir != "" && t.debug {
+			if err := saveDebugFile(ctx, objcopy, pkgDir, debugDir, t); err != nil {
+				return saved, err
+			}
+			saved++
+		}
+
+		if err := runTool(ctx, strip, "--strip-unneeded", t.path); err != nil {
+			return saved, err
+		}
+
+		if mode&0o200 == 0 {
+			if err := os.Chmod(t.path, mode); err != nil {
+				return saved, err
+			}
+		}
+	}
+	return saved, nil
+}
+
+// saveDebugFile копирует отладочную информацию t в debugDir и добавляет
+// в исходный файл ссылку .gnu_debuglink на неё
+func saveDebugFile(ctx context.Context, objcopy, pkgDir, debugDir string, t elfTarget) error {
+	var rel string
+	if len(t.buildID) > 2 {
+		rel = filepath.Join(".build-id", t.buildID[:2], t.buildID[2:]+".debug")
+	} else {
+		r, err := filepath.Rel(pkgDir, t.path)
+		if err != nil {
+			return err
+		}
+		rel = r + ".debug"
+	}
+
+	dest := filepath.Join(debugDir, debugRoot, rel)
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	if err := runTool(ctx, objcopy, "--only-keep-debug", t.path, dest); err != nil {
+		return err
+	}
+	if err := os.Chmod(dest, 0o644); err != nil {
+		return err
+	}
+	return runTool(ctx, objcopy, "--add-gnu-debuglink="+dest, t.path)
+}
+
+// findELFTargets находит в root исполняемые файлы и разделяемые библиотеки ELF,
+// которые ещё содержат символы или отладочную информацию
+func findELFTargets(root string) ([]elfTarget, error) {
+	var targets []elfTarget
+	seen := map[uint64]struct{}{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		// Жёсткие ссылки обрабатываются один раз
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if st, ok := info.Sys().(*syscall.Stat_t); ok && st.Nlink > 1 {
+			if _, ok := seen[st.Ino]; ok {
+				return nil
+			}
+			seen[st.Ino] = struct{}{}
+		}
+
+		t, ok := inspectELF(path)
+		if ok {
+			targets = append(targets, t)
+		}
+		return nil
+	})
+	return targets, err
+}
+
+func inspectELF(path string) (elfTarget, bool) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return elfTarget{}, false
+	}
+	defer f.Close()
+
+	if f.Type != elf.ET_EXEC && f.Type != elf.ET_DYN {
+		return elfTarget{}, false
+	}
+	// Отладочная информация уже вынесена
+	if f.Section(".gnu_debuglink") != nil {
+		return elfTarget{}, false
+	}
+
+	t := elfTarget{path: path}
+	for _, s := range f.Sections {
+		if s.Name == ".debug_info" || s.Name == ".zdebug_info" {
+			t.debug = true
+		}
+	}
+	if !t.debug && f.Section(".symtab") == nil {
+		return elfTarget{}, false
+	}
+	t.buildID = elfBuildID(f)
+	return t, true
+}
+
+// elfBuildID читает идентификатор сборки из заметки .note.gnu.build-id
+func elfBuildID(f *elf.File) string {
+	s := f.Section(".note.gnu.build-id")
+	if s == nil {
+		return ""
+	}
+	data, err := s.Data()
+	if err != nil || len(data) < 16 {
+		return ""
+	}
+
+	namesz := f.ByteOrder.Uint32(data[0:4])
+	descsz := f.ByteOrder.Uint32(data[4:8])
+	if f.ByteOrder.Uint32(data[8:12]) != 3 { // NT_GNU_BUILD_ID
+		return ""
+	}
+	start := 12 + (namesz+3)&^3
+	if uint64(start)+uint64(descsz) > uint64(len(data)) {
+		return ""
+	}
+	return hex.EncodeToString(data[start : start+descsz])
+}
+
+func runTool(ctx context.Context, name string, args ...string) error {
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %v: %w: %s", filepath.Base(name), args, err, out)
+	}
+	return nil
+}
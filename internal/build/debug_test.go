@@ -0,0 +1,129 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"debug/elf"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+)
+
+func TestDebugOptions(t *testing.T) {
+	for _, tc := range []struct {
+		options []string
+		strip   bool
+		debug   bool
+	}{
+		{nil, true, true},
+		{[]string{"!debug"}, true, false},
+		{[]string{"!strip"}, false, false},
+	} {
+		vars := &alrsh.Package{}
+		vars.Options.SetResolved(tc.options)
+		assert.Equal(t, tc.strip, stripEnabled(vars), tc.options)
+		assert.Equal(t, tc.debug, debugEnabled(vars), tc.options)
+	}
+}
+
+func TestDebugDepends(t *testing.T) {
+	info := &nfpm.Info{Name: "foo+alr-default", Version: "1.2", Release: "3", Epoch: "2"}
+	for format, want := range map[string]string{
+		"deb":       "foo+alr-default (= 2:1.2-3)",
+		"ipk":       "foo+alr-default (= 2:1.2-3)",
+		"rpm":       "foo+alr-default = 2:1.2-3",
+		"archlinux": "foo+alr-default=2:1.2-3",
+		"apk":       "foo+alr-default=1.2-r3",
+		"xbps":      "foo+alr-default-1.2_3",
+	} {
+		assert.Equal(t, []string{want}, debugDepends(info, format), format)
+	}
+
+	info.Epoch = ""
+	assert.Equal(t, []string{"foo+alr-default = 1.2-3"}, debugDepends(info, "rpm"))
+}
+
+func TestSplitDebugInfo(t *testing.T) {
+	for _, tool := range []string{"cc", "strip", "objcopy"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not found", tool)
+		}
+	}
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "main.c")
+	require.NoError(t, os.WriteFile(src, []byte("int main(void) { return 0; }\n"), 0o644))
+
+	pkgDir := filepath.Join(dir, "pkg")
+	bin := filepath.Join(pkgDir, "usr", "bin", "foo")
+	require.NoError(t, os.MkdirAll(filepath.Dir(bin), 0o755))
+	out, err := exec.Command("cc", "-g", "-Wl,--build-id", "-o", bin, src).CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.NoError(t, os.Chmod(bin, 0o555))
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "usr", "bin", "script"), []byte("#!/bin/sh\n"), 0o755))
+
+	f, err := elf.Open(bin)
+	require.NoError(t, err)
+	buildID := elfBuildID(f)
+	f.Close()
+	require.NotEmpty(t, buildID)
+
+	debugDir := filepath.Join(dir, "pkg-debug")
+
+	// Без инструментов целевой архитектуры файлы остаются как есть
+	n, err := splitDebugInfo(context.Background(), pkgDir, debugDir, "alr-test-none-linux-gnu-")
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	f, err = elf.Open(bin)
+	require.NoError(t, err)
+	assert.NotNil(t, f.Section(".debug_info"))
+	f.Close()
+
+	n, err = splitDebugInfo(context.Background(), pkgDir, debugDir, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	fi, err := os.Stat(bin)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o555), fi.Mode().Perm())
+
+	f, err = elf.Open(bin)
+	require.NoError(t, err)
+	assert.Nil(t, f.Section(".debug_info"))
+	assert.Nil(t, f.Section(".symtab"))
+	assert.NotNil(t, f.Section(".gnu_debuglink"))
+	f.Close()
+
+	debugFile := filepath.Join(debugDir, "usr", "lib", "debug", ".build-id", buildID[:2], buildID[2:]+".debug")
+	f, err = elf.Open(debugFile)
+	require.NoError(t, err)
+	assert.NotNil(t, f.Section(".debug_info"))
+	f.Close()
+
+	// Повторный запуск ничего не находит
+	n, err = splitDebugInfo(context.Background(), pkgDir, debugDir, "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
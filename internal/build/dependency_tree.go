@@ -52,6 +52,7 @@ func (b *Builder) ResolveUnifiedDependencyTree(
 	ctx context.Context,
 	input interface {
 		OsInfoProvider
+		BuildOptsProvider
 		PkgFormatProvider
 	},
 	initialPkgs []string,
@@ -69,7 +70,7 @@ func (b *Builder) ResolveUnifiedDependencyTree(
 	optVisited := make(map[string]bool)
 	buildDepVisited := make(map[string]bool)
 
-	overrideNames, err := overrides.Resolve(input.OSRelease(), overrides.DefaultOpts)
+	overrideNames, err := overrides.Resolve(input.OSRelease(), overrides.DefaultOpts.WithArch(targetArch(input.BuildOpts())))
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve overrides: %w", err)
 	}
@@ -72,6 +72,7 @@ func getDirsForPackage(
 		SrcDir:    getSrcDir(cfg, basePkg),
 		PkgDir:    filepath.Join(baseDir, pkgDirName),
 		ScriptDir: getScriptDir(scriptPath),
+		StageDir:  filepath.Join(baseDir, "stage"),
 	}, nil
 }
 
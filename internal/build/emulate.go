@@ -0,0 +1,125 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+
+	"github.com/leonelquinteros/gotext"
+	"mvdan.cc/sh/v3/interp"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cpu"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+// emulation описывает сборку скрипта без поддержки кросс-компиляции
+// в корневой файловой системе целевой архитектуры. Внешние команды
+// скрипта запускаются в ней через bubblewrap, а программы целевой
+// архитектуры выполняет qemu-user, зарегистрированный в binfmt_misc.
+type emulation struct {
+	root    string
+	handler cpu.BinfmtHandler
+	dirs    types.Directories
+}
+
+// needsEmulation сообщает, собираются ли пакеты через эмуляцию:
+// это возможно только при сборке для другой архитектуры с --emulate-root
+// и нужно, только если скрипт не поддерживает кросс-компиляцию
+func needsEmulation(opts *types.BuildOpts, varsOfPackages []*alrsh.Package) bool {
+	if opts == nil || opts.EmulateRoot == "" || !isCrossBuild(opts) || isMultilib(opts) {
+		return false
+	}
+	return slices.ContainsFunc(varsOfPackages, func(vars *alrsh.Package) bool {
+		return !crossEnabled(vars)
+	})
+}
+
+// checkEmulation проверяет, что сборку можно выполнить в корневой
+// файловой системе opts.EmulateRoot: в ней есть /bin/sh, обработчик
+// qemu-user для целевой архитектуры включён и установлен bwrap
+func checkEmulation(opts *types.BuildOpts) (cpu.BinfmtHandler, error) {
+	arch := targetArch(opts)
+
+	if _, err := os.Stat(filepath.Join(opts.EmulateRoot, "bin", "sh")); err != nil {
+		return cpu.BinfmtHandler{}, errors.New(gotext.Get("%s is not a root filesystem: /bin/sh not found", opts.EmulateRoot))
+	}
+
+	handler, ok := cpu.Binfmt(arch)
+	if !ok {
+		return cpu.BinfmtHandler{}, errors.New(gotext.Get("qemu-user binfmt handler for %s is not registered", arch))
+	}
+
+	if _, err := exec.LookPath("bwrap"); err != nil {
+		return cpu.BinfmtHandler{}, errors.New(gotext.Get("bubblewrap (bwrap) is required to build in an emulated root filesystem"))
+	}
+	return handler, nil
+}
+
+// newEmulation подготавливает сборку в корневой файловой системе opts.EmulateRoot
+func newEmulation(opts *types.BuildOpts, dirs types.Directories) (*emulation, error) {
+	handler, err := checkEmulation(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &emulation{root: opts.EmulateRoot, handler: handler, dirs: dirs}, nil
+}
+
+// command возвращает команду, которая выполняет args в корневой
+// файловой системе в каталоге dir. Каталоги сборки и скрипта
+// монтируются по тем же путям, поэтому $srcdir и $pkgdir не меняются.
+// Если интерпретатор qemu-user не открыт ядром заранее (флаг F),
+// он тоже монтируется в корневую файловую систему.
+func (e *emulation) command(dir string, args []string) []string {
+	cmd := []string{
+		"bwrap",
+		"--bind", e.root, "/",
+		"--dev", "/dev",
+		"--unshare-pid",
+		"--proc", "/proc",
+		"--die-with-parent",
+		"--bind", e.dirs.BaseDir, e.dirs.BaseDir,
+		"--ro-bind", e.dirs.ScriptDir, e.dirs.ScriptDir,
+	}
+	if !e.handler.FixBinary() {
+		cmd = append(cmd, "--ro-bind", e.handler.Interpreter, e.handler.Interpreter)
+	}
+	cmd = append(cmd, "--chdir", dir, "--")
+	return append(cmd, args...)
+}
+
+// ExecHandler запускает внешние команды скрипта в корневой файловой системе
+func (e *emulation) ExecHandler(next interp.ExecHandlerFunc) interp.ExecHandlerFunc {
+	return func(ctx context.Context, args []string) error {
+		return next(ctx, e.command(interp.HandlerCtx(ctx).Dir, args))
+	}
+}
+
+// emulationEnv возвращает переменные окружения скрипта при эмуляции:
+// программы собираются компилятором целевой архитектуры из корневой
+// файловой системы, поэтому префикс кросс-компилятора не задаётся
+func emulationEnv() []string {
+	return []string{
+		"EMULATED=1",
+		"CROSS_COMPILE=",
+	}
+}
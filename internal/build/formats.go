@@ -0,0 +1,171 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/leonelquinteros/gotext"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/overrides"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/signing"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+// formatDistros - дистрибутивы, переопределения которых используются
+// при упаковке в формат, отличный от формата системы
+var formatDistros = map[string]string{
+	"deb":       "debian",
+	"rpm":       "fedora",
+	"apk":       "alpine",
+	"archlinux": "arch",
+	"ipk":       "openwrt",
+	"xbps":      "void",
+}
+
+// extraFormats возвращает форматы из opts, в которые нужно упаковать
+// результат сборки помимо основного формата pkgFormat
+func extraFormats(opts *types.BuildOpts, pkgFormat string) []string {
+	if opts == nil {
+		return nil
+	}
+	var out []string
+	for _, format := range opts.Formats {
+		if format != pkgFormat && !slices.Contains(out, format) {
+			out = append(out, format)
+		}
+	}
+	return out
+}
+
+// checkFormats проверяет, что все форматы из opts поддерживаются nfpm
+func checkFormats(opts *types.BuildOpts) error {
+	for _, format := range opts.Formats {
+		if _, err := nfpm.Get(format); err != nil {
+			return errors.New(gotext.Get("Unsupported package format: %s", format))
+		}
+	}
+	return nil
+}
+
+// formatRelease возвращает дистрибутив, для которого разрешаются
+// переопределения при упаковке в формат format
+func formatRelease(format string) *distro.OSRelease {
+	id, ok := formatDistros[format]
+	if !ok {
+		id = format
+	}
+	info := overrides.KnownDistro(id)
+	return &info
+}
+
+// formatInput - параметры сборки при упаковке в дополнительный формат
+type formatInput struct {
+	*BuildInput
+	info      *distro.OSRelease
+	pkgFormat string
+}
+
+func (f *formatInput) OSRelease() *distro.OSRelease {
+	return f.info
+}
+
+func (f *formatInput) PkgFormat() string {
+	return f.pkgFormat
+}
+
+// formatPackages - переменные пакетов, разобранные для дополнительного формата
+type formatPackages struct {
+	format string
+	input  *formatInput
+	vars   map[string]*alrsh.Package
+}
+
+// parseFormatPackages разбирает скрипт для каждого дополнительного формата,
+// чтобы зависимости и другие переменные учитывали переопределения его дистрибутива
+func parseFormatPackages(ctx context.Context, input *BuildInput, sf *alrsh.ScriptFile) ([]*formatPackages, error) {
+	var out []*formatPackages
+	for _, format := range extraFormats(input.opts, input.pkgFormat) {
+		fi := &formatInput{input, formatRelease(format), format}
+		_, pkgs, err := sf.ParseBuildVarsForArch(ctx, fi.info, targetArch(input.opts), input.packages)
+		if err != nil {
+			return nil, err
+		}
+
+		fp := &formatPackages{format: format, input: fi, vars: map[string]*alrsh.Package{}}
+		for _, vars := range pkgs {
+			fp.vars[vars.Name] = vars
+		}
+		out = append(out, fp)
+	}
+	return out, nil
+}
+
+// packageExtraFormats упаковывает уже собранный пакет vars (и его пакет
+// с отладочной информацией, если он есть) в дополнительные форматы и
+// возвращает пути к созданным файлам
+func (e *LocalScriptExecutor) packageExtraFormats(
+	ctx context.Context,
+	formats []*formatPackages,
+	vars *alrsh.Package,
+	dirs, debugDirs types.Directories,
+	debugFiles int,
+	preferedContents *[]string,
+) ([]string, error) {
+	var paths []string
+	for _, fp := range formats {
+		format := fp.format
+		fvars, ok := fp.vars[vars.Name]
+		if !ok {
+			fvars = vars
+		}
+
+		slog.Info(gotext.Get("Building package metadata"), "name", vars.Name, "format", format)
+
+		pkgInfo, err := buildPkgMetadata(ctx, fp.input, fvars, dirs, fvars.Depends.Resolved(), preferedContents)
+		if err != nil {
+			return nil, err
+		}
+		signing.Apply(pkgInfo, format, fp.input.signing)
+		path, err := createPackageFile(pkgInfo, format, dirs.BaseDir)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+
+		if debugFiles == 0 {
+			continue
+		}
+		debugInfo, err := buildPkgMetadata(ctx, fp.input, debugPackageVars(fvars), debugDirs, debugDepends(pkgInfo, format), nil)
+		if err != nil {
+			return nil, err
+		}
+		signing.Apply(debugInfo, format, fp.input.signing)
+		debugPath, err := createPackageFile(debugInfo, format, dirs.BaseDir)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, debugPath)
+	}
+	return paths, nil
+}
@@ -0,0 +1,174 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/config"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/signing"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+type testConfig struct{}
+
+func (testConfig) GetPaths() *config.Paths      { return &config.Paths{} }
+func (testConfig) PagerStyle() string           { return "" }
+func (testConfig) PreferALRDeps() bool          { return false }
+func (testConfig) Signing() types.SigningConfig { return types.SigningConfig{} }
+
+func TestExtraFormats(t *testing.T) {
+	opts := &types.BuildOpts{Formats: []string{"deb", "rpm", "deb", "apk"}}
+	assert.Equal(t, []string{"rpm", "apk"}, extraFormats(opts, "deb"))
+	assert.Equal(t, []string{"deb", "rpm", "apk"}, extraFormats(opts, "archlinux"))
+	assert.Empty(t, extraFormats(&types.BuildOpts{}, "deb"))
+
+	assert.NoError(t, checkFormats(opts))
+	assert.Error(t, checkFormats(&types.BuildOpts{Formats: []string{"deb", "msi"}}))
+
+	assert.Equal(t, "fedora", formatRelease("rpm").ID)
+	assert.Equal(t, []string{"lede", "openwrt"}, formatRelease("ipk").Like)
+}
+
+func TestPackageExtraFormats(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "alr.sh")
+	require.NoError(t, os.WriteFile(script, []byte(`name=foo
+version=1.0
+release=1
+desc="foo"
+maintainer="ALR <alr@example.com>"
+architectures=('all')
+deps=('libfoo')
+deps_fedora=('libfoo-libs')
+deps_arch=('foo-runtime')
+`), 0o644))
+
+	sf, err := alrsh.ReadFromLocal(script)
+	require.NoError(t, err)
+
+	input := &BuildInput{
+		opts:       &types.BuildOpts{Formats: []string{"deb", "rpm", "archlinux"}},
+		info:       &distro.OSRelease{ID: "debian"},
+		pkgFormat:  "deb",
+		script:     script,
+		repository: "default",
+	}
+
+	formats, err := parseFormatPackages(t.Context(), input, sf)
+	require.NoError(t, err)
+	require.Len(t, formats, 2)
+	assert.Equal(t, "rpm", formats[0].format)
+	assert.Equal(t, []string{"libfoo-libs"}, formats[0].vars["foo"].Depends.Resolved())
+	assert.Equal(t, []string{"foo-runtime"}, formats[1].vars["foo"].Depends.Resolved())
+
+	dirs := types.Directories{
+		BaseDir:   dir,
+		PkgDir:    filepath.Join(dir, "pkg"),
+		ScriptDir: dir,
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dirs.PkgDir, "usr", "share", "foo"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dirs.PkgDir, "usr", "share", "foo", "data"), []byte("data"), 0o644))
+
+	e := NewLocalScriptExecutor(testConfig{})
+	paths, err := e.packageExtraFormats(t.Context(), formats, formats[0].vars["foo"], dirs, types.Directories{}, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+	assert.Equal(t, "foo+default-1.0-1.noarch.rpm", filepath.Base(paths[0]))
+	assert.Equal(t, "foo+default-1.0-1-any.pkg.tar.zst", filepath.Base(paths[1]))
+	for _, path := range paths {
+		assert.FileExists(t, path)
+	}
+}
+
+func TestSigningPassedToExecutor(t *testing.T) {
+	dir := t.TempDir()
+
+	// Зашифрованный ключ, пароль к которому есть только в окружении alr
+	keyFile := filepath.Join(dir, "key.asc")
+	entity, err := openpgp.NewEntity("alr", "", "alr@example.com", nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.EncryptPrivateKeys([]byte("secret"), nil))
+	fl, err := os.Create(keyFile)
+	require.NoError(t, err)
+	w, err := armor.Encode(fl, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivateWithoutSigning(w, nil))
+	require.NoError(t, w.Close())
+	require.NoError(t, fl.Close())
+
+	t.Setenv("ALR_TEST_PASSPHRASE", "secret")
+	key, err := signing.Resolve(types.SigningConfig{KeyFile: keyFile, Passphrase: "env:ALR_TEST_PASSPHRASE"})
+	require.NoError(t, err)
+
+	script := filepath.Join(dir, "alr.sh")
+	require.NoError(t, os.WriteFile(script, []byte(`name=foo
+version=1.0
+release=1
+desc="foo"
+maintainer="ALR <alr@example.com>"
+architectures=('all')
+`), 0o644))
+
+	sent := &BuildInput{
+		opts:       &types.BuildOpts{Formats: []string{"deb", "rpm"}},
+		info:       &distro.OSRelease{ID: "debian"},
+		pkgFormat:  "deb",
+		script:     script,
+		repository: "default",
+		signing:    key,
+	}
+
+	// BuildInput передаётся процессу сборки через RPC, переменных
+	// окружения alr в этом процессе нет
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(sent))
+	input := &BuildInput{}
+	require.NoError(t, gob.NewDecoder(&buf).Decode(input))
+	require.NoError(t, os.Unsetenv("ALR_TEST_PASSPHRASE"))
+	assert.Equal(t, key, input.signing)
+
+	sf, err := alrsh.ReadFromLocal(script)
+	require.NoError(t, err)
+	formats, err := parseFormatPackages(t.Context(), input, sf)
+	require.NoError(t, err)
+
+	dirs := types.Directories{
+		BaseDir:   dir,
+		PkgDir:    filepath.Join(dir, "pkg"),
+		ScriptDir: dir,
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dirs.PkgDir, "usr", "share", "foo"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dirs.PkgDir, "usr", "share", "foo", "data"), []byte("data"), 0o644))
+
+	e := NewLocalScriptExecutor(testConfig{})
+	paths, err := e.packageExtraFormats(t.Context(), formats, formats[0].vars["foo"], dirs, types.Directories{}, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	assert.FileExists(t, paths[0])
+}
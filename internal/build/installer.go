@@ -144,14 +144,7 @@ func (i *Installer) FilterPackagesByVersion(ctx context.Context, packages []alrs
 			continue
 		}
 
-		repoVer := pkg.Version
-		releaseStr := overrides.ReleasePlatformSpecific(pkg.Release, osRelease)
-
-		if pkg.Release != 0 && pkg.Epoch == 0 {
-			repoVer = fmt.Sprintf("%s-%s", pkg.Version, releaseStr)
-		} else if pkg.Release != 0 && pkg.Epoch != 0 {
-			repoVer = fmt.Sprintf("%d:%s-%s", pkg.Epoch, pkg.Version, releaseStr)
-		}
+		repoVer := overrides.VersionPlatformSpecific(pkg.Version, pkg.Release, pkg.Epoch, osRelease)
 
 		cmp := vercmp.Compare(repoVer, installedVer)
 
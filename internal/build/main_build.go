@@ -26,6 +26,7 @@ func NewMainBuilder(
 	repos PackageFinder,
 	scriptExecutor ScriptExecutor,
 	installerExecutor InstallerExecutor,
+	reasons InstallReasonRecorder,
 ) (*Builder, error) {
 	builder := &Builder{
 		scriptExecutor: scriptExecutor,
@@ -45,9 +46,10 @@ func NewMainBuilder(
 		sourceExecutor: &SourceDownloader{
 			cfg,
 		},
-		repos: repos,
-		mgr:   mgr,
-		cfg:   cfg,
+		repos:   repos,
+		mgr:     mgr,
+		cfg:     cfg,
+		reasons: reasons,
 	}
 
 	return builder, nil
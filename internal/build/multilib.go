@@ -0,0 +1,215 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"cmp"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/leonelquinteros/gotext"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cpu"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/depver"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+// multilibArch - архитектура пакетов, собираемых в режиме multilib
+const multilibArch = "386"
+
+// multilibFormats - форматы, для которых известно соглашение
+// об именовании 32-битных пакетов на x86_64
+var multilibFormats = []string{"deb", "rpm", "archlinux", "xbps"}
+
+// isMultilib сообщает, собирается ли 32-битный вариант пакета
+func isMultilib(opts *types.BuildOpts) bool {
+	return opts != nil && opts.Multilib
+}
+
+// checkMultilib проверяет, что 32-битный вариант пакета можно собрать:
+// multilib-сборка возможна только на x86_64 и только в форматах,
+// в которых принято соглашение об именовании 32-битных пакетов
+func checkMultilib(opts *types.BuildOpts, pkgFormat string) error {
+	if !isMultilib(opts) {
+		return nil
+	}
+
+	if opts.TargetArch != "" && opts.TargetArch != multilibArch {
+		return errors.New(gotext.Get("Multilib builds cannot target the %s architecture", opts.TargetArch))
+	}
+
+	if cpu.Arch() != "amd64" {
+		return errors.New(gotext.Get("Multilib builds are only supported on x86_64 systems"))
+	}
+
+	for _, format := range append([]string{pkgFormat}, opts.Formats...) {
+		if !slices.Contains(multilibFormats, format) {
+			return errors.New(gotext.Get("Multilib builds are not supported for %s packages", format))
+		}
+	}
+	return nil
+}
+
+// multilibEnv возвращает переменные окружения скрипта сборки для
+// 32-битной сборки системным компилятором: флаг -m32 и pkg-config,
+// который ищет 32-битные библиотеки
+func multilibEnv(info *distro.OSRelease) []string {
+	return []string{
+		"MULTILIB=1",
+		"CC=" + cmp.Or(os.Getenv("CC"), "gcc") + " -m32",
+		"CXX=" + cmp.Or(os.Getenv("CXX"), "g++") + " -m32",
+		"CFLAGS=" + strings.TrimSpace("-m32 "+os.Getenv("CFLAGS")),
+		"CXXFLAGS=" + strings.TrimSpace("-m32 "+os.Getenv("CXXFLAGS")),
+		"LDFLAGS=" + strings.TrimSpace("-m32 "+os.Getenv("LDFLAGS")),
+		"PKG_CONFIG_LIBDIR=" + info.MultilibLibDir() + "/pkgconfig:/usr/share/pkgconfig",
+	}
+}
+
+// multilibName возвращает имя 32-битного варианта пакета name:
+// lib32-name в Arch Linux и name-32bit в Void Linux. В deb и rpm
+// 32-битный пакет называется так же и отличается только архитектурой.
+// Суффикс репозитория ALR (+repo) сохраняется.
+func multilibName(pkgFormat, name string) string {
+	base, repo, hasRepo := strings.Cut(name, "+")
+
+	switch pkgFormat {
+	case "archlinux":
+		if !strings.HasPrefix(base, "lib32-") {
+			base = "lib32-" + base
+		}
+	case "xbps":
+		if !strings.HasSuffix(base, "-32bit") {
+			base += "-32bit"
+		}
+	}
+
+	if hasRepo {
+		return base + "+" + repo
+	}
+	return base
+}
+
+// multilibInstallName возвращает имя, под которым менеджер пакетов
+// устанавливает 32-битный вариант пакета name: name:i386 в apt,
+// name.i686 в dnf/zypper, lib32-name в pacman и name-32bit в xbps
+func multilibInstallName(pkgFormat, name string) string {
+	switch pkgFormat {
+	case "deb":
+		return name + ":i386"
+	case "rpm":
+		return name + ".i686"
+	default:
+		return multilibName(pkgFormat, name)
+	}
+}
+
+// multilibDep переводит зависимость dep на 32-битный вариант пакета.
+// В rpm используется ISA-квалификатор name(x86-32), который
+// предоставляют 32-битные пакеты. Ограничение версии сохраняется.
+func multilibDep(pkgFormat, dep string) string {
+	dep = strings.TrimSpace(dep)
+	name := depver.Parse(dep).Name
+	// Зависимости от файлов, библиотек и уже квалифицированные зависимости не меняются
+	if name == "" || strings.ContainsAny(name, "/()") || strings.Contains(name, ".so") {
+		return dep
+	}
+
+	var out string
+	switch pkgFormat {
+	case "rpm":
+		if isa := goArchToRPMISA(multilibArch); isa != "" {
+			out = name + "(" + isa + ")"
+		}
+	case "archlinux", "xbps":
+		out = multilibName(pkgFormat, name)
+	}
+	if out == "" {
+		return dep
+	}
+	return out + dep[len(name):]
+}
+
+func multilibDeps(pkgFormat string, deps []string) []string {
+	out := make([]string, len(deps))
+	for i, dep := range deps {
+		out[i] = multilibDep(pkgFormat, dep)
+	}
+	return out
+}
+
+// applyMultilib приводит метаданные 32-битного пакета к соглашениям
+// формата pkgFormat. selfConflict - автоматический конфликт пакета
+// с одноимёнными пакетами других репозиториев.
+func applyMultilib(info *nfpm.Info, pkgFormat, selfConflict string) {
+	o := &info.Overridables
+
+	switch pkgFormat {
+	case "deb":
+		// Пакет i386 ставится рядом с пакетом amd64 только при Multi-Arch: same,
+		// а его зависимости dpkg и так разрешает пакетами i386. Конфликт по имени
+		// без архитектуры мешал бы установке рядом с 64-битным вариантом.
+		if info.Deb.Fields == nil {
+			info.Deb.Fields = map[string]string{}
+		}
+		info.Deb.Fields["Multi-Arch"] = "same"
+		o.Conflicts = slices.DeleteFunc(o.Conflicts, func(s string) bool {
+			return s == selfConflict
+		})
+	case "rpm":
+		// 32-битные пакеты Fedora и openSUSE имеют архитектуру i686
+		info.RPM.Arch = "i686"
+		o.Provides = append(o.Provides, info.Name)
+		o.Depends = multilibDeps(pkgFormat, o.Depends)
+		o.Conflicts = multilibDeps(pkgFormat, o.Conflicts)
+		o.Provides = multilibDeps(pkgFormat, o.Provides)
+	case "archlinux", "xbps":
+		info.Arch = multilibPackageArch(pkgFormat)
+		o.Depends = multilibDeps(pkgFormat, o.Depends)
+		o.Conflicts = multilibDeps(pkgFormat, o.Conflicts)
+		o.Replaces = multilibDeps(pkgFormat, o.Replaces)
+		o.Provides = multilibDeps(pkgFormat, o.Provides)
+	}
+}
+
+// multilibPackageArch возвращает архитектуру 32-битного пакета в формате
+// pkgFormat. Пакеты lib32-* в Arch Linux и *-32bit в Void Linux
+// устанавливаются в систему x86_64 и имеют архитектуру x86_64.
+func multilibPackageArch(pkgFormat string) string {
+	switch pkgFormat {
+	case "archlinux", "xbps":
+		return "amd64"
+	default:
+		return multilibArch
+	}
+}
+
+// multilibBuildDepends возвращает имена 32-битных зависимостей для сборки
+// из multilib_build_deps в том виде, в котором их устанавливает менеджер пакетов
+func multilibBuildDepends(pkgFormat string, varsOfPackages []*alrsh.Package) []string {
+	var out []string
+	for _, vars := range varsOfPackages {
+		for _, dep := range vars.MultilibBuildDepends.Resolved() {
+			out = append(out, multilibInstallName(pkgFormat, dep))
+		}
+	}
+	return removeDuplicates(out)
+}
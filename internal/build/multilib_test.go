@@ -0,0 +1,143 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"testing"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/stretchr/testify/assert"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cpu"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+func TestMultilibNames(t *testing.T) {
+	for _, tc := range []struct {
+		format  string
+		name    string
+		install string
+		dep     string
+	}{
+		{"archlinux", "lib32-foo+alr-default", "lib32-foo", "lib32-foo>=1.0"},
+		{"xbps", "foo-32bit+alr-default", "foo-32bit", "foo-32bit>=1.0"},
+		{"deb", "foo+alr-default", "foo:i386", "foo>=1.0"},
+		{"rpm", "foo+alr-default", "foo.i686", "foo(x86-32)>=1.0"},
+	} {
+		assert.Equal(t, tc.name, multilibName(tc.format, "foo+alr-default"), tc.format)
+		assert.Equal(t, tc.name, multilibName(tc.format, tc.name), tc.format)
+		assert.Equal(t, tc.install, multilibInstallName(tc.format, "foo"), tc.format)
+		assert.Equal(t, tc.dep, multilibDep(tc.format, "foo>=1.0"), tc.format)
+	}
+
+	assert.Equal(t, "libfoo.so.1", multilibDep("archlinux", "libfoo.so.1"))
+	assert.Equal(t, "/usr/bin/sh", multilibDep("rpm", "/usr/bin/sh"))
+	assert.Equal(t, "foo(x86-32)", multilibDep("rpm", "foo(x86-32)"))
+}
+
+func TestApplyMultilib(t *testing.T) {
+	newInfo := func() *nfpm.Info {
+		return &nfpm.Info{
+			Name: "foo+alr-default",
+			Overridables: nfpm.Overridables{
+				Depends:   []string{"glibc", "bar+alr-default"},
+				Conflicts: []string{"foo"},
+				Provides:  []string{"foo"},
+			},
+		}
+	}
+
+	info := newInfo()
+	applyMultilib(info, "archlinux", "foo")
+	assert.Equal(t, []string{"lib32-glibc", "lib32-bar+alr-default"}, info.Depends)
+	assert.Equal(t, []string{"lib32-foo"}, info.Conflicts)
+	assert.Equal(t, []string{"lib32-foo"}, info.Provides)
+
+	info = newInfo()
+	applyMultilib(info, "rpm", "foo")
+	assert.Equal(t, "i686", info.RPM.Arch)
+	assert.Equal(t, []string{"glibc(x86-32)", "bar+alr-default(x86-32)"}, info.Depends)
+	assert.Equal(t, []string{"foo(x86-32)", "foo+alr-default(x86-32)"}, info.Provides)
+
+	info = newInfo()
+	applyMultilib(info, "deb", "foo")
+	assert.Equal(t, "same", info.Deb.Fields["Multi-Arch"])
+	assert.Equal(t, []string{"glibc", "bar+alr-default"}, info.Depends)
+	assert.Empty(t, info.Conflicts)
+}
+
+func TestApplyMultilibArch(t *testing.T) {
+	for format, arch := range map[string]string{
+		"deb":       "386",
+		"rpm":       "386",
+		"archlinux": "amd64",
+		"xbps":      "amd64",
+	} {
+		info := &nfpm.Info{Name: "foo+alr-default", Arch: multilibArch}
+		applyMultilib(info, format, "foo")
+		assert.Equal(t, arch, info.Arch, format)
+		assert.Equal(t, arch, multilibPackageArch(format), format)
+	}
+}
+
+func TestCheckMultilib(t *testing.T) {
+	assert.NoError(t, checkMultilib(&types.BuildOpts{}, "apk"))
+
+	opts := &types.BuildOpts{Multilib: true}
+	if cpu.Arch() != "amd64" {
+		assert.Error(t, checkMultilib(opts, "deb"))
+		return
+	}
+
+	assert.NoError(t, checkMultilib(opts, "deb"))
+	assert.Error(t, checkMultilib(opts, "apk"))
+	assert.Error(t, checkMultilib(&types.BuildOpts{Multilib: true, Formats: []string{"rpm", "apk"}}, "deb"))
+	assert.Error(t, checkMultilib(&types.BuildOpts{Multilib: true, TargetArch: "arm64"}, "deb"))
+}
+
+func TestCreateBuildEnvVarsMultilib(t *testing.T) {
+	t.Setenv("CFLAGS", "-O2")
+
+	info := &distro.OSRelease{ID: "manjaro", Like: []string{"arch"}}
+	opts := &types.BuildOpts{Multilib: true}
+
+	env := createBuildEnvVars(info, opts, types.Directories{})
+	assert.Contains(t, env, "ARCH=386")
+	assert.Contains(t, env, "MULTILIB=1")
+	assert.Contains(t, env, "CFLAGS=-m32 -O2")
+	assert.Contains(t, env, "PKG_CONFIG_LIBDIR=/usr/lib32/pkgconfig:/usr/share/pkgconfig")
+	// 32-битный код собирается системным компилятором, а не кросс-компилятором
+	assert.Contains(t, env, "CROSS_COMPILE=")
+	assert.Empty(t, crossToolPrefix(info, opts))
+
+	native := &alrsh.Package{Name: "native"}
+	native.Options.SetResolved([]string{"!cross"})
+	assert.NoError(t, checkCrossBuild(opts, []*alrsh.Package{native}))
+	assert.False(t, isMultilib(newHostBuildInput(&BuildInput{opts: opts}).BuildOpts()))
+}
+
+func TestMultilibBuildDepends(t *testing.T) {
+	a := &alrsh.Package{Name: "a"}
+	a.MultilibBuildDepends.SetResolved([]string{"libfoo-dev", "libbar-dev"})
+	b := &alrsh.Package{Name: "b"}
+	b.MultilibBuildDepends.SetResolved([]string{"libfoo-dev"})
+
+	assert.Equal(t, []string{"libfoo-dev:i386", "libbar-dev:i386"}, multilibBuildDepends("deb", []*alrsh.Package{a, b}))
+	assert.Equal(t, []string{"lib32-libfoo-dev", "lib32-libbar-dev"}, multilibBuildDepends("archlinux", []*alrsh.Package{a, b}))
+}
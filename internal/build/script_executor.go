@@ -36,10 +36,10 @@ import (
 	"mvdan.cc/sh/v3/syntax"
 
 	finddeps "git.alr-pkg.ru/Plemya-x/ALR/internal/build/find_deps"
-	"git.alr-pkg.ru/Plemya-x/ALR/internal/cpu"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/shutils/decoder"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/shutils/handlers"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/shutils/helpers"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/signing"
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
 )
@@ -59,7 +59,7 @@ func (e *LocalScriptExecutor) ReadScript(ctx context.Context, scriptPath string)
 }
 
 func (e *LocalScriptExecutor) ExecuteFirstPass(ctx context.Context, input *BuildInput, sf *alrsh.ScriptFile) (string, []*alrsh.Package, error) {
-	return sf.ParseBuildVars(ctx, input.info, input.packages)
+	return sf.ParseBuildVarsForArch(ctx, input.info, targetArch(input.opts), input.packages)
 }
 
 func (e *LocalScriptExecutor) PrepareDirs(
@@ -97,9 +97,19 @@ func (e *LocalScriptExecutor) ExecuteSecondPass(
 	if err != nil {
 		return nil, err
 	}
-	env := createBuildEnvVars(input.info, dirs)
+	env := createBuildEnvVars(input.info, input.opts, dirs)
 
 	fakeroot := handlers.FakerootExecHandler(2 * time.Second)
+	if needsEmulation(input.opts, varsOfPackages) {
+		emu, err := newEmulation(input.opts, dirs)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info(gotext.Get("Building in emulated root filesystem"), "root", input.opts.EmulateRoot, "arch", targetArch(input.opts))
+		env = append(env, emulationEnv()...)
+		fakeroot = emu.ExecHandler(fakeroot)
+	}
+
 	runner, err := interp.New(
 		interp.Env(expand.ListEnviron(env...)),       // Устанавливаем окружение
 		interp.StdIO(os.Stdin, os.Stderr, os.Stderr), // Устанавливаем стандартный ввод-вывод
@@ -117,6 +127,7 @@ func (e *LocalScriptExecutor) ExecuteSecondPass(
 	}
 
 	dec := decoder.New(input.info, runner)
+	dec.Arch = targetArch(input.opts)
 
 	// var builtPaths []string
 
@@ -125,6 +136,13 @@ func (e *LocalScriptExecutor) ExecuteSecondPass(
 		return nil, err
 	}
 
+	// Переменные для дополнительных форматов разбираются заранее,
+	// сборка при этом выполняется только один раз
+	formats, err := parseFormatPackages(ctx, input, sf)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, vars := range varsOfPackages {
 		packageName := ""
 		if vars.BasePkgName != "" {
@@ -165,6 +183,25 @@ func (e *LocalScriptExecutor) ExecuteSecondPass(
 			return nil, err
 		}
 
+		// Отладочная информация выносится до сборки метаданных,
+		// чтобы поиск зависимостей видел итоговые файлы
+		debugDirs := pkgDirs
+		debugDirs.PkgDir = pkgDirs.PkgDir + "-debug"
+		debugFiles := 0
+		if stripEnabled(vars) {
+			if err := os.RemoveAll(debugDirs.PkgDir); err != nil {
+				return nil, err
+			}
+			target := ""
+			if debugEnabled(vars) {
+				target = debugDirs.PkgDir
+			}
+			debugFiles, err = splitDebugInfo(ctx, pkgDirs.PkgDir, target, crossToolPrefix(input.info, input.opts))
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		slog.Info(gotext.Get("Building package metadata"), "name", basePkg)
 
 		pkgInfo, err := buildPkgMetadata(
@@ -182,48 +219,91 @@ func (e *LocalScriptExecutor) ExecuteSecondPass(
 			return nil, err
 		}
 
-		packager, err := nfpm.Get(pkgFormat) // Получаем упаковщик для формата пакета
-		if err != nil {
-			return nil, err
-		}
-
-		pkgName := packager.ConventionalFileName(pkgInfo)  // Получаем имя файла пакета
-		pkgPath := filepath.Join(pkgDirs.BaseDir, pkgName) // Определяем путь к пакету
-
-		slog.Info(gotext.Get("Creating package file"), "path", pkgPath, "name", pkgName)
+		// Подпись настраивается до упаковки, nfpm подписывает пакет при записи
+		signing.Apply(pkgInfo, pkgFormat, input.signing)
 
-		pkgFile, err := os.Create(pkgPath)
+		pkgPath, err := createPackageFile(pkgInfo, pkgFormat, pkgDirs.BaseDir)
 		if err != nil {
-			slog.Error(gotext.Get("Failed to create package file"), "path", pkgPath, "error", err)
 			return nil, err
 		}
-		defer pkgFile.Close()
 
-		slog.Info(gotext.Get("Packaging with nfpm"), "format", pkgFormat)
-		err = packager.Package(pkgInfo, pkgFile)
-		if err != nil {
-			slog.Error(gotext.Get("Failed to create package"), "path", pkgPath, "error", err)
-			return nil, err
+		debugPath := ""
+		if debugFiles > 0 {
+			slog.Info(gotext.Get("Building debug package"), "name", vars.Name+"-debug")
+
+			debugInfo, err := buildPkgMetadata(
+				ctx,
+				input,
+				debugPackageVars(vars),
+				debugDirs,
+				debugDepends(pkgInfo, pkgFormat),
+				nil,
+			)
+			if err != nil {
+				return nil, err
+			}
+			signing.Apply(debugInfo, pkgFormat, input.signing)
+			debugPath, err = createPackageFile(debugInfo, pkgFormat, pkgDirs.BaseDir)
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		slog.Info(gotext.Get("Package created successfully"), "path", pkgPath)
-
-		// Проверяем, что файл действительно существует
-		if _, err := os.Stat(pkgPath); err != nil {
-			slog.Error(gotext.Get("Package file not found after creation"), "path", pkgPath, "error", err)
+		extraPaths, err := e.packageExtraFormats(ctx, formats, vars, pkgDirs, debugDirs, debugFiles, funcOut.Contents)
+		if err != nil {
 			return nil, err
 		}
-		slog.Info(gotext.Get("Package file verified to exist"), "path", pkgPath)
 
 		builtDeps = append(builtDeps, &BuiltDep{
-			Name: vars.Name,
-			Path: pkgPath,
+			Name:       vars.Name,
+			Path:       pkgPath,
+			DebugPath:  debugPath,
+			ExtraPaths: extraPaths,
 		})
 	}
 
 	return builtDeps, nil
 }
 
+// createPackageFile упаковывает pkgInfo через nfpm в каталог baseDir
+// и возвращает путь к файлу пакета
+func createPackageFile(pkgInfo *nfpm.Info, pkgFormat, baseDir string) (string, error) {
+	packager, err := nfpm.Get(pkgFormat) // Получаем упаковщик для формата пакета
+	if err != nil {
+		return "", err
+	}
+
+	pkgName := packager.ConventionalFileName(pkgInfo) // Получаем имя файла пакета
+	pkgPath := filepath.Join(baseDir, pkgName)        // Определяем путь к пакету
+
+	slog.Info(gotext.Get("Creating package file"), "path", pkgPath, "name", pkgName)
+
+	pkgFile, err := os.Create(pkgPath)
+	if err != nil {
+		slog.Error(gotext.Get("Failed to create package file"), "path", pkgPath, "error", err)
+		return "", err
+	}
+	defer pkgFile.Close()
+
+	slog.Info(gotext.Get("Packaging with nfpm"), "format", pkgFormat)
+	err = packager.Package(pkgInfo, pkgFile)
+	if err != nil {
+		slog.Error(gotext.Get("Failed to create package"), "path", pkgPath, "error", err)
+		return "", err
+	}
+
+	slog.Info(gotext.Get("Package created successfully"), "path", pkgPath)
+
+	// Проверяем, что файл действительно существует
+	if _, err := os.Stat(pkgPath); err != nil {
+		slog.Error(gotext.Get("Package file not found after creation"), "path", pkgPath, "error", err)
+		return "", err
+	}
+	slog.Info(gotext.Get("Package file verified to exist"), "path", pkgPath)
+
+	return pkgPath, nil
+}
+
 func buildPkgMetadata(
 	ctx context.Context,
 	input interface {
@@ -252,7 +332,7 @@ func buildPkgMetadata(
 	// libdrm+alr.x86_64 не должна конфликтовать с libdrm.i686.
 	autoConflictName := vars.Name
 	if pkgFormat == "rpm" {
-		if isa := goArchToRPMISA(cpu.Arch()); isa != "" {
+		if isa := goArchToRPMISA(targetArch(input.BuildOpts())); isa != "" {
 			autoConflictName = fmt.Sprintf("%s(%s)", vars.Name, isa)
 		}
 	}
@@ -272,6 +352,10 @@ func buildPkgMetadata(
 		})
 	}
 
+	if isMultilib(input.BuildOpts()) {
+		applyMultilib(pkgInfo, pkgFormat, autoConflictName)
+	}
+
 	if pkgFormat == "rpm" {
 		pkgInfo.RPM.Group = vars.Group.Resolved()
 
@@ -289,6 +373,11 @@ func buildPkgMetadata(
 
 	setScripts(vars, pkgInfo, dirs.ScriptDir)
 
+	err := setServiceScripts(vars, pkgInfo, pkgFormat, dirs.PkgDir, filepath.Join(dirs.BaseDir, "scripts"))
+	if err != nil {
+		return nil, err
+	}
+
 	if slices.Contains(vars.Architectures, "all") {
 		pkgInfo.Arch = "all"
 	}
@@ -0,0 +1,520 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/shlex"
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/leonelquinteros/gotext"
+	"mvdan.cc/sh/v3/syntax"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+)
+
+const (
+	sysusersDir = "/usr/lib/sysusers.d"
+	tmpfilesDir = "/usr/lib/tmpfiles.d"
+)
+
+// systemdUnitDirs - каталоги, в которых ищутся юниты из systemd_units
+var systemdUnitDirs = []string{
+	"/usr/lib/systemd/system",
+	"/lib/systemd/system",
+	"/etc/systemd/system",
+}
+
+// serviceActions содержит команды сценариев сопровождающего,
+// созданные из systemd_units, sysusers и tmpfiles.
+type serviceActions struct {
+	setup   []string // пользователи, временные файлы и daemon-reload при установке и обновлении
+	install []string // первая установка: включение юнитов
+	upgrade []string // обновление: перезапуск работающих юнитов
+	remove  []string // перед удалением: остановка и отключение юнитов
+	cleanup []string // после удаления: daemon-reload
+}
+
+// serviceScripts - тексты сгенерированных сценариев для одного формата
+type serviceScripts struct {
+	PostInstall string
+	PostUpgrade string
+	PreRemove   string
+	PostRemove  string
+}
+
+// setServiceScripts дополняет сценарии сопровождающего командами для
+// systemd_units, sysusers и tmpfiles. Сгенерированные команды выполняются
+// перед пользовательскими сценариями из scripts.
+func setServiceScripts(vars *alrsh.Package, info *nfpm.Info, pkgFormat, pkgDir, outDir string) error {
+	actions, err := newServiceActions(vars, pkgFormat, pkgDir)
+	if err != nil || actions == nil {
+		return err
+	}
+
+	scripts := actions.scripts(pkgFormat)
+
+	outDir = filepath.Join(outDir, vars.Name+"_"+pkgFormat)
+	if err := os.RemoveAll(outDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outDir, defaultDirMode); err != nil {
+		return err
+	}
+
+	for _, s := range []struct {
+		name      string
+		generated string
+		target    *string
+	}{
+		{"postinstall", scripts.PostInstall, &info.Scripts.PostInstall},
+		{"preremove", scripts.PreRemove, &info.Scripts.PreRemove},
+		{"postremove", scripts.PostRemove, &info.Scripts.PostRemove},
+		{"postupgrade-apk", apkOnly(pkgFormat, scripts.PostUpgrade), &info.APK.Scripts.PostUpgrade},
+		{"postupgrade-archlinux", archOnly(pkgFormat, scripts.PostUpgrade), &info.ArchLinux.Scripts.PostUpgrade},
+	} {
+		merged, err := mergeScript(outDir, s.name, s.generated, *s.target)
+		if err != nil {
+			return err
+		}
+		*s.target = merged
+	}
+
+	return nil
+}
+
+func apkOnly(pkgFormat, script string) string {
+	if pkgFormat != "apk" {
+		return ""
+	}
+	return script
+}
+
+func archOnly(pkgFormat, script string) string {
+	if pkgFormat != "archlinux" {
+		return ""
+	}
+	return script
+}
+
+// newServiceActions собирает команды для пакета vars. Если ни одна из
+// переменных systemd_units, sysusers и tmpfiles не задана, возвращает nil.
+func newServiceActions(vars *alrsh.Package, pkgFormat, pkgDir string) (*serviceActions, error) {
+	units := vars.SystemdUnits.Resolved()
+	sysusers := vars.SysUsers.Resolved()
+	tmpfiles := vars.TmpFiles.Resolved()
+
+	if len(units) == 0 && len(sysusers) == 0 && len(tmpfiles) == 0 {
+		return nil, nil
+	}
+
+	a := &serviceActions{}
+
+	for _, name := range sysusers {
+		conf := confPath(sysusersDir, name)
+		data, err := os.ReadFile(filepath.Join(pkgDir, conf))
+		if err != nil {
+			return nil, fmt.Errorf("sysusers: %w", err)
+		}
+
+		fallback, err := sysusersFallback(string(data), pkgFormat == "apk")
+		if err != nil {
+			return nil, fmt.Errorf("sysusers: %s: %w", conf, err)
+		}
+
+		a.setup = append(a.setup, ifElse(
+			"command -v systemd-sysusers >/dev/null 2>&1",
+			[]string{"systemd-sysusers " + quote(conf)},
+			fallback,
+		)...)
+	}
+
+	for _, name := range tmpfiles {
+		conf := confPath(tmpfilesDir, name)
+		data, err := os.ReadFile(filepath.Join(pkgDir, conf))
+		if err != nil {
+			return nil, fmt.Errorf("tmpfiles: %w", err)
+		}
+
+		fallback, err := tmpfilesFallback(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("tmpfiles: %s: %w", conf, err)
+		}
+
+		a.setup = append(a.setup, ifElse(
+			"command -v systemd-tmpfiles >/dev/null 2>&1",
+			[]string{"systemd-tmpfiles --create " + quote(conf) + " || :"},
+			fallback,
+		)...)
+	}
+
+	if len(units) == 0 {
+		return a, nil
+	}
+
+	for _, unit := range units {
+		if !unitExists(pkgDir, unit) {
+			slog.Warn(gotext.Get("Systemd unit is not in the package"), "unit", unit)
+		}
+	}
+
+	quoted := make([]string, len(units))
+	for i, unit := range units {
+		quoted[i] = quote(unit)
+	}
+	list := strings.Join(quoted, " ")
+
+	daemonReload := ifElse("[ -d /run/systemd/system ]", []string{"systemctl daemon-reload || :"}, nil)
+
+	a.setup = append(a.setup, daemonReload...)
+	a.install = ifElse(
+		"command -v systemctl >/dev/null 2>&1",
+		[]string{"systemctl enable " + list + " || :"},
+		nil,
+	)
+	a.upgrade = ifElse(
+		"[ -d /run/systemd/system ]",
+		[]string{"systemctl try-restart " + list + " || :"},
+		nil,
+	)
+	a.remove = ifElse(
+		"command -v systemctl >/dev/null 2>&1",
+		[]string{"systemctl disable --now " + list + " || :"},
+		nil,
+	)
+	a.cleanup = daemonReload
+
+	return a, nil
+}
+
+// scripts раскладывает команды по сценариям с учётом того,
+// как менеджер пакета pkgFormat вызывает сценарии сопровождающего.
+func (a *serviceActions) scripts(pkgFormat string) serviceScripts {
+	var s serviceScripts
+
+	switch pkgFormat {
+	case "deb":
+		// postinst configure <предыдущая версия>; при первой установке версия пустая
+		s.PostInstall = joinLines(ifElse(`[ "$1" = "configure" ]`,
+			append(slices.Clone(a.setup), ifElse(`[ -z "$2" ]`, a.install, a.upgrade)...),
+			nil,
+		))
+		s.PreRemove = joinLines(ifElse(`[ "$1" = "remove" ]`, a.remove, nil))
+		s.PostRemove = joinLines(ifElse(`[ "$1" = "remove" ] || [ "$1" = "purge" ]`, a.cleanup, nil))
+	case "rpm":
+		// %post, %preun и %postun получают число экземпляров пакета после операции
+		s.PostInstall = joinLines(append(slices.Clone(a.setup), ifElse(`[ "$1" -eq 1 ]`, a.install, a.upgrade)...))
+		s.PreRemove = joinLines(ifElse(`[ "$1" -eq 0 ]`, a.remove, nil))
+		s.PostRemove = joinLines(ifElse(`[ "$1" -eq 0 ]`, a.cleanup, nil))
+	case "apk", "archlinux":
+		// отдельные сценарии для установки и обновления
+		s.PostInstall = joinLines(append(slices.Clone(a.setup), a.install...))
+		s.PostUpgrade = joinLines(append(slices.Clone(a.setup), a.upgrade...))
+		s.PreRemove = joinLines(a.remove)
+		s.PostRemove = joinLines(a.cleanup)
+	default:
+		s.PostInstall = joinLines(append(slices.Clone(a.setup), a.install...))
+		s.PreRemove = joinLines(a.remove)
+		s.PostRemove = joinLines(a.cleanup)
+	}
+
+	return s
+}
+
+// mergeScript записывает в dir сценарий name, состоящий из сгенерированных
+// команд generated и содержимого пользовательского сценария userPath,
+// и возвращает путь к нему. Если generated пуст, возвращает userPath.
+func mergeScript(dir, name, generated, userPath string) (string, error) {
+	if generated == "" {
+		return userPath, nil
+	}
+
+	shebang := "#!/bin/sh"
+	var user string
+	if userPath != "" {
+		data, err := os.ReadFile(userPath)
+		if err != nil {
+			return "", err
+		}
+		user = string(data)
+		if strings.HasPrefix(user, "#!") {
+			shebang, user, _ = strings.Cut(user, "\n")
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(shebang)
+	sb.WriteString("\n\n")
+	sb.WriteString(generated)
+	if strings.TrimSpace(user) != "" {
+		sb.WriteString("\n")
+		sb.WriteString(user)
+	}
+
+	out := filepath.Join(dir, name)
+	if err := os.WriteFile(out, []byte(sb.String()), defaultScriptMode); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// sysusersFallback преобразует конфигурацию sysusers.d в команды
+// groupadd/useradd (или addgroup/adduser из busybox) для систем без systemd-sysusers.
+// Поддерживаются строки u, g и m.
+func sysusersFallback(conf string, busybox bool) ([]string, error) {
+	nologin := "/usr/sbin/nologin"
+	if busybox {
+		nologin = "/sbin/nologin"
+	}
+
+	var out []string
+	err := eachConfLine(conf, func(fields []string) error {
+		if len(fields) < 2 {
+			return fmt.Errorf("invalid line: %q", strings.Join(fields, " "))
+		}
+		typ, name := fields[0], fields[1]
+		id := field(fields, 2)
+
+		switch typ {
+		case "g":
+			out = append(out, addGroup(name, id, busybox))
+		case "u":
+			uid, group := id, name
+			gid := id
+			if u, g, ok := strings.Cut(id, ":"); ok {
+				uid, gid = u, g
+				if !isNumeric(g) {
+					group, gid = g, ""
+				}
+			}
+			if strings.HasPrefix(uid, "/") {
+				uid, gid = "", ""
+			}
+			if group == name {
+				out = append(out, addGroup(name, gid, busybox))
+			}
+
+			home := cmp.Or(field(fields, 4), "/")
+			shell := cmp.Or(field(fields, 5), nologin)
+			gecos := field(fields, 3)
+
+			var cmd []string
+			if busybox {
+				cmd = []string{"adduser", "-S", "-D", "-H", "-h", quote(home), "-s", quote(shell), "-G", quote(group)}
+				if gecos != "" {
+					cmd = append(cmd, "-g", quote(gecos))
+				}
+			} else {
+				cmd = []string{"useradd", "-r", "-M", "-d", quote(home), "-s", quote(shell), "-g", quote(group)}
+				if gecos != "" {
+					cmd = append(cmd, "-c", quote(gecos))
+				}
+			}
+			if isNumeric(uid) {
+				cmd = append(cmd, "-u", uid)
+			}
+			cmd = append(cmd, quote(name))
+
+			out = append(out, "grep -q "+quote("^"+name+":")+" /etc/passwd || "+strings.Join(cmd, " "))
+		case "m":
+			group := field(fields, 2)
+			if group == "" {
+				return fmt.Errorf("missing group for %s", name)
+			}
+			if busybox {
+				out = append(out, "addgroup "+quote(name)+" "+quote(group)+" || :")
+			} else {
+				out = append(out, "usermod -a -G "+quote(group)+" "+quote(name)+" || :")
+			}
+		case "r":
+			// диапазоны идентификаторов не имеют смысла без systemd-sysusers
+		default:
+			return fmt.Errorf("unsupported line type %q", typ)
+		}
+
+		return nil
+	})
+
+	return out, err
+}
+
+func addGroup(name, gid string, busybox bool) string {
+	cmd := []string{"groupadd", "-r"}
+	if busybox {
+		cmd = []string{"addgroup", "-S"}
+	}
+	if isNumeric(gid) {
+		cmd = append(cmd, "-g", gid)
+	}
+	cmd = append(cmd, quote(name))
+
+	return "grep -q " + quote("^"+name+":") + " /etc/group || " + strings.Join(cmd, " ")
+}
+
+// tmpfilesFallback преобразует строки d и D конфигурации tmpfiles.d
+// в команды mkdir/chmod/chown для систем без systemd-tmpfiles.
+func tmpfilesFallback(conf string) ([]string, error) {
+	var out []string
+	err := eachConfLine(conf, func(fields []string) error {
+		if len(fields) < 2 {
+			return fmt.Errorf("invalid line: %q", strings.Join(fields, " "))
+		}
+
+		switch strings.TrimRight(fields[0], "!+-=~^") {
+		case "d", "D":
+		default:
+			return nil
+		}
+
+		dir := quote(fields[1])
+		out = append(out, "mkdir -p "+dir)
+		if mode := field(fields, 2); mode != "" {
+			out = append(out, "chmod "+quote(strings.TrimLeft(mode, "~:"))+" "+dir)
+		}
+		user, group := field(fields, 3), field(fields, 4)
+		if user != "" || group != "" {
+			owner := user
+			if group != "" {
+				owner += ":" + group
+			}
+			out = append(out, "chown "+quote(owner)+" "+dir+" || :")
+		}
+
+		return nil
+	})
+
+	return out, err
+}
+
+// eachConfLine вызывает fn для каждой значимой строки конфигурации
+// в формате sysusers.d/tmpfiles.d, разбитой на поля с учётом кавычек.
+func eachConfLine(conf string, fn func(fields []string) error) error {
+	sc := bufio.NewScanner(strings.NewReader(conf))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields, err := shlex.Split(line)
+		if err != nil {
+			return err
+		}
+		if err := fn(fields); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// field возвращает i-е поле строки конфигурации; "-" означает пустое значение.
+func field(fields []string, i int) string {
+	if i >= len(fields) || fields[i] == "-" {
+		return ""
+	}
+	return fields[i]
+}
+
+// confPath возвращает путь к файлу конфигурации в пакете:
+// относительные имена ищутся в каталоге dir.
+func confPath(dir, name string) string {
+	if path.IsAbs(name) {
+		return name
+	}
+	return path.Join(dir, name)
+}
+
+func unitExists(pkgDir, unit string) bool {
+	// для экземпляров шаблонов (foo@bar.service) проверяется сам шаблон
+	if prefix, rest, ok := strings.Cut(unit, "@"); ok {
+		if _, suffix, ok := strings.Cut(rest, "."); ok {
+			unit = prefix + "@." + suffix
+		}
+	}
+
+	for _, dir := range systemdUnitDirs {
+		if _, err := os.Lstat(filepath.Join(pkgDir, dir, unit)); err == nil {
+			return true
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return false
+		}
+	}
+	return false
+}
+
+// ifElse возвращает строки условного оператора оболочки.
+// Пустые ветви заменяются на ":", а при пустых обеих ветвях возвращается nil.
+func ifElse(cond string, then, els []string) []string {
+	if len(then) == 0 && len(els) == 0 {
+		return nil
+	}
+	if len(then) == 0 {
+		then = []string{":"}
+	}
+
+	out := []string{"if " + cond + "; then"}
+	out = append(out, indent(then)...)
+	if len(els) > 0 {
+		out = append(out, "else")
+		out = append(out, indent(els)...)
+	}
+	return append(out, "fi")
+}
+
+func indent(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = "\t" + line
+	}
+	return out
+}
+
+func joinLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func quote(s string) string {
+	q, err := syntax.Quote(s, syntax.LangPOSIX)
+	if err != nil {
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+	return q
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+)
+
+func servicePackage(t *testing.T) (*alrsh.Package, string) {
+	t.Helper()
+
+	pkgDir := t.TempDir()
+	for name, content := range map[string]string{
+		"usr/lib/systemd/system/foo.service": "[Service]\nExecStart=/usr/bin/foo\n",
+		"usr/lib/sysusers.d/foo.conf":        "# foo\nu foo - \"Foo daemon\" /var/lib/foo\nm foo adm\n",
+		"usr/lib/tmpfiles.d/foo.conf":        "d /run/foo 0750 foo foo -\nL /run/foo/link - - - - /tmp\n",
+	} {
+		path := filepath.Join(pkgDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	vars := &alrsh.Package{Name: "foo"}
+	vars.SystemdUnits.SetResolved([]string{"foo.service"})
+	vars.SysUsers.SetResolved([]string{"foo.conf"})
+	vars.TmpFiles.SetResolved([]string{"foo.conf"})
+	return vars, pkgDir
+}
+
+func TestSysusersFallback(t *testing.T) {
+	conf := "g bar 500\nu foo 400:bar \"Foo daemon\" /var/lib/foo /bin/sh\nu baz -\nm baz bar\nr - 500-900\n"
+
+	lines, err := sysusersFallback(conf, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"grep -q ^bar: /etc/group || groupadd -r -g 500 bar",
+		"grep -q ^foo: /etc/passwd || useradd -r -M -d /var/lib/foo -s /bin/sh -g bar -c 'Foo daemon' -u 400 foo",
+		"grep -q ^baz: /etc/group || groupadd -r baz",
+		"grep -q ^baz: /etc/passwd || useradd -r -M -d / -s /usr/sbin/nologin -g baz baz",
+		"usermod -a -G bar baz || :",
+	}, lines)
+
+	lines, err = sysusersFallback("u foo 400\n", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"grep -q ^foo: /etc/group || addgroup -S -g 400 foo",
+		"grep -q ^foo: /etc/passwd || adduser -S -D -H -h / -s /sbin/nologin -G foo -u 400 foo",
+	}, lines)
+
+	_, err = sysusersFallback("x foo\n", false)
+	assert.Error(t, err)
+}
+
+func TestTmpfilesFallback(t *testing.T) {
+	lines, err := tmpfilesFallback("d /run/foo 0750 foo foo -\nD! /run/bar - - -\nf /run/foo/file\n")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"mkdir -p /run/foo",
+		"chmod 0750 /run/foo",
+		"chown foo:foo /run/foo || :",
+		"mkdir -p /run/bar",
+	}, lines)
+}
+
+func TestServiceScripts(t *testing.T) {
+	vars, pkgDir := servicePackage(t)
+
+	actions, err := newServiceActions(vars, "deb", pkgDir)
+	require.NoError(t, err)
+
+	deb := actions.scripts("deb")
+	assert.Contains(t, deb.PostInstall, `if [ "$1" = "configure" ]; then`)
+	assert.Contains(t, deb.PostInstall, "systemd-sysusers /usr/lib/sysusers.d/foo.conf")
+	assert.Contains(t, deb.PostInstall, "systemd-tmpfiles --create /usr/lib/tmpfiles.d/foo.conf || :")
+	assert.Contains(t, deb.PostInstall, "systemctl enable foo.service || :")
+	assert.Contains(t, deb.PostInstall, "systemctl try-restart foo.service || :")
+	assert.Contains(t, deb.PreRemove, `if [ "$1" = "remove" ]; then`)
+	assert.Contains(t, deb.PreRemove, "systemctl disable --now foo.service || :")
+	assert.Contains(t, deb.PostRemove, "systemctl daemon-reload || :")
+	assert.Empty(t, deb.PostUpgrade)
+
+	rpm := actions.scripts("rpm")
+	assert.Contains(t, rpm.PostInstall, `if [ "$1" -eq 1 ]; then`)
+	assert.Contains(t, rpm.PreRemove, `if [ "$1" -eq 0 ]; then`)
+
+	apk := actions.scripts("apk")
+	assert.Contains(t, apk.PostInstall, "systemctl enable foo.service || :")
+	assert.NotContains(t, apk.PostInstall, "try-restart")
+	assert.Contains(t, apk.PostUpgrade, "systemctl try-restart foo.service || :")
+	assert.NotContains(t, apk.PostUpgrade, "systemctl enable")
+
+	if _, err := exec.LookPath("sh"); err == nil {
+		for format, s := range map[string]serviceScripts{"deb": deb, "rpm": rpm, "apk": apk} {
+			for _, script := range []string{s.PostInstall, s.PostUpgrade, s.PreRemove, s.PostRemove} {
+				out, err := exec.Command("sh", "-n", "-c", script).CombinedOutput()
+				assert.NoError(t, err, "%s: %s\n%s", format, out, script)
+			}
+		}
+	}
+
+	vars = &alrsh.Package{Name: "bar"}
+	actions, err = newServiceActions(vars, "deb", pkgDir)
+	require.NoError(t, err)
+	assert.Nil(t, actions)
+
+	vars.SysUsers.SetResolved([]string{"missing.conf"})
+	_, err = newServiceActions(vars, "deb", pkgDir)
+	assert.Error(t, err)
+}
+
+func TestSetServiceScripts(t *testing.T) {
+	vars, pkgDir := servicePackage(t)
+	dir := t.TempDir()
+
+	userScript := filepath.Join(dir, "postinstall.sh")
+	require.NoError(t, os.WriteFile(userScript, []byte("#!/bin/bash\necho installed\n"), 0o755))
+
+	info := &nfpm.Info{}
+	info.Scripts.PostInstall = userScript
+	require.NoError(t, setServiceScripts(vars, info, "apk", pkgDir, filepath.Join(dir, "scripts")))
+
+	assert.NotEqual(t, userScript, info.Scripts.PostInstall)
+	data, err := os.ReadFile(info.Scripts.PostInstall)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Regexp(t, `^#!/bin/bash\n`, content)
+	assert.Contains(t, content, "systemctl enable foo.service")
+	assert.Regexp(t, `(?s)systemctl enable.*echo installed\n$`, content)
+
+	assert.NotEmpty(t, info.APK.Scripts.PostUpgrade)
+	assert.Empty(t, info.ArchLinux.Scripts.PostUpgrade)
+	assert.FileExists(t, info.Scripts.PreRemove)
+	assert.FileExists(t, info.Scripts.PostRemove)
+}
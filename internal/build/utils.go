@@ -28,11 +28,12 @@ import (
 	"strconv"
 	"strings"
 
-	// Импортируем пакеты для поддержки различных форматов пакетов (APK, DEB, RPM и ARCH).
+	// Импортируем пакеты для поддержки различных форматов пакетов (APK, DEB, RPM, ARCH, IPK и XBPS).
 
 	_ "github.com/goreleaser/nfpm/v2/apk"
 	_ "github.com/goreleaser/nfpm/v2/arch"
 	_ "github.com/goreleaser/nfpm/v2/deb"
+	_ "github.com/goreleaser/nfpm/v2/ipk"
 	_ "github.com/goreleaser/nfpm/v2/rpm"
 
 	"github.com/goreleaser/nfpm/v2"
@@ -45,6 +46,7 @@ import (
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+	_ "git.alr-pkg.ru/Plemya-x/ALR/pkg/xbps"
 )
 
 // Функция prepareDirs подготавливает директории для сборки.
@@ -73,7 +75,21 @@ func prepareDirs(dirs types.Directories) error {
 	}
 
 	// Создаем директорию для пакетов с setgid битом
-	return utils.EnsureTempDirWithRootOwner(dirs.PkgDir, 0o2775)
+	err = utils.EnsureTempDirWithRootOwner(dirs.PkgDir, 0o2775)
+	if err != nil {
+		return err
+	}
+
+	if dirs.StageDir == "" {
+		return nil
+	}
+
+	// Общий каталог установки для разделения на подпакеты всегда начинается пустым
+	err = os.RemoveAll(dirs.StageDir)
+	if err != nil {
+		slog.Debug("Failed to remove stage directory", "path", dirs.StageDir, "error", err)
+	}
+	return utils.EnsureTempDirWithRootOwner(dirs.StageDir, 0o2775)
 }
 
 // Функция buildContents создает секцию содержимого пакета, которая содержит файлы,
@@ -198,7 +214,12 @@ func escapeGlobChars(path string) string {
 	return buf.String()
 }
 
-var RegexpALRPackageName = regexp.MustCompile(`^(?P<package>[^+]+)\+(?P<repo>.+)$`)
+// RegexpALRPackageName разбирает имя установленного пакета ALR вида "name+repo".
+// apt, opkg и другие менеджеры сообщают имя без версии, а xbps хранит пакет
+// как pkgver "name+repo-version_revision", поэтому такой суффикс допускается
+// и попадает в группу version. Версия xbps начинается с цифры, что отличает
+// её от части имени репозитория.
+var RegexpALRPackageName = regexp.MustCompile(`^(?P<package>[^+]+)\+(?P<repo>.+?)(?:-(?P<version>\d[^-_]*_\d+))?$`)
 
 // goArchToRPMISA конвертирует Go-архитектуру в RPM ISA (Instruction Set Architecture)
 // квалификатор, используемый в спецификациях зависимостей (например, "x86-64" для amd64).
@@ -219,12 +240,20 @@ func goArchToRPMISA(goarch string) string {
 func getBasePkgInfo(vars *alrsh.Package, input interface {
 	RepositoryProvider
 	OsInfoProvider
+	BuildOptsProvider
+	PkgFormatProvider
 },
 ) *nfpm.Info {
 	repo := input.Repository()
+	name := vars.Name
+	arch := targetArch(input.BuildOpts())
+	if isMultilib(input.BuildOpts()) {
+		name = multilibName(input.PkgFormat(), name)
+		arch = multilibPackageArch(input.PkgFormat())
+	}
 	return &nfpm.Info{
-		Name:    fmt.Sprintf("%s+%s", vars.Name, repo),
-		Arch:    cpu.Arch(),
+		Name:    fmt.Sprintf("%s+%s", name, repo),
+		Arch:    arch,
 		Version: vars.Version,
 		Release: overrides.ReleasePlatformSpecific(vars.Release, input.OSRelease()),
 		Epoch:   strconv.FormatUint(uint64(vars.Epoch), 10),
@@ -243,7 +272,7 @@ func GetPkgFormat(mgr manager.Manager) string {
 
 // Функция createBuildEnvVars создает переменные окружения, которые будут установлены
 // в скрипте сборки при его выполнении.
-func createBuildEnvVars(info *distro.OSRelease, dirs types.Directories) []string {
+func createBuildEnvVars(info *distro.OSRelease, opts *types.BuildOpts, dirs types.Directories) []string {
 	env := os.Environ()
 
 	env = append(
@@ -253,9 +282,15 @@ func createBuildEnvVars(info *distro.OSRelease, dirs types.Directories) []string
 		"DISTRO_ID="+info.ID,
 		"DISTRO_VERSION_ID="+info.VersionID,
 		"DISTRO_ID_LIKE="+strings.Join(info.Like, " "),
-		"ARCH="+cpu.Arch(),
 		"NCPU="+strconv.Itoa(runtime.NumCPU()),
 	)
+	// multilib-сборка использует системный компилятор, поэтому
+	// префикс кросс-компилятора не задаётся
+	cross := isCrossBuild(opts) && !isMultilib(opts)
+	env = append(env, cpu.TargetEnv(targetArch(opts), info.IsMusl(), cross)...)
+	if isMultilib(opts) {
+		env = append(env, multilibEnv(info)...)
+	}
 
 	if dirs.ScriptDir != "" {
 		env = append(env, "scriptdir="+dirs.ScriptDir)
@@ -269,6 +304,10 @@ func createBuildEnvVars(info *distro.OSRelease, dirs types.Directories) []string
 		env = append(env, "srcdir="+dirs.SrcDir)
 	}
 
+	if dirs.StageDir != "" {
+		env = append(env, "stagedir="+dirs.StageDir)
+	}
+
 	return env
 }
 
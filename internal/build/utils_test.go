@@ -19,13 +19,25 @@ package build
 import (
 	"testing"
 
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cpu"
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
 )
 
 type mockInput struct {
 	repo   string
 	osInfo *distro.OSRelease
+	opts   *types.BuildOpts
+	format string
+}
+
+func (m *mockInput) PkgFormat() string {
+	return m.format
+}
+
+func (m *mockInput) BuildOpts() *types.BuildOpts {
+	return m.opts
 }
 
 func (m *mockInput) Repository() string {
@@ -83,10 +95,52 @@ func TestGetBasePkgInfo(t *testing.T) {
 			if info.Name != tt.expectedName {
 				t.Errorf("getBasePkgInfo() имя пакета = %v, ожидается %v", info.Name, tt.expectedName)
 			}
+			if info.Arch != cpu.Arch() {
+				t.Errorf("getBasePkgInfo() архитектура = %v, ожидается %v", info.Arch, cpu.Arch())
+			}
 		})
 	}
 }
 
+func TestGetBasePkgInfoTargetArch(t *testing.T) {
+	pkg := &alrsh.Package{Name: "test-package", Version: "1.0.0", Release: 1}
+	input := &mockInput{
+		repo:   "default",
+		osInfo: &distro.OSRelease{ID: "test"},
+		opts:   &types.BuildOpts{TargetArch: "riscv64"},
+	}
+
+	info := getBasePkgInfo(pkg, input)
+	if info.Arch != "riscv64" {
+		t.Errorf("getBasePkgInfo() архитектура = %v, ожидается riscv64", info.Arch)
+	}
+}
+
+func TestGetBasePkgInfoMultilib(t *testing.T) {
+	pkg := &alrsh.Package{Name: "test-package", Version: "1.0.0", Release: 1}
+	input := &mockInput{
+		repo:   "default",
+		osInfo: &distro.OSRelease{ID: "arch"},
+		opts:   &types.BuildOpts{Multilib: true},
+		format: "archlinux",
+	}
+
+	info := getBasePkgInfo(pkg, input)
+	if info.Name != "lib32-test-package+default" {
+		t.Errorf("getBasePkgInfo() имя пакета = %v, ожидается lib32-test-package+default", info.Name)
+	}
+	// lib32-пакеты Arch Linux имеют архитектуру x86_64
+	if info.Arch != "amd64" {
+		t.Errorf("getBasePkgInfo() архитектура = %v, ожидается amd64", info.Arch)
+	}
+
+	input.format = "deb"
+	info = getBasePkgInfo(pkg, input)
+	if info.Arch != "386" {
+		t.Errorf("getBasePkgInfo() архитектура = %v, ожидается 386", info.Arch)
+	}
+}
+
 func TestRegexpALRPackageName(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -116,6 +170,27 @@ func TestRegexpALRPackageName(t *testing.T) {
 			expectedRepo: "alr-alr-repo",
 			shouldMatch:  true,
 		},
+		{
+			name:         "репозиторий с суффиксом вида -x_N",
+			packageName:  "test-package+repo-x_1",
+			expectedPkg:  "test-package",
+			expectedRepo: "repo-x_1",
+			shouldMatch:  true,
+		},
+		{
+			name:         "xbps pkgver с версией и ревизией",
+			packageName:  "test-package+alr-default-1.2.3_1",
+			expectedPkg:  "test-package",
+			expectedRepo: "alr-default",
+			shouldMatch:  true,
+		},
+		{
+			name:         "xbps pkgver с версией из нескольких частей",
+			packageName:  "test-package+repo-2024.01.rc1_12",
+			expectedPkg:  "test-package",
+			expectedRepo: "repo",
+			shouldMatch:  true,
+		},
 		{
 			name:        "некорректный формат - без плюса",
 			packageName: "test-package",
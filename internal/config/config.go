@@ -259,14 +259,27 @@ func (c *ALRConfig) doCreateDefaultConfig() error {
 	return nil
 }
 
-func (c *ALRConfig) RootCmd() string             { return c.cfg.RootCmd }
-func (c *ALRConfig) PagerStyle() string          { return c.cfg.PagerStyle }
-func (c *ALRConfig) AutoPull() bool              { return c.cfg.AutoPull }
-func (c *ALRConfig) Repos() []types.Repo         { return c.cfg.Repos }
-func (c *ALRConfig) SetRepos(repos []types.Repo) { c.System.SetRepos(repos) }
-func (c *ALRConfig) IgnorePkgUpdates() []string  { return c.cfg.IgnorePkgUpdates }
-func (c *ALRConfig) LogLevel() string            { return c.cfg.LogLevel }
-func (c *ALRConfig) UseRootCmd() bool            { return c.cfg.UseRootCmd }
-func (c *ALRConfig) UpdateSystemOnUpgrade() bool { return c.cfg.UpdateSystemOnUpgrade }
-func (c *ALRConfig) PreferALRDeps() bool         { return c.cfg.PreferALRDeps }
-func (c *ALRConfig) GetPaths() *Paths            { return c.paths }
+func (c *ALRConfig) RootCmd() string              { return c.cfg.RootCmd }
+func (c *ALRConfig) PagerStyle() string           { return c.cfg.PagerStyle }
+func (c *ALRConfig) AutoPull() bool               { return c.cfg.AutoPull }
+func (c *ALRConfig) Repos() []types.Repo          { return c.cfg.Repos }
+func (c *ALRConfig) IgnorePkgUpdates() []string   { return c.cfg.IgnorePkgUpdates }
+func (c *ALRConfig) LogLevel() string             { return c.cfg.LogLevel }
+func (c *ALRConfig) UseRootCmd() bool             { return c.cfg.UseRootCmd }
+func (c *ALRConfig) UpdateSystemOnUpgrade() bool  { return c.cfg.UpdateSystemOnUpgrade }
+func (c *ALRConfig) PreferALRDeps() bool          { return c.cfg.PreferALRDeps }
+func (c *ALRConfig) Signing() types.SigningConfig { return c.cfg.Signing }
+func (c *ALRConfig) GetPaths() *Paths             { return c.paths }
+
+// SaveSystem сохраняет системную конфигурацию
+func (c *ALRConfig) SaveSystem() error {
+	return c.System.Save()
+}
+
+// SetRepos записывает список репозиториев в системную конфигурацию
+// и обновляет уже загруженное значение, чтобы долгоживущие процессы
+// (например, D-Bus сервис) сразу видели изменения.
+func (c *ALRConfig) SetRepos(repos []types.Repo) {
+	c.System.SetRepos(repos)
+	c.cfg.Repos = repos
+}
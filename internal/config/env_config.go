@@ -45,6 +45,12 @@ func (c *EnvConfig) Load() error {
 		"ALR_PAGER_STYLE":     {},
 		"ALR_AUTO_PULL":       {},
 		"ALR_PREFER_ALR_DEPS": {},
+
+		"ALR_SIGNING__KEY_FILE":     {},
+		"ALR_SIGNING__KEY_ID":       {},
+		"ALR_SIGNING__APK_KEY_FILE": {},
+		"ALR_SIGNING__APK_KEY_NAME": {},
+		"ALR_SIGNING__PASSPHRASE":   {},
 	}
 	err := c.k.Load(env.Provider("ALR_", ".", func(s string) string {
 		_, ok := allowedKeys[s]
@@ -156,3 +156,11 @@ func (c *SystemConfig) SetPreferALRDeps(v bool) {
 		panic(err)
 	}
 }
+
+// SetSigning записывает параметр name раздела signing
+func (c *SystemConfig) SetSigning(name, v string) {
+	err := c.k.Set("signing."+name, v)
+	if err != nil {
+		panic(err)
+	}
+}
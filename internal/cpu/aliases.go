@@ -0,0 +1,96 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cpu
+
+import "strings"
+
+// archAliases сопоставляет распространённые названия архитектур (из имён
+// файлов релизов, uname -m, дистрибутивов) с каноническими названиями ALR.
+// Порядок важен для FindArch: более длинные названия проверяются раньше
+// своих префиксов (x86_64 раньше x86, armv7l раньше armv7).
+var archAliases = []struct {
+	arch    string
+	aliases []string
+}{
+	{"amd64", []string{"amd64", "x86_64", "x86-64", "x64", "linux64"}},
+	{"arm64", []string{"arm64", "aarch64", "armv8", "armv8l"}},
+	{"arm7", []string{"arm7", "armv7", "armv7l", "armv7h", "armv7hf", "armv7hl", "armhf"}},
+	{"arm6", []string{"arm6", "armv6", "armv6l", "armv6h", "armv6hf"}},
+	{"arm5", []string{"arm5", "armv5", "armv5l", "armv5tel", "armel"}},
+	{"386", []string{"386", "i386", "i486", "i586", "i686", "x86", "x32", "ia32"}},
+	{"riscv64", []string{"riscv64", "riscv64gc"}},
+	{"loong64", []string{"loong64", "loongarch64"}},
+	{"ppc64le", []string{"ppc64le", "powerpc64le"}},
+	{"s390x", []string{"s390x"}},
+}
+
+// Canonical возвращает каноническое название архитектуры ALR для
+// названия name (например, "x86_64" -> "amd64")
+func Canonical(name string) (string, bool) {
+	name = strings.ToLower(name)
+	for _, a := range archAliases {
+		for _, alias := range a.aliases {
+			if name == alias {
+				return a.arch, true
+			}
+		}
+	}
+	return "", false
+}
+
+// FindArch ищет название архитектуры внутри строки s (например, имени файла
+// "foo-1.0-linux-x86_64.tar.gz") и возвращает каноническое название ALR.
+// Название должно быть отделено от остального текста не буквенно-цифровыми символами.
+func FindArch(s string) (string, bool) {
+	s = strings.ToLower(s)
+	for _, a := range archAliases {
+		for _, alias := range a.aliases {
+			if containsWord(s, alias) {
+				return a.arch, true
+			}
+		}
+	}
+	return "", false
+}
+
+func containsWord(s, word string) bool {
+	for start := 0; ; {
+		i := strings.Index(s[start:], word)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(word)
+		if (i == 0 || !isAlnum(s[i-1])) && (end == len(s) || !isAlnum(s[end])) {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// KnownArches возвращает канонические названия архитектур ALR
+func KnownArches() []string {
+	out := make([]string, len(archAliases))
+	for i, a := range archAliases {
+		out[i] = a.arch
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cpu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonical(t *testing.T) {
+	for name, expected := range map[string]string{
+		"x86_64":  "amd64",
+		"AMD64":   "amd64",
+		"aarch64": "arm64",
+		"armhf":   "arm7",
+		"i686":    "386",
+	} {
+		arch, ok := Canonical(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, expected, arch, name)
+	}
+
+	_, ok := Canonical("sparc")
+	assert.False(t, ok)
+}
+
+func TestFindArch(t *testing.T) {
+	for name, expected := range map[string]string{
+		"ripgrep-14.1.1-x86_64-unknown-linux-musl.tar.gz": "amd64",
+		"fzf-0.56.3-linux_amd64.tar.gz":                   "amd64",
+		"tool_Linux_x86.tar.gz":                           "386",
+		"tool-linux-arm64":                                "arm64",
+		"tool-armv7l-linux.zip":                           "arm7",
+		"tool-1.0-aarch64-unknown-linux-gnu.tar.xz":       "arm64",
+	} {
+		arch, ok := FindArch(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, expected, arch, name)
+	}
+
+	for _, name := range []string{"tool-1.0.tar.gz", "tool-armv7something.zip", "checksums.txt"} {
+		_, ok := FindArch(name)
+		assert.False(t, ok, name)
+	}
+}
@@ -66,7 +66,7 @@ func IsCompatibleWith(target string, list []string) bool {
 	}
 
 	for _, arch := range list {
-		if strings.HasPrefix(target, "arm") && strings.HasPrefix(arch, "arm") {
+		if isARM32(target) && isARM32(arch) {
 			targetVer, err := getARMVersion(target)
 			if err != nil {
 				return false
@@ -91,7 +91,7 @@ func IsCompatibleWith(target string, list []string) bool {
 }
 
 func CompatibleArches(arch string) ([]string, error) {
-	if strings.HasPrefix(arch, "arm") {
+	if isARM32(arch) {
 		ver, err := getARMVersion(arch)
 		if err != nil {
 			return nil, err
@@ -109,6 +109,12 @@ func CompatibleArches(arch string) ([]string, error) {
 	return []string{arch}, nil
 }
 
+// isARM32 сообщает, является ли arch одним из вариантов 32-битного ARM
+// (arm5, arm6, arm7), для которых действует обратная совместимость версий
+func isARM32(arch string) bool {
+	return strings.HasPrefix(arch, "arm") && arch != "arm64"
+}
+
 func getARMVersion(arch string) (int, error) {
 	// Extract the version number from ARM architecture
 	version := strings.TrimPrefix(arch, "arm")
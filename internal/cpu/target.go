@@ -0,0 +1,149 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cpu
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gnuArches сопоставляет канонические архитектуры ALR с названиями
+// процессоров GNU, которые используются в триплетах и uname -m
+var gnuArches = map[string]string{
+	"amd64":   "x86_64",
+	"arm64":   "aarch64",
+	"arm7":    "armv7l",
+	"arm6":    "armv6l",
+	"arm5":    "armv5tel",
+	"386":     "i686",
+	"riscv64": "riscv64",
+	"loong64": "loongarch64",
+	"ppc64le": "powerpc64le",
+	"s390x":   "s390x",
+}
+
+// qemuArches сопоставляет архитектуры ALR с суффиксами qemu-user
+// (qemu-aarch64, qemu-arm и т.д.), которые не совпадают с названиями GNU
+var qemuArches = map[string]string{
+	"arm7":    "arm",
+	"arm6":    "arm",
+	"arm5":    "arm",
+	"386":     "i386",
+	"ppc64le": "ppc64le",
+}
+
+// binfmtDir - каталог, в котором ядро регистрирует обработчики binfmt_misc
+var binfmtDir = "/proc/sys/fs/binfmt_misc"
+
+// GNUArch возвращает название процессора GNU для архитектуры ALR
+// (например, "arm64" -> "aarch64"). Неизвестные архитектуры возвращаются как есть.
+func GNUArch(arch string) string {
+	if gnu, ok := gnuArches[arch]; ok {
+		return gnu
+	}
+	return arch
+}
+
+// Triplet возвращает GNU-триплет для архитектуры ALR, например
+// "aarch64-linux-gnu" или "aarch64-alpine-linux-musl" при musl = true
+func Triplet(arch string, musl bool) string {
+	machine := GNUArch(arch)
+	abi := "gnu"
+	if isARM32(arch) {
+		// Для 32-битного ARM ABI зависит от наличия аппаратной плавающей точки
+		machine = "arm"
+		abi = "gnueabihf"
+		if arch == "arm5" {
+			abi = "gnueabi"
+		}
+	}
+
+	if musl {
+		if machine == "arm" {
+			machine = strings.TrimSuffix(GNUArch(arch), "l")
+		}
+		return machine + "-alpine-linux-" + strings.Replace(abi, "gnu", "musl", 1)
+	}
+	return machine + "-linux-" + abi
+}
+
+// TargetEnv возвращает переменные окружения скрипта сборки для целевой
+// архитектуры target: ARCH и CARCH описывают целевую архитектуру, CHOST и
+// CBUILD - триплеты целевой системы и системы сборки. Если cross = true,
+// CROSS_COMPILE содержит префикс инструментов кросс-компиляции.
+func TargetEnv(target string, musl, cross bool) []string {
+	crossCompile := ""
+	if cross {
+		crossCompile = Triplet(target, musl) + "-"
+	}
+
+	return []string{
+		"ARCH=" + target,
+		"CARCH=" + GNUArch(target),
+		"CHOST=" + Triplet(target, musl),
+		"CBUILD=" + Triplet(Arch(), musl),
+		"CROSS_COMPILE=" + crossCompile,
+	}
+}
+
+// QEMUArch возвращает суффикс эмулятора qemu-user для архитектуры ALR
+func QEMUArch(arch string) string {
+	if q, ok := qemuArches[arch]; ok {
+		return q
+	}
+	return GNUArch(arch)
+}
+
+// BinfmtHandler - обработчик binfmt_misc, через который ядро
+// запускает программы другой архитектуры
+type BinfmtHandler struct {
+	Interpreter string
+	Flags       string
+}
+
+// FixBinary сообщает, открыт ли интерпретатор при регистрации (флаг F).
+// Такой интерпретатор доступен и в chroot, где его файла нет.
+func (h BinfmtHandler) FixBinary() bool {
+	return strings.Contains(h.Flags, "F")
+}
+
+// Binfmt возвращает включённый обработчик qemu-user для архитектуры arch.
+// ok = false, если обработчик не зарегистрирован или выключен.
+func Binfmt(arch string) (h BinfmtHandler, ok bool) {
+	fl, err := os.Open(filepath.Join(binfmtDir, "qemu-"+QEMUArch(arch)))
+	if err != nil {
+		return h, false
+	}
+	defer fl.Close()
+
+	sc := bufio.NewScanner(fl)
+	if !sc.Scan() || strings.TrimSpace(sc.Text()) != "enabled" {
+		return h, false
+	}
+	for sc.Scan() {
+		key, value, _ := strings.Cut(sc.Text(), " ")
+		switch key {
+		case "interpreter":
+			h.Interpreter = value
+		case "flags:":
+			h.Flags = value
+		}
+	}
+	return h, h.Interpreter != ""
+}
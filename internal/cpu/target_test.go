@@ -0,0 +1,94 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cpu
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriplet(t *testing.T) {
+	for arch, expected := range map[string][2]string{
+		"amd64":   {"x86_64-linux-gnu", "x86_64-alpine-linux-musl"},
+		"arm64":   {"aarch64-linux-gnu", "aarch64-alpine-linux-musl"},
+		"arm7":    {"arm-linux-gnueabihf", "armv7-alpine-linux-musleabihf"},
+		"arm5":    {"arm-linux-gnueabi", "armv5te-alpine-linux-musleabi"},
+		"386":     {"i686-linux-gnu", "i686-alpine-linux-musl"},
+		"ppc64le": {"powerpc64le-linux-gnu", "powerpc64le-alpine-linux-musl"},
+	} {
+		assert.Equal(t, expected[0], Triplet(arch, false), arch)
+		assert.Equal(t, expected[1], Triplet(arch, true), arch)
+	}
+}
+
+func TestTargetEnv(t *testing.T) {
+	t.Setenv("ALR_ARCH", "amd64")
+
+	assert.Equal(t, []string{
+		"ARCH=arm64",
+		"CARCH=aarch64",
+		"CHOST=aarch64-linux-gnu",
+		"CBUILD=x86_64-linux-gnu",
+		"CROSS_COMPILE=aarch64-linux-gnu-",
+	}, TargetEnv("arm64", false, true))
+
+	assert.Contains(t, TargetEnv("amd64", false, false), "CROSS_COMPILE=")
+}
+
+func TestBinfmt(t *testing.T) {
+	dir := t.TempDir()
+	old := binfmtDir
+	binfmtDir = dir
+	defer func() { binfmtDir = old }()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "qemu-aarch64"), []byte("enabled\ninterpreter /usr/bin/qemu-aarch64-static\nflags: OCF\noffset 0\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "qemu-riscv64"), []byte("enabled\ninterpreter /usr/libexec/qemu-binfmt/riscv64-binfmt-P\nflags: P\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "qemu-arm"), []byte("disabled\ninterpreter /usr/bin/qemu-arm-static\n"), 0o644))
+
+	h, ok := Binfmt("arm64")
+	assert.True(t, ok)
+	assert.Equal(t, "/usr/bin/qemu-aarch64-static", h.Interpreter)
+	assert.True(t, h.FixBinary())
+
+	h, ok = Binfmt("riscv64")
+	assert.True(t, ok)
+	assert.False(t, h.FixBinary())
+
+	_, ok = Binfmt("arm7")
+	assert.False(t, ok)
+	_, ok = Binfmt("s390x")
+	assert.False(t, ok)
+	assert.Equal(t, "i386", QEMUArch("386"))
+}
+
+func TestCompatibleArchesARM64(t *testing.T) {
+	arches, err := CompatibleArches("arm64")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"arm64"}, arches)
+
+	arches, err = CompatibleArches("arm7")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"arm7", "arm6", "arm5"}, arches)
+
+	assert.False(t, IsCompatibleWith("arm64", []string{"arm7"}))
+	assert.False(t, IsCompatibleWith("arm7", []string{"arm64"}))
+	assert.True(t, IsCompatibleWith("arm7", []string{"arm6"}))
+}
@@ -50,6 +50,24 @@ type PackageAvailabilityCache struct {
 	CheckedAt    int64  `xorm:"'checked_at'"`
 }
 
+// Причины установки пакетов ALR
+const (
+	// InstallReasonExplicit - пакет установлен по запросу пользователя
+	InstallReasonExplicit = "explicit"
+	// InstallReasonDependency - пакет установлен как зависимость другого пакета
+	InstallReasonDependency = "dependency"
+)
+
+// InstalledPackage хранит причину установки пакета ALR. В отличие от
+// остальных таблиц эти данные нельзя восстановить из репозиториев,
+// поэтому таблица не сбрасывается при смене версии базы.
+type InstalledPackage struct {
+	Name        string `xorm:"'name' pk"`
+	Repository  string `xorm:"'repository' pk"`
+	Reason      string `xorm:"'reason'"`
+	InstalledAt int64  `xorm:"'installed_at'"`
+}
+
 type Config interface {
 	GetPaths() *config.Paths
 }
@@ -96,7 +114,7 @@ func (d *Database) Init(ctx context.Context) error {
 	if err := d.Connect(); err != nil {
 		return err
 	}
-	if err := d.engine.Sync2(new(alrsh.Package), new(Version), new(PackageAvailabilityCache)); err != nil {
+	if err := d.engine.Sync2(new(alrsh.Package), new(Version), new(PackageAvailabilityCache), new(InstalledPackage)); err != nil {
 		return err
 	}
 	ver, ok := d.GetVersion(ctx)
@@ -131,6 +149,18 @@ func (d *Database) reset() error {
 	return d.engine.DropTables(new(alrsh.Package), new(Version))
 }
 
+// ResetRepoData удаляет данные, полученные из репозиториев, и сохраняет
+// причины установки пакетов, которые нельзя восстановить
+func (d *Database) ResetRepoData(_ context.Context) error {
+	if err := d.engine.DropTables(new(alrsh.Package), new(Version), new(PackageAvailabilityCache)); err != nil {
+		return err
+	}
+	if err := d.engine.Sync2(new(alrsh.Package), new(Version), new(PackageAvailabilityCache)); err != nil {
+		return err
+	}
+	return d.addVersion(CurrentVersion)
+}
+
 func (d *Database) InsertPackage(ctx context.Context, pkg alrsh.Package) error {
 	session := d.engine.Context(ctx)
 
@@ -229,3 +259,47 @@ func (d *Database) SetPackageAvailability(name, manager string, isAvailable bool
 	
 	return err
 }
+
+// SetInstallReason записывает причину установки пакета, заменяя предыдущую
+func (d *Database) SetInstallReason(ctx context.Context, name, repo, reason string) error {
+	session := d.engine.Context(ctx)
+	rec := &InstalledPackage{
+		Name:        name,
+		Repository:  repo,
+		Reason:      reason,
+		InstalledAt: time.Now().Unix(),
+	}
+
+	affected, err := session.Where("name = ? AND repository = ?", name, repo).Update(rec)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		_, err = session.Insert(rec)
+	}
+	return err
+}
+
+// AddInstallReason записывает причину установки пакета, только если
+// она ещё не записана. Так пакет, установленный пользователем явно,
+// не становится зависимостью при установке другого пакета.
+func (d *Database) AddInstallReason(ctx context.Context, name, repo, reason string) error {
+	has, err := d.engine.Context(ctx).Where("name = ? AND repository = ?", name, repo).Exist(new(InstalledPackage))
+	if err != nil || has {
+		return err
+	}
+	return d.SetInstallReason(ctx, name, repo, reason)
+}
+
+// GetInstallReasons возвращает записанные причины установки пакетов
+func (d *Database) GetInstallReasons(ctx context.Context) ([]InstalledPackage, error) {
+	var out []InstalledPackage
+	err := d.engine.Context(ctx).Find(&out)
+	return out, err
+}
+
+// DeleteInstallReason удаляет причину установки удалённого пакета
+func (d *Database) DeleteInstallReason(ctx context.Context, name, repo string) error {
+	_, err := d.engine.Context(ctx).Where("name = ? AND repository = ?", name, repo).Delete(new(InstalledPackage))
+	return err
+}
@@ -249,3 +249,58 @@ func TestJsonArrayContains(t *testing.T) {
 		t.Errorf("Expected provides to contain 'x'")
 	}
 }
+
+func TestInstallReasons(t *testing.T) {
+	ctx := context.Background()
+	database := prepareDb()
+	defer database.Close()
+
+	assert.NoError(t, database.AddInstallReason(ctx, "lib", "default", db.InstallReasonDependency))
+	assert.NoError(t, database.SetInstallReason(ctx, "app", "default", db.InstallReasonExplicit))
+
+	// Явно установленный пакет не становится зависимостью
+	assert.NoError(t, database.AddInstallReason(ctx, "app", "default", db.InstallReasonDependency))
+	// Повторная явная установка зависимости меняет причину
+	assert.NoError(t, database.SetInstallReason(ctx, "lib", "default", db.InstallReasonExplicit))
+	assert.NoError(t, database.AddInstallReason(ctx, "lib", "other", db.InstallReasonDependency))
+
+	reasons, err := database.GetInstallReasons(ctx)
+	assert.NoError(t, err)
+	got := map[string]string{}
+	for _, r := range reasons {
+		got[r.Repository+"/"+r.Name] = r.Reason
+	}
+	assert.Equal(t, map[string]string{
+		"default/app": db.InstallReasonExplicit,
+		"default/lib": db.InstallReasonExplicit,
+		"other/lib":   db.InstallReasonDependency,
+	}, got)
+
+	assert.NoError(t, database.DeleteInstallReason(ctx, "lib", "default"))
+	reasons, err = database.GetInstallReasons(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, reasons, 2)
+}
+
+func TestResetRepoData(t *testing.T) {
+	ctx := context.Background()
+	database := prepareDb()
+	defer database.Close()
+
+	assert.NoError(t, database.InsertPackage(ctx, testPkg))
+	assert.NoError(t, database.SetPackageAvailability("foo", "apt", true))
+	assert.NoError(t, database.SetInstallReason(ctx, "app", "default", db.InstallReasonExplicit))
+
+	assert.NoError(t, database.ResetRepoData(ctx))
+
+	assert.True(t, database.IsEmpty())
+	_, ok := database.GetPackageAvailability("foo", "apt")
+	assert.False(t, ok)
+	ver, ok := database.GetVersion(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, db.CurrentVersion, ver)
+
+	reasons, err := database.GetInstallReasons(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, reasons, 1)
+}
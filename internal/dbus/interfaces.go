@@ -38,6 +38,20 @@ type ManagerInterface interface {
 	// Возвращает object path задачи
 	RefreshRepositories() (dbus.ObjectPath, *dbus.Error)
 
+	// AddRepository клонирует и добавляет новый репозиторий
+	// Возвращает object path задачи
+	AddRepository(sender dbus.Sender, name, url, ref string, mirrors []string) (dbus.ObjectPath, *dbus.Error)
+
+	// RemoveRepository удаляет репозиторий и его пакеты
+	RemoveRepository(sender dbus.Sender, name string) *dbus.Error
+
+	// SetRepositoryRef меняет ссылку репозитория
+	// Возвращает object path задачи обновления
+	SetRepositoryRef(sender dbus.Sender, name, ref string) (dbus.ObjectPath, *dbus.Error)
+
+	// SetRepositoryMirrors заменяет список зеркал репозитория
+	SetRepositoryMirrors(sender dbus.Sender, name string, mirrors []string) *dbus.Error
+
 	// GetVersion возвращает версию ALR
 	GetVersion() (string, *dbus.Error)
 
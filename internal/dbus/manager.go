@@ -17,22 +17,30 @@
 package dbus
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 
 	"github.com/godbus/dbus/v5"
 	"github.com/godbus/dbus/v5/introspect"
 	"github.com/godbus/dbus/v5/prop"
 
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/build"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/config"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/repos"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/search"
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
 )
 
 // DBusManager реализует ru.alr-pkg.ALR.Manager интерфейс
 type DBusManager struct {
 	service    *Service
 	properties *prop.Properties
+
+	// Сериализует изменения списка репозиториев
+	reposMu sync.Mutex
 }
 
 // NewDBusManager создает новый Manager
@@ -204,6 +212,188 @@ func (m *DBusManager) RefreshRepositories() (dbus.ObjectPath, *dbus.Error) {
 	return path, nil
 }
 
+// AddRepository добавляет новый репозиторий
+func (m *DBusManager) AddRepository(sender dbus.Sender, name, url, ref string, mirrors []string) (dbus.ObjectPath, *dbus.Error) {
+	slog.Info("AddRepository called", "name", name, "url", url, "ref", ref)
+
+	if err := m.authorize(sender, ActionRepos); err != nil {
+		return "", err
+	}
+
+	deps := m.service.GetDeps()
+	if deps == nil || deps.Repos == nil {
+		return "", dbus.NewError("ru.alr-pkg.ALR.Error.NotInitialized", []interface{}{"service not initialized"})
+	}
+
+	if m.service.GetConfig() == nil {
+		return "", dbus.NewError("ru.alr-pkg.ALR.Error.NotInitialized", []interface{}{"config not loaded"})
+	}
+
+	if name == "" || url == "" {
+		return "", dbus.NewError("ru.alr-pkg.ALR.Error.InvalidArgs", []interface{}{"repository name and url are required"})
+	}
+
+	jobID := m.service.NextJobID()
+	job := NewDBusJob(m.service, jobID, JobTypeAddRepo, "", name)
+
+	go func() {
+		job.SetStatus(JobStatusRunning)
+		job.SetProgress(0.0, fmt.Sprintf("Cloning repository %s...", name))
+
+		m.reposMu.Lock()
+		newRepo, err := repos.AddRepo(m.service.Context(), m.service.GetConfig(), build.NewRepos(deps.Repos), types.Repo{
+			Name:    name,
+			URL:     url,
+			Ref:     ref,
+			Mirrors: mirrors,
+		})
+		m.reposMu.Unlock()
+		if err != nil {
+			job.SetFailed(err.Error())
+			m.service.Notify("ALR Error", fmt.Sprintf("Failed to add repository %s: %v", name, err), UrgencyCritical)
+			return
+		}
+
+		job.SetProgress(1.0, "Repository added successfully")
+		job.SetCompleted()
+
+		m.service.Notify("ALR", fmt.Sprintf("Repository %s added", newRepo.Name), UrgencyNormal)
+		m.EmitSignal(DBusObjectPath, ManagerInterfaceName, ManagerSignalRepositoryAdded, newRepo.Name, newRepo.URL)
+	}()
+
+	path := m.service.RegisterJob(job)
+	return path, nil
+}
+
+// RemoveRepository удаляет репозиторий и его пакеты из базы данных
+func (m *DBusManager) RemoveRepository(sender dbus.Sender, name string) *dbus.Error {
+	slog.Info("RemoveRepository called", "name", name)
+
+	if err := m.authorize(sender, ActionRepos); err != nil {
+		return err
+	}
+
+	deps := m.service.GetDeps()
+	if deps == nil || deps.DB == nil {
+		return dbus.NewError("ru.alr-pkg.ALR.Error.NotInitialized", []interface{}{"service not initialized"})
+	}
+
+	if m.service.GetConfig() == nil {
+		return dbus.NewError("ru.alr-pkg.ALR.Error.NotInitialized", []interface{}{"config not loaded"})
+	}
+
+	m.reposMu.Lock()
+	err := repos.RemoveRepo(m.service.Context(), m.service.GetConfig(), deps.DB, name)
+	m.reposMu.Unlock()
+	if err != nil {
+		return repoError(err)
+	}
+
+	m.EmitSignal(DBusObjectPath, ManagerInterfaceName, ManagerSignalRepositoryRemoved, name)
+	return nil
+}
+
+// SetRepositoryRef меняет ссылку репозитория и обновляет его
+func (m *DBusManager) SetRepositoryRef(sender dbus.Sender, name, ref string) (dbus.ObjectPath, *dbus.Error) {
+	slog.Info("SetRepositoryRef called", "name", name, "ref", ref)
+
+	if err := m.authorize(sender, ActionRepos); err != nil {
+		return "", err
+	}
+
+	deps := m.service.GetDeps()
+	if deps == nil || deps.Repos == nil {
+		return "", dbus.NewError("ru.alr-pkg.ALR.Error.NotInitialized", []interface{}{"service not initialized"})
+	}
+
+	if m.service.GetConfig() == nil {
+		return "", dbus.NewError("ru.alr-pkg.ALR.Error.NotInitialized", []interface{}{"config not loaded"})
+	}
+
+	m.reposMu.Lock()
+	repo, err := repos.SetRepoRef(m.service.GetConfig(), name, ref)
+	m.reposMu.Unlock()
+	if err != nil {
+		return "", repoError(err)
+	}
+
+	jobID := m.service.NextJobID()
+	job := NewDBusJob(m.service, jobID, JobTypeRefresh, "", name)
+
+	go func() {
+		job.SetStatus(JobStatusRunning)
+		job.SetProgress(0.0, fmt.Sprintf("Switching repository %s to %s...", name, ref))
+
+		if err := deps.Repos.Pull(m.service.Context(), []types.Repo{repo}); err != nil {
+			job.SetFailed(err.Error())
+			m.service.Notify("ALR Error", fmt.Sprintf("Failed to update repository %s: %v", name, err), UrgencyCritical)
+			return
+		}
+
+		job.SetProgress(1.0, "Repository updated successfully")
+		job.SetCompleted()
+
+		m.EmitSignal(DBusObjectPath, ManagerInterfaceName, ManagerSignalRepositoryUpdated, repo.Name, repo.Ref)
+	}()
+
+	path := m.service.RegisterJob(job)
+	return path, nil
+}
+
+// SetRepositoryMirrors заменяет список зеркал репозитория
+func (m *DBusManager) SetRepositoryMirrors(sender dbus.Sender, name string, mirrors []string) *dbus.Error {
+	slog.Info("SetRepositoryMirrors called", "name", name, "mirrors", mirrors)
+
+	if err := m.authorize(sender, ActionRepos); err != nil {
+		return err
+	}
+
+	if m.service.GetConfig() == nil {
+		return dbus.NewError("ru.alr-pkg.ALR.Error.NotInitialized", []interface{}{"config not loaded"})
+	}
+
+	m.reposMu.Lock()
+	repo, err := repos.SetRepoMirrors(m.service.GetConfig(), name, mirrors)
+	m.reposMu.Unlock()
+	if err != nil {
+		return repoError(err)
+	}
+
+	m.EmitSignal(DBusObjectPath, ManagerInterfaceName, ManagerSignalRepositoryUpdated, repo.Name, repo.Ref)
+	return nil
+}
+
+// authorize проверяет право вызывающего клиента на выполнение действия
+func (m *DBusManager) authorize(sender dbus.Sender, action string) *dbus.Error {
+	authorizer := m.service.GetAuthorizer()
+	if authorizer == nil {
+		return dbus.NewError("ru.alr-pkg.ALR.Error.NotInitialized", []interface{}{"authorizer not initialized"})
+	}
+
+	ok, err := authorizer.CheckSenderAuthorization(sender, action, nil, true)
+	if err != nil {
+		slog.Error("Authorization check failed", "action", action, "sender", sender, "err", err)
+		return dbus.NewError("ru.alr-pkg.ALR.Error.NotAuthorized", []interface{}{err.Error()})
+	}
+	if !ok {
+		return dbus.NewError("ru.alr-pkg.ALR.Error.NotAuthorized", []interface{}{fmt.Sprintf("not authorized to perform %s", action)})
+	}
+
+	return nil
+}
+
+// repoError преобразует ошибку управления репозиториями в ошибку D-Bus
+func repoError(err error) *dbus.Error {
+	switch {
+	case errors.Is(err, repos.ErrRepoNotFound):
+		return dbus.NewError("ru.alr-pkg.ALR.Error.RepositoryNotFound", []interface{}{err.Error()})
+	case errors.Is(err, repos.ErrRepoExists):
+		return dbus.NewError("ru.alr-pkg.ALR.Error.RepositoryExists", []interface{}{err.Error()})
+	default:
+		return dbus.NewError("ru.alr-pkg.ALR.Error.RepositoryFailed", []interface{}{err.Error()})
+	}
+}
+
 // GetVersion возвращает версию ALR
 func (m *DBusManager) GetVersion() (string, *dbus.Error) {
 	return config.Version, nil
@@ -233,7 +423,7 @@ func (m *DBusManager) convertToPackageInfo(pkg *alrsh.Package) PackageInfo {
 	deps := m.service.GetDeps()
 	installed := false
 	if deps != nil && deps.Manager != nil {
-		fullName := fmt.Sprintf("alr-%s+%s", pkg.Name, pkg.Repository)
+		fullName := GetALRPackageName(pkg.Name, pkg.Repository)
 		if isInstalled, err := deps.Manager.IsInstalled(fullName); err == nil {
 			installed = isInstalled
 		}
@@ -295,6 +485,37 @@ func (m *DBusManager) IntrospectionData() introspect.Interface {
 					{Name: "error", Type: "s", Direction: "out"},
 				},
 			},
+			{
+				Name: "AddRepository",
+				Args: []introspect.Arg{
+					{Name: "name", Type: "s", Direction: "in"},
+					{Name: "url", Type: "s", Direction: "in"},
+					{Name: "ref", Type: "s", Direction: "in"},
+					{Name: "mirrors", Type: "as", Direction: "in"},
+					{Name: "job", Type: "o", Direction: "out"},
+				},
+			},
+			{
+				Name: "RemoveRepository",
+				Args: []introspect.Arg{
+					{Name: "name", Type: "s", Direction: "in"},
+				},
+			},
+			{
+				Name: "SetRepositoryRef",
+				Args: []introspect.Arg{
+					{Name: "name", Type: "s", Direction: "in"},
+					{Name: "ref", Type: "s", Direction: "in"},
+					{Name: "job", Type: "o", Direction: "out"},
+				},
+			},
+			{
+				Name: "SetRepositoryMirrors",
+				Args: []introspect.Arg{
+					{Name: "name", Type: "s", Direction: "in"},
+					{Name: "mirrors", Type: "as", Direction: "in"},
+				},
+			},
 			{
 				Name: "GetVersion",
 				Args: []introspect.Arg{
@@ -337,6 +558,13 @@ func (m *DBusManager) IntrospectionData() introspect.Interface {
 					{Name: "name", Type: "s"},
 				},
 			},
+			{
+				Name: "RepositoryUpdated",
+				Args: []introspect.Arg{
+					{Name: "name", Type: "s"},
+					{Name: "ref", Type: "s"},
+				},
+			},
 			{
 				Name: "PackageInstalled",
 				Args: []introspect.Arg{
@@ -0,0 +1,73 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dbus
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appbuilder "git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils/app_builder"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/db"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/manager"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/repos"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+)
+
+// installedManager сообщает, что установлены только пакеты из installed
+type installedManager struct {
+	manager.Manager
+	installed map[string]bool
+}
+
+func (m installedManager) IsInstalled(name string) (bool, error) {
+	return m.installed[name], nil
+}
+
+func TestRepositoryMethodsWithoutConfig(t *testing.T) {
+	const sender = dbus.Sender(":1.42")
+	m := &DBusManager{service: &Service{
+		deps:       &appbuilder.AppDeps{DB: &db.Database{}, Repos: &repos.Repos{}},
+		authorizer: &fakeAuthorizer{allowed: map[dbus.Sender]bool{sender: true}},
+	}}
+
+	notInitialized := func(err *dbus.Error) {
+		t.Helper()
+		require.NotNil(t, err)
+		assert.Equal(t, "ru.alr-pkg.ALR.Error.NotInitialized", err.Name)
+	}
+
+	_, err := m.AddRepository(sender, "extra", "https://example.com/extra.git", "", nil)
+	notInitialized(err)
+	notInitialized(m.RemoveRepository(sender, "extra"))
+	_, err = m.SetRepositoryRef(sender, "extra", "main")
+	notInitialized(err)
+	notInitialized(m.SetRepositoryMirrors(sender, "extra", nil))
+}
+
+func TestConvertToPackageInfoInstalled(t *testing.T) {
+	m := &DBusManager{service: &Service{
+		deps: &appbuilder.AppDeps{Manager: installedManager{installed: map[string]bool{"foo+default": true}}},
+	}}
+
+	// Пакеты ALR устанавливаются под именем <имя>+<репозиторий>, без префикса alr-
+	assert.True(t, m.convertToPackageInfo(&alrsh.Package{Name: "foo", Repository: "default"}).Installed)
+	assert.False(t, m.convertToPackageInfo(&alrsh.Package{Name: "foo", Repository: "extra"}).Installed)
+	assert.False(t, m.convertToPackageInfo(&alrsh.Package{Name: "bar", Repository: "default"}).Installed)
+}
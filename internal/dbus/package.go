@@ -130,7 +130,7 @@ func (p *DBusPackage) updateInstalledStatus() {
 		return
 	}
 
-	fullName := fmt.Sprintf("alr-%s+%s", p.name, p.repository)
+	fullName := GetALRPackageName(p.name, p.repository)
 	installed, err := deps.Manager.IsInstalled(fullName)
 	if err != nil {
 		slog.Debug("Failed to check installation status", "package", fullName, "err", err)
@@ -213,36 +213,14 @@ func (p *DBusPackage) runInstall(job *DBusJob, clean, interactive bool) {
 	ctx := p.service.Context()
 	deps := p.service.GetDeps()
 
-	// Получаем исполнители
-	installer, installerClose, err := build.GetSafeInstaller()
-	if err != nil {
-		job.SetFailed(fmt.Sprintf("Failed to get installer: %v", err))
-		p.service.Notify("ALR Error", fmt.Sprintf("Failed to install %s", p.name), UrgencyCritical)
-		return
-	}
-	defer installerClose()
-
-	scripter, scripterClose, err := build.GetSafeScriptExecutor()
-	if err != nil {
-		job.SetFailed(fmt.Sprintf("Failed to get scripter: %v", err))
-		p.service.Notify("ALR Error", fmt.Sprintf("Failed to install %s", p.name), UrgencyCritical)
-		return
-	}
-	defer scripterClose()
-
 	// Создаем билдер
-	builder, err := build.NewMainBuilder(
-		deps.Cfg,
-		deps.Manager,
-		deps.Repos,
-		scripter,
-		installer,
-	)
+	builder, builderClose, err := p.service.NewBuilder()
 	if err != nil {
-		job.SetFailed(fmt.Sprintf("Failed to create builder: %v", err))
+		job.SetFailed(err.Error())
 		p.service.Notify("ALR Error", fmt.Sprintf("Failed to install %s", p.name), UrgencyCritical)
 		return
 	}
+	defer builderClose()
 
 	// Прогресс обратного вызова
 	progressFunc := func(percent float64, message string) {
@@ -324,7 +302,7 @@ func (p *DBusPackage) runRemove(job *DBusJob, interactive bool) {
 
 	deps := p.service.GetDeps()
 
-	fullName := fmt.Sprintf("alr-%s+%s", p.name, p.repository)
+	fullName := GetALRPackageName(p.name, p.repository)
 
 	if err := deps.Manager.Remove(&manager.Opts{
 		NoConfirm: !interactive,
@@ -359,7 +337,7 @@ func (p *DBusPackage) CheckForUpdates() (bool, string, *dbus.Error) {
 		return false, "", dbus.NewError("ru.alr-pkg.ALR.Error.NotInitialized", []interface{}{"service not initialized"})
 	}
 
-	fullName := fmt.Sprintf("alr-%s+%s", p.name, p.repository)
+	fullName := GetALRPackageName(p.name, p.repository)
 	installedVer, err := deps.Manager.GetInstalledVersion(fullName)
 	if err != nil {
 		return false, "", dbus.NewError("ru.alr-pkg.ALR.Error.Internal", []interface{}{err.Error()})
@@ -0,0 +1,789 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/config"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cpu"
+)
+
+// Фасад повторяет D-Bus API демона PackageKit (org.freedesktop.PackageKit),
+// чтобы GNOME Software, KDE Discover и pkcon могли работать с пакетами ALR.
+// Реализовано подмножество транзакций: Resolve, SearchNames, GetDetails,
+// InstallPackages и GetUpdates.
+
+const (
+	PackageKitName                 = "org.freedesktop.PackageKit"
+	PackageKitObjectPath           = "/org/freedesktop/PackageKit"
+	PackageKitInterfaceName        = "org.freedesktop.PackageKit"
+	PackageKitTransactionInterface = "org.freedesktop.PackageKit.Transaction"
+)
+
+// Значения перечислений PackageKit (pk-enum.h)
+const (
+	pkRoleGetDetails      uint32 = 3
+	pkRoleGetUpdates      uint32 = 9
+	pkRoleInstallPackages uint32 = 11
+	pkRoleResolve         uint32 = 17
+	pkRoleSearchName      uint32 = 21
+
+	pkStatusWait     uint32 = 1
+	pkStatusRunning  uint32 = 3
+	pkStatusQuery    uint32 = 4
+	pkStatusInstall  uint32 = 9
+	pkStatusFinished uint32 = 18
+
+	pkFilterInstalled    uint32 = 2
+	pkFilterNotInstalled uint32 = 3
+
+	pkInfoInstalled uint32 = 1
+	pkInfoAvailable uint32 = 2
+	pkInfoNormal    uint32 = 5
+
+	pkExitSuccess   uint32 = 1
+	pkExitFailed    uint32 = 2
+	pkExitCancelled uint32 = 3
+
+	pkErrorNotSupported         uint32 = 3
+	pkErrorInternalError        uint32 = 4
+	pkErrorPackageIDInvalid     uint32 = 6
+	pkErrorPackageNotFound      uint32 = 8
+	pkErrorTransactionCancelled uint32 = 17
+
+	pkTransactionFlagSimulate     uint64 = 1 << 2
+	pkTransactionFlagOnlyDownload uint64 = 1 << 3
+
+	pkPercentageUnknown uint32 = 101
+)
+
+// ErrPackageNotFound возвращается бэкендом, если пакет не найден
+var ErrPackageNotFound = errors.New("package not found")
+
+// PackageKitBackend предоставляет фасаду PackageKit данные о пакетах ALR.
+// Основная реализация работает поверх Service (DBusManager и Builder),
+// в тестах используется подставной бэкенд.
+type PackageKitBackend interface {
+	// SearchNames ищет пакеты, имя которых содержит любое из значений
+	SearchNames(ctx context.Context, values []string) ([]PackageInfo, error)
+	// Resolve возвращает пакеты с точно совпадающими именами (name или repo/name)
+	Resolve(ctx context.Context, names []string) ([]PackageInfo, error)
+	// GetDetails возвращает информацию о пакете из репозитория
+	GetDetails(ctx context.Context, name, repository string) (PackageInfo, error)
+	// InstallPackages собирает и устанавливает пакеты
+	InstallPackages(ctx context.Context, pkgs []PackageInfo, progress func(percent float64, message string)) error
+	// GetUpdates возвращает установленные пакеты, для которых есть новая версия
+	GetUpdates(ctx context.Context) ([]PackageInfo, error)
+}
+
+// PackageKit реализует org.freedesktop.PackageKit поверх PackageKitBackend
+type PackageKit struct {
+	conn       *dbus.Conn
+	backend    PackageKitBackend
+	authorizer Authorizer
+	arch       string
+
+	// Счетчик ID для транзакций
+	txCounter uint32
+
+	ctx context.Context
+
+	transactions map[dbus.ObjectPath]*PackageKitTransaction
+	mu           sync.Mutex
+
+	properties *prop.Properties
+}
+
+// NewPackageKit создает фасад PackageKit
+func NewPackageKit(ctx context.Context, conn *dbus.Conn, backend PackageKitBackend, authorizer Authorizer) *PackageKit {
+	return &PackageKit{
+		conn:         conn,
+		backend:      backend,
+		authorizer:   authorizer,
+		arch:         cpu.Arch(),
+		ctx:          ctx,
+		transactions: make(map[dbus.ObjectPath]*PackageKitTransaction),
+	}
+}
+
+// Export экспортирует корневой объект PackageKit на шину.
+// Имя org.freedesktop.PackageKit запрашивает вызывающая сторона.
+func (pk *PackageKit) Export() error {
+	roles := uint64(0)
+	for _, role := range []uint32{pkRoleGetDetails, pkRoleGetUpdates, pkRoleInstallPackages, pkRoleResolve, pkRoleSearchName} {
+		roles |= 1 << role
+	}
+	filters := uint64(1)<<pkFilterInstalled | uint64(1)<<pkFilterNotInstalled
+
+	propsSpec := map[string]map[string]*prop.Prop{
+		PackageKitInterfaceName: {
+			"VersionMajor":       {Value: uint32(1), Emit: prop.EmitFalse},
+			"VersionMinor":       {Value: uint32(2), Emit: prop.EmitFalse},
+			"VersionMicro":       {Value: uint32(0), Emit: prop.EmitFalse},
+			"BackendName":        {Value: "alr", Emit: prop.EmitFalse},
+			"BackendDescription": {Value: "ALR - Any Linux Repository " + config.Version, Emit: prop.EmitFalse},
+			"BackendAuthor":      {Value: "The ALR Authors", Emit: prop.EmitFalse},
+			"Roles":              {Value: roles, Emit: prop.EmitFalse},
+			"Filters":            {Value: filters, Emit: prop.EmitFalse},
+			"Groups":             {Value: uint64(0), Emit: prop.EmitFalse},
+			"MimeTypes":          {Value: []string{}, Emit: prop.EmitFalse},
+			"Locked":             {Value: false, Emit: prop.EmitFalse},
+			"NetworkState":       {Value: uint32(2), Emit: prop.EmitFalse}, // online
+			"DistroId":           {Value: "alr", Emit: prop.EmitFalse},
+		},
+	}
+
+	if err := pk.conn.Export(pk, PackageKitObjectPath, PackageKitInterfaceName); err != nil {
+		return fmt.Errorf("failed to export packagekit: %w", err)
+	}
+
+	props, err := prop.Export(pk.conn, PackageKitObjectPath, propsSpec)
+	if err != nil {
+		return fmt.Errorf("failed to export packagekit properties: %w", err)
+	}
+	pk.properties = props
+
+	if err := pk.conn.Export(
+		introspect.NewIntrospectable(&introspect.Node{
+			Interfaces: []introspect.Interface{
+				pk.IntrospectionData(),
+				prop.IntrospectData,
+			},
+		}),
+		PackageKitObjectPath,
+		"org.freedesktop.DBus.Introspectable",
+	); err != nil {
+		return fmt.Errorf("failed to export packagekit introspection: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTransaction создает новую транзакцию, принадлежащую вызывающему клиенту.
+// Путь транзакции содержит случайную часть, чтобы его нельзя было угадать.
+func (pk *PackageKit) CreateTransaction(sender dbus.Sender) (dbus.ObjectPath, *dbus.Error) {
+	var uid uint32
+	if err := pk.conn.BusObject().Call("org.freedesktop.DBus.GetConnectionUnixUser", 0, string(sender)).Store(&uid); err != nil {
+		slog.Error("Failed to get sender uid", "sender", sender, "err", err)
+		return "", dbus.NewError("org.freedesktop.PackageKit.Denied", []interface{}{err.Error()})
+	}
+
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", dbus.NewError("org.freedesktop.PackageKit.Denied", []interface{}{err.Error()})
+	}
+
+	id := atomic.AddUint32(&pk.txCounter, 1)
+	path := dbus.ObjectPath(fmt.Sprintf("%s/tx%d_%s", PackageKitObjectPath, id, hex.EncodeToString(random)))
+
+	tx := &PackageKitTransaction{
+		pk:    pk,
+		path:  path,
+		owner: sender,
+		uid:   uid,
+	}
+	if err := tx.export(); err != nil {
+		slog.Error("Failed to export transaction", "path", path, "err", err)
+		return "", dbus.NewError("org.freedesktop.PackageKit.Denied", []interface{}{err.Error()})
+	}
+
+	pk.mu.Lock()
+	pk.transactions[path] = tx
+	pk.mu.Unlock()
+
+	return path, nil
+}
+
+// GetDaemonState возвращает отладочное описание состояния
+func (pk *PackageKit) GetDaemonState() (string, *dbus.Error) {
+	pk.mu.Lock()
+	defer pk.mu.Unlock()
+	return fmt.Sprintf("alr backend, %d transaction(s)", len(pk.transactions)), nil
+}
+
+// GetTransactionList возвращает список активных транзакций
+func (pk *PackageKit) GetTransactionList() ([]dbus.ObjectPath, *dbus.Error) {
+	pk.mu.Lock()
+	defer pk.mu.Unlock()
+
+	paths := make([]dbus.ObjectPath, 0, len(pk.transactions))
+	for path := range pk.transactions {
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// removeTransaction удаляет транзакцию с шины
+func (pk *PackageKit) removeTransaction(path dbus.ObjectPath) {
+	pk.mu.Lock()
+	delete(pk.transactions, path)
+	pk.mu.Unlock()
+
+	pk.conn.Export(nil, path, PackageKitTransactionInterface)
+	pk.conn.Export(nil, path, PropertiesInterface)
+	pk.conn.Export(nil, path, "org.freedesktop.DBus.Introspectable")
+}
+
+// PackageID формирует идентификатор пакета PackageKit: name;version;arch;data
+func (pk *PackageKit) PackageID(pkg PackageInfo) string {
+	data := pkg.Repository
+	if pkg.Installed {
+		data = "installed:" + pkg.Repository
+	}
+	return strings.Join([]string{pkg.Name, pkg.Version, pk.arch, data}, ";")
+}
+
+// ParsePackageID разбирает идентификатор пакета PackageKit
+func ParsePackageID(id string) (name, version, arch, repository string, err error) {
+	parts := strings.Split(id, ";")
+	if len(parts) != 4 || parts[0] == "" {
+		return "", "", "", "", fmt.Errorf("invalid package id %q", id)
+	}
+	repository = strings.TrimPrefix(parts[3], "installed:")
+	if repository == "" {
+		return "", "", "", "", fmt.Errorf("package id %q has no repository", id)
+	}
+	return parts[0], parts[1], parts[2], repository, nil
+}
+
+// IntrospectionData возвращает данные для introspection
+func (pk *PackageKit) IntrospectionData() introspect.Interface {
+	return introspect.Interface{
+		Name: PackageKitInterfaceName,
+		Methods: []introspect.Method{
+			{
+				Name: "CreateTransaction",
+				Args: []introspect.Arg{
+					{Name: "object_path", Type: "o", Direction: "out"},
+				},
+			},
+			{
+				Name: "GetDaemonState",
+				Args: []introspect.Arg{
+					{Name: "state", Type: "s", Direction: "out"},
+				},
+			},
+			{
+				Name: "GetTransactionList",
+				Args: []introspect.Arg{
+					{Name: "transactions", Type: "ao", Direction: "out"},
+				},
+			},
+		},
+		Properties: []introspect.Property{
+			{Name: "VersionMajor", Type: "u", Access: "read"},
+			{Name: "VersionMinor", Type: "u", Access: "read"},
+			{Name: "VersionMicro", Type: "u", Access: "read"},
+			{Name: "BackendName", Type: "s", Access: "read"},
+			{Name: "BackendDescription", Type: "s", Access: "read"},
+			{Name: "BackendAuthor", Type: "s", Access: "read"},
+			{Name: "Roles", Type: "t", Access: "read"},
+			{Name: "Filters", Type: "t", Access: "read"},
+			{Name: "Groups", Type: "t", Access: "read"},
+			{Name: "MimeTypes", Type: "as", Access: "read"},
+			{Name: "Locked", Type: "b", Access: "read"},
+			{Name: "NetworkState", Type: "u", Access: "read"},
+			{Name: "DistroId", Type: "s", Access: "read"},
+		},
+	}
+}
+
+// PackageKitTransaction реализует org.freedesktop.PackageKit.Transaction.
+// Каждая транзакция выполняет ровно одну операцию и сообщает результат сигналами.
+type PackageKitTransaction struct {
+	pk   *PackageKit
+	path dbus.ObjectPath
+
+	// owner и uid - клиент, создавший транзакцию, и его пользователь.
+	// Вызывать методы транзакции может только этот клиент.
+	owner dbus.Sender
+	uid   uint32
+
+	started bool
+	cancel  context.CancelFunc
+	hints   []string
+
+	properties *prop.Properties
+
+	mu sync.Mutex
+}
+
+// export экспортирует транзакцию на шину
+func (t *PackageKitTransaction) export() error {
+	propsSpec := map[string]map[string]*prop.Prop{
+		PackageKitTransactionInterface: {
+			"Role":       {Value: uint32(0), Emit: prop.EmitTrue},
+			"Status":     {Value: pkStatusWait, Emit: prop.EmitTrue},
+			"Percentage": {Value: pkPercentageUnknown, Emit: prop.EmitTrue},
+			"AllowCancel": {
+				Value: true,
+				Emit:  prop.EmitTrue,
+			},
+			"CallerActive":  {Value: true, Emit: prop.EmitFalse},
+			"ElapsedTime":   {Value: uint32(0), Emit: prop.EmitFalse},
+			"RemainingTime": {Value: uint32(0), Emit: prop.EmitFalse},
+			"Speed":         {Value: uint32(0), Emit: prop.EmitFalse},
+			"LastPackage":   {Value: "", Emit: prop.EmitTrue},
+			"Uid":           {Value: t.uid, Emit: prop.EmitFalse},
+		},
+	}
+
+	if err := t.pk.conn.Export(t, t.path, PackageKitTransactionInterface); err != nil {
+		return err
+	}
+
+	props, err := prop.Export(t.pk.conn, t.path, propsSpec)
+	if err != nil {
+		return err
+	}
+	t.properties = props
+
+	return t.pk.conn.Export(
+		introspect.NewIntrospectable(&introspect.Node{
+			Interfaces: []introspect.Interface{
+				t.IntrospectionData(),
+				prop.IntrospectData,
+			},
+		}),
+		t.path,
+		"org.freedesktop.DBus.Introspectable",
+	)
+}
+
+// SetHints принимает подсказки клиента (locale, interactive и т.д.)
+func (t *PackageKitTransaction) SetHints(sender dbus.Sender, hints []string) *dbus.Error {
+	if err := t.checkOwner(sender); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.hints = hints
+	return nil
+}
+
+// Cancel отменяет выполняющуюся транзакцию. Отмена чужой транзакции
+// требует авторизации через PolicyKit.
+func (t *PackageKitTransaction) Cancel(sender dbus.Sender) *dbus.Error {
+	if sender != t.owner {
+		if err := t.pk.authorize(sender, ActionCancelForeign); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.cancel != nil {
+		t.cancel()
+	}
+	return nil
+}
+
+// checkOwner разрешает вызов только клиенту, создавшему транзакцию
+func (t *PackageKitTransaction) checkOwner(sender dbus.Sender) *dbus.Error {
+	if sender != t.owner {
+		return dbus.NewError("org.freedesktop.PackageKit.Transaction.RefusedByPolicy", []interface{}{"transaction belongs to another client"})
+	}
+	return nil
+}
+
+// Resolve ищет пакеты по точным именам
+func (t *PackageKitTransaction) Resolve(sender dbus.Sender, filter uint64, packages []string) *dbus.Error {
+	if err := t.checkOwner(sender); err != nil {
+		return err
+	}
+
+	return t.run(pkRoleResolve, func(ctx context.Context) error {
+		t.setStatus(pkStatusQuery)
+		pkgs, err := t.pk.backend.Resolve(ctx, packages)
+		if err != nil {
+			return err
+		}
+		t.emitPackages(filterPackages(pkgs, filter), false)
+		return nil
+	})
+}
+
+// SearchNames ищет пакеты по подстроке имени
+func (t *PackageKitTransaction) SearchNames(sender dbus.Sender, filter uint64, values []string) *dbus.Error {
+	if err := t.checkOwner(sender); err != nil {
+		return err
+	}
+
+	return t.run(pkRoleSearchName, func(ctx context.Context) error {
+		t.setStatus(pkStatusQuery)
+		pkgs, err := t.pk.backend.SearchNames(ctx, values)
+		if err != nil {
+			return err
+		}
+		t.emitPackages(filterPackages(pkgs, filter), false)
+		return nil
+	})
+}
+
+// GetDetails отправляет сигнал Details для каждого пакета
+func (t *PackageKitTransaction) GetDetails(sender dbus.Sender, packageIDs []string) *dbus.Error {
+	if err := t.checkOwner(sender); err != nil {
+		return err
+	}
+
+	return t.run(pkRoleGetDetails, func(ctx context.Context) error {
+		t.setStatus(pkStatusQuery)
+		for _, id := range packageIDs {
+			name, _, _, repo, err := ParsePackageID(id)
+			if err != nil {
+				return pkError{pkErrorPackageIDInvalid, err}
+			}
+
+			pkg, err := t.pk.backend.GetDetails(ctx, name, repo)
+			if err != nil {
+				return err
+			}
+
+			t.emit("Details", map[string]dbus.Variant{
+				"package-id":  dbus.MakeVariant(t.pk.PackageID(pkg)),
+				"summary":     dbus.MakeVariant(pkg.Summary),
+				"description": dbus.MakeVariant(pkg.Description),
+				"url":         dbus.MakeVariant(pkg.Homepage),
+				"license":     dbus.MakeVariant(strings.Join(pkg.Licenses, " AND ")),
+				"size":        dbus.MakeVariant(pkg.Size),
+				"group":       dbus.MakeVariant(uint32(0)),
+			})
+		}
+		return nil
+	})
+}
+
+// InstallPackages собирает и устанавливает пакеты через Builder.
+// Сборка и установка выполняются от root, поэтому вызывающий клиент
+// должен быть авторизован через PolicyKit.
+func (t *PackageKitTransaction) InstallPackages(sender dbus.Sender, transactionFlags uint64, packageIDs []string) *dbus.Error {
+	if err := t.checkOwner(sender); err != nil {
+		return err
+	}
+	if err := t.pk.authorize(sender, ActionInstall); err != nil {
+		return err
+	}
+
+	return t.run(pkRoleInstallPackages, func(ctx context.Context) error {
+		if transactionFlags&pkTransactionFlagOnlyDownload != 0 {
+			return pkError{pkErrorNotSupported, errors.New("download-only transactions are not supported")}
+		}
+
+		pkgs := make([]PackageInfo, 0, len(packageIDs))
+		for _, id := range packageIDs {
+			name, version, _, repo, err := ParsePackageID(id)
+			if err != nil {
+				return pkError{pkErrorPackageIDInvalid, err}
+			}
+			pkgs = append(pkgs, PackageInfo{Name: name, Version: version, Repository: repo})
+		}
+
+		if transactionFlags&pkTransactionFlagSimulate != 0 {
+			t.emitPackages(pkgs, false)
+			return nil
+		}
+
+		t.setStatus(pkStatusInstall)
+		err := t.pk.backend.InstallPackages(ctx, pkgs, func(percent float64, message string) {
+			t.setPercentage(percent)
+		})
+		if err != nil {
+			return err
+		}
+
+		for i := range pkgs {
+			pkgs[i].Installed = true
+		}
+		t.emitPackages(pkgs, false)
+		return nil
+	})
+}
+
+// GetUpdates возвращает пакеты ALR, для которых доступны обновления
+func (t *PackageKitTransaction) GetUpdates(sender dbus.Sender, filter uint64) *dbus.Error {
+	if err := t.checkOwner(sender); err != nil {
+		return err
+	}
+
+	return t.run(pkRoleGetUpdates, func(ctx context.Context) error {
+		t.setStatus(pkStatusQuery)
+		pkgs, err := t.pk.backend.GetUpdates(ctx)
+		if err != nil {
+			return err
+		}
+		// Обновление предлагается как доступный (не установленный) пакет новой версии
+		for i := range pkgs {
+			pkgs[i].Installed = false
+		}
+		t.emitPackages(pkgs, true)
+		return nil
+	})
+}
+
+// authorize проверяет право вызывающего клиента на выполнение действия
+func (pk *PackageKit) authorize(sender dbus.Sender, action string) *dbus.Error {
+	if pk.authorizer == nil {
+		return dbus.NewError("org.freedesktop.PackageKit.Transaction.RefusedByPolicy", []interface{}{"authorizer not initialized"})
+	}
+
+	ok, err := pk.authorizer.CheckSenderAuthorization(sender, action, nil, true)
+	if err != nil {
+		slog.Error("Authorization check failed", "action", action, "sender", sender, "err", err)
+		return dbus.NewError("org.freedesktop.PackageKit.Transaction.RefusedByPolicy", []interface{}{err.Error()})
+	}
+	if !ok {
+		return dbus.NewError("org.freedesktop.PackageKit.Transaction.RefusedByPolicy", []interface{}{fmt.Sprintf("not authorized to perform %s", action)})
+	}
+
+	return nil
+}
+
+// run запускает операцию транзакции асинхронно и отправляет Finished по завершении
+func (t *PackageKitTransaction) run(role uint32, fn func(ctx context.Context) error) *dbus.Error {
+	t.mu.Lock()
+	if t.started {
+		t.mu.Unlock()
+		return dbus.NewError("org.freedesktop.PackageKit.Transaction.RoleUnknown", []interface{}{"transaction already used"})
+	}
+	t.started = true
+	ctx, cancel := context.WithCancel(t.pk.ctx)
+	t.cancel = cancel
+	t.mu.Unlock()
+
+	t.setProperty("Role", role)
+
+	go func() {
+		defer cancel()
+
+		start := time.Now()
+		t.setStatus(pkStatusRunning)
+
+		exit := pkExitSuccess
+		if err := fn(ctx); err != nil {
+			exit = pkExitFailed
+			if errors.Is(ctx.Err(), context.Canceled) {
+				exit = pkExitCancelled
+			}
+			t.emit("ErrorCode", pkErrorCode(err, exit), err.Error())
+		}
+
+		t.setPercentage(1.0)
+		t.setStatus(pkStatusFinished)
+		t.emit("Finished", exit, uint32(time.Since(start).Milliseconds()))
+		t.emit("Destroy")
+
+		// Даем клиенту время прочитать свойства, затем удаляем транзакцию
+		time.AfterFunc(5*time.Second, func() {
+			t.pk.removeTransaction(t.path)
+		})
+	}()
+
+	return nil
+}
+
+// emitPackages отправляет сигнал Package для каждого пакета
+func (t *PackageKitTransaction) emitPackages(pkgs []PackageInfo, update bool) {
+	for _, pkg := range pkgs {
+		info := pkInfoAvailable
+		switch {
+		case update:
+			info = pkInfoNormal
+		case pkg.Installed:
+			info = pkInfoInstalled
+		}
+
+		id := t.pk.PackageID(pkg)
+		t.setProperty("LastPackage", id)
+		t.emit("Package", info, id, pkg.Summary)
+	}
+}
+
+func (t *PackageKitTransaction) setStatus(status uint32) {
+	t.setProperty("Status", status)
+}
+
+func (t *PackageKitTransaction) setPercentage(percent float64) {
+	t.setProperty("Percentage", uint32(percent*100))
+}
+
+func (t *PackageKitTransaction) setProperty(name string, value interface{}) {
+	if t.properties == nil {
+		return
+	}
+	if err := t.properties.Set(PackageKitTransactionInterface, name, dbus.MakeVariant(value)); err != nil {
+		slog.Debug("Failed to set transaction property", "name", name, "err", err)
+	}
+}
+
+func (t *PackageKitTransaction) emit(name string, values ...interface{}) {
+	if err := t.pk.conn.Emit(t.path, PackageKitTransactionInterface+"."+name, values...); err != nil {
+		slog.Debug("Failed to emit signal", "signal", name, "err", err)
+	}
+}
+
+// IntrospectionData возвращает данные для introspection
+func (t *PackageKitTransaction) IntrospectionData() introspect.Interface {
+	return introspect.Interface{
+		Name: PackageKitTransactionInterface,
+		Methods: []introspect.Method{
+			{
+				Name: "SetHints",
+				Args: []introspect.Arg{
+					{Name: "hints", Type: "as", Direction: "in"},
+				},
+			},
+			{
+				Name: "Cancel",
+			},
+			{
+				Name: "Resolve",
+				Args: []introspect.Arg{
+					{Name: "filter", Type: "t", Direction: "in"},
+					{Name: "packages", Type: "as", Direction: "in"},
+				},
+			},
+			{
+				Name: "SearchNames",
+				Args: []introspect.Arg{
+					{Name: "filter", Type: "t", Direction: "in"},
+					{Name: "values", Type: "as", Direction: "in"},
+				},
+			},
+			{
+				Name: "GetDetails",
+				Args: []introspect.Arg{
+					{Name: "package_ids", Type: "as", Direction: "in"},
+				},
+			},
+			{
+				Name: "InstallPackages",
+				Args: []introspect.Arg{
+					{Name: "transaction_flags", Type: "t", Direction: "in"},
+					{Name: "package_ids", Type: "as", Direction: "in"},
+				},
+			},
+			{
+				Name: "GetUpdates",
+				Args: []introspect.Arg{
+					{Name: "filter", Type: "t", Direction: "in"},
+				},
+			},
+		},
+		Signals: []introspect.Signal{
+			{
+				Name: "Package",
+				Args: []introspect.Arg{
+					{Name: "info", Type: "u"},
+					{Name: "package_id", Type: "s"},
+					{Name: "summary", Type: "s"},
+				},
+			},
+			{
+				Name: "Details",
+				Args: []introspect.Arg{
+					{Name: "data", Type: "a{sv}"},
+				},
+			},
+			{
+				Name: "ErrorCode",
+				Args: []introspect.Arg{
+					{Name: "code", Type: "u"},
+					{Name: "details", Type: "s"},
+				},
+			},
+			{
+				Name: "Finished",
+				Args: []introspect.Arg{
+					{Name: "exit", Type: "u"},
+					{Name: "runtime", Type: "u"},
+				},
+			},
+			{
+				Name: "Destroy",
+			},
+		},
+		Properties: []introspect.Property{
+			{Name: "Role", Type: "u", Access: "read"},
+			{Name: "Status", Type: "u", Access: "read"},
+			{Name: "Percentage", Type: "u", Access: "read"},
+			{Name: "AllowCancel", Type: "b", Access: "read"},
+			{Name: "CallerActive", Type: "b", Access: "read"},
+			{Name: "ElapsedTime", Type: "u", Access: "read"},
+			{Name: "RemainingTime", Type: "u", Access: "read"},
+			{Name: "Speed", Type: "u", Access: "read"},
+			{Name: "LastPackage", Type: "s", Access: "read"},
+			{Name: "Uid", Type: "u", Access: "read"},
+		},
+	}
+}
+
+// pkError - ошибка с явным кодом PackageKit
+type pkError struct {
+	code uint32
+	err  error
+}
+
+func (e pkError) Error() string { return e.err.Error() }
+func (e pkError) Unwrap() error { return e.err }
+
+// pkErrorCode подбирает код ошибки PackageKit
+func pkErrorCode(err error, exit uint32) uint32 {
+	var pe pkError
+	switch {
+	case errors.As(err, &pe):
+		return pe.code
+	case exit == pkExitCancelled:
+		return pkErrorTransactionCancelled
+	case errors.Is(err, ErrPackageNotFound):
+		return pkErrorPackageNotFound
+	default:
+		return pkErrorInternalError
+	}
+}
+
+// filterPackages применяет фильтры installed/~installed
+func filterPackages(pkgs []PackageInfo, filter uint64) []PackageInfo {
+	onlyInstalled := filter&(1<<pkFilterInstalled) != 0
+	onlyAvailable := filter&(1<<pkFilterNotInstalled) != 0
+	if onlyInstalled == onlyAvailable {
+		return pkgs
+	}
+
+	out := make([]PackageInfo, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		if pkg.Installed == onlyInstalled {
+			out = append(out, pkg)
+		}
+	}
+	return out
+}
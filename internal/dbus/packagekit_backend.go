@@ -0,0 +1,183 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"git.alr-pkg.ru/xpamych/vercmp"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/build"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/overrides"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+// serviceBackend реализует PackageKitBackend поверх DBusManager и Builder
+type serviceBackend struct {
+	service *Service
+}
+
+// NewServiceBackend создает бэкенд PackageKit для сервиса ALR
+func NewServiceBackend(service *Service) PackageKitBackend {
+	return &serviceBackend{service: service}
+}
+
+func (b *serviceBackend) SearchNames(ctx context.Context, values []string) ([]PackageInfo, error) {
+	seen := make(map[string]struct{})
+	var out []PackageInfo
+
+	for _, value := range values {
+		pkgs, dbusErr := b.service.manager.SearchPackages(value, nil)
+		if dbusErr != nil {
+			return nil, dbusErr
+		}
+		for _, pkg := range pkgs {
+			key := pkg.Repository + "/" + pkg.Name
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			out = append(out, pkg)
+		}
+	}
+
+	return out, nil
+}
+
+func (b *serviceBackend) Resolve(ctx context.Context, names []string) ([]PackageInfo, error) {
+	deps := b.service.GetDeps()
+	if deps == nil || deps.DB == nil {
+		return nil, errors.New("service not initialized")
+	}
+
+	var out []PackageInfo
+	for _, name := range names {
+		where, args := "name = ?", []any{name}
+		if repo, pkgName, ok := strings.Cut(name, "/"); ok {
+			where, args = "name = ? AND repository = ?", []any{pkgName, repo}
+		}
+
+		pkgs, err := deps.DB.GetPkgs(ctx, where, args...)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pkgs {
+			out = append(out, b.service.manager.convertToPackageInfo(&pkgs[i]))
+		}
+	}
+
+	return out, nil
+}
+
+func (b *serviceBackend) GetDetails(ctx context.Context, name, repository string) (PackageInfo, error) {
+	deps := b.service.GetDeps()
+	if deps == nil || deps.DB == nil {
+		return PackageInfo{}, errors.New("service not initialized")
+	}
+
+	pkg, err := deps.DB.GetPkg("name = ? AND repository = ?", name, repository)
+	if err != nil {
+		return PackageInfo{}, err
+	}
+	if pkg == nil {
+		return PackageInfo{}, fmt.Errorf("%w: %s/%s", ErrPackageNotFound, repository, name)
+	}
+
+	return b.service.manager.convertToPackageInfo(pkg), nil
+}
+
+func (b *serviceBackend) InstallPackages(ctx context.Context, pkgs []PackageInfo, progress func(percent float64, message string)) error {
+	deps := b.service.GetDeps()
+
+	builder, builderClose, err := b.service.NewBuilder()
+	if err != nil {
+		return err
+	}
+	defer builderClose()
+
+	names := make([]string, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		names = append(names, fmt.Sprintf("%s/%s", pkg.Repository, pkg.Name))
+	}
+
+	progress(0.1, "Resolving dependencies...")
+
+	_, err = builder.InstallPkgs(
+		ctx,
+		&build.BuildArgs{
+			Opts: &types.BuildOpts{
+				Interactive: false,
+			},
+			Info:       deps.Info,
+			PkgFormat_: build.GetPkgFormat(deps.Manager),
+		},
+		names,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, pkg := range pkgs {
+		b.service.EmitSignal(GetPackageObjectPath(pkg.Repository, pkg.Name), ManagerInterfaceName, ManagerSignalPackageInstalled, pkg.Name, pkg.Repository, pkg.Version)
+	}
+
+	return nil
+}
+
+func (b *serviceBackend) GetUpdates(ctx context.Context) ([]PackageInfo, error) {
+	deps := b.service.GetDeps()
+	if deps == nil || deps.DB == nil || deps.Manager == nil {
+		return nil, errors.New("service not initialized")
+	}
+
+	installed, err := deps.Manager.ListInstalled(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgIdx := build.RegexpALRPackageName.SubexpIndex("package")
+	repoIdx := build.RegexpALRPackageName.SubexpIndex("repo")
+
+	var out []PackageInfo
+	for fullName, installedVer := range installed {
+		matches := build.RegexpALRPackageName.FindStringSubmatch(fullName)
+		if matches == nil {
+			continue
+		}
+
+		pkg, err := deps.DB.GetPkg("name = ? AND repository = ?", matches[pkgIdx], matches[repoIdx])
+		if err != nil {
+			return nil, err
+		}
+		if pkg == nil {
+			continue
+		}
+
+		repoVer := overrides.VersionPlatformSpecific(pkg.Version, pkg.Release, pkg.Epoch, deps.Info)
+
+		if vercmp.Compare(repoVer, installedVer) == 1 {
+			info := b.service.manager.convertToPackageInfo(pkg)
+			info.Version = repoVer
+			out = append(out, info)
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,371 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package dbus
+
+import (
+	"bufio"
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackend - подставной менеджер пакетов для тестов фасада PackageKit
+type fakeBackend struct {
+	pkgs      []PackageInfo
+	updates   []PackageInfo
+	installed []string
+	mu        sync.Mutex
+}
+
+func (f *fakeBackend) SearchNames(ctx context.Context, values []string) ([]PackageInfo, error) {
+	var out []PackageInfo
+	for _, pkg := range f.pkgs {
+		for _, v := range values {
+			if strings.Contains(pkg.Name, v) {
+				out = append(out, pkg)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeBackend) Resolve(ctx context.Context, names []string) ([]PackageInfo, error) {
+	var out []PackageInfo
+	for _, pkg := range f.pkgs {
+		for _, name := range names {
+			if pkg.Name == name {
+				out = append(out, pkg)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeBackend) GetDetails(ctx context.Context, name, repository string) (PackageInfo, error) {
+	for _, pkg := range f.pkgs {
+		if pkg.Name == name && pkg.Repository == repository {
+			return pkg, nil
+		}
+	}
+	return PackageInfo{}, ErrPackageNotFound
+}
+
+func (f *fakeBackend) InstallPackages(ctx context.Context, pkgs []PackageInfo, progress func(percent float64, message string)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, pkg := range pkgs {
+		f.installed = append(f.installed, pkg.Repository+"/"+pkg.Name)
+	}
+	progress(1.0, "done")
+	return nil
+}
+
+func (f *fakeBackend) installedPkgs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.installed...)
+}
+
+func (f *fakeBackend) GetUpdates(ctx context.Context) ([]PackageInfo, error) {
+	return f.updates, nil
+}
+
+// fakeAuthorizer разрешает действия только отправителям из allowed
+type fakeAuthorizer struct {
+	mu      sync.Mutex
+	allowed map[dbus.Sender]bool
+	checks  []string
+}
+
+func (a *fakeAuthorizer) CheckAuthorization(actionID string, details map[string]string, allowUserInteraction bool) (bool, error) {
+	return false, nil
+}
+
+func (a *fakeAuthorizer) CheckSenderAuthorization(sender dbus.Sender, actionID string, details map[string]string, allowUserInteraction bool) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.checks = append(a.checks, actionID)
+	return a.allowed[sender], nil
+}
+
+// startSessionBus запускает отдельный dbus-daemon и возвращает его адрес
+func startSessionBus(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("dbus-daemon"); err != nil {
+		t.Skip("dbus-daemon is not available")
+	}
+
+	cmd := exec.Command("dbus-daemon", "--session", "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	addr, err := bufio.NewReader(stdout).ReadString('\n')
+	require.NoError(t, err)
+	return strings.TrimSpace(addr)
+}
+
+func connect(t *testing.T, addr string) *dbus.Conn {
+	t.Helper()
+
+	conn, err := dbus.Connect(addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+type pkSignals struct {
+	packages []string
+	details  []map[string]dbus.Variant
+	errors   []uint32
+	exit     uint32
+}
+
+// runTransaction создает транзакцию, вызывает метод и собирает сигналы до Finished
+func runTransaction(t *testing.T, client *dbus.Conn, method string, args ...interface{}) pkSignals {
+	t.Helper()
+
+	var txPath dbus.ObjectPath
+	require.NoError(t, client.Object(PackageKitName, PackageKitObjectPath).
+		Call(PackageKitInterfaceName+".CreateTransaction", 0).Store(&txPath))
+
+	require.NoError(t, client.AddMatchSignal(dbus.WithMatchObjectPath(txPath)))
+	ch := make(chan *dbus.Signal, 32)
+	client.Signal(ch)
+	defer client.RemoveSignal(ch)
+
+	call := client.Object(PackageKitName, txPath).Call(PackageKitTransactionInterface+"."+method, 0, args...)
+	require.NoError(t, call.Err)
+
+	var res pkSignals
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case sig := <-ch:
+			if sig.Path != txPath {
+				continue
+			}
+			switch sig.Name {
+			case PackageKitTransactionInterface + ".Package":
+				res.packages = append(res.packages, sig.Body[1].(string))
+			case PackageKitTransactionInterface + ".Details":
+				res.details = append(res.details, sig.Body[0].(map[string]dbus.Variant))
+			case PackageKitTransactionInterface + ".ErrorCode":
+				res.errors = append(res.errors, sig.Body[0].(uint32))
+			case PackageKitTransactionInterface + ".Finished":
+				res.exit = sig.Body[0].(uint32)
+				return res
+			}
+		case <-timeout:
+			t.Fatalf("transaction %s did not finish", method)
+		}
+	}
+}
+
+func TestPackageKitFacade(t *testing.T) {
+	addr := startSessionBus(t)
+	server := connect(t, addr)
+	client := connect(t, addr)
+
+	backend := &fakeBackend{
+		pkgs: []PackageInfo{
+			{Name: "go-bin", Repository: "alr-default", Version: "1.22.0", Summary: "Go toolchain", Installed: true},
+			{Name: "gopls", Repository: "alr-default", Version: "0.16.0", Summary: "Go language server", Licenses: []string{"BSD-3-Clause"}},
+			{Name: "zed", Repository: "extra", Version: "0.150.0", Summary: "Editor"},
+		},
+		updates: []PackageInfo{
+			{Name: "go-bin", Repository: "alr-default", Version: "1.23.0", Summary: "Go toolchain", Installed: true},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	authorizer := &fakeAuthorizer{allowed: map[dbus.Sender]bool{dbus.Sender(client.Names()[0]): true}}
+	pk := NewPackageKit(ctx, server, backend, authorizer)
+	pk.arch = "amd64"
+	require.NoError(t, pk.Export())
+
+	reply, err := server.RequestName(PackageKitName, dbus.NameFlagDoNotQueue)
+	require.NoError(t, err)
+	require.Equal(t, dbus.RequestNameReplyPrimaryOwner, reply)
+
+	t.Run("BackendName", func(t *testing.T) {
+		v, err := client.Object(PackageKitName, PackageKitObjectPath).GetProperty(PackageKitInterfaceName + ".BackendName")
+		require.NoError(t, err)
+		assert.Equal(t, "alr", v.Value())
+	})
+
+	t.Run("SearchNames", func(t *testing.T) {
+		res := runTransaction(t, client, "SearchNames", uint64(0), []string{"go"})
+		assert.Equal(t, pkExitSuccess, res.exit)
+		assert.Equal(t, []string{
+			"go-bin;1.22.0;amd64;installed:alr-default",
+			"gopls;0.16.0;amd64;alr-default",
+		}, res.packages)
+	})
+
+	t.Run("ResolveNotInstalled", func(t *testing.T) {
+		res := runTransaction(t, client, "Resolve", uint64(1)<<pkFilterNotInstalled, []string{"go-bin", "gopls"})
+		assert.Equal(t, pkExitSuccess, res.exit)
+		assert.Equal(t, []string{"gopls;0.16.0;amd64;alr-default"}, res.packages)
+	})
+
+	t.Run("GetDetails", func(t *testing.T) {
+		res := runTransaction(t, client, "GetDetails", []string{"gopls;0.16.0;amd64;alr-default"})
+		assert.Equal(t, pkExitSuccess, res.exit)
+		require.Len(t, res.details, 1)
+		assert.Equal(t, "Go language server", res.details[0]["summary"].Value())
+		assert.Equal(t, "BSD-3-Clause", res.details[0]["license"].Value())
+	})
+
+	t.Run("GetDetailsNotFound", func(t *testing.T) {
+		res := runTransaction(t, client, "GetDetails", []string{"missing;1.0;amd64;alr-default"})
+		assert.Equal(t, pkExitFailed, res.exit)
+		assert.Equal(t, []uint32{pkErrorPackageNotFound}, res.errors)
+	})
+
+	t.Run("InstallPackages", func(t *testing.T) {
+		res := runTransaction(t, client, "InstallPackages", uint64(0), []string{"zed;0.150.0;amd64;extra"})
+		assert.Equal(t, pkExitSuccess, res.exit)
+		assert.Equal(t, []string{"zed;0.150.0;amd64;installed:extra"}, res.packages)
+		assert.Equal(t, []string{"extra/zed"}, backend.installedPkgs())
+	})
+
+	t.Run("InstallPackagesSimulate", func(t *testing.T) {
+		res := runTransaction(t, client, "InstallPackages", pkTransactionFlagSimulate, []string{"gopls;0.16.0;amd64;alr-default"})
+		assert.Equal(t, pkExitSuccess, res.exit)
+		assert.Equal(t, []string{"extra/zed"}, backend.installedPkgs())
+	})
+
+	t.Run("InstallPackagesInvalidID", func(t *testing.T) {
+		res := runTransaction(t, client, "InstallPackages", uint64(0), []string{"zed"})
+		assert.Equal(t, pkExitFailed, res.exit)
+		assert.Equal(t, []uint32{pkErrorPackageIDInvalid}, res.errors)
+	})
+
+	t.Run("GetUpdates", func(t *testing.T) {
+		res := runTransaction(t, client, "GetUpdates", uint64(0))
+		assert.Equal(t, pkExitSuccess, res.exit)
+		assert.Equal(t, []string{"go-bin;1.23.0;amd64;alr-default"}, res.packages)
+	})
+}
+
+func TestPackageKitInstallUnauthorized(t *testing.T) {
+	addr := startSessionBus(t)
+	server := connect(t, addr)
+	client := connect(t, addr)
+
+	backend := &fakeBackend{
+		pkgs: []PackageInfo{{Name: "zed", Repository: "extra", Version: "0.150.0"}},
+	}
+	authorizer := &fakeAuthorizer{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pk := NewPackageKit(ctx, server, backend, authorizer)
+	pk.arch = "amd64"
+	require.NoError(t, pk.Export())
+
+	reply, err := server.RequestName(PackageKitName, dbus.NameFlagDoNotQueue)
+	require.NoError(t, err)
+	require.Equal(t, dbus.RequestNameReplyPrimaryOwner, reply)
+
+	var txPath dbus.ObjectPath
+	require.NoError(t, client.Object(PackageKitName, PackageKitObjectPath).
+		Call(PackageKitInterfaceName+".CreateTransaction", 0).Store(&txPath))
+
+	call := client.Object(PackageKitName, txPath).
+		Call(PackageKitTransactionInterface+".InstallPackages", 0, uint64(0), []string{"zed;0.150.0;amd64;extra"})
+	var dbusErr dbus.Error
+	require.ErrorAs(t, call.Err, &dbusErr)
+	assert.Equal(t, "org.freedesktop.PackageKit.Transaction.RefusedByPolicy", dbusErr.Name)
+	assert.Equal(t, []string{ActionInstall}, authorizer.checks)
+	assert.Empty(t, backend.installedPkgs())
+}
+
+func TestPackageKitTransactionOwner(t *testing.T) {
+	addr := startSessionBus(t)
+	server := connect(t, addr)
+	owner := connect(t, addr)
+	other := connect(t, addr)
+
+	authorizer := &fakeAuthorizer{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pk := NewPackageKit(ctx, server, &fakeBackend{}, authorizer)
+	require.NoError(t, pk.Export())
+
+	reply, err := server.RequestName(PackageKitName, dbus.NameFlagDoNotQueue)
+	require.NoError(t, err)
+	require.Equal(t, dbus.RequestNameReplyPrimaryOwner, reply)
+
+	var first, second dbus.ObjectPath
+	require.NoError(t, owner.Object(PackageKitName, PackageKitObjectPath).
+		Call(PackageKitInterfaceName+".CreateTransaction", 0).Store(&first))
+	require.NoError(t, owner.Object(PackageKitName, PackageKitObjectPath).
+		Call(PackageKitInterfaceName+".CreateTransaction", 0).Store(&second))
+	assert.NotEqual(t, first, second)
+	assert.Regexp(t, `^/org/freedesktop/PackageKit/tx\d+_[0-9a-f]{16}$`, string(first))
+
+	tx := other.Object(PackageKitName, first)
+	for _, call := range []*dbus.Call{
+		tx.Call(PackageKitTransactionInterface+".Resolve", 0, uint64(0), []string{"zed"}),
+		tx.Call(PackageKitTransactionInterface+".SetHints", 0, []string{"interactive=false"}),
+		tx.Call(PackageKitTransactionInterface+".InstallPackages", 0, uint64(0), []string{"zed;0.150.0;amd64;extra"}),
+		tx.Call(PackageKitTransactionInterface+".Cancel", 0),
+	} {
+		var dbusErr dbus.Error
+		require.ErrorAs(t, call.Err, &dbusErr)
+		assert.Equal(t, "org.freedesktop.PackageKit.Transaction.RefusedByPolicy", dbusErr.Name)
+	}
+	// Чужая транзакция отменяется только с разрешения PolicyKit
+	assert.Equal(t, []string{ActionCancelForeign}, authorizer.checks)
+
+	require.NoError(t, owner.Object(PackageKitName, first).Call(PackageKitTransactionInterface+".Cancel", 0).Err)
+}
+
+func TestParsePackageID(t *testing.T) {
+	name, version, arch, repo, err := ParsePackageID("go-bin;1.22.0;amd64;installed:alr-default")
+	require.NoError(t, err)
+	assert.Equal(t, "go-bin", name)
+	assert.Equal(t, "1.22.0", version)
+	assert.Equal(t, "amd64", arch)
+	assert.Equal(t, "alr-default", repo)
+
+	_, _, _, _, err = ParsePackageID("go-bin;1.22.0;amd64;")
+	assert.Error(t, err)
+
+	_, _, _, _, err = ParsePackageID("go-bin")
+	assert.Error(t, err)
+}
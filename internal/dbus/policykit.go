@@ -38,6 +38,9 @@ const (
 	ActionBuild   = "ru.alr-pkg.build"
 	ActionRefresh = "ru.alr-pkg.refresh"
 	ActionUpgrade = "ru.alr-pkg.upgrade"
+	ActionRepos   = "ru.alr-pkg.manage-repos"
+	// ActionCancelForeign - отмена транзакции PackageKit, созданной другим клиентом
+	ActionCancelForeign = "ru.alr-pkg.cancel-foreign"
 )
 
 // PolicyKitAuthorizer предоставляет интеграцию с PolicyKit
@@ -50,8 +53,49 @@ func NewPolicyKitAuthorizer(conn *dbus.Conn) *PolicyKitAuthorizer {
 	return &PolicyKitAuthorizer{conn: conn}
 }
 
+// polkitSubject - структура subject для PolicyKit (sa{sv})
+type polkitSubject struct {
+	Kind    string
+	Details map[string]dbus.Variant
+}
+
 // CheckAuthorization проверяет авторизацию через PolicyKit
 func (p *PolicyKitAuthorizer) CheckAuthorization(actionID string, details map[string]string, allowUserInteraction bool) (bool, error) {
+	// Создаем subject для текущего процесса
+	pid := os.Getpid()
+	uid := os.Getuid()
+
+	subject := polkitSubject{
+		Kind: "unix-process",
+		Details: map[string]dbus.Variant{
+			"pid":        dbus.MakeVariant(uint32(pid)),
+			"start-time": dbus.MakeVariant(uint64(0)), // PolicyKit определит сам
+			"uid":        dbus.MakeVariant(int32(uid)),
+		},
+	}
+
+	return p.checkSubject(subject, actionID, details, allowUserInteraction, p.fallbackCheck)
+}
+
+// CheckSenderAuthorization проверяет авторизацию вызывающего клиента D-Bus
+// по его уникальному имени на шине
+func (p *PolicyKitAuthorizer) CheckSenderAuthorization(sender dbus.Sender, actionID string, details map[string]string, allowUserInteraction bool) (bool, error) {
+	subject := polkitSubject{
+		Kind: "system-bus-name",
+		Details: map[string]dbus.Variant{
+			"name": dbus.MakeVariant(string(sender)),
+		},
+	}
+
+	fallback := func() bool {
+		return p.senderIsRoot(sender)
+	}
+
+	return p.checkSubject(subject, actionID, details, allowUserInteraction, fallback)
+}
+
+// checkSubject выполняет запрос CheckAuthorization для указанного subject
+func (p *PolicyKitAuthorizer) checkSubject(subject polkitSubject, actionID string, details map[string]string, allowUserInteraction bool, fallback func() bool) (bool, error) {
 	if p.conn == nil {
 		return false, fmt.Errorf("no D-Bus connection")
 	}
@@ -59,17 +103,7 @@ func (p *PolicyKitAuthorizer) CheckAuthorization(actionID string, details map[st
 	// Проверяем доступность PolicyKit
 	if !p.isPolicyKitAvailable() {
 		slog.Debug("PolicyKit not available, falling back to root check")
-		return p.fallbackCheck(), nil
-	}
-
-	// Создаем subject для текущего процесса
-	pid := os.Getpid()
-	uid := os.Getuid()
-
-	subject := map[string]dbus.Variant{
-		"pid":        dbus.MakeVariant(uint32(pid)),
-		"start-time": dbus.MakeVariant(uint64(1)), // Упрощенно
-		"uid":        dbus.MakeVariant(uint32(uid)),
+		return fallback(), nil
 	}
 
 	// Детали авторизации
@@ -96,19 +130,19 @@ func (p *PolicyKitAuthorizer) CheckAuthorization(actionID string, details map[st
 
 	if call.Err != nil {
 		slog.Error("PolicyKit check failed", "action", actionID, "err", call.Err)
-		return p.fallbackCheck(), nil
+		return fallback(), nil
 	}
 
-	// Результат: (is_authorized, is_challenge, details)
+	// Результат: структура (is_authorized, is_challenge, details)
 	var result struct {
 		IsAuthorized bool
 		IsChallenge  bool
-		Details      map[string]dbus.Variant
+		Details      map[string]string
 	}
 
-	if err := call.Store(&result.IsAuthorized, &result.IsChallenge, &result.Details); err != nil {
+	if err := call.Store(&result); err != nil {
 		slog.Error("Failed to parse PolicyKit response", "err", err)
-		return p.fallbackCheck(), nil
+		return fallback(), nil
 	}
 
 	return result.IsAuthorized, nil
@@ -139,6 +173,21 @@ func (p *PolicyKitAuthorizer) fallbackCheck() bool {
 	return os.Getuid() == 0
 }
 
+// senderIsRoot - fallback проверка для клиента D-Bus (root)
+func (p *PolicyKitAuthorizer) senderIsRoot(sender dbus.Sender) bool {
+	if p.conn == nil || sender == "" {
+		return p.fallbackCheck()
+	}
+
+	var uid uint32
+	if err := p.conn.BusObject().Call("org.freedesktop.DBus.GetConnectionUnixUser", 0, string(sender)).Store(&uid); err != nil {
+		slog.Debug("Failed to get sender uid", "sender", sender, "err", err)
+		return false
+	}
+
+	return uid == 0
+}
+
 // CheckInstall проверяет авторизацию для установки
 func (p *PolicyKitAuthorizer) CheckInstall() (bool, error) {
 	return p.CheckAuthorization(ActionInstall, nil, true)
@@ -164,9 +213,15 @@ func (p *PolicyKitAuthorizer) CheckUpgrade() (bool, error) {
 	return p.CheckAuthorization(ActionUpgrade, nil, true)
 }
 
+// CheckRepos проверяет авторизацию для управления репозиториями
+func (p *PolicyKitAuthorizer) CheckRepos(sender dbus.Sender) (bool, error) {
+	return p.CheckSenderAuthorization(sender, ActionRepos, nil, true)
+}
+
 // Authorizer интерфейс для авторизации
 type Authorizer interface {
 	CheckAuthorization(actionID string, details map[string]string, allowUserInteraction bool) (bool, error)
+	CheckSenderAuthorization(sender dbus.Sender, actionID string, details map[string]string, allowUserInteraction bool) (bool, error)
 }
 
 // DefaultAuthorizer возвращает авторизатор по умолчанию
@@ -26,6 +26,7 @@ import (
 	"github.com/godbus/dbus/v5"
 	"github.com/godbus/dbus/v5/introspect"
 
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/build"
 	appbuilder "git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils/app_builder"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/config"
 )
@@ -53,6 +54,13 @@ type Service struct {
 
 	// Notification клиент
 	notifier *Notifier
+
+	// Авторизация вызовов через PolicyKit
+	authorizer Authorizer
+
+	// Фасад PackageKit для графических центров приложений
+	packageKitEnabled bool
+	packageKit        *PackageKit
 }
 
 // NewService создает новый D-Bus сервис
@@ -66,6 +74,12 @@ func NewService() *Service {
 	}
 }
 
+// EnablePackageKit включает экспорт фасада org.freedesktop.PackageKit.
+// Имеет смысл только при отсутствии настоящего демона packagekitd на шине.
+func (s *Service) EnablePackageKit() {
+	s.packageKitEnabled = true
+}
+
 // Init инициализирует ALR зависимости
 func (s *Service) Init() error {
 	ctx := context.Background()
@@ -90,6 +104,9 @@ func (s *Service) Init() error {
 	// Инициализация notifier
 	s.notifier = NewNotifier(s.conn)
 
+	// Инициализация авторизатора
+	s.authorizer = DefaultAuthorizer(s.conn)
+
 	return nil
 }
 
@@ -132,6 +149,12 @@ func (s *Service) Run(sessionBus bool) error {
 		return fmt.Errorf("failed to export objects: %w", err)
 	}
 
+	if s.packageKitEnabled {
+		if err := s.exportPackageKit(); err != nil {
+			slog.Warn("PackageKit facade is not available", "err", err)
+		}
+	}
+
 	slog.Info("D-Bus service running")
 
 	// Ожидание сигнала завершения
@@ -170,6 +193,25 @@ func (s *Service) exportObjects() error {
 	return nil
 }
 
+// exportPackageKit экспортирует фасад PackageKit и запрашивает его well-known name
+func (s *Service) exportPackageKit() error {
+	s.packageKit = NewPackageKit(s.ctx, s.conn, NewServiceBackend(s), s.authorizer)
+	if err := s.packageKit.Export(); err != nil {
+		return err
+	}
+
+	reply, err := s.conn.RequestName(PackageKitName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return fmt.Errorf("failed to request name: %w", err)
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return fmt.Errorf("name %s already taken", PackageKitName)
+	}
+
+	slog.Info("Acquired D-Bus name", "name", PackageKitName)
+	return nil
+}
+
 // Stop останавливает сервис
 func (s *Service) Stop() {
 	s.cancel()
@@ -190,6 +232,46 @@ func (s *Service) GetConfig() *config.ALRConfig {
 	return s.config
 }
 
+// GetAuthorizer возвращает авторизатор
+func (s *Service) GetAuthorizer() Authorizer {
+	return s.authorizer
+}
+
+// NewBuilder создает Builder с безопасными исполнителями плагинов.
+// Возвращаемую функцию нужно вызвать после завершения работы с Builder.
+func (s *Service) NewBuilder() (*build.Builder, func(), error) {
+	installer, installerClose, err := build.GetSafeInstaller()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get installer: %w", err)
+	}
+
+	scripter, scripterClose, err := build.GetSafeScriptExecutor()
+	if err != nil {
+		installerClose()
+		return nil, nil, fmt.Errorf("failed to get scripter: %w", err)
+	}
+
+	closeFn := func() {
+		scripterClose()
+		installerClose()
+	}
+
+	builder, err := build.NewMainBuilder(
+		s.deps.Cfg,
+		s.deps.Manager,
+		s.deps.Repos,
+		scripter,
+		installer,
+		s.deps.DB,
+	)
+	if err != nil {
+		closeFn()
+		return nil, nil, fmt.Errorf("failed to create builder: %w", err)
+	}
+
+	return builder, closeFn, nil
+}
+
 // NextJobID возвращает следующий ID задачи
 func (s *Service) NextJobID() uint32 {
 	return atomic.AddUint32(&s.jobIDCounter, 1)
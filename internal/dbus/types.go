@@ -50,6 +50,7 @@ const (
 	JobTypeBuild   JobType = "build"
 	JobTypeUpgrade JobType = "upgrade"
 	JobTypeRefresh JobType = "refresh"
+	JobTypeAddRepo JobType = "add-repository"
 )
 
 // JobStatus статус задачи
@@ -179,6 +180,11 @@ func GetPackageObjectPath(repo, name string) dbus.ObjectPath {
 	return dbus.ObjectPath(DBusObjectPath + "/packages/" + repo + "/" + name)
 }
 
+// GetALRPackageName возвращает имя пакета в системном пакетном менеджере (name+repo)
+func GetALRPackageName(name, repo string) string {
+	return name + "+" + repo
+}
+
 // GetJobObjectPath возвращает object path для задачи
 func GetJobObjectPath(id uint32) dbus.ObjectPath {
 	return dbus.ObjectPath(DBusObjectPath + "/jobs/" + string(rune(id)))
@@ -20,6 +20,7 @@
 package gen
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
@@ -91,6 +92,12 @@ type aurResult struct {
 	HasPatches    bool     `json:"-"` // Есть ли патчи
 	Architectures []string `json:"-"` // Поддерживаемые архитектуры
 
+	// Переопределения для архитектур ALR из source_<arch>, depends_<arch> и т.д.
+	ArchSources     map[string][]string `json:"-"`
+	ArchChecksums   map[string][]string `json:"-"`
+	ArchDepends     map[string][]string `json:"-"`
+	ArchMakeDepends map[string][]string `json:"-"`
+
 	// Автоматически определяемые файлы для install-* команд
 	BinaryFiles     []string          `json:"-"` // Исполняемые файлы для install-binary
 	LicenseFiles    []string          `json:"-"` // Лицензионные файлы для install-license
@@ -114,28 +121,20 @@ func (r aurResult) LicenseString() string {
 }
 
 func (r aurResult) DependsString() string {
-	if len(r.Depends) == 0 {
-		return ""
-	}
-	deps := make([]string, len(r.Depends))
-	for i, d := range r.Depends {
-		// Убираем версионные ограничения для простоты
-		dep := strings.Split(d, ">=")[0]
-		dep = strings.Split(dep, "<=")[0]
-		dep = strings.Split(dep, "=")[0]
-		dep = strings.Split(dep, ">")[0]
-		dep = strings.Split(dep, "<")[0]
-		deps[i] = fmt.Sprintf("'%s'", dep)
-	}
-	return strings.Join(deps, " ")
+	return r.FormatDeps(r.Depends)
 }
 
 func (r aurResult) MakeDependsString() string {
-	if len(r.MakeDepends) == 0 {
+	return r.FormatDeps(r.MakeDepends)
+}
+
+// FormatDeps форматирует список зависимостей для alr.sh
+func (r aurResult) FormatDeps(depends []string) string {
+	if len(depends) == 0 {
 		return ""
 	}
-	deps := make([]string, len(r.MakeDepends))
-	for i, d := range r.MakeDepends {
+	deps := make([]string, len(depends))
+	for i, d := range depends {
 		// Убираем версионные ограничения для простоты
 		dep := strings.Split(d, ">=")[0]
 		dep = strings.Split(dep, "<=")[0]
@@ -270,116 +269,6 @@ func fetchPKGBUILD(packageBase string) (string, error) {
 	return string(data), nil
 }
 
-// parseSources извлекает источники из PKGBUILD
-func parseSources(pkgbuild string) []string {
-	var sources []string
-
-	// Регулярное выражение для поиска массива source
-	// Поддерживает как однострочные, так и многострочные определения
-	sourceRegex := regexp.MustCompile(`(?ms)source=\((.*?)\)`)
-	matches := sourceRegex.FindStringSubmatch(pkgbuild)
-
-	if len(matches) > 1 {
-		// Извлекаем содержимое массива source
-		sourceContent := matches[1]
-
-		// Разбираем элементы массива
-		// Учитываем кавычки и переносы строк
-		elemRegex := regexp.MustCompile(`['"]([^'"]+)['"]`)
-		elements := elemRegex.FindAllStringSubmatch(sourceContent, -1)
-
-		for _, elem := range elements {
-			if len(elem) > 1 {
-				source := elem[1]
-				// Заменяем переменные версии
-				source = strings.ReplaceAll(source, "$pkgver", "${version}")
-				source = strings.ReplaceAll(source, "${pkgver}", "${version}")
-				source = strings.ReplaceAll(source, "$pkgname", "${name}")
-				source = strings.ReplaceAll(source, "${pkgname}", "${name}")
-				// Обрабатываем другие переменные (упрощенно)
-				source = strings.ReplaceAll(source, "$_commit", "${_commit}")
-				sources = append(sources, source)
-			}
-		}
-	}
-
-	// Если источники не найдены в source=(), проверяем source_x86_64 и другие архитектуры
-	if len(sources) == 0 {
-		archSourceRegex := regexp.MustCompile(`(?ms)source_(?:x86_64|aarch64)=\((.*?)\)`)
-		matches = archSourceRegex.FindStringSubmatch(pkgbuild)
-		if len(matches) > 1 {
-			sourceContent := matches[1]
-			elemRegex := regexp.MustCompile(`['"]([^'"]+)['"]`)
-			elements := elemRegex.FindAllStringSubmatch(sourceContent, -1)
-
-			for _, elem := range elements {
-				if len(elem) > 1 {
-					source := elem[1]
-					source = strings.ReplaceAll(source, "$pkgver", "${version}")
-					source = strings.ReplaceAll(source, "${pkgver}", "${version}")
-					source = strings.ReplaceAll(source, "$pkgname", "${name}")
-					source = strings.ReplaceAll(source, "${pkgname}", "${name}")
-					sources = append(sources, source)
-				}
-			}
-		}
-	}
-
-	return sources
-}
-
-// parseChecksums извлекает контрольные суммы из PKGBUILD
-func parseChecksums(pkgbuild string) []string {
-	var checksums []string
-
-	// Пробуем разные типы контрольных сумм
-	for _, hashType := range []string{"sha256sums", "sha512sums", "sha1sums", "md5sums", "b2sums"} {
-		regex := regexp.MustCompile(fmt.Sprintf(`(?ms)%s=\((.*?)\)`, hashType))
-		matches := regex.FindStringSubmatch(pkgbuild)
-
-		if len(matches) > 1 {
-			content := matches[1]
-			elemRegex := regexp.MustCompile(`['"]([^'"]+)['"]`)
-			elements := elemRegex.FindAllStringSubmatch(content, -1)
-
-			for _, elem := range elements {
-				if len(elem) > 1 {
-					checksums = append(checksums, elem[1])
-				}
-			}
-
-			if len(checksums) > 0 {
-				break // Используем первый найденный тип хешей
-			}
-		}
-	}
-
-	return checksums
-}
-
-// parseFunctions извлекает функции build(), package() и prepare() из PKGBUILD
-func parseFunctions(pkgbuild string) (buildFunc, packageFunc, prepareFunc string) {
-	// Извлекаем функцию build()
-	buildRegex := regexp.MustCompile(`(?ms)^build\(\)\s*\{(.*?)^\}`)
-	if matches := buildRegex.FindStringSubmatch(pkgbuild); len(matches) > 1 {
-		buildFunc = strings.TrimSpace(matches[1])
-	}
-
-	// Извлекаем функцию package()
-	packageRegex := regexp.MustCompile(`(?ms)^package\(\)\s*\{(.*?)^\}`)
-	if matches := packageRegex.FindStringSubmatch(pkgbuild); len(matches) > 1 {
-		packageFunc = strings.TrimSpace(matches[1])
-	}
-
-	// Извлекаем функцию prepare()
-	prepareRegex := regexp.MustCompile(`(?ms)^prepare\(\)\s*\{(.*?)^\}`)
-	if matches := prepareRegex.FindStringSubmatch(pkgbuild); len(matches) > 1 {
-		prepareFunc = strings.TrimSpace(matches[1])
-	}
-
-	return buildFunc, packageFunc, prepareFunc
-}
-
 // detectInstallableFiles анализирует PKGBUILD и определяет файлы для install-* команд
 func detectInstallableFiles(pkg *aurResult, pkgbuild string) {
 	// Инициализируем карту для файлов автодополнения
@@ -576,6 +465,36 @@ func hasGoSources(sources []string) bool {
 	return false
 }
 
+// applyPKGBUILDInfo переносит значения из выполненного PKGBUILD в данные пакета
+func applyPKGBUILDInfo(pkg *aurResult, info *pkgbuildInfo) {
+	// Версия из AUR API включает pkgrel, а в ALR он задаётся отдельно
+	if info.Version != "" {
+		pkg.Version = info.Version
+	}
+
+	pkg.Sources = info.Sources
+	pkg.Checksums = info.Checksums
+	pkg.ArchSources = info.ArchSources
+	pkg.ArchChecksums = info.ArchChecksums
+	pkg.ArchDepends = info.ArchDepends
+	pkg.ArchMakeDepends = info.ArchMakeDepends
+	pkg.BuildFunc = info.BuildFunc
+	pkg.PackageFunc = info.PackageFunc
+	pkg.PrepareFunc = info.PrepareFunc
+
+	// Если общих источников нет, но есть источники для архитектур,
+	// базовыми считаются источники первой из них
+	if len(pkg.Sources) == 0 {
+		for _, arch := range info.Architectures {
+			if sources, ok := info.ArchSources[arch]; ok {
+				pkg.Sources = sources
+				pkg.Checksums = info.ArchChecksums[arch]
+				break
+			}
+		}
+	}
+}
+
 // AUR генерирует шаблон alr.sh на основе пакета из AUR
 func AUR(w io.Writer, opts AUROptions) error {
 	// Создаем шаблон с функциями
@@ -638,13 +557,19 @@ func AUR(w io.Writer, opts AUROptions) error {
 		pkg.Sources = []string{fmt.Sprintf("%s::git+%s", pkg.Name, pkg.GitURL())}
 		pkg.Checksums = []string{"SKIP"}
 	} else {
-		// Извлекаем источники из PKGBUILD
-		pkg.Sources = parseSources(pkgbuild)
-		pkg.Checksums = parseChecksums(pkgbuild)
-		pkg.BuildFunc, pkg.PackageFunc, pkg.PrepareFunc = parseFunctions(pkgbuild)
+		// Выполняем PKGBUILD для получения источников, зависимостей и функций
+		info, err := parsePKGBUILD(context.Background(), pkgbuild)
+		if err != nil {
+			fmt.Fprintf(w, "# WARNING: Could not evaluate PKGBUILD: %v\n", err)
+			info = &pkgbuildInfo{}
+		}
+		applyPKGBUILDInfo(&pkg, info)
 
 		// Определяем тип пакета
 		detectPackageType(&pkg, pkgbuild)
+		if len(info.Architectures) > 0 {
+			pkg.Architectures = info.Architectures
+		}
 
 		// Определяем файлы для install-* команд
 		detectInstallableFiles(&pkg, pkgbuild)
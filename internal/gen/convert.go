@@ -0,0 +1,132 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cpu"
+)
+
+// Встраиваем шаблон для скриптов, преобразованных из пакетов других дистрибутивов
+//
+//go:embed tmpls/convert.tmpl.sh
+var convertTmpl string
+
+// defaultArches - архитектуры для пакетов, собираемых из исходного кода
+var defaultArches = []string{"amd64", "arm64"}
+
+// convertedScript - данные для шаблона convert.tmpl.sh, общие для
+// преобразования spec-файлов и исходных пакетов Debian
+type convertedScript struct {
+	Origin        string
+	Name          string
+	Version       string
+	Release       string
+	Description   string
+	Homepage      string
+	Maintainer    string
+	License       []string
+	Architectures []string
+	Deps          []string
+	BuildDeps     []string
+	Provides      []string
+	Conflicts     []string
+	Replaces      []string
+	Sources       []string
+	Checksums     []string
+
+	// Notes - замечания о непреобразованных частях, выводятся в начале скрипта
+	Notes []string
+	// Тела функций, по одной команде или комментарию в строке
+	Prepare []string
+	Build   []string
+	Package []string
+}
+
+// note добавляет замечание о части исходного пакета, которую не удалось преобразовать
+func (s *convertedScript) note(format string, args ...any) {
+	s.Notes = append(s.Notes, fmt.Sprintf(format, args...))
+}
+
+// render выполняет шаблон convert.tmpl.sh
+func (s *convertedScript) render(w io.Writer) error {
+	tmpl, err := template.New("convert").
+		Funcs(funcs).
+		Funcs(template.FuncMap{
+			"quote": shellQuote,
+			"lines": func(lines []string) string {
+				return "\t" + strings.Join(lines, "\n\t")
+			},
+		}).
+		Parse(convertTmpl)
+	if err != nil {
+		return err
+	}
+
+	if s.Release == "" {
+		s.Release = "1"
+	}
+	if len(s.Architectures) == 0 {
+		s.Architectures = defaultArches
+	}
+	if len(s.License) == 0 {
+		s.License = []string{"custom:Unknown"}
+	}
+	for len(s.Checksums) < len(s.Sources) {
+		s.Checksums = append(s.Checksums, "SKIP")
+	}
+
+	return tmpl.Execute(w, s)
+}
+
+// shellQuote заключает строку в одинарные кавычки для оболочки
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// untranslated возвращает закомментированную строку, которую не удалось преобразовать
+func untranslated(kind, line string) string {
+	return fmt.Sprintf("# TODO (%s): %s", kind, strings.TrimSpace(line))
+}
+
+// convertArches преобразует названия архитектур другого дистрибутива в названия ALR.
+// Пакеты, не зависящие от архитектуры, отмечаются как 'all'.
+func convertArches(arches []string) []string {
+	var out []string
+	for _, arch := range arches {
+		switch arch {
+		case "all", "noarch":
+			return []string{"all"}
+		case "any", "linux-any":
+			return defaultArches
+		}
+		if alrArch, ok := cpu.Canonical(strings.TrimPrefix(arch, "linux-")); ok && !contains(out, alrArch) {
+			out = append(out, alrArch)
+		}
+	}
+	return out
+}
+
+// localSource возвращает источник для файла, лежащего рядом со скриптом
+func localSource(name string) string {
+	return "local:///" + strings.TrimPrefix(name, "/")
+}
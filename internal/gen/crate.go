@@ -0,0 +1,131 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/webapi"
+)
+
+// Встраиваем шаблон для crates.io
+//
+//go:embed tmpls/crate.tmpl.sh
+var crateTmpl string
+
+// CratesIOURL - адрес crates.io по умолчанию
+const CratesIOURL = webapi.CratesIOURL
+
+// CrateOptions содержит параметры для генерации шаблона Rust crate
+type CrateOptions struct {
+	Name        string // Имя crate
+	Version     string // Версия (опционально, если не указана - берется последняя стабильная)
+	Description string // Описание (опционально, по умолчанию из crates.io)
+	BaseURL     string // Адрес API crates.io (опционально, для тестов и зеркал)
+}
+
+// crateTmplData - данные для шаблона crate.tmpl.sh
+type crateTmplData struct {
+	Name        string
+	Version     string
+	Description string
+	Homepage    string
+	License     string
+	Source      string
+	Checksum    string
+	Bins        []string
+}
+
+// Crate генерирует шаблон alr.sh для Rust crate из crates.io
+func Crate(w io.Writer, opts CrateOptions) error {
+	tmpl, err := template.New("crate").
+		Funcs(funcs).
+		Parse(crateTmpl)
+	if err != nil {
+		return err
+	}
+
+	baseURL := strings.TrimSuffix(opts.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = CratesIOURL
+	}
+
+	resp, err := webapi.FetchCrate(context.Background(), baseURL, opts.Name)
+	if err != nil {
+		return err
+	}
+
+	version := opts.Version
+	if version == "" {
+		version = resp.Crate.MaxStableVersion
+	}
+	if version == "" {
+		version = resp.Crate.NewestVersion
+	}
+
+	var ver *webapi.CrateVersion
+	for i := range resp.Versions {
+		if resp.Versions[i].Num == version {
+			ver = &resp.Versions[i]
+			break
+		}
+	}
+	if ver == nil {
+		return fmt.Errorf("crates.io: version %s of crate %s not found", version, opts.Name)
+	}
+	if ver.Yanked {
+		return fmt.Errorf("crates.io: version %s of crate %s is yanked", version, opts.Name)
+	}
+	if len(ver.BinNames) == 0 {
+		return fmt.Errorf("crates.io: crate %s %s has no binary targets", opts.Name, version)
+	}
+
+	data := crateTmplData{
+		Name:        resp.Crate.Name,
+		Version:     ver.Num,
+		Description: resp.Crate.Description,
+		Homepage:    resp.Crate.Homepage,
+		License:     ver.License,
+		Checksum:    ver.Checksum,
+		Bins:        ver.BinNames,
+	}
+	if opts.Description != "" {
+		data.Description = opts.Description
+	}
+	// Описание подставляется в одинарных кавычках
+	data.Description = strings.Join(strings.Fields(data.Description), " ")
+	data.Description = strings.ReplaceAll(data.Description, "'", `'\''`)
+	if data.Homepage == "" {
+		data.Homepage = resp.Crate.Repository
+	}
+	if data.License == "" {
+		data.License = "custom:Unknown"
+	}
+
+	// Путь загрузки содержит версию, заменяем её на переменную, чтобы
+	// скрипт оставался корректным при обновлении. Файл .crate - это tar.gz,
+	// поэтому явно задаём имя для распаковки.
+	dlPath := strings.ReplaceAll(ver.DlPath, "/"+ver.Num+"/", "/${version}/")
+	data.Source = fmt.Sprintf("%s%s?~name=%s-${version}.tar.gz", baseURL, dlPath, data.Name)
+
+	return tmpl.Execute(w, data)
+}
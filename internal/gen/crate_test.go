@@ -0,0 +1,114 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCrateResponse = `{
+  "crate": {
+    "name": "ripgrep",
+    "description": "ripgrep is a line-oriented search tool that\nrecursively searches the current directory for a regex pattern.",
+    "homepage": null,
+    "repository": "https://github.com/BurntSushi/ripgrep",
+    "max_stable_version": "14.1.1",
+    "newest_version": "15.0.0-beta"
+  },
+  "versions": [
+    {"num": "15.0.0-beta", "checksum": "ffff", "license": "Unlicense OR MIT", "dl_path": "/api/v1/crates/ripgrep/15.0.0-beta/download", "bin_names": ["rg"], "yanked": false},
+    {"num": "14.1.1", "checksum": "4cd1d3d5", "license": "Unlicense OR MIT", "dl_path": "/api/v1/crates/ripgrep/14.1.1/download", "bin_names": ["rg"], "yanked": false},
+    {"num": "14.1.0", "checksum": "eeee", "license": "Unlicense OR MIT", "dl_path": "/api/v1/crates/ripgrep/14.1.0/download", "bin_names": ["rg"], "yanked": true},
+    {"num": "0.1.0", "checksum": "dddd", "license": null, "dl_path": "/api/v1/crates/ripgrep/0.1.0/download", "bin_names": [], "yanked": false}
+  ]
+}`
+
+func newCratesStub(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" {
+			http.Error(w, "missing user agent", http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/api/v1/crates/ripgrep" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testCrateResponse))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCrate(t *testing.T) {
+	srv := newCratesStub(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, Crate(&buf, CrateOptions{Name: "ripgrep", BaseURL: srv.URL}))
+	out := buf.String()
+
+	assert.Contains(t, out, "name='ripgrep'\n")
+	assert.Contains(t, out, "version='14.1.1'\n")
+	assert.Contains(t, out, "desc='ripgrep is a line-oriented search tool that recursively searches the current directory for a regex pattern.'\n")
+	assert.Contains(t, out, "homepage='https://github.com/BurntSushi/ripgrep'\n")
+	assert.Contains(t, out, "license=('Unlicense OR MIT')\n")
+	assert.Contains(t, out, `sources=("`+srv.URL+`/api/v1/crates/ripgrep/${version}/download?~name=ripgrep-${version}.tar.gz")`)
+	assert.Contains(t, out, "checksums=('4cd1d3d5')\n")
+	assert.Contains(t, out, "build_deps_arch=('rust')\n")
+	assert.Contains(t, out, "\tcargo build --release --locked\n")
+	assert.Contains(t, out, "\tinstall-binary \"target/release/rg\"\n}")
+}
+
+func TestCrateOptions(t *testing.T) {
+	srv := newCratesStub(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, Crate(&buf, CrateOptions{
+		Name:        "ripgrep",
+		Version:     "15.0.0-beta",
+		Description: "Rust's grep",
+		BaseURL:     srv.URL + "/",
+	}))
+	assert.Contains(t, buf.String(), "version='15.0.0-beta'\n")
+	assert.Contains(t, buf.String(), `desc='Rust'\''s grep'`)
+}
+
+func TestCrateErrors(t *testing.T) {
+	srv := newCratesStub(t)
+
+	for _, tc := range []struct {
+		opts CrateOptions
+		err  string
+	}{
+		{CrateOptions{Name: "missing"}, "not found on crates.io"},
+		{CrateOptions{Name: "ripgrep", Version: "9.9.9"}, "version 9.9.9 of crate ripgrep not found"},
+		{CrateOptions{Name: "ripgrep", Version: "14.1.0"}, "is yanked"},
+		{CrateOptions{Name: "ripgrep", Version: "0.1.0"}, "has no binary targets"},
+	} {
+		tc.opts.BaseURL = srv.URL
+		var buf bytes.Buffer
+		assert.ErrorContains(t, Crate(&buf, tc.opts), tc.err)
+	}
+}
@@ -0,0 +1,596 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mholt/archiver/v4"
+)
+
+// DebianOptions содержит параметры для преобразования исходного пакета Debian
+type DebianOptions struct {
+	Path string // Каталог с debian/ или файл .dsc
+}
+
+// debianFiles - файлы из каталога debian/, используемые при преобразовании
+var debianFiles = []string{"control", "rules", "changelog", "copyright", "patches/series"}
+
+// debianPackage - прочитанный исходный пакет Debian
+type debianPackage struct {
+	// files - содержимое файлов debian/ по относительному пути
+	files map[string]string
+	// sources и checksums - файлы исходного кода из .dsc
+	sources   []string
+	checksums []string
+}
+
+// Debian преобразует исходный пакет Debian в шаблон alr.sh
+func Debian(w io.Writer, opts DebianOptions) error {
+	var (
+		pkg *debianPackage
+		err error
+	)
+	if strings.HasSuffix(opts.Path, ".dsc") {
+		pkg, err = readDSC(opts.Path)
+	} else {
+		pkg, err = readDebianDir(opts.Path)
+	}
+	if err != nil {
+		return err
+	}
+
+	script, err := convertDebian(pkg)
+	if err != nil {
+		return err
+	}
+	script.Origin = "Debian source package " + script.Name
+
+	return script.render(w)
+}
+
+// readDebianDir читает файлы из каталога исходного кода или из самого каталога debian/
+func readDebianDir(dir string) (*debianPackage, error) {
+	if _, err := os.Stat(filepath.Join(dir, "debian", "control")); err == nil {
+		dir = filepath.Join(dir, "debian")
+	}
+
+	pkg := &debianPackage{files: map[string]string{}}
+	for _, name := range debianFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		pkg.files[name] = string(data)
+	}
+
+	if _, ok := pkg.files["control"]; !ok {
+		return nil, fmt.Errorf("%s: debian/control not found", dir)
+	}
+	return pkg, nil
+}
+
+// readDSC читает файл .dsc и каталог debian/ из архива, лежащего рядом с ним
+func readDSC(dscPath string) (*debianPackage, error) {
+	fl, err := os.Open(dscPath)
+	if err != nil {
+		return nil, err
+	}
+	defer fl.Close()
+
+	stanzas, err := parseDeb822(fl)
+	if err != nil {
+		return nil, err
+	}
+	if len(stanzas) == 0 {
+		return nil, fmt.Errorf("%s: empty .dsc file", dscPath)
+	}
+	dsc := stanzas[0]
+
+	pkg := &debianPackage{files: map[string]string{}}
+	debianTar := ""
+	for _, line := range strings.Split(dsc["checksums-sha256"], "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		sum, name := fields[0], fields[2]
+		switch {
+		case strings.Contains(name, ".debian.tar."):
+			debianTar = name
+		case strings.HasSuffix(name, ".asc"):
+		default:
+			pkg.sources = append(pkg.sources, localSource(name))
+			pkg.checksums = append(pkg.checksums, sum)
+		}
+	}
+
+	// В нативных пакетах каталог debian/ находится в самом архиве исходного кода
+	if debianTar == "" && len(pkg.sources) == 1 {
+		debianTar = strings.TrimPrefix(pkg.sources[0], "local:///")
+	}
+	if debianTar == "" {
+		return nil, fmt.Errorf("%s: no debian archive listed in Checksums-Sha256", dscPath)
+	}
+	if err := readDebianTar(filepath.Join(filepath.Dir(dscPath), debianTar), pkg.files); err != nil {
+		return nil, err
+	}
+
+	if _, ok := pkg.files["control"]; !ok {
+		return nil, fmt.Errorf("%s: debian/control not found", debianTar)
+	}
+	// Без debian/changelog версия берётся из самого .dsc
+	if _, ok := pkg.files["changelog"]; !ok && dsc["version"] != "" {
+		pkg.files["changelog"] = fmt.Sprintf("%s (%s) unstable; urgency=medium\n", dsc["source"], dsc["version"])
+	}
+	return pkg, nil
+}
+
+// readDebianTar извлекает нужные файлы debian/ из архива
+func readDebianTar(archive string, files map[string]string) error {
+	fl, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer fl.Close()
+
+	format, _, err := archiver.Identify(filepath.Base(archive), fl)
+	if err != nil {
+		return fmt.Errorf("%s: %w", archive, err)
+	}
+	extractor, ok := format.(archiver.Extractor)
+	if !ok {
+		return fmt.Errorf("%s: not an archive", archive)
+	}
+	if _, err := fl.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	return extractor.Extract(context.Background(), fl, nil, func(ctx context.Context, f archiver.File) error {
+		name := strings.TrimPrefix(f.NameInArchive, "./")
+		// В нативных архивах debian/ лежит внутри каталога верхнего уровня
+		if top, rest, ok := strings.Cut(name, "/debian/"); ok && !strings.Contains(top, "/") {
+			name = "debian/" + rest
+		}
+		rel, ok := strings.CutPrefix(name, "debian/")
+		if !ok || !contains(debianFiles, rel) {
+			return nil
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		files[rel] = string(data)
+		return nil
+	})
+}
+
+// parseDeb822 разбирает файл в формате deb822 (control, .dsc, copyright).
+// Имена полей приводятся к нижнему регистру, подпись PGP отбрасывается.
+func parseDeb822(r io.Reader) ([]map[string]string, error) {
+	var (
+		stanzas []map[string]string
+		current map[string]string
+		field   string
+		inPGP   bool
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case line == "-----BEGIN PGP SIGNED MESSAGE-----":
+			inPGP = true
+			continue
+		case line == "-----BEGIN PGP SIGNATURE-----":
+			return stanzas, nil
+		case inPGP:
+			// Заголовки подписи (Hash: ...) заканчиваются пустой строкой
+			if strings.TrimSpace(line) == "" {
+				inPGP = false
+			}
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		case strings.TrimSpace(line) == "":
+			current = nil
+			continue
+		case line[0] == ' ' || line[0] == '\t':
+			if current == nil || field == "" {
+				continue
+			}
+			value := strings.TrimSpace(line)
+			if value == "." {
+				value = ""
+			}
+			current[field] += "\n" + value
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid line: %q", line)
+		}
+		if current == nil {
+			current = map[string]string{}
+			stanzas = append(stanzas, current)
+		}
+		field = strings.ToLower(strings.TrimSpace(name))
+		current[field] = strings.TrimSpace(value)
+	}
+	return stanzas, scanner.Err()
+}
+
+var debianChangelogRegex = regexp.MustCompile(`^(\S+) \(([^)]+)\)`)
+
+func convertDebian(pkg *debianPackage) (*convertedScript, error) {
+	stanzas, err := parseDeb822(strings.NewReader(pkg.files["control"]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read debian/control: %w", err)
+	}
+	if len(stanzas) < 2 || stanzas[0]["source"] == "" {
+		return nil, fmt.Errorf("debian/control has no source or binary package stanzas")
+	}
+	source := stanzas[0]
+	binary := stanzas[1]
+	for _, stanza := range stanzas[1:] {
+		if stanza["package"] == source["source"] {
+			binary = stanza
+		}
+	}
+
+	script := &convertedScript{
+		Name:       source["source"],
+		Homepage:   source["homepage"],
+		Maintainer: source["maintainer"],
+		Sources:    pkg.sources,
+		Checksums:  pkg.checksums,
+	}
+	for _, stanza := range stanzas[1:] {
+		if stanza["package"] != binary["package"] {
+			script.note("binary package %q is not converted", stanza["package"])
+		}
+	}
+
+	m := debianChangelogRegex.FindStringSubmatch(pkg.files["changelog"])
+	if m == nil {
+		return nil, fmt.Errorf("failed to read version from debian/changelog")
+	}
+	script.Version, script.Release = splitDebianVersion(script, m[2])
+
+	script.Description, _, _ = strings.Cut(binary["description"], "\n")
+	script.Architectures = convertArches(strings.Fields(binary["architecture"]))
+	script.License = debianLicense(script, pkg.files["copyright"])
+
+	script.BuildDeps = debianDeps(script, source["build-depends"]+","+source["build-depends-arch"]+","+source["build-depends-indep"])
+	script.Deps = debianDeps(script, binary["depends"]+","+binary["pre-depends"])
+	script.Provides = debianDeps(script, binary["provides"])
+	script.Conflicts = debianDeps(script, binary["conflicts"]+","+binary["breaks"])
+	script.Replaces = debianDeps(script, binary["replaces"])
+
+	if len(script.Sources) == 0 {
+		script.note("add the upstream source archive to sources")
+	}
+
+	dir := `"$srcdir/${name}-${version}"`
+	if patches := debianPatches(pkg.files["patches/series"]); len(patches) > 0 {
+		script.note("copy the patches from debian/patches next to alr.sh")
+		script.Prepare = []string{"cd " + dir}
+		for _, patch := range patches {
+			script.Sources = append(script.Sources, localSource(path.Base(patch)))
+			script.Prepare = append(script.Prepare, fmt.Sprintf(`patch -p1 -i "$srcdir/%s"`, path.Base(patch)))
+		}
+	}
+
+	rules := parseDebianRules(pkg.files["rules"])
+	rules.convert(script, binary["package"], source["build-depends"])
+	script.Build = append([]string{"cd " + dir}, script.Build...)
+	script.Package = append([]string{"cd " + dir}, script.Package...)
+
+	return script, nil
+}
+
+// splitDebianVersion отделяет эпоху и ревизию Debian от версии исходного кода
+func splitDebianVersion(script *convertedScript, version string) (string, string) {
+	if epoch, rest, ok := strings.Cut(version, ":"); ok {
+		script.note("epoch %s is not converted (set epoch=%s if needed)", epoch, epoch)
+		version = rest
+	}
+
+	release := ""
+	if i := strings.LastIndex(version, "-"); i >= 0 {
+		release = specReleaseRegex.FindString(version[i+1:])
+		version = version[:i]
+	}
+	return version, release
+}
+
+// debianLicense возвращает лицензию из машиночитаемого файла debian/copyright (DEP-5)
+func debianLicense(script *convertedScript, copyright string) []string {
+	stanzas, err := parseDeb822(strings.NewReader(copyright))
+	if err == nil {
+		for _, stanza := range stanzas {
+			if strings.TrimSpace(stanza["files"]) == "*" && stanza["license"] != "" {
+				license, _, _ := strings.Cut(stanza["license"], "\n")
+				return []string{license}
+			}
+		}
+	}
+	script.note("license is not found in debian/copyright")
+	return nil
+}
+
+var (
+	debianDepArchRegex    = regexp.MustCompile(`\[[^]]*\]|<[^>]*>`)
+	debianDepVersionRegex = regexp.MustCompile(`^(\S+?)(?::\w+)?\s*\(\s*(<<|<=|=|>=|>>)\s*([^)\s]+)\s*\)$`)
+)
+
+// debianDepOps сопоставляет операторы сравнения версий Debian и ALR
+var debianDepOps = map[string]string{"<<": "<", "<=": "<=", "=": "=", ">=": ">=", ">>": ">"}
+
+// debianDeps разбирает поле зависимостей в формат ALR ("foo>=1.0").
+// Подстановки ${...} и зависимости debhelper пропускаются.
+func debianDeps(script *convertedScript, field string) []string {
+	var out []string
+	skippedHelpers := false
+	for _, dep := range strings.Split(field, ",") {
+		dep = strings.TrimSpace(debianDepArchRegex.ReplaceAllString(strings.ReplaceAll(dep, "\n", " "), ""))
+		if dep == "" || strings.HasPrefix(dep, "${") {
+			continue
+		}
+
+		alternatives := strings.Split(dep, "|")
+		if len(alternatives) > 1 {
+			script.note("alternative dependencies %q, using the first one", dep)
+		}
+		dep = strings.TrimSpace(alternatives[0])
+
+		name, version := dep, ""
+		if m := debianDepVersionRegex.FindStringSubmatch(dep); m != nil {
+			name, version = m[1], debianDepOps[m[2]]+m[3]
+		} else {
+			name, _, _ = strings.Cut(strings.Fields(dep)[0], ":")
+		}
+
+		if name == "debhelper" || name == "debhelper-compat" || strings.HasPrefix(name, "dh-") {
+			skippedHelpers = true
+			continue
+		}
+		if !contains(out, name+version) {
+			out = append(out, name+version)
+		}
+	}
+	if skippedHelpers {
+		script.note("debhelper build dependencies are dropped")
+	}
+	return out
+}
+
+// debianPatches возвращает список патчей из debian/patches/series
+func debianPatches(series string) []string {
+	var out []string
+	for _, line := range strings.Split(series, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		if fields := strings.Fields(line); len(fields) > 0 {
+			out = append(out, fields[0])
+		}
+	}
+	return out
+}
+
+// debianRules - разобранный файл debian/rules
+type debianRules struct {
+	buildsystem string
+	// targets - команды целей make по именам целей
+	targets map[string][]string
+	// order - порядок целей в файле
+	order []string
+	// variables - присваивания переменных верхнего уровня
+	variables []string
+}
+
+var (
+	debianTargetRegex   = regexp.MustCompile(`^([A-Za-z0-9_.%-]+)\s*:([^=]|$)`)
+	debianVariableRegex = regexp.MustCompile(`^(export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*[:?+]?=`)
+	debianBuildsystem   = regexp.MustCompile(`--buildsystem[= ](\S+)`)
+)
+
+func parseDebianRules(rules string) *debianRules {
+	out := &debianRules{targets: map[string][]string{}}
+
+	// Склеиваем строки, продолжающиеся обратной косой чертой
+	rules = strings.ReplaceAll(rules, "\\\n", " ")
+
+	target := ""
+	for _, line := range strings.Split(rules, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			if target == "" {
+				continue
+			}
+			recipe := strings.TrimSpace(line)
+			if m := debianBuildsystem.FindStringSubmatch(recipe); m != nil && strings.HasPrefix(recipe, "dh ") {
+				out.buildsystem = m[1]
+			}
+			out.targets[target] = append(out.targets[target], recipe)
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+		case debianVariableRegex.MatchString(trimmed):
+			m := debianVariableRegex.FindStringSubmatch(trimmed)
+			if !strings.HasPrefix(m[2], "DEB_") && !strings.HasPrefix(m[2], "DH_") {
+				out.variables = append(out.variables, trimmed)
+			}
+			target = ""
+		case debianTargetRegex.MatchString(trimmed):
+			target = debianTargetRegex.FindStringSubmatch(trimmed)[1]
+			if _, ok := out.targets[target]; !ok {
+				out.order = append(out.order, target)
+				out.targets[target] = nil
+			}
+		default:
+			target = ""
+		}
+	}
+	return out
+}
+
+// debianBuildsystems - команды настройки, сборки и установки для систем сборки debhelper
+var debianBuildsystems = map[string][3]string{
+	"autoconf": {specShellMacros["configure"], "make -j$(nproc)", `make DESTDIR="$pkgdir" install`},
+	"makefile": {"", "make -j$(nproc)", `make DESTDIR="$pkgdir" prefix=/usr install`},
+	"cmake":    {specShellMacros["cmake"], specShellMacros["cmake_build"], specShellMacros["cmake_install"]},
+	"meson":    {specShellMacros["meson"], specShellMacros["meson_build"], specShellMacros["meson_install"]},
+	"pybuild":  {"", specShellMacros["pyproject_wheel"], specShellMacros["pyproject_install"]},
+}
+
+// detectBuildsystem угадывает систему сборки, если она не указана в вызове dh
+func (r *debianRules) detectBuildsystem(script *convertedScript, buildDepends string) string {
+	if _, ok := debianBuildsystems[r.buildsystem]; ok {
+		return r.buildsystem
+	}
+	if r.buildsystem != "" {
+		script.note("debhelper build system %q is not supported", r.buildsystem)
+	}
+
+	deps := debianDeps(&convertedScript{}, buildDepends)
+	for _, dep := range deps {
+		switch {
+		case dep == "cmake" || strings.HasPrefix(dep, "cmake>"):
+			return "cmake"
+		case dep == "meson" || strings.HasPrefix(dep, "meson>"):
+			return "meson"
+		case strings.HasPrefix(dep, "python3-") || strings.HasPrefix(dep, "pybuild-"):
+			return "pybuild"
+		}
+	}
+	script.note("build system is detected by debhelper automatically, autoconf is assumed")
+	return "autoconf"
+}
+
+// convert заполняет build() и package() командами из debian/rules
+func (r *debianRules) convert(script *convertedScript, binaryPkg, buildDepends string) {
+	cmds := debianBuildsystems[r.detectBuildsystem(script, buildDepends)]
+
+	for _, v := range r.variables {
+		script.Build = append(script.Build, untranslated("debian/rules", v))
+	}
+
+	configure, hasConfigure := r.targets["override_dh_auto_configure"]
+	build, hasBuild := r.targets["override_dh_auto_build"]
+	install, hasInstall := r.targets["override_dh_auto_install"]
+
+	if hasConfigure {
+		script.Build = append(script.Build, r.recipe(cmds, binaryPkg, configure)...)
+	} else if cmds[0] != "" {
+		script.Build = append(script.Build, cmds[0])
+	}
+	if hasBuild {
+		script.Build = append(script.Build, r.recipe(cmds, binaryPkg, build)...)
+	} else {
+		script.Build = append(script.Build, cmds[1])
+	}
+	if hasInstall {
+		script.Package = append(script.Package, r.recipe(cmds, binaryPkg, install)...)
+	} else {
+		script.Package = append(script.Package, cmds[2])
+	}
+
+	for _, target := range r.order {
+		switch target {
+		case "%", "override_dh_auto_configure", "override_dh_auto_build", "override_dh_auto_install",
+			"override_dh_auto_test", "override_dh_auto_clean", "override_dh_clean":
+			continue
+		}
+		script.note("debian/rules target %s is not converted", target)
+		for _, line := range r.targets[target] {
+			script.Package = append(script.Package, untranslated("debian/rules "+target, line))
+		}
+	}
+}
+
+var debianMakeVarRegex = regexp.MustCompile(`\$[({]`)
+
+// recipe преобразует команды цели make в команды оболочки
+func (r *debianRules) recipe(cmds [3]string, binaryPkg string, lines []string) []string {
+	replacer := strings.NewReplacer(
+		"$(CURDIR)/debian/tmp", `"$pkgdir"`,
+		"$(CURDIR)/debian/"+binaryPkg, `"$pkgdir"`,
+		"debian/tmp", `"$pkgdir"`,
+		"debian/"+binaryPkg, `"$pkgdir"`,
+		"$(CURDIR)", "$PWD",
+		"$(MAKE)", "make",
+		"$$", "$",
+	)
+
+	var out []string
+	for _, line := range lines {
+		cmd := strings.TrimLeft(line, "@-")
+		fields := strings.Fields(cmd)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// Аргументы после "--" передаются системе сборки
+		_, args, _ := strings.Cut(cmd, " -- ")
+		switch fields[0] {
+		case "dh_auto_configure":
+			cmd = strings.TrimSpace(cmds[0] + " " + args)
+		case "dh_auto_build":
+			cmd = strings.TrimSpace(cmds[1] + " " + args)
+		case "dh_auto_install":
+			cmd = strings.TrimSpace(cmds[2] + " " + args)
+		default:
+			if strings.HasPrefix(fields[0], "dh_") {
+				out = append(out, untranslated("debian/rules", line))
+				continue
+			}
+			// Остальные переменные и функции make не переводятся
+			known := strings.NewReplacer("$(CURDIR)", "", "$(MAKE)", "", "$$", "").Replace(cmd)
+			if debianMakeVarRegex.MatchString(known) {
+				out = append(out, untranslated("debian/rules", line))
+				continue
+			}
+			cmd = replacer.Replace(cmd)
+		}
+		if cmd != "" {
+			out = append(out, cmd)
+		}
+	}
+	return out
+}
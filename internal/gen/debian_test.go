@@ -0,0 +1,189 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testDebianFiles = map[string]string{
+	"control": `Source: hello
+Section: devel
+Maintainer: Jane Doe <jane@example.com>
+Build-Depends: debhelper-compat (= 13),
+               cmake (>= 3.16),
+               libssl-dev [!hurd-any] <!nocheck>,
+               pkgconf | pkg-config
+Homepage: https://example.com/hello
+
+Package: hello
+Architecture: any
+Depends: ${shlibs:Depends}, ${misc:Depends}, libc6 (>= 2.34), zlib1g (<< 2:0)
+Breaks: hello-old (<< 1.0)
+Description: friendly greeting
+ Long description.
+
+Package: hello-doc
+Architecture: all
+Description: documentation
+`,
+	"rules": `#!/usr/bin/make -f
+export DEB_BUILD_MAINT_OPTIONS = hardening=+all
+export FOO = bar
+
+%:
+	dh $@ --buildsystem=cmake
+
+override_dh_auto_configure:
+	dh_auto_configure -- -DWITH_TLS=ON
+
+override_dh_auto_install:
+	$(MAKE) -C build install DESTDIR=$(CURDIR)/debian/hello
+	install -Dm644 extra.conf debian/hello/etc/hello.conf
+	@echo $(DEB_HOST_MULTIARCH)
+
+override_dh_auto_test:
+	dh_auto_test
+
+override_dh_installsystemd:
+	dh_installsystemd --name=hello
+`,
+	"changelog": `hello (1:2.4.1-3ubuntu1) noble; urgency=medium
+
+  * Initial release.
+
+ -- Jane Doe <jane@example.com>  Mon, 01 Jan 2024 00:00:00 +0000
+`,
+	"copyright": `Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/
+Upstream-Name: hello
+
+Files: *
+Copyright: 2024 Jane Doe
+License: MIT
+ Permission is hereby granted...
+
+Files: debian/*
+License: GPL-2+
+`,
+	"patches/series": "fix-build.patch\n# comment\nmore.patch -p1\n",
+}
+
+func TestDebianDir(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range testDebianFiles {
+		p := filepath.Join(dir, "debian", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Debian(&buf, DebianOptions{Path: dir}))
+	out := buf.String()
+
+	assert.Contains(t, out, "# Converted from Debian source package hello\n")
+	assert.Contains(t, out, "name='hello'\n")
+	assert.Contains(t, out, "version='2.4.1'\n")
+	assert.Contains(t, out, "release='3'\n")
+	assert.Contains(t, out, "desc='friendly greeting'\n")
+	assert.Contains(t, out, "homepage='https://example.com/hello'\n")
+	assert.Contains(t, out, "maintainer='Jane Doe <jane@example.com>'\n")
+	assert.Contains(t, out, "architectures=('amd64' 'arm64' )\n")
+	assert.Contains(t, out, "license=('MIT' )\n")
+	assert.Contains(t, out, "deps=('libc6>=2.34' 'zlib1g<2:0' )\n")
+	assert.Contains(t, out, "build_deps=('cmake>=3.16' 'libssl-dev' 'pkgconf' )\n")
+	assert.Contains(t, out, "conflicts=('hello-old<1.0' )\n")
+	assert.Contains(t, out, `sources=("local:///fix-build.patch" "local:///more.patch" )`)
+
+	assert.Contains(t, out, "# TODO: binary package \"hello-doc\" is not converted\n")
+	assert.Contains(t, out, "# TODO: epoch 1 is not converted (set epoch=1 if needed)\n")
+	assert.Contains(t, out, "# TODO: debhelper build dependencies are dropped\n")
+	assert.Contains(t, out, "# TODO: alternative dependencies \"pkgconf | pkg-config\", using the first one\n")
+	assert.Contains(t, out, "# TODO: debian/rules target override_dh_installsystemd is not converted\n")
+
+	assert.Contains(t, out, "\tpatch -p1 -i \"$srcdir/fix-build.patch\"\n")
+	assert.Contains(t, out, "\t# TODO (debian/rules): export FOO = bar\n")
+	assert.NotContains(t, out, "DEB_BUILD_MAINT_OPTIONS")
+	assert.Contains(t, out, "\tcmake -B build -DCMAKE_INSTALL_PREFIX=/usr -DCMAKE_BUILD_TYPE=Release -DWITH_TLS=ON\n\tcmake --build build -j$(nproc)\n")
+	assert.Contains(t, out, "\tmake -C build install DESTDIR=\"$pkgdir\"\n")
+	assert.Contains(t, out, "\tinstall -Dm644 extra.conf \"$pkgdir\"/etc/hello.conf\n")
+	assert.Contains(t, out, "\t# TODO (debian/rules): @echo $(DEB_HOST_MULTIARCH)\n")
+	assert.Contains(t, out, "\t# TODO (debian/rules override_dh_installsystemd): dh_installsystemd --name=hello\n")
+	assert.NotContains(t, out, "dh_auto_test")
+}
+
+func TestDebianDSC(t *testing.T) {
+	dir := t.TempDir()
+
+	var tarball bytes.Buffer
+	gw := gzip.NewWriter(&tarball)
+	tw := tar.NewWriter(gw)
+	for _, name := range []string{"control", "changelog"} {
+		content := testDebianFiles[name]
+		if name == "changelog" {
+			content = "hello (2.4.1-1) unstable; urgency=medium\n"
+		}
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "debian/" + name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hello_2.4.1-1.debian.tar.gz"), tarball.Bytes(), 0o644))
+
+	dsc := `-----BEGIN PGP SIGNED MESSAGE-----
+Hash: SHA512
+
+Format: 3.0 (quilt)
+Source: hello
+Version: 2.4.1-1
+Checksums-Sha256:
+ aaaa 100 hello_2.4.1.orig.tar.gz
+ bbbb 10 hello_2.4.1.orig.tar.gz.asc
+ ` + sha256Hex(tarball.Bytes()) + ` 200 hello_2.4.1-1.debian.tar.gz
+
+-----BEGIN PGP SIGNATURE-----
+abcdef
+-----END PGP SIGNATURE-----
+`
+	dscPath := filepath.Join(dir, "hello_2.4.1-1.dsc")
+	require.NoError(t, os.WriteFile(dscPath, []byte(dsc), 0o644))
+
+	var buf bytes.Buffer
+	require.NoError(t, Debian(&buf, DebianOptions{Path: dscPath}))
+	out := buf.String()
+
+	assert.Contains(t, out, "version='2.4.1'\n")
+	assert.Contains(t, out, "release='1'\n")
+	assert.Contains(t, out, `sources=("local:///hello_2.4.1.orig.tar.gz" )`)
+	assert.Contains(t, out, "checksums=('aaaa' )\n")
+	// Без debian/rules используются команды системы сборки по умолчанию
+	assert.Contains(t, out, "\tcmake --build build -j$(nproc)\n")
+	assert.Contains(t, out, "\tDESTDIR=\"$pkgdir\" cmake --install build\n")
+}
+
+func TestDebianMissingControl(t *testing.T) {
+	var buf bytes.Buffer
+	assert.ErrorContains(t, Debian(&buf, DebianOptions{Path: t.TempDir()}), "debian/control not found")
+}
@@ -0,0 +1,334 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"archive/zip"
+	"bufio"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Встраиваем шаблон для модулей Go
+//
+//go:embed tmpls/gomod.tmpl.sh
+var gomodTmpl string
+
+// GoProxyURL - адрес прокси модулей Go по умолчанию
+const GoProxyURL = "https://proxy.golang.org"
+
+// GoModOptions содержит параметры для генерации шаблона модуля Go
+type GoModOptions struct {
+	Module      string // Путь модуля
+	Version     string // Версия (опционально, по умолчанию последняя из прокси)
+	Description string // Описание (опционально)
+	BaseURL     string // Адрес прокси модулей (опционально, для тестов и зеркал)
+}
+
+// gomodMain - пакет main внутри модуля
+type gomodMain struct {
+	Dir string // Каталог относительно корня модуля ("." для корня)
+	Bin string // Имя исполняемого файла
+}
+
+// gomodTmplData - данные для шаблона gomod.tmpl.sh
+type gomodTmplData struct {
+	Name        string
+	Module      string
+	Version     string
+	Description string
+	Homepage    string
+	License     string
+	Source      string
+	Checksum    string
+	SrcDir      string
+	GoVersion   string
+	// Vendor - есть ли у модуля зависимости, которые нужно загрузить в vendor/
+	Vendor bool
+	Mains  []gomodMain
+}
+
+// GoMod генерирует шаблон alr.sh для программы из модуля Go
+func GoMod(w io.Writer, opts GoModOptions) error {
+	tmpl, err := template.New("gomod").
+		Funcs(funcs).
+		Parse(gomodTmpl)
+	if err != nil {
+		return err
+	}
+
+	baseURL := strings.TrimSuffix(opts.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = GoProxyURL
+	}
+
+	escaped, err := escapeModulePath(opts.Module)
+	if err != nil {
+		return err
+	}
+	modURL := baseURL + "/" + escaped
+
+	version := opts.Version
+	if version == "" {
+		if version, err = fetchGoModLatest(modURL); err != nil {
+			return err
+		}
+	}
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+
+	zipPath, checksum, err := downloadGoModZip(modURL + "/@v/" + version + ".zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(zipPath)
+
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return fmt.Errorf("failed to open module zip: %w", err)
+	}
+	defer zr.Close()
+
+	data, err := inspectGoModZip(&zr.Reader, opts.Module, version)
+	if err != nil {
+		return err
+	}
+	if len(data.Mains) == 0 {
+		return fmt.Errorf("module %s %s has no main packages", opts.Module, version)
+	}
+
+	data.Name = gomodName(opts.Module)
+	data.Module = opts.Module
+	data.Version = strings.TrimPrefix(version, "v")
+	data.Description = strings.ReplaceAll(strings.Join(strings.Fields(opts.Description), " "), "'", `'\''`)
+	data.Homepage = "https://pkg.go.dev/" + opts.Module
+	data.Checksum = checksum
+	data.Source = modURL + "/@v/v${version}.zip"
+	// Файлы в архиве модуля лежат в каталоге "путь@версия"
+	data.SrcDir = opts.Module + "@v${version}"
+
+	return tmpl.Execute(w, data)
+}
+
+// fetchGoModLatest возвращает последнюю версию модуля из /@latest
+func fetchGoModLatest(modURL string) (string, error) {
+	res, err := http.Get(modURL + "/@latest")
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch module info: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusGone {
+		return "", fmt.Errorf("module not found in proxy: %s", res.Status)
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("goproxy: %s", res.Status)
+	}
+
+	var info struct {
+		Version string `json:"Version"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&info); err != nil {
+		return "", fmt.Errorf("failed to decode goproxy response: %w", err)
+	}
+	return info.Version, nil
+}
+
+// downloadGoModZip загружает архив модуля во временный файл и вычисляет его sha256
+func downloadGoModZip(zipURL string) (string, string, error) {
+	res, err := http.Get(zipURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to download module zip: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("goproxy: %s: %s", zipURL, res.Status)
+	}
+
+	fl, err := os.CreateTemp("", "alr-gomod-*.zip")
+	if err != nil {
+		return "", "", err
+	}
+	defer fl.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(fl, h), res.Body); err != nil {
+		os.Remove(fl.Name())
+		return "", "", err
+	}
+	return fl.Name(), hex.EncodeToString(h.Sum(nil)), nil
+}
+
+var (
+	gomodPackageMainRegex = regexp.MustCompile(`(?m)^package\s+main\s*$`)
+	gomodGoDirectiveRegex = regexp.MustCompile(`(?m)^go\s+(\d+\.\d+)`)
+	gomodRequireRegex     = regexp.MustCompile(`(?m)^require\s`)
+	gomodMajorRegex       = regexp.MustCompile(`^v\d+$`)
+)
+
+// inspectGoModZip ищет пакеты main, версию Go, зависимости и лицензию в архиве модуля
+func inspectGoModZip(zr *zip.Reader, module, version string) (*gomodTmplData, error) {
+	prefix := module + "@" + version + "/"
+	data := &gomodTmplData{License: "custom:Unknown"}
+
+	for _, f := range zr.File {
+		rel, ok := strings.CutPrefix(f.Name, prefix)
+		if !ok || f.FileInfo().IsDir() {
+			continue
+		}
+		dir := path.Dir(rel)
+
+		switch {
+		case rel == "go.mod":
+			content, err := readZipFile(f)
+			if err != nil {
+				return nil, err
+			}
+			if m := gomodGoDirectiveRegex.FindStringSubmatch(content); m != nil {
+				data.GoVersion = m[1]
+			}
+			data.Vendor = gomodRequireRegex.MatchString(content)
+		case dir == "." && releaseLicenseRegex.MatchString(rel):
+			content, err := readZipFile(f)
+			if err != nil {
+				return nil, err
+			}
+			if license := detectLicense(content); license != "" {
+				data.License = license
+			}
+		case strings.HasSuffix(rel, ".go") && !strings.HasSuffix(rel, "_test.go") && !gomodSkipDir(dir):
+			if slices.ContainsFunc(data.Mains, func(m gomodMain) bool { return m.Dir == dir }) {
+				continue
+			}
+			content, err := readZipFile(f)
+			if err != nil {
+				return nil, err
+			}
+			if gomodPackageMainRegex.MatchString(content) {
+				bin := path.Base(dir)
+				if dir == "." {
+					bin = gomodName(module)
+				}
+				data.Mains = append(data.Mains, gomodMain{Dir: dir, Bin: bin})
+			}
+		}
+	}
+
+	slices.SortFunc(data.Mains, func(a, b gomodMain) int { return strings.Compare(a.Dir, b.Dir) })
+	return data, nil
+}
+
+// gomodSkipDir сообщает, что каталог не содержит устанавливаемых программ
+func gomodSkipDir(dir string) bool {
+	for _, part := range strings.Split(dir, "/") {
+		switch {
+		case part == ".":
+		case part == "testdata", part == "vendor", part == "example", part == "examples",
+			strings.HasPrefix(part, "_"), strings.HasPrefix(part, "."):
+			return true
+		}
+	}
+	return false
+}
+
+func readZipFile(f *zip.File) (string, error) {
+	r, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	// Для определения пакета и лицензии достаточно начала файла
+	var sb strings.Builder
+	scanner := bufio.NewScanner(io.LimitReader(r, 64*1024))
+	for scanner.Scan() {
+		sb.WriteString(scanner.Text())
+		sb.WriteByte('\n')
+	}
+	return sb.String(), scanner.Err()
+}
+
+// gomodName возвращает имя программы по пути модуля, пропуская суффикс старшей версии (/v2)
+func gomodName(module string) string {
+	parts := strings.Split(module, "/")
+	name := parts[len(parts)-1]
+	if gomodMajorRegex.MatchString(name) && len(parts) > 1 {
+		name = parts[len(parts)-2]
+	}
+	return strings.ToLower(name)
+}
+
+// escapeModulePath кодирует путь модуля для прокси: заглавные буквы
+// заменяются на "!" и строчную букву
+func escapeModulePath(module string) (string, error) {
+	if module == "" || strings.Contains(module, "!") || strings.Contains(module, "..") {
+		return "", fmt.Errorf("invalid module path %q", module)
+	}
+
+	var sb strings.Builder
+	for _, r := range module {
+		if unicode.IsUpper(r) {
+			sb.WriteByte('!')
+			sb.WriteRune(unicode.ToLower(r))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String(), nil
+}
+
+// licenseMarkers - характерные строки текстов распространённых лицензий
+var licenseMarkers = []struct {
+	marker  string
+	license string
+}{
+	{"Apache License", "Apache-2.0"},
+	{"Mozilla Public License Version 2.0", "MPL-2.0"},
+	{"GNU AFFERO GENERAL PUBLIC LICENSE", "AGPL-3.0"},
+	{"GNU LESSER GENERAL PUBLIC LICENSE", "LGPL-3.0"},
+	{"GNU GENERAL PUBLIC LICENSE Version 3", "GPL-3.0"},
+	{"GNU GENERAL PUBLIC LICENSE Version 2", "GPL-2.0"},
+	{"Permission is hereby granted, free of charge", "MIT"},
+	{"Neither the name of", "BSD-3-Clause"},
+	{"Redistribution and use in source and binary forms", "BSD-2-Clause"},
+	{"This is free and unencumbered software released into the public domain", "Unlicense"},
+	{"Permission to use, copy, modify, and/or distribute this software for any", "ISC"},
+}
+
+// detectLicense определяет лицензию по тексту файла LICENSE
+func detectLicense(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	for _, l := range licenseMarkers {
+		if strings.Contains(text, l.marker) {
+			return l.license
+		}
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testModuleZip собирает архив модуля в формате прокси
+func testModuleZip(t *testing.T, prefix string, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		fw, err := zw.Create(prefix + name)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func newGoProxyStub(t *testing.T, zips map[string][]byte) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/github.com/!example/tool/v2/@latest", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Version":"v2.3.0","Time":"2024-01-01T00:00:00Z"}`))
+	})
+	for p, data := range zips {
+		mux.HandleFunc(p, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(data)
+		})
+	}
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGoMod(t *testing.T) {
+	data := testModuleZip(t, "github.com/Example/tool/v2@v2.3.0/", map[string]string{
+		"go.mod":                    "module github.com/Example/tool/v2\n\ngo 1.22.1\n\nrequire (\n\tgolang.org/x/sys v0.20.0\n)\n",
+		"LICENSE":                   "Apache License\n                           Version 2.0, January 2004\n",
+		"main.go":                   "// Command tool\npackage main\n\nfunc main() {}\n",
+		"lib.go":                    "package tool\n",
+		"cmd/tool-helper/main.go":   "package main\n",
+		"internal/util/util.go":     "package util\n",
+		"examples/demo/main.go":     "package main\n",
+		"cmd/tool-helper/x_test.go": "package main_test\n",
+	})
+	srv := newGoProxyStub(t, map[string][]byte{"/github.com/!example/tool/v2/@v/v2.3.0.zip": data})
+
+	var buf bytes.Buffer
+	require.NoError(t, GoMod(&buf, GoModOptions{Module: "github.com/Example/tool/v2", BaseURL: srv.URL}))
+	out := buf.String()
+
+	assert.Contains(t, out, "name='tool'\n")
+	assert.Contains(t, out, "version='2.3.0'\n")
+	assert.Contains(t, out, "homepage='https://pkg.go.dev/github.com/Example/tool/v2'\n")
+	assert.Contains(t, out, "license=('Apache-2.0')\n")
+	assert.Contains(t, out, "build_deps=('golang>=1.22')\n")
+	assert.Contains(t, out, `sources=("`+srv.URL+`/github.com/!example/tool/v2/@v/v${version}.zip")`)
+	assert.Contains(t, out, "checksums=('"+sha256Hex(data)+"')\n")
+
+	// Зависимости загружаются в vendor/ и сборка идёт без сети
+	assert.Contains(t, out, "prepare() {\n\tcd \"$srcdir/github.com/Example/tool/v2@v${version}\"\n\tgo mod vendor\n}")
+	assert.Contains(t, out, "-mod=vendor")
+	assert.Contains(t, out, "\tgo build -o \"build/tool\" .\n")
+	assert.Contains(t, out, "\tgo build -o \"build/tool-helper\" ./cmd/tool-helper\n")
+	assert.NotContains(t, out, "demo")
+	assert.Contains(t, out, "\tinstall-binary \"build/tool\"\n\tinstall-binary \"build/tool-helper\"\n")
+}
+
+func TestGoModWithoutDeps(t *testing.T) {
+	data := testModuleZip(t, "example.com/hello@v1.0.0/", map[string]string{
+		"go.mod":  "module example.com/hello\n\ngo 1.21\n",
+		"main.go": "package main\n",
+	})
+	srv := newGoProxyStub(t, map[string][]byte{"/example.com/hello/@v/v1.0.0.zip": data})
+
+	var buf bytes.Buffer
+	require.NoError(t, GoMod(&buf, GoModOptions{Module: "example.com/hello", Version: "1.0.0", BaseURL: srv.URL}))
+	out := buf.String()
+
+	assert.Contains(t, out, "license=('custom:Unknown')\n")
+	assert.NotContains(t, out, "prepare()")
+	assert.NotContains(t, out, "-mod=vendor")
+}
+
+func TestGoModNoMain(t *testing.T) {
+	data := testModuleZip(t, "example.com/lib@v1.0.0/", map[string]string{
+		"go.mod": "module example.com/lib\n",
+		"lib.go": "package lib\n",
+	})
+	srv := newGoProxyStub(t, map[string][]byte{"/example.com/lib/@v/v1.0.0.zip": data})
+
+	var buf bytes.Buffer
+	err := GoMod(&buf, GoModOptions{Module: "example.com/lib", Version: "v1.0.0", BaseURL: srv.URL})
+	assert.ErrorContains(t, err, "no main packages")
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	escaped, err := escapeModulePath("github.com/BurntSushi/toml")
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/!burnt!sushi/toml", escaped)
+
+	_, err = escapeModulePath("../etc")
+	assert.Error(t, err)
+}
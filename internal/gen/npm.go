@@ -0,0 +1,234 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Встраиваем шаблон для пакетов npm
+//
+//go:embed tmpls/npm.tmpl.sh
+var npmTmpl string
+
+// NPMRegistryURL - адрес реестра npm по умолчанию
+const NPMRegistryURL = "https://registry.npmjs.org"
+
+// NPMOptions содержит параметры для генерации шаблона пакета npm
+type NPMOptions struct {
+	Name        string // Имя пакета, может включать scope (@scope/name)
+	Version     string // Версия (опционально, по умолчанию dist-tags.latest)
+	Description string // Описание (опционально, по умолчанию из реестра)
+	BaseURL     string // Адрес реестра (опционально, для тестов и зеркал)
+}
+
+// npmPackument представляет ответ реестра на запрос /{name}
+type npmPackument struct {
+	Name     string                `json:"name"`
+	DistTags map[string]string     `json:"dist-tags"`
+	Versions map[string]npmVersion `json:"versions"`
+}
+
+// npmVersion содержит манифест одной версии пакета
+type npmVersion struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	Description string            `json:"description"`
+	Homepage    string            `json:"homepage"`
+	License     json.RawMessage   `json:"license"`
+	Engines     map[string]string `json:"engines"`
+	Dist        npmDist           `json:"dist"`
+}
+
+// npmDist содержит адрес и контрольные суммы архива пакета
+type npmDist struct {
+	Tarball   string `json:"tarball"`
+	Integrity string `json:"integrity"`
+	Shasum    string `json:"shasum"`
+}
+
+// npmTmplData - данные для шаблона npm.tmpl.sh
+type npmTmplData struct {
+	Name        string
+	Package     string
+	Version     string
+	Description string
+	Homepage    string
+	License     string
+	Source      string
+	Checksum    string
+	Tarball     string
+	NodeDep     string
+}
+
+// npmIntegrityAlgos сопоставляет алгоритмы Subresource Integrity и ALR
+var npmIntegrityAlgos = map[string]string{
+	"sha512": "sha512",
+	"sha384": "sha384",
+	"sha256": "sha256",
+	"sha1":   "sha1",
+}
+
+// NPM генерирует шаблон alr.sh для пакета из реестра npm
+func NPM(w io.Writer, opts NPMOptions) error {
+	tmpl, err := template.New("npm").
+		Funcs(funcs).
+		Parse(npmTmpl)
+	if err != nil {
+		return err
+	}
+
+	baseURL := strings.TrimSuffix(opts.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = NPMRegistryURL
+	}
+
+	pkg, err := fetchNPMPackument(baseURL, opts.Name)
+	if err != nil {
+		return err
+	}
+
+	version := opts.Version
+	if version == "" {
+		version = pkg.DistTags["latest"]
+	}
+	ver, ok := pkg.Versions[version]
+	if !ok {
+		return fmt.Errorf("npm: version %s of package %s not found", version, opts.Name)
+	}
+
+	checksum, err := npmChecksum(ver.Dist)
+	if err != nil {
+		return err
+	}
+
+	data := npmTmplData{
+		Name:        npmScriptName(pkg.Name),
+		Package:     pkg.Name,
+		Version:     ver.Version,
+		Description: ver.Description,
+		Homepage:    ver.Homepage,
+		License:     npmLicense(ver.License),
+		Checksum:    checksum,
+		NodeDep:     npmNodeDep(ver.Engines["node"]),
+	}
+	if opts.Description != "" {
+		data.Description = opts.Description
+	}
+	data.Description = strings.ReplaceAll(strings.Join(strings.Fields(data.Description), " "), "'", `'\''`)
+	if data.Homepage == "" {
+		data.Homepage = "https://www.npmjs.com/package/" + pkg.Name
+	}
+
+	// npm устанавливает пакет прямо из архива, поэтому он не распаковывается
+	tarball := strings.ReplaceAll(ver.Dist.Tarball, "-"+ver.Version+".tgz", "-${version}.tgz")
+	data.Source = tarball + "?~archive=false"
+	data.Tarball = path.Base(tarball)
+
+	return tmpl.Execute(w, data)
+}
+
+// fetchNPMPackument запрашивает метаданные пакета из реестра
+func fetchNPMPackument(baseURL, name string) (*npmPackument, error) {
+	// В имени со scope косая черта экранируется
+	apiURL := baseURL + "/" + strings.ReplaceAll(name, "/", "%2F")
+
+	res, err := http.Get(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch npm package info: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("package '%s' not found in npm registry", name)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("npm: %s", res.Status)
+	}
+
+	var pkg npmPackument
+	if err := json.NewDecoder(res.Body).Decode(&pkg); err != nil {
+		return nil, fmt.Errorf("failed to decode npm registry response: %w", err)
+	}
+	return &pkg, nil
+}
+
+// npmChecksum преобразует хеш Subresource Integrity ("sha512-<base64>")
+// в контрольную сумму ALR. Если его нет, используется shasum (SHA-1).
+func npmChecksum(dist npmDist) (string, error) {
+	for _, integrity := range strings.Fields(dist.Integrity) {
+		algo, b64, ok := strings.Cut(integrity, "-")
+		alrAlgo, supported := npmIntegrityAlgos[algo]
+		if !ok || !supported {
+			continue
+		}
+		sum, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			return "", fmt.Errorf("npm: invalid integrity %q: %w", integrity, err)
+		}
+		if alrAlgo == "sha256" {
+			return hex.EncodeToString(sum), nil
+		}
+		return alrAlgo + ":" + hex.EncodeToString(sum), nil
+	}
+	if dist.Shasum != "" {
+		return "sha1:" + dist.Shasum, nil
+	}
+	return "", fmt.Errorf("npm: package tarball has no integrity hash")
+}
+
+// npmScriptName возвращает имя пакета ALR: "nodejs-" + имя без символа @ scope
+func npmScriptName(name string) string {
+	name = strings.ReplaceAll(strings.TrimPrefix(name, "@"), "/", "-")
+	return "nodejs-" + strings.ToLower(name)
+}
+
+// npmLicense разбирает поле license, которое может быть строкой или объектом {"type": ...}
+func npmLicense(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil && s != "" {
+		return s
+	}
+	var obj struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(raw, &obj) == nil && obj.Type != "" {
+		return obj.Type
+	}
+	return "custom:Unknown"
+}
+
+var npmEngineRegex = regexp.MustCompile(`^>=\s*v?(\d+(?:\.\d+)*)$`)
+
+// npmNodeDep возвращает зависимость от Node.js с учётом поля engines.node.
+// Переводятся только простые ограничения вида ">=18".
+func npmNodeDep(engine string) string {
+	if m := npmEngineRegex.FindStringSubmatch(strings.TrimSpace(engine)); m != nil {
+		return ">=" + m[1]
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNPMResponse = `{
+  "name": "@scope/tool",
+  "dist-tags": {"latest": "2.0.1", "next": "3.0.0-rc.1"},
+  "versions": {
+    "2.0.1": {
+      "name": "@scope/tool",
+      "version": "2.0.1",
+      "description": "A tool that's\nuseful",
+      "license": {"type": "MIT"},
+      "bin": {"tool": "bin/tool.js"},
+      "engines": {"node": ">=18"},
+      "dist": {
+        "tarball": "https://registry.npmjs.org/@scope/tool/-/tool-2.0.1.tgz",
+        "integrity": "sha512-AAECAwQFBgcICQoLDA0ODw==",
+        "shasum": "0123"
+      }
+    },
+    "1.0.0": {
+      "name": "@scope/tool",
+      "version": "1.0.0",
+      "license": "ISC",
+      "engines": {"node": "^14 || ^16"},
+      "dist": {
+        "tarball": "https://registry.npmjs.org/@scope/tool/-/tool-1.0.0.tgz",
+        "shasum": "abcd"
+      }
+    }
+  }
+}`
+
+func newNPMStub(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/@scope%2Ftool" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testNPMResponse))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNPM(t *testing.T) {
+	srv := newNPMStub(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, NPM(&buf, NPMOptions{Name: "@scope/tool", BaseURL: srv.URL}))
+	out := buf.String()
+
+	assert.Contains(t, out, "name='nodejs-scope-tool'\n")
+	assert.Contains(t, out, "version='2.0.1'\n")
+	assert.Contains(t, out, `desc='A tool that'\''s useful'`)
+	assert.Contains(t, out, "homepage='https://www.npmjs.com/package/@scope/tool'\n")
+	assert.Contains(t, out, "license=('MIT')\n")
+	assert.Contains(t, out, "provides=('@scope/tool')\n")
+	assert.Contains(t, out, "deps=('nodejs>=18')\n")
+	assert.Contains(t, out, "deps_altlinux=('node>=18')\n")
+	assert.Contains(t, out, `sources=("https://registry.npmjs.org/@scope/tool/-/tool-${version}.tgz?~archive=false")`)
+	// Хеш integrity в base64 переводится в шестнадцатеричную контрольную сумму
+	assert.Contains(t, out, "checksums=('sha512:000102030405060708090a0b0c0d0e0f')\n")
+	assert.Contains(t, out, "\t\t\"$srcdir/tool-${version}.tgz\"\n")
+}
+
+func TestNPMShasumFallback(t *testing.T) {
+	srv := newNPMStub(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, NPM(&buf, NPMOptions{Name: "@scope/tool", Version: "1.0.0", BaseURL: srv.URL}))
+	out := buf.String()
+
+	assert.Contains(t, out, "license=('ISC')\n")
+	assert.Contains(t, out, "checksums=('sha1:abcd')\n")
+	// Сложные диапазоны версий Node.js не переводятся
+	assert.Contains(t, out, "deps=('nodejs')\n")
+	assert.Contains(t, out, "homepage='https://www.npmjs.com/package/@scope/tool'\n")
+}
+
+func TestNPMNotFound(t *testing.T) {
+	srv := newNPMStub(t)
+
+	var buf bytes.Buffer
+	assert.ErrorContains(t, NPM(&buf, NPMOptions{Name: "missing", BaseURL: srv.URL}), "not found")
+	assert.ErrorContains(t, NPM(&buf, NPMOptions{Name: "@scope/tool", Version: "9.9.9", BaseURL: srv.URL}), "version 9.9.9")
+}
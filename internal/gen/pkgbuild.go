@@ -0,0 +1,236 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"mvdan.cc/sh/v3/expand"
+	"mvdan.cc/sh/v3/interp"
+	"mvdan.cc/sh/v3/syntax"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/shutils/handlers"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/shutils/helpers"
+)
+
+// pkgbuildTimeout ограничивает время выполнения PKGBUILD
+const pkgbuildTimeout = 10 * time.Second
+
+// pkgbuildArches сопоставляет архитектуры Arch Linux с архитектурами ALR
+var pkgbuildArches = map[string]string{
+	"any":     "all",
+	"x86_64":  "amd64",
+	"i686":    "386",
+	"aarch64": "arm64",
+	"armv7h":  "arm7",
+	"armv6h":  "arm6",
+	"arm":     "arm5",
+	"riscv64": "riscv64",
+	"loong64": "loong64",
+}
+
+// pkgbuildChecksumAlgos перечисляет массивы контрольных сумм PKGBUILD в порядке
+// предпочтения вместе с префиксом алгоритма ALR (пустой для sha256 по умолчанию)
+var pkgbuildChecksumAlgos = []struct {
+	array  string
+	prefix string
+}{
+	{"sha256sums", ""},
+	{"sha512sums", "sha512:"},
+	{"b2sums", "blake2b-512:"},
+	{"sha384sums", "sha384:"},
+	{"sha224sums", "sha224:"},
+	{"sha1sums", "sha1:"},
+	{"md5sums", "md5:"},
+}
+
+// pkgbuildInfo содержит значения, полученные при выполнении PKGBUILD
+type pkgbuildInfo struct {
+	Version       string
+	Architectures []string
+	Sources       []string
+	Checksums     []string
+	Depends       []string
+	MakeDepends   []string
+
+	// Переопределения для архитектур ALR. Так как переопределение заменяет
+	// базовую переменную, они уже включают общие значения.
+	ArchSources     map[string][]string
+	ArchChecksums   map[string][]string
+	ArchDepends     map[string][]string
+	ArchMakeDepends map[string][]string
+
+	BuildFunc   string
+	PackageFunc string
+	PrepareFunc string
+}
+
+// parsePKGBUILD выполняет PKGBUILD интерпретатором оболочки с теми же
+// ограничениями, что и скрипты ALR: внешние команды не запускаются, а
+// доступ к файловой системе запрещён. Так корректно обрабатываются
+// многострочные массивы, подстановка переменных и массивы для архитектур.
+func parsePKGBUILD(ctx context.Context, pkgbuild string) (*pkgbuildInfo, error) {
+	file, err := syntax.NewParser().Parse(strings.NewReader(pkgbuild), "PKGBUILD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PKGBUILD: %w", err)
+	}
+
+	runner, err := interp.New(
+		interp.Env(expand.ListEnviron()),
+		interp.StdIO(nil, io.Discard, io.Discard),
+		interp.ExecHandler(helpers.Restricted.ExecHandler(handlers.NopExec)),
+		interp.ReadDirHandler2(handlers.RestrictedReadDir()),
+		interp.StatHandler(handlers.RestrictedStat()),
+		interp.OpenHandler(handlers.RestrictedOpen()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// PKGBUILD загружается из сети, поэтому ограничиваем время выполнения
+	ctx, cancel := context.WithTimeout(ctx, pkgbuildTimeout)
+	defer cancel()
+
+	if err := runner.Run(ctx, file); err != nil {
+		return nil, fmt.Errorf("failed to evaluate PKGBUILD: %w", err)
+	}
+
+	info := &pkgbuildInfo{
+		Depends:         pkgbuildVar(runner, "depends"),
+		MakeDepends:     pkgbuildVar(runner, "makedepends"),
+		ArchSources:     map[string][]string{},
+		ArchChecksums:   map[string][]string{},
+		ArchDepends:     map[string][]string{},
+		ArchMakeDepends: map[string][]string{},
+	}
+	if v := pkgbuildVar(runner, "pkgver"); len(v) > 0 {
+		info.Version = v[0]
+	}
+
+	algo := pkgbuildChecksumAlgo(runner)
+	info.Sources = replaceVersion(pkgbuildVar(runner, "source"), info.Version)
+	info.Checksums = pkgbuildChecksums(runner, algo, "")
+
+	for _, arch := range pkgbuildVar(runner, "arch") {
+		alrArch, ok := pkgbuildArches[arch]
+		if !ok {
+			continue
+		}
+		info.Architectures = append(info.Architectures, alrArch)
+		if arch == "any" {
+			continue
+		}
+
+		if sources := pkgbuildVar(runner, "source_"+arch); len(sources) > 0 {
+			info.ArchSources[alrArch] = slices.Concat(info.Sources, replaceVersion(sources, info.Version))
+			info.ArchChecksums[alrArch] = slices.Concat(info.Checksums, pkgbuildChecksums(runner, algo, "_"+arch))
+		}
+		if deps := pkgbuildVar(runner, "depends_"+arch); len(deps) > 0 {
+			info.ArchDepends[alrArch] = slices.Concat(info.Depends, deps)
+		}
+		if deps := pkgbuildVar(runner, "makedepends_"+arch); len(deps) > 0 {
+			info.ArchMakeDepends[alrArch] = slices.Concat(info.MakeDepends, deps)
+		}
+	}
+
+	info.BuildFunc = pkgbuildFunc(runner, pkgbuild, "build")
+	info.PackageFunc = pkgbuildFunc(runner, pkgbuild, "package")
+	info.PrepareFunc = pkgbuildFunc(runner, pkgbuild, "prepare")
+
+	return info, nil
+}
+
+// pkgbuildVar возвращает значение переменной PKGBUILD в виде списка
+func pkgbuildVar(runner *interp.Runner, name string) []string {
+	v, ok := runner.Vars[name]
+	if !ok {
+		return nil
+	}
+	switch v.Kind {
+	case expand.Indexed:
+		return v.List
+	case expand.String:
+		if v.Str == "" {
+			return nil
+		}
+		return []string{v.Str}
+	}
+	return nil
+}
+
+// pkgbuildChecksumAlgo выбирает первый тип контрольных сумм, объявленный в PKGBUILD
+// как для общих, так и для архитектурных источников
+func pkgbuildChecksumAlgo(runner *interp.Runner) int {
+	for i, algo := range pkgbuildChecksumAlgos {
+		for name := range runner.Vars {
+			if name == algo.array || strings.HasPrefix(name, algo.array+"_") {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// pkgbuildChecksums возвращает контрольные суммы в формате ALR ("алгоритм:хеш" или "SKIP")
+func pkgbuildChecksums(runner *interp.Runner, algo int, suffix string) []string {
+	sums := pkgbuildVar(runner, pkgbuildChecksumAlgos[algo].array+suffix)
+	out := make([]string, len(sums))
+	for i, sum := range sums {
+		if strings.EqualFold(sum, "SKIP") {
+			out[i] = "SKIP"
+			continue
+		}
+		out[i] = pkgbuildChecksumAlgos[algo].prefix + sum
+	}
+	return out
+}
+
+// replaceVersion заменяет значение pkgver в источниках на ${version}, чтобы
+// сгенерированный скрипт оставался корректным при обновлении версии.
+// Версии без точки слишком коротки для надёжной замены и не трогаются.
+func replaceVersion(sources []string, version string) []string {
+	if !strings.Contains(version, ".") {
+		return sources
+	}
+	out := make([]string, len(sources))
+	for i, src := range sources {
+		out[i] = strings.ReplaceAll(src, version, "${version}")
+	}
+	return out
+}
+
+// pkgbuildFunc возвращает исходный текст тела функции PKGBUILD без фигурных скобок
+func pkgbuildFunc(runner *interp.Runner, src, name string) string {
+	body, ok := runner.Funcs[name]
+	if !ok {
+		return ""
+	}
+
+	start, end := body.Pos().Offset(), body.End().Offset()
+	if block, ok := body.Cmd.(*syntax.Block); ok {
+		start, end = block.Lbrace.Offset()+1, block.Rbrace.Offset()
+	}
+	if start > end || int(end) > len(src) {
+		return ""
+	}
+	return strings.TrimSpace(src[start:end])
+}
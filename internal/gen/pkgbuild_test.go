@@ -0,0 +1,146 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPKGBUILD = `# Maintainer: Someone <someone@example.com>
+pkgname=foo-bin
+_pkgname=foo
+pkgver=1.2.3
+pkgrel=2
+arch=('x86_64' 'aarch64')
+depends=('glibc'
+         'gtk3>=3.24')
+makedepends=()
+depends_x86_64=('lib32-glibc')
+_base="https://example.com/${_pkgname}/releases/download/v${pkgver}"
+source=("${_pkgname}.desktop"
+        "LICENSE::${_base}/LICENSE")
+source_x86_64=("${_base}/${_pkgname}-${pkgver}-linux-amd64.tar.gz")
+source_aarch64=("${_base}/${_pkgname}-${pkgver}-linux-arm64.tar.gz")
+sha256sums=('SKIP'
+            'aaaa')
+sha256sums_x86_64=('bbbb')
+sha256sums_aarch64=('cccc')
+echo "should not be printed"
+uname -m > /tmp/should-not-exist
+
+prepare() {
+	cat > foo.conf <<EOF
+key=value
+EOF
+}
+
+package() {
+	install -Dm755 "${_pkgname}" "${pkgdir}/usr/bin/${_pkgname}"
+}
+`
+
+func TestParsePKGBUILD(t *testing.T) {
+	info, err := parsePKGBUILD(context.Background(), testPKGBUILD)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.2.3", info.Version)
+	assert.Equal(t, []string{"amd64", "arm64"}, info.Architectures)
+	assert.Equal(t, []string{"foo.desktop", "LICENSE::https://example.com/foo/releases/download/v${version}/LICENSE"}, info.Sources)
+	assert.Equal(t, []string{"SKIP", "aaaa"}, info.Checksums)
+	assert.Equal(t, map[string][]string{
+		"amd64": {
+			"foo.desktop",
+			"LICENSE::https://example.com/foo/releases/download/v${version}/LICENSE",
+			"https://example.com/foo/releases/download/v${version}/foo-${version}-linux-amd64.tar.gz",
+		},
+		"arm64": {
+			"foo.desktop",
+			"LICENSE::https://example.com/foo/releases/download/v${version}/LICENSE",
+			"https://example.com/foo/releases/download/v${version}/foo-${version}-linux-arm64.tar.gz",
+		},
+	}, info.ArchSources)
+	assert.Equal(t, map[string][]string{
+		"amd64": {"SKIP", "aaaa", "bbbb"},
+		"arm64": {"SKIP", "aaaa", "cccc"},
+	}, info.ArchChecksums)
+	assert.Equal(t, []string{"glibc", "gtk3>=3.24"}, info.Depends)
+	assert.Empty(t, info.MakeDepends)
+	assert.Equal(t, map[string][]string{"amd64": {"glibc", "gtk3>=3.24", "lib32-glibc"}}, info.ArchDepends)
+	assert.Empty(t, info.ArchMakeDepends)
+
+	assert.Equal(t, "cat > foo.conf <<EOF\nkey=value\nEOF", info.PrepareFunc)
+	assert.Equal(t, `install -Dm755 "${_pkgname}" "${pkgdir}/usr/bin/${_pkgname}"`, info.PackageFunc)
+	assert.Empty(t, info.BuildFunc)
+}
+
+func TestParsePKGBUILDChecksumAlgo(t *testing.T) {
+	info, err := parsePKGBUILD(context.Background(), `pkgver=2
+arch=(any)
+source=("https://example.com/a-$pkgver.tar.gz" "b.patch")
+b2sums=('0123' 'SKIP')
+`)
+	require.NoError(t, err)
+
+	// Версия без точки не подставляется в источники
+	assert.Equal(t, []string{"https://example.com/a-2.tar.gz", "b.patch"}, info.Sources)
+	assert.Equal(t, []string{"blake2b-512:0123", "SKIP"}, info.Checksums)
+	assert.Equal(t, []string{"all"}, info.Architectures)
+	assert.Empty(t, info.ArchSources)
+}
+
+func TestParsePKGBUILDSyntaxError(t *testing.T) {
+	_, err := parsePKGBUILD(context.Background(), "source=(")
+	assert.Error(t, err)
+}
+
+func TestAURTemplateArchOverrides(t *testing.T) {
+	info, err := parsePKGBUILD(context.Background(), testPKGBUILD)
+	require.NoError(t, err)
+
+	pkg := aurResult{Name: "foo-bin", Version: "1.2.3-2", Depends: []string{"glibc", "gtk3>=3.24"}}
+	applyPKGBUILDInfo(&pkg, info)
+	assert.Equal(t, "1.2.3", pkg.Version)
+
+	tmpl, err := template.New("aur").Funcs(funcs).Parse(aurTmpl)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, tmpl.Execute(&buf, pkg))
+	out := buf.String()
+
+	assert.Contains(t, out, "version='1.2.3'\n")
+	assert.Contains(t, out, `sources_amd64=("foo.desktop" "LICENSE::https://example.com/foo/releases/download/v${version}/LICENSE" "https://example.com/foo/releases/download/v${version}/foo-${version}-linux-amd64.tar.gz" )`)
+	assert.Contains(t, out, "checksums_arm64=('SKIP' 'aaaa' 'cccc' )")
+	assert.Contains(t, out, "deps_amd64=('glibc' 'gtk3' 'lib32-glibc')")
+	// Переопределения для дистрибутивов скрыли бы переопределения для архитектур
+	assert.NotContains(t, out, "deps_debian=")
+}
+
+func TestParsePKGBUILDTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := parsePKGBUILD(ctx, "while :; do :; done")
+	assert.Error(t, err)
+}
@@ -0,0 +1,425 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/mholt/archiver/v4"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cpu"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/webapi"
+)
+
+// Встраиваем шаблон для бинарных релизов
+//
+//go:embed tmpls/release.tmpl.sh
+var releaseTmpl string
+
+// Поддерживаемые forge-платформы
+const (
+	ForgeGitHub = webapi.ForgeGitHub
+	ForgeGitea  = webapi.ForgeGitea
+)
+
+// ReleaseOptions содержит параметры для генерации шаблона из релиза
+type ReleaseOptions struct {
+	Repo    string // Репозиторий в формате owner/name
+	Forge   string // github или gitea (по умолчанию github)
+	Tag     string // Тег релиза (опционально, по умолчанию последний релиз)
+	BaseURL string // Адрес API (опционально, для Gitea-инстансов и тестов)
+}
+
+// releaseArch - выбранный для архитектуры файл релиза
+type releaseArch struct {
+	Arch     string
+	Source   string
+	Checksum string
+	// Dir - общий каталог верхнего уровня внутри архива
+	Dir string
+}
+
+// releaseFiles - файлы, найденные внутри файла релиза,
+// пути указаны относительно Dir
+type releaseFiles struct {
+	Binaries    []string
+	Manuals     []string
+	Licenses    []string
+	Completions map[string]string
+}
+
+// releaseTmplData - данные для шаблона release.tmpl.sh
+type releaseTmplData struct {
+	Name        string
+	Program     string
+	Version     string
+	Description string
+	Homepage    string
+	License     string
+	Forge       string
+	Repo        string
+	Tag         string
+	Arches      []releaseArch
+	Files       releaseFiles
+}
+
+// SameDir сообщает, совпадает ли каталог внутри архива для всех архитектур
+func (d releaseTmplData) SameDir() bool {
+	for _, a := range d.Arches {
+		if a.Dir != d.Arches[0].Dir {
+			return false
+		}
+	}
+	return true
+}
+
+// InstallCommands генерирует команды install-* для функции package()
+func (d releaseTmplData) InstallCommands() string {
+	var commands []string
+	for _, bin := range d.Files.Binaries {
+		commands = append(commands, fmt.Sprintf("\tinstall-binary %q %s", "./"+bin, path.Base(bin)))
+	}
+	for _, man := range d.Files.Manuals {
+		commands = append(commands, fmt.Sprintf("\tinstall-manual %q", "./"+man))
+	}
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		if file, ok := d.Files.Completions[shell]; ok {
+			commands = append(commands, fmt.Sprintf("\tinstall-completion %s %s < %q", shell, d.Program, "./"+file))
+		}
+	}
+	for _, license := range d.Files.Licenses {
+		commands = append(commands, fmt.Sprintf("\tinstall-license %q %s/%s", "./"+license, d.Name, path.Base(license)))
+	}
+	if len(commands) == 0 {
+		return "\t# TODO: Добавьте команды установки файлов"
+	}
+	return strings.Join(commands, "\n")
+}
+
+// Release генерирует шаблон alr.sh для "-bin" пакета из файлов релиза GitHub или Gitea
+func Release(w io.Writer, opts ReleaseOptions) error {
+	tmpl, err := template.New("release").
+		Funcs(funcs).
+		Parse(releaseTmpl)
+	if err != nil {
+		return err
+	}
+
+	forge := opts.Forge
+	if forge == "" {
+		forge = ForgeGitHub
+	}
+	baseURL := strings.TrimSuffix(opts.BaseURL, "/")
+	if baseURL == "" {
+		var ok bool
+		if baseURL, ok = webapi.ForgeDefaultURLs[forge]; !ok {
+			return fmt.Errorf("unsupported forge: %s", forge)
+		}
+	}
+
+	owner, repoName, ok := strings.Cut(opts.Repo, "/")
+	if !ok || owner == "" || repoName == "" || strings.Contains(repoName, "/") {
+		return fmt.Errorf("invalid repository %q, expected owner/name", opts.Repo)
+	}
+
+	ctx := context.Background()
+	api, err := webapi.NewForge(forge, baseURL, owner, repoName)
+	if err != nil {
+		return err
+	}
+
+	repo, err := api.Repo(ctx)
+	if err != nil {
+		return err
+	}
+
+	release, err := api.Release(ctx, opts.Tag)
+	if err != nil {
+		return err
+	}
+
+	data := releaseTmplData{
+		Name:        strings.ToLower(repoName) + "-bin",
+		Program:     strings.ToLower(repoName),
+		Version:     strings.TrimPrefix(release.TagName, "v"),
+		Description: strings.ReplaceAll(strings.Join(strings.Fields(repo.Description), " "), "'", `'\''`),
+		Homepage:    firstNonEmpty(repo.Homepage, repo.Website, repo.HTMLURL),
+		License:     "custom:Unknown",
+		Forge:       forge,
+		Repo:        opts.Repo,
+		Tag:         release.TagName,
+	}
+	switch {
+	case repo.License != nil && repo.License.SPDXID != "" && repo.License.SPDXID != "NOASSERTION":
+		data.License = repo.License.SPDXID
+	case len(repo.Licenses) > 0:
+		data.License = repo.Licenses[0]
+	}
+
+	assets := selectReleaseAssets(release.Assets)
+	if len(assets) == 0 {
+		return fmt.Errorf("release %s of %s has no Linux assets for known architectures", release.TagName, opts.Repo)
+	}
+
+	for _, arch := range releaseArchOrder() {
+		asset, ok := assets[arch]
+		if !ok {
+			continue
+		}
+
+		checksum, entries, name, err := inspectReleaseAsset(ctx, api, asset, data.Program)
+		if err != nil {
+			return fmt.Errorf("%s: %w", asset.Name, err)
+		}
+
+		ra := releaseArch{
+			Arch:     arch,
+			Source:   replaceVersion([]string{asset.URL}, data.Version)[0],
+			Checksum: checksum,
+		}
+		if name != "" {
+			ra.Source += "?~name=" + url.QueryEscape(name)
+		}
+
+		dir, rel := splitTopDir(entries)
+		ra.Dir = replaceVersion([]string{dir}, data.Version)[0]
+
+		// Файлы определяются по первой архитектуре, в архивах для
+		// остальных архитектур их расположение обычно совпадает
+		if len(data.Arches) == 0 {
+			data.Files = detectReleaseFiles(rel, data.Program)
+		}
+		data.Arches = append(data.Arches, ra)
+	}
+
+	return tmpl.Execute(w, data)
+}
+
+// releaseArchOrder возвращает архитектуры в порядке вывода в шаблон
+func releaseArchOrder() []string {
+	return []string{"amd64", "arm64", "arm7", "arm6", "arm5", "386", "riscv64", "loong64", "ppc64le", "s390x"}
+}
+
+var (
+	// releaseOtherOS - файлы для других ОС
+	releaseOtherOS = regexp.MustCompile(`(?i)(darwin|macos|apple|osx|windows|win32|win64|freebsd|netbsd|openbsd|dragonfly|illumos|solaris|android)`)
+	// releaseArchiveExt - архивы, которые распаковывает ALR
+	releaseArchiveExt = regexp.MustCompile(`(?i)\.(tar\.gz|tgz|tar\.xz|txz|tar\.bz2|tbz2?|tar\.zst|tzst|zip|gz|xz|bz2|zst)$`)
+	// releaseSkipExt - подписи, контрольные суммы, пакеты других форматов и т.п.
+	releaseSkipExt = regexp.MustCompile(`(?i)\.(exe|msi|dmg|pkg|deb|rpm|apk|ipk|xbps|appimage|flatpak|snap|sig|asc|pem|crt|cert|minisig|sbom|spdx|json|jsonl|txt|md|sha1|sha256|sha256sum|sha512|sha512sum|md5|b3|yml|yaml|sh|ps1|zsync|vsix|whl|jar)$`)
+)
+
+// selectReleaseAssets выбирает для каждой архитектуры наиболее подходящий
+// файл релиза: архивы предпочтительнее отдельных исполняемых файлов,
+// а статические сборки musl предпочтительнее сборок glibc
+func selectReleaseAssets(assets []webapi.Asset) map[string]webapi.Asset {
+	selected := map[string]webapi.Asset{}
+	scores := map[string]int{}
+
+	for _, asset := range assets {
+		if releaseOtherOS.MatchString(asset.Name) || releaseSkipExt.MatchString(asset.Name) {
+			continue
+		}
+
+		arch, ok := cpu.FindArch(asset.Name)
+		if !ok {
+			continue
+		}
+
+		score := 10
+		if releaseArchiveExt.MatchString(asset.Name) {
+			score = 20
+		}
+		if strings.Contains(strings.ToLower(asset.Name), "musl") {
+			score++
+		}
+
+		if score > scores[arch] {
+			selected[arch] = asset
+			scores[arch] = score
+		}
+	}
+
+	return selected
+}
+
+// releaseEntry - файл внутри файла релиза
+type releaseEntry struct {
+	Path string
+	Mode fs.FileMode
+}
+
+// inspectReleaseAsset загружает файл релиза, вычисляет его контрольную сумму и
+// возвращает список содержащихся в нём файлов в том виде, в котором ALR их распакует.
+// Отдельный исполняемый файл сохраняется под именем программы, чтобы путь к нему
+// не зависел от архитектуры, для этого возвращается имя для параметра ~name.
+func inspectReleaseAsset(ctx context.Context, api *webapi.Forge, asset webapi.Asset, program string) (checksum string, entries []releaseEntry, name string, err error) {
+	res, err := api.Request(ctx, asset.URL)
+	if err != nil {
+		return "", nil, "", err
+	}
+	defer res.Body.Close()
+
+	fl, err := os.CreateTemp("", "alr-gen-release-*")
+	if err != nil {
+		return "", nil, "", err
+	}
+	defer os.Remove(fl.Name())
+	defer fl.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(fl, h), res.Body); err != nil {
+		return "", nil, "", err
+	}
+	checksum = hex.EncodeToString(h.Sum(nil))
+
+	if algo, sum, ok := strings.Cut(asset.Digest, ":"); ok && algo == "sha256" && !strings.EqualFold(sum, checksum) {
+		return "", nil, "", fmt.Errorf("checksum mismatch: expected %s, got %s", sum, checksum)
+	}
+
+	if _, err := fl.Seek(0, io.SeekStart); err != nil {
+		return "", nil, "", err
+	}
+
+	format, _, err := archiver.Identify(asset.Name, fl)
+	if errors.Is(err, archiver.ErrNoMatch) {
+		return checksum, []releaseEntry{{Path: program, Mode: 0o755}}, program, nil
+	} else if err != nil {
+		return "", nil, "", err
+	}
+
+	if _, err := fl.Seek(0, io.SeekStart); err != nil {
+		return "", nil, "", err
+	}
+
+	switch format := format.(type) {
+	case archiver.Extractor:
+		err = format.Extract(ctx, fl, nil, func(ctx context.Context, f archiver.File) error {
+			if !f.IsDir() {
+				entries = append(entries, releaseEntry{Path: path.Clean(strings.TrimPrefix(f.NameInArchive, "./")), Mode: f.Mode()})
+			}
+			return nil
+		})
+		if err != nil {
+			return "", nil, "", err
+		}
+		return checksum, entries, "", nil
+	default:
+		// Сжатый файл без архива распаковывается ALR без расширения сжатия
+		return checksum, []releaseEntry{{Path: program, Mode: 0o755}}, program + format.Name(), nil
+	}
+}
+
+// splitTopDir отделяет общий каталог верхнего уровня, если все файлы находятся в нём
+func splitTopDir(entries []releaseEntry) (string, []releaseEntry) {
+	if len(entries) == 0 {
+		return "", entries
+	}
+
+	top, _, ok := strings.Cut(entries[0].Path, "/")
+	if !ok {
+		return "", entries
+	}
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Path, top+"/") {
+			return "", entries
+		}
+	}
+
+	rel := make([]releaseEntry, len(entries))
+	for i, e := range entries {
+		rel[i] = releaseEntry{Path: strings.TrimPrefix(e.Path, top+"/"), Mode: e.Mode}
+	}
+	return top, rel
+}
+
+var (
+	releaseManRegex     = regexp.MustCompile(`\.[1-8](\.gz)?$`)
+	releaseLicenseRegex = regexp.MustCompile(`(?i)^(LICEN[CS]E|COPYING|UNLICENSE|COPYRIGHT)([-._].*)?$`)
+)
+
+// detectReleaseFiles определяет исполняемые файлы, man-страницы, файлы
+// автодополнения и лицензии среди файлов релиза
+func detectReleaseFiles(entries []releaseEntry, program string) releaseFiles {
+	files := releaseFiles{Completions: map[string]string{}}
+
+	var named string
+	for _, e := range entries {
+		base := path.Base(e.Path)
+		dir := strings.ToLower(path.Dir(e.Path))
+		inCompletions := strings.Contains(dir, "complet") || strings.Contains(dir, "autocomplete")
+
+		switch {
+		case releaseLicenseRegex.MatchString(base):
+			files.Licenses = append(files.Licenses, e.Path)
+		case releaseManRegex.MatchString(base):
+			files.Manuals = append(files.Manuals, e.Path)
+		case strings.HasSuffix(base, ".fish"):
+			setCompletion(files.Completions, "fish", e.Path)
+		case strings.HasSuffix(base, ".zsh") || (inCompletions && strings.HasPrefix(base, "_")):
+			setCompletion(files.Completions, "zsh", e.Path)
+		case strings.HasSuffix(base, ".bash") || (inCompletions && strings.Contains(dir, "bash")) ||
+			(inCompletions && !strings.Contains(base, ".")):
+			setCompletion(files.Completions, "bash", e.Path)
+		case inCompletions || strings.Contains(base, "."):
+			// Прочие файлы (документация, библиотеки, конфигурация) не устанавливаются автоматически
+		case e.Mode&0o111 != 0:
+			files.Binaries = append(files.Binaries, e.Path)
+		case base == program:
+			named = e.Path
+		}
+	}
+
+	// Некоторые архивы (например, zip) не сохраняют права доступа
+	if len(files.Binaries) == 0 && named != "" {
+		files.Binaries = append(files.Binaries, named)
+	}
+
+	slices.Sort(files.Binaries)
+	slices.Sort(files.Manuals)
+	slices.Sort(files.Licenses)
+	return files
+}
+
+func setCompletion(completions map[string]string, shell, file string) {
+	if _, ok := completions[shell]; !ok {
+		completions[shell] = file
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,199 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTarGz собирает архив tar.gz из файлов с указанными правами доступа
+func testTarGz(t *testing.T, files map[string]int64) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, mode := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: mode, Size: 4, Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte("data"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// newForgeStub запускает сервер, отвечающий как API GitHub (apiPrefix "")
+// или Gitea (apiPrefix "/api/v1")
+func newForgeStub(t *testing.T, apiPrefix string, assets map[string][]byte, digests map[string]string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	writeJSON := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(v))
+	}
+
+	mux.HandleFunc(apiPrefix+"/repos/owner/tool", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"name":        "tool",
+			"description": "A tool that's useful",
+			"html_url":    srv.URL + "/owner/tool",
+			"license":     map[string]string{"spdx_id": "MIT"},
+		})
+	})
+
+	release := func(w http.ResponseWriter, r *http.Request) {
+		var list []map[string]string
+		for _, name := range []string{
+			"tool-1.2.3-x86_64-unknown-linux-gnu.tar.gz",
+			"tool-1.2.3-x86_64-unknown-linux-musl.tar.gz",
+			"tool-1.2.3-x86_64-apple-darwin.tar.gz",
+			"tool-1.2.3-linux-arm64",
+			"tool-1.2.3-x86_64-unknown-linux-musl.tar.gz.sha256",
+			"tool-1.2.3-x86_64.deb",
+		} {
+			list = append(list, map[string]string{
+				"name":                 name,
+				"browser_download_url": srv.URL + "/owner/tool/releases/download/v1.2.3/" + name,
+				"digest":               digests[name],
+			})
+		}
+		writeJSON(w, map[string]any{"tag_name": "v1.2.3", "assets": list})
+	}
+	mux.HandleFunc(apiPrefix+"/repos/owner/tool/releases/latest", release)
+	mux.HandleFunc(apiPrefix+"/repos/owner/tool/releases/tags/v1.2.3", release)
+
+	mux.HandleFunc("/owner/tool/releases/download/v1.2.3/", func(w http.ResponseWriter, r *http.Request) {
+		data, ok := assets[r.URL.Path[len("/owner/tool/releases/download/v1.2.3/"):]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	})
+
+	return srv
+}
+
+func testReleaseAssets(t *testing.T) map[string][]byte {
+	return map[string][]byte{
+		"tool-1.2.3-x86_64-unknown-linux-musl.tar.gz": testTarGz(t, map[string]int64{
+			"tool-1.2.3-x86_64-unknown-linux-musl/tool":               0o755,
+			"tool-1.2.3-x86_64-unknown-linux-musl/doc/tool.1":         0o644,
+			"tool-1.2.3-x86_64-unknown-linux-musl/complete/tool.bash": 0o644,
+			"tool-1.2.3-x86_64-unknown-linux-musl/complete/_tool":     0o644,
+			"tool-1.2.3-x86_64-unknown-linux-musl/complete/tool.fish": 0o644,
+			"tool-1.2.3-x86_64-unknown-linux-musl/LICENSE-MIT":        0o644,
+			"tool-1.2.3-x86_64-unknown-linux-musl/README.md":          0o644,
+		}),
+		"tool-1.2.3-linux-arm64": []byte("\x7fELF arm64 binary"),
+	}
+}
+
+func TestRelease(t *testing.T) {
+	assets := testReleaseAssets(t)
+	srv := newForgeStub(t, "", assets, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, Release(&buf, ReleaseOptions{Repo: "owner/tool", BaseURL: srv.URL}))
+	out := buf.String()
+
+	assert.Contains(t, out, "name='tool-bin'\n")
+	assert.Contains(t, out, "version='1.2.3'\n")
+	assert.Contains(t, out, `desc='A tool that'\''s useful'`)
+	assert.Contains(t, out, "license=('MIT')\n")
+	assert.Contains(t, out, "architectures=('amd64' 'arm64' )\n")
+
+	// Для amd64 выбирается статическая сборка musl, а не glibc или darwin
+	assert.Contains(t, out, `sources_amd64=("`+srv.URL+`/owner/tool/releases/download/v${version}/tool-${version}-x86_64-unknown-linux-musl.tar.gz")`)
+	assert.Contains(t, out, "checksums_amd64=('"+sha256Hex(assets["tool-1.2.3-x86_64-unknown-linux-musl.tar.gz"])+"')")
+	// Отдельный исполняемый файл сохраняется под именем программы
+	assert.Contains(t, out, `sources_arm64=("`+srv.URL+`/owner/tool/releases/download/v${version}/tool-${version}-linux-arm64?~name=tool")`)
+	assert.Contains(t, out, "checksums_arm64=('"+sha256Hex(assets["tool-1.2.3-linux-arm64"])+"')")
+
+	assert.Contains(t, out, "\tamd64) cd \"$srcdir/tool-${version}-x86_64-unknown-linux-musl\" ;;\n")
+	assert.Contains(t, out, "\tarm64) cd \"$srcdir\" ;;\n")
+	assert.Contains(t, out, "\tinstall-binary \"./tool\" tool\n")
+	assert.Contains(t, out, "\tinstall-manual \"./doc/tool.1\"\n")
+	assert.Contains(t, out, "\tinstall-completion bash tool < \"./complete/tool.bash\"\n")
+	assert.Contains(t, out, "\tinstall-completion zsh tool < \"./complete/_tool\"\n")
+	assert.Contains(t, out, "\tinstall-completion fish tool < \"./complete/tool.fish\"\n")
+	assert.Contains(t, out, "\tinstall-license \"./LICENSE-MIT\" tool-bin/LICENSE-MIT\n")
+	assert.NotContains(t, out, "README")
+}
+
+func TestReleaseGitea(t *testing.T) {
+	assets := testReleaseAssets(t)
+	srv := newForgeStub(t, "/api/v1", assets, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, Release(&buf, ReleaseOptions{
+		Repo:    "owner/tool",
+		Forge:   ForgeGitea,
+		Tag:     "v1.2.3",
+		BaseURL: srv.URL,
+	}))
+	assert.Contains(t, buf.String(), "# Generated from gitea release: owner/tool v1.2.3\n")
+}
+
+func TestReleaseDigestMismatch(t *testing.T) {
+	srv := newForgeStub(t, "", testReleaseAssets(t), map[string]string{
+		"tool-1.2.3-linux-arm64": "sha256:0000",
+	})
+
+	var buf bytes.Buffer
+	err := Release(&buf, ReleaseOptions{Repo: "owner/tool", BaseURL: srv.URL})
+	assert.ErrorContains(t, err, "checksum mismatch")
+}
+
+func TestReleaseInvalidOptions(t *testing.T) {
+	var buf bytes.Buffer
+	assert.ErrorContains(t, Release(&buf, ReleaseOptions{Repo: "tool"}), "expected owner/name")
+	assert.ErrorContains(t, Release(&buf, ReleaseOptions{Repo: "owner/tool", Forge: "gitlab"}), "unsupported forge")
+}
+
+func TestDetectReleaseFilesWithoutModes(t *testing.T) {
+	// zip-архивы могут не сохранять права доступа
+	files := detectReleaseFiles([]releaseEntry{
+		{Path: "tool", Mode: 0o644},
+		{Path: "helper.so", Mode: 0o644},
+		{Path: "completions/tool", Mode: 0o644},
+	}, "tool")
+
+	assert.Equal(t, []string{"tool"}, files.Binaries)
+	assert.Equal(t, map[string]string{"bash": "completions/tool"}, files.Completions)
+}
@@ -0,0 +1,654 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SpecOptions содержит параметры для преобразования spec-файла RPM
+type SpecOptions struct {
+	Path string // Путь к spec-файлу
+}
+
+// specPathMacros - стандартные макросы путей RPM
+var specPathMacros = map[string]string{
+	"_prefix":         "/usr",
+	"_exec_prefix":    "/usr",
+	"_bindir":         "/usr/bin",
+	"_sbindir":        "/usr/sbin",
+	"_libdir":         "/usr/lib",
+	"_libexecdir":     "/usr/libexec",
+	"_datadir":        "/usr/share",
+	"_datarootdir":    "/usr/share",
+	"_mandir":         "/usr/share/man",
+	"_infodir":        "/usr/share/info",
+	"_docdir":         "/usr/share/doc",
+	"_licensedir":     "/usr/share/licenses",
+	"_includedir":     "/usr/include",
+	"_sysconfdir":     "/etc",
+	"_localstatedir":  "/var",
+	"_sharedstatedir": "/var/lib",
+	"_rundir":         "/run",
+	"_unitdir":        "/usr/lib/systemd/system",
+	"_userunitdir":    "/usr/lib/systemd/user",
+	"_tmpfilesdir":    "/usr/lib/tmpfiles.d",
+	"_sysusersdir":    "/usr/lib/sysusers.d",
+	"_udevrulesdir":   "/usr/lib/udev/rules.d",
+	"_pkgdocdir":      "/usr/share/doc/%{name}",
+	"nil":             "",
+}
+
+// specShellMacros - макросы, которые в функциях сборки заменяются командами и переменными ALR
+var specShellMacros = map[string]string{
+	"name":              "${name}",
+	"version":           "${version}",
+	"buildroot":         "$pkgdir",
+	"_builddir":         "$srcdir",
+	"_sourcedir":        "$srcdir",
+	"optflags":          "$CFLAGS",
+	"set_build_flags":   "",
+	"_smp_mflags":       "-j$(nproc)",
+	"_smp_build_ncpus":  "$(nproc)",
+	"make_build":        "make -j$(nproc)",
+	"make_install":      `make DESTDIR="$pkgdir" install`,
+	"makeinstall":       `make DESTDIR="$pkgdir" install`,
+	"configure":         "./configure --prefix=/usr --sysconfdir=/etc --localstatedir=/var --libdir=/usr/lib --mandir=/usr/share/man",
+	"cmake":             "cmake -B build -DCMAKE_INSTALL_PREFIX=/usr -DCMAKE_BUILD_TYPE=Release",
+	"cmake_build":       "cmake --build build -j$(nproc)",
+	"cmake_install":     `DESTDIR="$pkgdir" cmake --install build`,
+	"meson":             "meson setup build --prefix=/usr --buildtype=release",
+	"meson_build":       "meson compile -C build",
+	"meson_install":     `meson install -C build --destdir "$pkgdir"`,
+	"py3_build":         "python3 setup.py build",
+	"py3_install":       `python3 setup.py install --root="$pkgdir" --optimize=1 --skip-build`,
+	"pyproject_wheel":   "python3 -m build --wheel --no-isolation",
+	"pyproject_install": `python3 -m installer --destdir="$pkgdir" dist/*.whl`,
+}
+
+// specSections - директивы, начинающие новую секцию spec-файла
+var specSections = []string{
+	"%description", "%package", "%prep", "%generate_buildrequires", "%conf", "%build", "%install",
+	"%check", "%clean", "%files", "%changelog", "%pretrans", "%pre", "%post", "%preun",
+	"%postun", "%posttrans", "%triggerin", "%triggerun", "%triggerpostun", "%filetriggerin",
+	"%filetriggerun", "%verifyscript",
+}
+
+var (
+	specTagRegex    = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]*)(\([^)]*\))?\s*:\s*(.*)$`)
+	specDefineRegex = regexp.MustCompile(`^%(define|global)\s+(\S+?)(\(.*\))?\s+(.*)$`)
+	specMacroRegex  = regexp.MustCompile(`%(\{|\(|[A-Za-z_])`)
+)
+
+// specMacros - определённые макросы spec-файла
+type specMacros map[string]string
+
+// expand подставляет значения макросов. Возвращает false, если в строке
+// остались неизвестные макросы или вызовы оболочки %(...).
+func (m specMacros) expand(s string) (string, bool) {
+	return m.expandDepth(s, 0)
+}
+
+func (m specMacros) expandDepth(s string, depth int) (string, bool) {
+	if depth > 16 || !strings.Contains(s, "%") {
+		return s, depth <= 16
+	}
+
+	var sb strings.Builder
+	ok := true
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		switch c := s[i+1]; {
+		case c == '%':
+			sb.WriteByte('%')
+			i++
+		case c == '{':
+			end := matchingBrace(s, i+1)
+			if end < 0 {
+				sb.WriteString(s[i:])
+				return sb.String(), false
+			}
+			value, found := m.lookup(s[i+2 : end])
+			if !found {
+				sb.WriteString(s[i : end+1])
+				ok = false
+			} else {
+				expanded, eok := m.expandDepth(value, depth+1)
+				sb.WriteString(expanded)
+				ok = ok && eok
+			}
+			i = end
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || s[j] >= 'a' && s[j] <= 'z' || s[j] >= 'A' && s[j] <= 'Z' || s[j] >= '0' && s[j] <= '9') {
+				j++
+			}
+			value, found := m[s[i+1:j]]
+			if !found {
+				sb.WriteString(s[i:j])
+				ok = false
+			} else {
+				expanded, eok := m.expandDepth(value, depth+1)
+				sb.WriteString(expanded)
+				ok = ok && eok
+			}
+			i = j - 1
+		case c == '(':
+			sb.WriteByte('%')
+			ok = false
+		default:
+			sb.WriteByte('%')
+		}
+	}
+	return sb.String(), ok
+}
+
+// lookup возвращает значение макроса в фигурных скобках, включая
+// условные формы %{?name}, %{?name:value} и %{!?name:value}
+func (m specMacros) lookup(expr string) (string, bool) {
+	negate := false
+	conditional := false
+	for len(expr) > 0 && (expr[0] == '?' || expr[0] == '!') {
+		if expr[0] == '?' {
+			conditional = true
+		} else {
+			negate = true
+		}
+		expr = expr[1:]
+	}
+
+	name, value, hasValue := strings.Cut(expr, ":")
+	if !conditional {
+		v, ok := m[strings.TrimSpace(name)]
+		return v, ok
+	}
+
+	v, defined := m[name]
+	if defined == negate {
+		return "", true
+	}
+	if hasValue {
+		return value, true
+	}
+	return v, true
+}
+
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// specFile - прочитанный spec-файл
+type specFile struct {
+	tags     map[string][]string
+	sections map[string][]string
+	// subpackages - имена подпакетов из %package
+	subpackages []string
+	// scriptlets - найденные секции сценариев установки
+	scriptlets []string
+	macros     specMacros
+	// conditionals - число строк %if/%else/%endif в преамбуле
+	conditionals int
+}
+
+// parseSpec разбивает spec-файл на теги преамбулы и секции
+func parseSpec(r io.Reader) (*specFile, error) {
+	spec := &specFile{
+		tags:     map[string][]string{},
+		sections: map[string][]string{},
+		macros:   specMacros{},
+	}
+	for k, v := range specPathMacros {
+		spec.macros[k] = v
+	}
+
+	section := ""
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if name, args, ok := specSectionHeader(trimmed); ok {
+			section = name
+			// Секции подпакетов (%files -n foo, %description devel) не преобразуются
+			if args != "" && !strings.HasPrefix(args, "-f") && name != "%prep" && name != "%build" && name != "%install" {
+				if name == "%package" {
+					spec.subpackages = append(spec.subpackages, args)
+				}
+				section = name + " " + args
+			}
+			if name != "%description" && name != "%package" && name != "%prep" && name != "%build" &&
+				name != "%install" && name != "%check" && name != "%files" && name != "%changelog" &&
+				name != "%clean" && name != "%conf" && name != "%generate_buildrequires" && !contains(spec.scriptlets, name) {
+				spec.scriptlets = append(spec.scriptlets, name)
+			}
+			continue
+		}
+
+		if m := specDefineRegex.FindStringSubmatch(trimmed); m != nil && section == "" {
+			spec.macros[m[2]] = strings.TrimSpace(m[4])
+			continue
+		}
+
+		if section == "" {
+			if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			if strings.HasPrefix(trimmed, "%if") || strings.HasPrefix(trimmed, "%else") ||
+				strings.HasPrefix(trimmed, "%elif") || strings.HasPrefix(trimmed, "%endif") {
+				spec.conditionals++
+				continue
+			}
+			if m := specTagRegex.FindStringSubmatch(trimmed); m != nil {
+				tag := strings.ToLower(m[1])
+				if m[2] != "" {
+					// Requires(post) и подобные относятся к сценариям установки
+					tag += m[2]
+				}
+				spec.tags[tag] = append(spec.tags[tag], strings.TrimSpace(m[3]))
+			}
+			continue
+		}
+
+		spec.sections[section] = append(spec.sections[section], line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, tag := range []string{"name", "version", "release", "summary", "url"} {
+		if v := spec.tags[tag]; len(v) > 0 {
+			spec.macros[tag] = v[0]
+		}
+	}
+	return spec, nil
+}
+
+// specSectionHeader разбирает заголовок секции ("%files -n foo")
+func specSectionHeader(line string) (name, args string, ok bool) {
+	for _, s := range specSections {
+		if line == s || strings.HasPrefix(line, s+" ") || strings.HasPrefix(line, s+"\t") {
+			return s, strings.TrimSpace(line[len(s):]), true
+		}
+	}
+	return "", "", false
+}
+
+// tag возвращает значение тега с подставленными макросами
+func (s *specFile) tag(name string) string {
+	if v := s.tags[name]; len(v) > 0 {
+		expanded, _ := s.macros.expand(v[0])
+		return strings.TrimSpace(expanded)
+	}
+	return ""
+}
+
+// numberedTags возвращает значения тегов вида Source0, Source1... по порядку номеров
+func (s *specFile) numberedTags(prefix string) []string {
+	type numbered struct {
+		n     int
+		value string
+	}
+	var list []numbered
+	for tag, values := range s.tags {
+		if !strings.HasPrefix(tag, prefix) {
+			continue
+		}
+		suffix := tag[len(prefix):]
+		n := 0
+		if suffix != "" {
+			var err error
+			if n, err = strconv.Atoi(suffix); err != nil {
+				continue
+			}
+		}
+		for _, v := range values {
+			list = append(list, numbered{n, v})
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].n < list[j].n })
+
+	out := make([]string, len(list))
+	for i, v := range list {
+		out[i] = v.value
+	}
+	return out
+}
+
+// Spec преобразует spec-файл RPM в шаблон alr.sh
+func Spec(w io.Writer, opts SpecOptions) error {
+	fl, err := os.Open(opts.Path)
+	if err != nil {
+		return err
+	}
+	defer fl.Close()
+
+	spec, err := parseSpec(fl)
+	if err != nil {
+		return fmt.Errorf("failed to read spec file: %w", err)
+	}
+
+	script, err := convertSpec(spec)
+	if err != nil {
+		return err
+	}
+	script.Origin = "RPM spec file " + filepath.Base(opts.Path)
+
+	return script.render(w)
+}
+
+var specReleaseRegex = regexp.MustCompile(`^\d+`)
+
+func convertSpec(spec *specFile) (*convertedScript, error) {
+	script := &convertedScript{
+		Name:        spec.tag("name"),
+		Version:     spec.tag("version"),
+		Description: spec.tag("summary"),
+		Homepage:    spec.tag("url"),
+	}
+	if script.Name == "" || script.Version == "" {
+		return nil, fmt.Errorf("spec file has no Name or Version")
+	}
+
+	release := spec.tag("release")
+	script.Release = specReleaseRegex.FindString(release)
+	if script.Release != release {
+		script.note("Release %q is not a plain number", release)
+	}
+	if epoch := spec.tag("epoch"); epoch != "" && epoch != "0" {
+		script.note("Epoch: %s (set epoch=%s if needed)", epoch, epoch)
+	}
+	if license := spec.tag("license"); license != "" {
+		script.License = []string{license}
+	}
+
+	arches := strings.Fields(spec.tag("buildarch"))
+	if len(arches) == 0 {
+		arches = strings.Fields(spec.tag("exclusivearch"))
+	}
+	script.Architectures = convertArches(arches)
+
+	script.BuildDeps = spec.deps(script, "buildrequires")
+	script.Deps = spec.deps(script, "requires")
+	script.Provides = spec.deps(script, "provides")
+	script.Conflicts = spec.deps(script, "conflicts")
+	script.Replaces = spec.deps(script, "obsoletes")
+
+	// В источниках name и version заменяются переменными скрипта
+	shell := spec.shellMacros()
+	var patches []string
+	for _, src := range spec.numberedTags("source") {
+		script.Sources = append(script.Sources, specSource(script, shell, src))
+	}
+	for _, patch := range spec.numberedTags("patch") {
+		script.Sources = append(script.Sources, specSource(script, shell, patch))
+		expanded, _ := shell.expand(patch)
+		patches = append(patches, specSourceName(expanded))
+	}
+
+	for _, sp := range spec.subpackages {
+		script.note("subpackage %q is not converted", sp)
+	}
+	for _, s := range spec.scriptlets {
+		script.note("scriptlet %s is not converted", s)
+	}
+	if spec.conditionals > 0 {
+		script.note("the preamble contains %%if conditionals, only the first value of each tag is used")
+	}
+	if len(spec.sections["%check"]) > 0 {
+		script.note("%%check section is not converted")
+	}
+
+	dir := `"$srcdir/${name}-${version}"`
+	script.Prepare, dir = convertSpecPrep(shell, spec.sections["%prep"], patches, dir)
+	script.Build = append([]string{"cd " + dir}, convertSpecScript(shell, spec.sections["%conf"])...)
+	script.Build = append(script.Build, convertSpecScript(shell, spec.sections["%build"])...)
+	script.Package = append([]string{"cd " + dir}, convertSpecScript(shell, spec.sections["%install"])...)
+	script.Package = append(script.Package, convertSpecFiles(shell, spec.sections["%files"])...)
+	if len(script.Prepare) == 1 {
+		script.Prepare = nil
+	}
+
+	return script, nil
+}
+
+// shellMacros возвращает макросы для подстановки в функции скрипта ALR
+func (s *specFile) shellMacros() specMacros {
+	out := specMacros{}
+	for k, v := range s.macros {
+		out[k] = v
+	}
+	for k, v := range specShellMacros {
+		out[k] = v
+	}
+	return out
+}
+
+// deps разбирает теги зависимостей (BuildRequires, Requires...) в формат ALR ("foo>=1.0")
+func (s *specFile) deps(script *convertedScript, tag string) []string {
+	var out []string
+	for _, value := range s.tags[tag] {
+		expanded, ok := s.macros.expand(value)
+		if !ok || strings.Contains(expanded, "(") && strings.Contains(expanded, " or ") {
+			script.note("%s: %s", tag, value)
+			continue
+		}
+
+		fields := strings.FieldsFunc(expanded, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		for i := 0; i < len(fields); i++ {
+			f := fields[i]
+			switch f {
+			case ">=", "<=", "=", "==", ">", "<":
+				if len(out) > 0 && i+1 < len(fields) {
+					op := f
+					if op == "==" {
+						op = "="
+					}
+					out[len(out)-1] += op + fields[i+1]
+					i++
+				}
+				continue
+			}
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// specSource преобразует Source/Patch в источник ALR
+func specSource(script *convertedScript, shell specMacros, src string) string {
+	expanded, ok := shell.expand(src)
+	if !ok {
+		script.note("source %q contains unknown macros", src)
+	}
+
+	u, err := url.Parse(expanded)
+	if err != nil || u.Scheme == "" {
+		return localSource(path.Base(expanded))
+	}
+
+	// Приём "url#/имя-файла" задаёт имя сохраняемого файла
+	if base, fragment, ok := strings.Cut(expanded, "#/"); ok {
+		sep := "?"
+		if strings.Contains(base, "?") {
+			sep = "&"
+		}
+		return base + sep + "~name=" + path.Base(fragment)
+	}
+	return expanded
+}
+
+// specSourceName возвращает имя файла источника после загрузки
+func specSourceName(src string) string {
+	if i := strings.Index(src, "#/"); i >= 0 {
+		return path.Base(src[i+2:])
+	}
+	return path.Base(src)
+}
+
+// convertSpecPrep преобразует %prep. Возвращает команды prepare() и
+// каталог исходного кода, заданный через %setup -n или %autosetup -n.
+func convertSpecPrep(shell specMacros, lines, patches []string, dir string) ([]string, string) {
+	var out []string
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case fields[0] == "%setup" || fields[0] == "%autosetup":
+			for i := 1; i+1 < len(fields); i++ {
+				if fields[i] == "-n" {
+					expanded, _ := shell.expand(fields[i+1])
+					dir = fmt.Sprintf(`"$srcdir/%s"`, expanded)
+				}
+			}
+			if fields[0] == "%autosetup" {
+				out = append(out, specPatchCommands(fields, patches)...)
+			}
+		case fields[0] == "%autopatch":
+			out = append(out, specPatchCommands(fields, patches)...)
+		case strings.HasPrefix(fields[0], "%patch"):
+			n := strings.TrimPrefix(fields[0], "%patch")
+			level := "-p0"
+			for i := 1; i < len(fields); i++ {
+				switch {
+				case fields[i] == "-P" && i+1 < len(fields):
+					n = fields[i+1]
+					i++
+				case strings.HasPrefix(fields[i], "-p"):
+					level = fields[i]
+				}
+			}
+			idx, err := strconv.Atoi(n)
+			if n == "" {
+				idx, err = 0, nil
+			}
+			if err != nil || idx >= len(patches) {
+				out = append(out, untranslated("spec", line))
+				continue
+			}
+			out = append(out, fmt.Sprintf(`patch %s -i "$srcdir/%s"`, level, patches[idx]))
+		default:
+			out = append(out, convertSpecScript(shell, []string{line})...)
+		}
+	}
+	return append([]string{"cd " + dir}, out...), dir
+}
+
+// specPatchCommands применяет все патчи для %autosetup и %autopatch
+func specPatchCommands(fields, patches []string) []string {
+	level := "-p1"
+	if fields[0] == "%autosetup" {
+		level = "-p0"
+	}
+	for _, f := range fields[1:] {
+		if strings.HasPrefix(f, "-p") {
+			level = f
+		}
+	}
+
+	var out []string
+	for _, patch := range patches {
+		out = append(out, fmt.Sprintf(`patch %s -i "$srcdir/%s"`, level, patch))
+	}
+	return out
+}
+
+var specBuildRootVars = strings.NewReplacer(
+	"${RPM_BUILD_ROOT}", "$pkgdir",
+	"$RPM_BUILD_ROOT", "$pkgdir",
+	"${RPM_OPT_FLAGS}", "$CFLAGS",
+	"$RPM_OPT_FLAGS", "$CFLAGS",
+)
+
+var specCleanBuildRoot = regexp.MustCompile(`^rm\s+-[rf]+\s+"?\$pkgdir"?/?$`)
+
+// convertSpecScript преобразует строки %build или %install в команды ALR
+func convertSpecScript(shell specMacros, lines []string) []string {
+	var out []string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			out = append(out, trimmed)
+			continue
+		}
+
+		expanded, ok := shell.expand(specBuildRootVars.Replace(trimmed))
+		if !ok || specMacroRegex.MatchString(expanded) {
+			out = append(out, untranslated("spec", trimmed))
+			continue
+		}
+		expanded = strings.TrimSpace(expanded)
+		if expanded == "" || specCleanBuildRoot.MatchString(expanded) {
+			continue
+		}
+		out = append(out, expanded)
+	}
+	return out
+}
+
+// convertSpecFiles переносит файлы лицензий из %files, остальное
+// содержимое пакета определяется тем, что установлено в $pkgdir
+func convertSpecFiles(shell specMacros, lines []string) []string {
+	var out []string
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "%license" {
+			continue
+		}
+		for _, f := range fields[1:] {
+			expanded, ok := shell.expand(f)
+			if !ok {
+				out = append(out, untranslated("spec", line))
+				break
+			}
+			out = append(out, fmt.Sprintf(`install-license %q "${name}/%s"`, expanded, path.Base(expanded)))
+		}
+	}
+	return out
+}
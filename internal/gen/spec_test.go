@@ -0,0 +1,174 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `%global forgeurl https://example.com/hello
+
+Name:           hello
+Version:        2.12
+Release:        3%{?dist}
+Summary:        Prints a friendly greeting
+License:        GPL-3.0-or-later
+URL:            %{forgeurl}
+Source0:        %{url}/releases/%{name}-%{version}.tar.gz
+Source1:        %{name}.conf
+Patch0:         fix-build.patch
+BuildRequires:  gcc, make
+BuildRequires:  gettext-devel >= 0.19
+Requires:       glibc
+Obsoletes:      hello-old < 2.0
+BuildArch:      x86_64 aarch64
+
+%description
+GNU hello.
+
+%package devel
+Summary: Development files
+
+%prep
+%autosetup -p1
+
+%build
+%configure --disable-nls
+%make_build
+
+%install
+rm -rf %{buildroot}
+%make_install
+install -Dm644 %{SOURCE1} %{buildroot}%{_sysconfdir}/hello.conf
+%find_lang %{name}
+
+%check
+make check
+
+%post
+/sbin/ldconfig
+
+%files
+%license COPYING
+%{_bindir}/hello
+
+%changelog
+* Mon Jan 01 2024 Someone <someone@example.com> - 2.12-3
+- Rebuild
+`
+
+func TestSpec(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "hello.spec")
+	require.NoError(t, os.WriteFile(specPath, []byte(testSpec), 0o644))
+
+	var buf bytes.Buffer
+	require.NoError(t, Spec(&buf, SpecOptions{Path: specPath}))
+	out := buf.String()
+
+	assert.Contains(t, out, "# Converted from RPM spec file hello.spec\n")
+	assert.Contains(t, out, "name='hello'\n")
+	assert.Contains(t, out, "version='2.12'\n")
+	assert.Contains(t, out, "release='3'\n")
+	assert.Contains(t, out, "desc='Prints a friendly greeting'\n")
+	assert.Contains(t, out, "homepage='https://example.com/hello'\n")
+	assert.Contains(t, out, "architectures=('amd64' 'arm64' )\n")
+	assert.Contains(t, out, "license=('GPL-3.0-or-later' )\n")
+	assert.Contains(t, out, "replaces=('hello-old<2.0' )\n")
+	assert.Contains(t, out, "deps=('glibc' )\n")
+	assert.Contains(t, out, "build_deps=('gcc' 'make' 'gettext-devel>=0.19' )\n")
+	assert.Contains(t, out, `sources=("https://example.com/hello/releases/${name}-${version}.tar.gz" "local:///${name}.conf" "local:///fix-build.patch" )`)
+	assert.Contains(t, out, "checksums=('SKIP' 'SKIP' 'SKIP' )\n")
+
+	// Непреобразованные части отмечаются комментариями
+	assert.Contains(t, out, "# TODO: subpackage \"devel\" is not converted\n")
+	assert.Contains(t, out, "# TODO: scriptlet %post is not converted\n")
+	assert.Contains(t, out, "# TODO: %check section is not converted\n")
+	assert.Contains(t, out, "\t# TODO (spec): install -Dm644 %{SOURCE1} %{buildroot}%{_sysconfdir}/hello.conf\n")
+	assert.Contains(t, out, "\t# TODO (spec): %find_lang %{name}\n")
+
+	assert.Contains(t, out, "prepare() {\n\tcd \"$srcdir/${name}-${version}\"\n\tpatch -p1 -i \"$srcdir/fix-build.patch\"\n}")
+	assert.Contains(t, out, "\t./configure --prefix=/usr --sysconfdir=/etc --localstatedir=/var --libdir=/usr/lib --mandir=/usr/share/man --disable-nls\n\tmake -j$(nproc)\n")
+	assert.Contains(t, out, "\tmake DESTDIR=\"$pkgdir\" install\n")
+	assert.Contains(t, out, "\tinstall-license \"COPYING\" \"${name}/COPYING\"\n")
+	assert.NotContains(t, out, "rm -rf")
+	assert.NotContains(t, out, "Rebuild")
+}
+
+func TestSpecMacros(t *testing.T) {
+	m := specMacros{"name": "foo", "with_x": "1"}
+
+	for in, expected := range map[string]string{
+		"%{name}-%name":          "foo-foo",
+		"100%%":                  "100%",
+		"%{?dist}":               "",
+		"%{?with_x:--enable-x}":  "--enable-x",
+		"%{!?with_x:--disable}":  "",
+		"%{!?with_y:--disable}":  "--disable",
+		"%{?with_y:--enable-y}x": "x",
+	} {
+		out, ok := m.expand(in)
+		assert.True(t, ok, in)
+		assert.Equal(t, expected, out, in)
+	}
+
+	for _, in := range []string{"%{unknown}", "%(date)", "%unknown"} {
+		_, ok := m.expand(in)
+		assert.False(t, ok, in)
+	}
+
+	// Рекурсивные определения не зацикливаются
+	_, ok := specMacros{"a": "%{b}", "b": "%{a}"}.expand("%{a}")
+	assert.False(t, ok)
+}
+
+func TestSpecSetupDir(t *testing.T) {
+	spec, err := parseSpec(bytes.NewBufferString(`Name: foo
+Version: 1.0
+Source0: https://example.com/foo.tar.gz#/foo-%{version}.tar.gz
+BuildArch: noarch
+
+%prep
+%setup -q -n foo-src-%{version}
+%patch -P 0 -p1
+
+%install
+cp foo %{buildroot}/usr/bin
+`))
+	require.NoError(t, err)
+
+	script, err := convertSpec(spec)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"all"}, script.Architectures)
+	assert.Equal(t, []string{"https://example.com/foo.tar.gz?~name=foo-${version}.tar.gz"}, script.Sources)
+	// %patch без Patch-тегов не может быть сопоставлен с файлом
+	assert.Equal(t, []string{`cd "$srcdir/foo-src-${version}"`, "# TODO (spec): %patch -P 0 -p1"}, script.Prepare)
+	assert.Equal(t, []string{`cd "$srcdir/foo-src-${version}"`, "cp foo $pkgdir/usr/bin"}, script.Package)
+}
+
+func TestSpecMissingName(t *testing.T) {
+	spec, err := parseSpec(bytes.NewBufferString("Version: 1.0\n"))
+	require.NoError(t, err)
+	_, err = convertSpec(spec)
+	assert.Error(t, err)
+}
@@ -41,13 +41,16 @@ license=({{.LicenseString}})
 {{if .DependsString}}deps=({{.DependsString}}){{else}}deps=(){{end}}
 {{if .MakeDependsString}}build_deps=({{.MakeDependsString}}){{else}}build_deps=(){{end}}
 
+{{range $arch, $deps := .ArchDepends}}deps_{{$arch}}=({{$.FormatDeps $deps}})
+{{end}}{{range $arch, $deps := .ArchMakeDepends}}build_deps_{{$arch}}=({{$.FormatDeps $deps}})
+{{end}}
 # Зависимости для конкретных дистрибутивов (адаптируйте под нужды пакета)
-{{if .DependsString}}deps_arch=({{.DependsString}})
+{{if and .DependsString (not .ArchDepends)}}deps_arch=({{.DependsString}})
 deps_debian=({{.DependsString}})
 deps_altlinux=({{.DependsString}})
 deps_alpine=({{.DependsString}}){{end}}
 
-{{if and .MakeDependsString (ne .PackageType "bin")}}# Зависимости сборки для конкретных дистрибутивов
+{{if and .MakeDependsString (ne .PackageType "bin") (not .ArchMakeDepends)}}# Зависимости сборки для конкретных дистрибутивов
 build_deps_arch=({{.MakeDependsString}})
 build_deps_debian=({{.MakeDependsString}})
 build_deps_altlinux=({{.MakeDependsString}})
@@ -61,7 +64,10 @@ opt_deps=(
 # Источники из PKGBUILD
 sources=({{range .Sources}}"{{.}}" {{end}})
 checksums=({{range .Checksums}}'{{.}}' {{end}})
-
+{{range $arch, $sources := .ArchSources}}
+sources_{{$arch}}=({{range $sources}}"{{.}}" {{end}})
+checksums_{{$arch}}=({{range index $.ArchChecksums $arch}}'{{.}}' {{end}})
+{{end}}
 {{if .HasVersion}}# Функция версии для Git-пакетов
 version() {
 	cd "$srcdir/{{.Name}}"
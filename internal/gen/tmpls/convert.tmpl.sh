@@ -0,0 +1,61 @@
+# ALR - Any Linux Repository
+# Copyright (C) 2025 The ALR Authors
+#
+# This program is free software: you can redistribute it and/or modify
+# it under the terms of the GNU General Public License as published by
+# the Free Software Foundation, either version 3 of the License, or
+# (at your option) any later version.
+#
+# This program is distributed in the hope that it will be useful,
+# but WITHOUT ANY WARRANTY; without even the implied warranty of
+# MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+# GNU General Public License for more details.
+#
+# You should have received a copy of the GNU General Public License
+# along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+# Converted from {{.Origin}}
+# Dependency names are taken as-is and may need adapting for other distributions.
+{{- range .Notes}}
+# TODO: {{.}}
+{{- end}}
+
+name={{quote .Name}}
+version={{quote .Version}}
+release={{quote .Release}}
+desc={{quote .Description}}
+homepage={{quote .Homepage}}
+maintainer={{if .Maintainer}}{{quote .Maintainer}}{{else}}'Example <user@example.com>'{{end}}
+architectures=({{range .Architectures}}{{quote .}} {{end}})
+license=({{range .License}}{{quote .}} {{end}})
+{{- if .Provides}}
+provides=({{range .Provides}}{{quote .}} {{end}})
+{{- end}}
+{{- if .Conflicts}}
+conflicts=({{range .Conflicts}}{{quote .}} {{end}})
+{{- end}}
+{{- if .Replaces}}
+replaces=({{range .Replaces}}{{quote .}} {{end}})
+{{- end}}
+
+deps=({{range .Deps}}{{quote .}} {{end}})
+build_deps=({{range .BuildDeps}}{{quote .}} {{end}})
+
+sources=({{range .Sources}}"{{.}}" {{end}})
+checksums=({{range .Checksums}}{{quote .}} {{end}})
+{{- if .Prepare}}
+
+prepare() {
+{{lines .Prepare}}
+}
+{{- end}}
+{{- if .Build}}
+
+build() {
+{{lines .Build}}
+}
+{{- end}}
+
+package() {
+{{if .Package}}{{lines .Package}}{{else}}	# TODO: Добавьте команды установки файлов{{end}}
+}
@@ -0,0 +1,49 @@
+# ALR - Any Linux Repository
+# Copyright (C) 2025 The ALR Authors
+#
+# This program is free software: you can redistribute it and/or modify
+# it under the terms of the GNU General Public License as published by
+# the Free Software Foundation, either version 3 of the License, or
+# (at your option) any later version.
+#
+# This program is distributed in the hope that it will be useful,
+# but WITHOUT ANY WARRANTY; without even the implied warranty of
+# MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+# GNU General Public License for more details.
+#
+# You should have received a copy of the GNU General Public License
+# along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+# Generated from crates.io: {{.Name}}
+
+name='{{.Name | tolower}}'
+version='{{.Version}}'
+release='1'
+desc='{{.Description}}'
+homepage='{{.Homepage}}'
+maintainer='Example <user@example.com>'
+architectures=('amd64' 'arm64')
+license=('{{.License}}')
+
+build_deps=('cargo')
+build_deps_arch=('rust')
+build_deps_debian=('cargo')
+build_deps_fedora=('cargo')
+build_deps_altlinux=('rust-cargo')
+build_deps_opensuse=('cargo')
+build_deps_alpine=('cargo')
+
+sources=("{{.Source}}")
+checksums=('{{.Checksum}}')
+
+build() {
+	cd "$srcdir/{{.Name}}-${version}"
+	cargo build --release --locked
+}
+
+package() {
+	cd "$srcdir/{{.Name}}-${version}"
+{{- range .Bins}}
+	install-binary "target/release/{{.}}"
+{{- end}}
+}
@@ -0,0 +1,61 @@
+# ALR - Any Linux Repository
+# Copyright (C) 2025 The ALR Authors
+#
+# This program is free software: you can redistribute it and/or modify
+# it under the terms of the GNU General Public License as published by
+# the Free Software Foundation, either version 3 of the License, or
+# (at your option) any later version.
+#
+# This program is distributed in the hope that it will be useful,
+# but WITHOUT ANY WARRANTY; without even the implied warranty of
+# MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+# GNU General Public License for more details.
+#
+# You should have received a copy of the GNU General Public License
+# along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+# Generated from Go module: {{.Module}}
+
+name='{{.Name}}'
+version='{{.Version}}'
+release='1'
+desc='{{.Description}}'
+homepage='{{.Homepage}}'
+maintainer='Example <user@example.com>'
+architectures=('amd64' 'arm64')
+license=('{{.License}}')
+
+build_deps=('golang{{if .GoVersion}}>={{.GoVersion}}{{end}}')
+build_deps_arch=('go{{if .GoVersion}}>={{.GoVersion}}{{end}}')
+build_deps_debian=('golang-go')
+build_deps_opensuse=('go{{if .GoVersion}}>={{.GoVersion}}{{end}}')
+build_deps_alpine=('go{{if .GoVersion}}>={{.GoVersion}}{{end}}')
+
+sources=("{{.Source}}")
+checksums=('{{.Checksum}}')
+{{- if .Vendor}}
+
+prepare() {
+	cd "$srcdir/{{.SrcDir}}"
+	go mod vendor
+}
+{{- end}}
+
+build() {
+	cd "$srcdir/{{.SrcDir}}"
+	export CGO_CPPFLAGS="${CPPFLAGS}"
+	export CGO_CFLAGS="${CFLAGS}"
+	export CGO_CXXFLAGS="${CXXFLAGS}"
+	export CGO_LDFLAGS="${LDFLAGS}"
+	export GOFLAGS="-buildmode=pie -trimpath{{if .Vendor}} -mod=vendor{{end}} -modcacherw"
+{{- range .Mains}}
+	go build -o "build/{{.Bin}}" {{if eq .Dir "."}}.{{else}}./{{.Dir}}{{end}}
+{{- end}}
+}
+
+package() {
+	cd "$srcdir/{{.SrcDir}}"
+{{- range .Mains}}
+	install-binary "build/{{.Bin}}"
+{{- end}}
+}
@@ -0,0 +1,48 @@
+# ALR - Any Linux Repository
+# Copyright (C) 2025 The ALR Authors
+#
+# This program is free software: you can redistribute it and/or modify
+# it under the terms of the GNU General Public License as published by
+# the Free Software Foundation, either version 3 of the License, or
+# (at your option) any later version.
+#
+# This program is distributed in the hope that it will be useful,
+# but WITHOUT ANY WARRANTY; without even the implied warranty of
+# MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+# GNU General Public License for more details.
+#
+# You should have received a copy of the GNU General Public License
+# along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+# Generated from npm: {{.Package}}
+
+name='{{.Name}}'
+version='{{.Version}}'
+release='1'
+desc='{{.Description}}'
+homepage='{{.Homepage}}'
+maintainer='Example <user@example.com>'
+architectures=('all')
+license=('{{.License}}')
+provides=('{{.Package}}')
+
+deps=('nodejs{{.NodeDep}}')
+deps_altlinux=('node{{.NodeDep}}')
+deps_opensuse=('nodejs-default{{.NodeDep}}')
+
+build_deps=('npm')
+build_deps_opensuse=('npm-default')
+
+sources=("{{.Source}}")
+checksums=('{{.Checksum}}')
+
+package() {
+	npm install -g \
+		--cache "$srcdir/npm-cache" \
+		--prefix "$pkgdir/usr" \
+		--no-audit --no-fund \
+		"$srcdir/{{.Tarball}}"
+
+	# npm создаёт каталоги с правами из umask сборки
+	find "$pkgdir/usr" -type d -exec chmod 755 {} +
+}
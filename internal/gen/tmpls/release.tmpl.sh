@@ -0,0 +1,44 @@
+# ALR - Any Linux Repository
+# Copyright (C) 2025 The ALR Authors
+#
+# This program is free software: you can redistribute it and/or modify
+# it under the terms of the GNU General Public License as published by
+# the Free Software Foundation, either version 3 of the License, or
+# (at your option) any later version.
+#
+# This program is distributed in the hope that it will be useful,
+# but WITHOUT ANY WARRANTY; without even the implied warranty of
+# MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+# GNU General Public License for more details.
+#
+# You should have received a copy of the GNU General Public License
+# along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+# Generated from {{.Forge}} release: {{.Repo}} {{.Tag}}
+
+name='{{.Name}}'
+version='{{.Version}}'
+release='1'
+desc='{{.Description}}'
+homepage='{{.Homepage}}'
+maintainer='Example <user@example.com>'
+architectures=({{range .Arches}}'{{.Arch}}' {{end}})
+license=('{{.License}}')
+provides=('{{.Program}}')
+conflicts=('{{.Program}}')
+{{range .Arches}}
+sources_{{.Arch}}=("{{.Source}}")
+checksums_{{.Arch}}=('{{.Checksum}}')
+{{end}}
+package() {
+{{- if .SameDir}}
+	cd "$srcdir{{with (index .Arches 0).Dir}}/{{.}}{{end}}"
+{{- else}}
+	case "$ARCH" in
+{{- range .Arches}}
+	{{.Arch}}) cd "$srcdir{{with .Dir}}/{{.}}{{end}}" ;;
+{{- end}}
+	esac
+{{- end}}
+{{.InstallCommands}}
+}
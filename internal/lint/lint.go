@@ -0,0 +1,699 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package lint выполняет статический анализ скриптов alr.sh без их запуска
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+	"golang.org/x/text/language"
+	"mvdan.cc/sh/v3/syntax"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cpu"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/overrides"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+)
+
+// Severity - уровень важности замечания
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule описывает одну проверку линтера
+type Rule struct {
+	ID          string
+	Severity    Severity
+	Description string
+}
+
+// Идентификаторы проверок
+const (
+	RuleSyntax           = "syntax"
+	RuleMissingField     = "missing-field"
+	RuleMissingFunction  = "missing-function"
+	RuleChecksums        = "checksums-length"
+	RuleUnknownOverride  = "unknown-override"
+	RuleNotOverridable   = "not-overridable"
+	RuleUnknownVariable  = "unknown-variable"
+	RuleUnknownFunction  = "unknown-function"
+	RuleTopLevelCommand  = "top-level-command"
+	RuleUnsafeConstruct  = "unsafe-construct"
+	RuleMultiPackageBase = "multi-package-base"
+
+	// Проверки всего репозитория (alr repo check)
+	RuleEvaluation        = "evaluation"
+	RuleDuplicatePackage  = "duplicate-package"
+	RuleDuplicateProvides = "duplicate-provides"
+	RuleUnresolvedDep     = "unresolved-dependency"
+	RuleRepoConfig        = "repo-config"
+)
+
+// Rules - все проверки линтера
+var Rules = []Rule{
+	{RuleSyntax, SeverityError, "The script must be valid shell syntax"},
+	{RuleMissingField, SeverityError, "Required variables must be set"},
+	{RuleMissingFunction, SeverityError, "Functions required to build the package must be defined"},
+	{RuleChecksums, SeverityError, "checksums must have the same length as sources for every override"},
+	{RuleUnknownOverride, SeverityWarning, "Override suffixes must be a known architecture, distribution or language, in that order"},
+	{RuleNotOverridable, SeverityWarning, "Only overridable variables may have override suffixes"},
+	{RuleUnknownVariable, SeverityWarning, "Variables should be known to ALR or used by the script"},
+	{RuleUnknownFunction, SeverityWarning, "Functions should be known to ALR or called by the script"},
+	{RuleTopLevelCommand, SeverityWarning, "Commands outside functions run every time the script is read"},
+	{RuleUnsafeConstruct, SeverityWarning, "Constructs that are unsafe in build scripts"},
+	{RuleMultiPackageBase, SeverityWarning, "Scripts with several packages should set basepkg_name"},
+	{RuleEvaluation, SeverityError, "The script must be readable by ALR on every checked distribution"},
+	{RuleDuplicatePackage, SeverityError, "Package names must be unique within a repository"},
+	{RuleDuplicateProvides, SeverityWarning, "Provided names should not be shared by several scripts"},
+	{RuleUnresolvedDep, SeverityWarning, "Dependencies should be ALR packages or known system packages"},
+	{RuleRepoConfig, SeverityError, "alr-repo.toml must be valid"},
+}
+
+// Diagnostic - одно замечание линтера
+type Diagnostic struct {
+	Path     string   `json:"path"`
+	Line     uint     `json:"line"`
+	Column   uint     `json:"column"`
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s [%s]", d.Path, d.Line, d.Column, d.Severity, d.Message, d.Rule)
+}
+
+// NewDiagnostic создаёт замечание без позиции с уровнем важности из Rules
+func NewDiagnostic(path, rule, message string) Diagnostic {
+	severity := SeverityWarning
+	for _, r := range Rules {
+		if r.ID == rule {
+			severity = r.Severity
+		}
+	}
+	return Diagnostic{Path: path, Severity: severity, Rule: rule, Message: message}
+}
+
+// HasErrors сообщает, есть ли среди замечаний ошибки
+func HasErrors(diags []Diagnostic) bool {
+	return slices.ContainsFunc(diags, func(d Diagnostic) bool { return d.Severity == SeverityError })
+}
+
+// LintFile читает и проверяет скрипт. Синтаксическая ошибка возвращается как замечание.
+func LintFile(path string) ([]Diagnostic, error) {
+	script, err := alrsh.ReadFromLocal(path)
+	if err != nil {
+		var parseErr syntax.ParseError
+		if errors.As(err, &parseErr) {
+			return []Diagnostic{{
+				Path:     path,
+				Line:     parseErr.Pos.Line(),
+				Column:   parseErr.Pos.Col(),
+				Severity: SeverityError,
+				Rule:     RuleSyntax,
+				Message:  parseErr.Text,
+			}}, nil
+		}
+		return nil, err
+	}
+	return Lint(script), nil
+}
+
+// Lint проверяет разобранный скрипт
+func Lint(script *alrsh.ScriptFile) []Diagnostic {
+	l := &linter{path: script.Path()}
+	l.collect(script.File())
+	l.checkPackages()
+	l.checkVariables()
+	l.checkFunctions()
+	l.checkUnsafe(script.File())
+
+	slices.SortStableFunc(l.diags, func(a, b Diagnostic) int {
+		if a.Line != b.Line {
+			return int(a.Line) - int(b.Line)
+		}
+		return int(a.Column) - int(b.Column)
+	})
+	return l.diags
+}
+
+// assignment - присваивание переменной
+type assignment struct {
+	name string
+	pos  syntax.Pos
+	// count - число элементов значения или -1, если его нельзя определить без запуска
+	count int
+	// values - значения элементов, если все они - простые строки
+	values []string
+}
+
+// scope - переменные, видимые при разборе одного пакета
+type scope map[string]*assignment
+
+type linter struct {
+	path  string
+	diags []Diagnostic
+
+	vars  scope
+	funcs map[string]*syntax.FuncDecl
+	// metaVars - переменные из функций meta_<имя> по именам пакетов
+	metaVars map[string]scope
+	// used - имена переменных и функций, на которые есть ссылки в скрипте
+	usedVars  map[string]bool
+	usedFuncs map[string]bool
+}
+
+func (l *linter) report(pos syntax.Pos, rule, message string) {
+	d := NewDiagnostic(l.path, rule, message)
+	d.Line, d.Column = pos.Line(), pos.Col()
+	l.diags = append(l.diags, d)
+}
+
+// collect собирает присваивания и функции верхнего уровня, а также все ссылки на них
+func (l *linter) collect(file *syntax.File) {
+	l.vars = scope{}
+	l.funcs = map[string]*syntax.FuncDecl{}
+	l.metaVars = map[string]scope{}
+	l.usedVars = map[string]bool{}
+	l.usedFuncs = map[string]bool{}
+
+	for _, stmt := range file.Stmts {
+		switch cmd := stmt.Cmd.(type) {
+		case *syntax.FuncDecl:
+			l.funcs[cmd.Name.Value] = cmd
+		case *syntax.CallExpr:
+			if len(cmd.Args) > 0 {
+				l.report(stmt.Pos(), RuleTopLevelCommand, gotext.Get("command %q runs every time the script is read; move it into a function", wordString(cmd.Args[0])))
+				continue
+			}
+			addAssigns(l.vars, cmd.Assigns)
+		case *syntax.DeclClause:
+			addAssigns(l.vars, cmd.Args)
+		}
+	}
+
+	for name, fn := range l.funcs {
+		pkg, ok := strings.CutPrefix(name, "meta_")
+		if !ok {
+			continue
+		}
+		vars := scope{}
+		syntax.Walk(fn.Body, func(node syntax.Node) bool {
+			switch n := node.(type) {
+			case *syntax.CallExpr:
+				addAssigns(vars, n.Assigns)
+			case *syntax.DeclClause:
+				addAssigns(vars, n.Args)
+			}
+			return true
+		})
+		l.metaVars[pkg] = vars
+	}
+
+	syntax.Walk(file, func(node syntax.Node) bool {
+		switch n := node.(type) {
+		case *syntax.ParamExp:
+			l.usedVars[n.Param.Value] = true
+		case *syntax.CallExpr:
+			if len(n.Args) > 0 {
+				name := wordString(n.Args[0])
+				l.usedFuncs[name] = true
+				// Имена в аргументах (declare -n, test -v, unset) тоже считаются ссылками
+				for _, arg := range n.Args[1:] {
+					l.usedVars[wordString(arg)] = true
+				}
+			}
+		case *syntax.ArithmExp, *syntax.ArithmCmd:
+			syntax.Walk(n, func(node syntax.Node) bool {
+				if lit, ok := node.(*syntax.Lit); ok {
+					l.usedVars[lit.Value] = true
+				}
+				return true
+			})
+		}
+		return true
+	})
+}
+
+func addAssigns(s scope, assigns []*syntax.Assign) {
+	for _, as := range assigns {
+		if as.Name == nil {
+			continue
+		}
+		a := &assignment{name: as.Name.Value, pos: as.Pos(), count: -1}
+		if prev, ok := s[a.name]; ok && as.Append {
+			// Добавление к массиву: итоговую длину определить нельзя
+			prev.count = -1
+			prev.values = nil
+			continue
+		}
+
+		switch {
+		case as.Index != nil:
+			// Присваивание элементу массива
+			if prev, ok := s[a.name]; ok {
+				prev.count = -1
+				continue
+			}
+		case as.Array != nil:
+			a.count = 0
+			for _, elem := range as.Array.Elems {
+				if elem.Index != nil || !isSimpleWord(elem.Value) {
+					a.count = -1
+					break
+				}
+				a.count++
+				a.values = append(a.values, wordString(elem.Value))
+			}
+		case as.Value != nil:
+			if isSimpleWord(as.Value) {
+				a.count = 1
+				a.values = []string{wordString(as.Value)}
+			}
+		default:
+			a.count = 0
+		}
+		s[a.name] = a
+	}
+}
+
+// isSimpleWord сообщает, что слово раскрывается ровно в одну строку
+// (без раскрытия массивов, подстановки команд и разбиения на слова)
+func isSimpleWord(w *syntax.Word) bool {
+	simple := true
+	var check func(parts []syntax.WordPart, quoted bool)
+	check = func(parts []syntax.WordPart, quoted bool) {
+		for _, part := range parts {
+			switch p := part.(type) {
+			case *syntax.Lit, *syntax.SglQuoted:
+			case *syntax.DblQuoted:
+				check(p.Parts, true)
+			case *syntax.ParamExp:
+				// Без кавычек значение разбивается на слова
+				if p.Index != nil || !quoted {
+					simple = false
+				}
+			default:
+				simple = false
+			}
+		}
+	}
+	check(w.Parts, false)
+	return simple
+}
+
+// wordString возвращает значение слова без кавычек; подстановки
+// переменных остаются в исходном виде
+func wordString(w *syntax.Word) string {
+	var sb strings.Builder
+	var write func(parts []syntax.WordPart)
+	write = func(parts []syntax.WordPart) {
+		for _, part := range parts {
+			switch p := part.(type) {
+			case *syntax.Lit:
+				sb.WriteString(p.Value)
+			case *syntax.SglQuoted:
+				sb.WriteString(p.Value)
+			case *syntax.DblQuoted:
+				write(p.Parts)
+			default:
+				_ = syntax.NewPrinter().Print(&sb, p)
+			}
+		}
+	}
+	write(w.Parts)
+	return sb.String()
+}
+
+// packageNames возвращает имена пакетов из переменной name
+func (l *linter) packageNames() []string {
+	name, ok := l.vars["name"]
+	if !ok {
+		return nil
+	}
+	return name.values
+}
+
+// checkPackages проверяет обязательные поля, функции и контрольные суммы каждого пакета
+func (l *linter) checkPackages() {
+	name, ok := l.vars["name"]
+	if !ok {
+		l.report(syntax.Pos{}, RuleMissingField, gotext.Get("variable %s is not set", "name"))
+		return
+	}
+
+	names := l.packageNames()
+	if len(names) <= 1 {
+		for _, field := range []string{"version", "release"} {
+			if _, ok := l.vars[field]; !ok {
+				l.report(name.pos, RuleMissingField, gotext.Get("variable %s is not set", field))
+			}
+		}
+		if !l.hasFunc("package") {
+			l.report(name.pos, RuleMissingFunction, gotext.Get("function %s is not defined", "package"))
+		}
+		l.checkChecksums(l.vars)
+		return
+	}
+
+	if _, ok := l.vars["basepkg_name"]; !ok {
+		l.report(name.pos, RuleMultiPackageBase, gotext.Get("variable basepkg_name is not set for a script with several packages"))
+	}
+	for _, pkg := range names {
+		for _, fn := range []string{"package_" + pkg, "meta_" + pkg} {
+			if !l.hasFunc(fn) {
+				l.report(name.pos, RuleMissingFunction, gotext.Get("function %s is not defined", fn))
+			}
+		}
+
+		vars := scope{}
+		for k, v := range l.vars {
+			vars[k] = v
+		}
+		for k, v := range l.metaVars[pkg] {
+			vars[k] = v
+		}
+		for _, field := range []string{"version", "release"} {
+			if _, ok := vars[field]; !ok {
+				l.report(name.pos, RuleMissingField, gotext.Get("variable %s is not set for package %s", field, pkg))
+			}
+		}
+		l.checkChecksums(vars)
+	}
+}
+
+// hasFunc сообщает, определена ли функция (возможно, только с переопределениями)
+func (l *linter) hasFunc(name string) bool {
+	for fn := range l.funcs {
+		if fn == name {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(fn, name+"_"); ok && parseSuffix(suffix) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkChecksums сравнивает длины sources и checksums для каждого набора переопределений
+func (l *linter) checkChecksums(vars scope) {
+	suffixes := map[string]bool{"": true}
+	for name := range vars {
+		for _, base := range []string{"sources", "checksums"} {
+			if suffix, ok := strings.CutPrefix(name, base+"_"); ok && parseSuffix(suffix) == nil {
+				suffixes[suffix] = true
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(suffixes))
+	for suffix := range suffixes {
+		keys = append(keys, suffix)
+	}
+	slices.Sort(keys)
+
+	for _, suffix := range keys {
+		sources := resolveOverride(vars, "sources", suffix)
+		checksums := resolveOverride(vars, "checksums", suffix)
+		if sources == nil || sources.count < 0 {
+			continue
+		}
+
+		switch {
+		case checksums == nil && sources.count > 0:
+			l.report(sources.pos, RuleChecksums, gotext.Get("%s has %d items but checksums are not set", sources.name, sources.count))
+		case checksums != nil && checksums.count >= 0 && checksums.count != sources.count:
+			pos := checksums.pos
+			if sources.pos.After(pos) {
+				pos = sources.pos
+			}
+			l.report(pos, RuleChecksums, gotext.Get("%s has %d items but %s has %d", sources.name, sources.count, checksums.name, checksums.count))
+		}
+	}
+}
+
+// resolveOverride возвращает присваивание, которое будет выбрано для переменной base
+// в системе, соответствующей суффиксу переопределения, в порядке overrides.Resolve
+func resolveOverride(vars scope, base, suffix string) *assignment {
+	s := parseSuffixParts(suffix)
+
+	var names []string
+	add := func(parts ...string) {
+		var nonEmpty []string
+		for _, p := range parts {
+			if p == "" {
+				return
+			}
+			nonEmpty = append(nonEmpty, p)
+		}
+		names = append(names, strings.Join(append([]string{base}, nonEmpty...), "_"))
+	}
+	add(s.arch, s.distro, s.lang)
+	add(s.distro, s.lang)
+	add(s.arch, s.lang)
+	add(s.lang)
+	add(s.arch, s.distro)
+	add(s.distro)
+	add(s.arch)
+	names = append(names, base)
+
+	for _, name := range names {
+		if a, ok := vars[name]; ok {
+			return a
+		}
+	}
+	return nil
+}
+
+// overrideSuffix - разобранный суффикс переопределения
+type overrideSuffix struct {
+	arch, distro, lang string
+}
+
+// parseSuffixParts разбирает суффикс вида "arch_distro_lang", любая часть может отсутствовать
+func parseSuffixParts(suffix string) overrideSuffix {
+	var s overrideSuffix
+	if suffix == "" {
+		return s
+	}
+	parts := strings.Split(suffix, "_")
+	i := 0
+	if i < len(parts) && slices.Contains(cpu.KnownArches(), parts[i]) {
+		s.arch = parts[i]
+		i++
+	}
+	if i < len(parts) && !isLanguage(parts[i]) {
+		s.distro = parts[i]
+		i++
+	}
+	if i < len(parts) && isLanguage(parts[i]) {
+		s.lang = parts[i]
+	}
+	return s
+}
+
+// parseSuffix проверяет суффикс переопределения. Части должны идти в том же
+// порядке, что и в overrides.Resolve: архитектура, дистрибутив, язык.
+func parseSuffix(suffix string) error {
+	parts := strings.Split(suffix, "_")
+	i := 0
+	if i < len(parts) && slices.Contains(cpu.KnownArches(), parts[i]) {
+		i++
+	}
+	if i < len(parts) && slices.Contains(overrides.KnownDistroIDs(), parts[i]) {
+		i++
+	}
+	if i < len(parts) && isLanguage(parts[i]) {
+		i++
+	}
+	if i == len(parts) {
+		return nil
+	}
+
+	part := parts[i]
+	switch {
+	case slices.Contains(cpu.KnownArches(), part):
+		return errors.New(gotext.Get("architecture %q must come first", part))
+	case slices.Contains(overrides.KnownDistroIDs(), part):
+		return errors.New(gotext.Get("distribution %q must come before the language", part))
+	default:
+		return errors.New(gotext.Get("%q is not a known architecture, distribution or language", part))
+	}
+}
+
+// isLanguage сообщает, что строка - двухбуквенный код языка ISO 639-1
+func isLanguage(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	_, err := language.ParseBase(s)
+	return err == nil
+}
+
+// knownVariables возвращает имена переменных, которые читает ALR, и признак
+// поддержки переопределений для каждой из них
+func knownVariables() map[string]bool {
+	out := map[string]bool{"name": false, "basepkg_name": false}
+	t := reflect.TypeOf(alrsh.Package{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("sh"), ",")
+		if name == "" {
+			continue
+		}
+		out[name] = strings.HasPrefix(field.Type.Name(), "OverridableField")
+	}
+	return out
+}
+
+// splitKnown находит самое длинное известное имя, являющееся префиксом name
+func splitKnown(name string, known []string) (base, suffix string, ok bool) {
+	for _, k := range known {
+		if s, found := strings.CutPrefix(name, k+"_"); found && len(k) > len(base) {
+			base, suffix, ok = k, s, true
+		}
+	}
+	return base, suffix, ok
+}
+
+// checkVariables проверяет суффиксы переопределений и неизвестные переменные
+func (l *linter) checkVariables() {
+	known := knownVariables()
+	names := make([]string, 0, len(known))
+	for name := range known {
+		names = append(names, name)
+	}
+
+	scopes := []scope{l.vars}
+	for _, vars := range l.metaVars {
+		scopes = append(scopes, vars)
+	}
+
+	for _, vars := range scopes {
+		for name, a := range vars {
+			if _, ok := known[name]; ok {
+				continue
+			}
+
+			base, suffix, ok := splitKnown(name, names)
+			switch {
+			case ok && !known[base]:
+				if parseSuffix(suffix) == nil {
+					l.report(a.pos, RuleNotOverridable, gotext.Get("variable %s does not support overrides, %s is ignored", base, name))
+				} else if !l.usedVars[name] {
+					l.report(a.pos, RuleUnknownVariable, gotext.Get("variable %s is not used by ALR or the script", name))
+				}
+			case ok:
+				if err := parseSuffix(suffix); err != nil && !l.usedVars[name] {
+					l.report(a.pos, RuleUnknownOverride, gotext.Get("unknown override %s: %s", name, err))
+				}
+			case !l.usedVars[name]:
+				l.report(a.pos, RuleUnknownVariable, gotext.Get("variable %s is not used by ALR or the script", name))
+			}
+		}
+	}
+}
+
+// checkFunctions проверяет суффиксы переопределений функций и неизвестные функции
+func (l *linter) checkFunctions() {
+	known := []string{"prepare", "build", "package", "files"}
+	for _, pkg := range l.packageNames() {
+		known = append(known, "package_"+pkg, "files_"+pkg, "meta_"+pkg)
+	}
+
+	for name, fn := range l.funcs {
+		if slices.Contains(known, name) || l.usedFuncs[name] {
+			continue
+		}
+		base, suffix, ok := splitKnown(name, known)
+		if !ok {
+			l.report(fn.Pos(), RuleUnknownFunction, gotext.Get("function %s is not called by ALR or the script", name))
+			continue
+		}
+		if err := parseSuffix(suffix); err != nil {
+			l.report(fn.Pos(), RuleUnknownOverride, gotext.Get("unknown override %s of function %s: %s", name, base, err))
+		}
+	}
+}
+
+// shells - интерпретаторы, передача загруженных данных в которые небезопасна
+var shells = []string{"sh", "bash", "zsh", "dash", "ash", "python", "python3", "perl"}
+
+// checkUnsafe ищет небезопасные конструкции во всём скрипте
+func (l *linter) checkUnsafe(file *syntax.File) {
+	syntax.Walk(file, func(node syntax.Node) bool {
+		switch n := node.(type) {
+		case *syntax.BinaryCmd:
+			if n.Op != syntax.Pipe && n.Op != syntax.PipeAll {
+				return true
+			}
+			if commandName(n.X) == "curl" || commandName(n.X) == "wget" {
+				if slices.Contains(shells, commandName(n.Y)) {
+					l.report(n.Pos(), RuleUnsafeConstruct, gotext.Get("downloaded content is piped into %s; add the file to sources with a checksum instead", commandName(n.Y)))
+				}
+			}
+		case *syntax.CallExpr:
+			if len(n.Args) == 0 {
+				return true
+			}
+			switch cmd := wordString(n.Args[0]); cmd {
+			case "sudo", "su", "doas", "pkexec":
+				l.report(n.Pos(), RuleUnsafeConstruct, gotext.Get("%s must not be used in build scripts", cmd))
+			case "eval":
+				l.report(n.Pos(), RuleUnsafeConstruct, gotext.Get("eval makes the script impossible to analyse"))
+			case "rm":
+				l.checkRemove(n)
+			}
+		}
+		return true
+	})
+}
+
+// checkRemove ищет рекурсивное удаление абсолютных путей вне $pkgdir и $srcdir
+func (l *linter) checkRemove(call *syntax.CallExpr) {
+	recursive := false
+	for _, arg := range call.Args[1:] {
+		s := wordString(arg)
+		if strings.HasPrefix(s, "-") && !strings.HasPrefix(s, "--") && strings.ContainsAny(s, "rR") || s == "--recursive" {
+			recursive = true
+		}
+	}
+	if !recursive {
+		return
+	}
+
+	for _, arg := range call.Args[1:] {
+		if lit, ok := arg.Parts[0].(*syntax.Lit); ok && (strings.HasPrefix(lit.Value, "/") || strings.HasPrefix(lit.Value, "~")) {
+			l.report(call.Pos(), RuleUnsafeConstruct, gotext.Get("recursive removal of %s outside $pkgdir and $srcdir", wordString(arg)))
+		}
+	}
+}
+
+// commandName возвращает имя команды простой команды в конвейере
+func commandName(stmt *syntax.Stmt) string {
+	if call, ok := stmt.Cmd.(*syntax.CallExpr); ok && len(call.Args) > 0 {
+		return wordString(call.Args[0])
+	}
+	return ""
+}
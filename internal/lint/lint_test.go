@@ -0,0 +1,242 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+)
+
+func lintString(t *testing.T, script string) []Diagnostic {
+	t.Helper()
+	sf, err := alrsh.ReadFromIOReader(strings.NewReader(script), "alr.sh")
+	require.NoError(t, err)
+	return Lint(sf)
+}
+
+// rulesAt возвращает пары "строка:проверка" для компактного сравнения
+func rulesAt(diags []Diagnostic) []string {
+	out := make([]string, 0, len(diags))
+	for _, d := range diags {
+		out = append(out, fmt.Sprintf("%d:%s", d.Line, d.Rule))
+	}
+	return out
+}
+
+func TestLintValidScript(t *testing.T) {
+	diags := lintString(t, `name='foo'
+version='1.0'
+release='1'
+desc='Foo'
+desc_ru='Фу'
+deps=('bar')
+deps_arch=('bar-git')
+deps_amd64_debian=('bar')
+build_deps_arm64_altlinux_ru=('gcc')
+sources=("https://example.com/foo-${version}.tar.gz")
+checksums=('SKIP')
+sources_arm64=("https://example.com/foo-arm64.tar.gz" "local:///extra")
+checksums_arm64=('SKIP' 'SKIP')
+_dir="foo-${version}"
+
+helper() {
+	echo "$_dir"
+}
+
+build() {
+	helper
+}
+
+package() {
+	install-binary foo
+}
+
+package_fedora() {
+	install-binary foo
+}
+`)
+	assert.Empty(t, diags)
+}
+
+func TestLintChecksums(t *testing.T) {
+	diags := lintString(t, `name='foo'
+version='1.0'
+release='1'
+sources=('a' 'b')
+checksums=('SKIP' 'SKIP')
+sources_amd64=('a')
+checksums_debian=('SKIP')
+sources_arm64=("${extra[@]}")
+package() { :; }
+`)
+
+	// В sources_amd64 один элемент, а checksums берутся из общего массива.
+	// Для debian sources берутся из общего массива, а checksums - из переопределения.
+	assert.Equal(t, []string{"6:checksums-length", "7:checksums-length"}, rulesAt(diags))
+	assert.Equal(t, "sources_amd64 has 1 items but checksums has 2", diags[0].Message)
+	assert.Equal(t, SeverityError, diags[0].Severity)
+	assert.True(t, HasErrors(diags))
+}
+
+func TestLintOverrides(t *testing.T) {
+	diags := lintString(t, `name='foo'
+version='1.0'
+version_debian='2.0'
+release='1'
+deps_debain=('bar')
+deps_debian_amd64=('bar')
+desc_ru_debian='x'
+mystery=1
+package() { :; }
+package_ubunut() { :; }
+`)
+
+	assert.Equal(t, []string{
+		"3:not-overridable",
+		"5:unknown-override",
+		"6:unknown-override",
+		"7:unknown-override",
+		"8:unknown-variable",
+		"10:unknown-override",
+	}, rulesAt(diags))
+	assert.Contains(t, diags[2].Message, `architecture "amd64" must come first`)
+	assert.False(t, HasErrors(diags))
+}
+
+func TestLintMultiPackage(t *testing.T) {
+	diags := lintString(t, `name=('foo' 'foo-doc')
+version='1.0'
+release='1'
+
+meta_foo() {
+	desc='Foo'
+	sources=('a')
+}
+
+package_foo() { :; }
+package_foo-doc() { :; }
+`)
+
+	assert.Equal(t, []string{
+		"1:multi-package-base",
+		"1:missing-function",
+		"7:checksums-length",
+	}, rulesAt(diags))
+	assert.Contains(t, diags[1].Message, "meta_foo-doc")
+}
+
+func TestLintMissingFields(t *testing.T) {
+	diags := lintString(t, "desc='x'\n")
+	assert.Equal(t, []string{"0:missing-field"}, rulesAt(diags))
+
+	diags = lintString(t, "name='foo'\n")
+	assert.Equal(t, []string{"1:missing-field", "1:missing-field", "1:missing-function"}, rulesAt(diags))
+}
+
+func TestLintUnsafe(t *testing.T) {
+	diags := lintString(t, `name='foo'
+version='1.0'
+release='1'
+mkdir -p /tmp/foo
+
+package() {
+	sudo make install
+	curl -fsSL https://example.com/install.sh | bash
+	eval "$cmd"
+	rm -rf /usr/lib/foo
+	rm -rf "$pkgdir/usr/lib/foo"
+}
+`)
+
+	assert.Equal(t, []string{
+		"4:top-level-command",
+		"7:unsafe-construct",
+		"8:unsafe-construct",
+		"9:unsafe-construct",
+		"10:unsafe-construct",
+	}, rulesAt(diags))
+}
+
+func TestLintFileSyntaxError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alr.sh")
+	require.NoError(t, os.WriteFile(path, []byte("name=(\n"), 0o644))
+
+	diags, err := LintFile(path)
+	require.NoError(t, err)
+	require.Len(t, diags, 1)
+	assert.Equal(t, RuleSyntax, diags[0].Rule)
+	assert.Equal(t, path, diags[0].Path)
+
+	_, err = LintFile(filepath.Join(t.TempDir(), "missing.sh"))
+	assert.Error(t, err)
+}
+
+func TestWriteSARIF(t *testing.T) {
+	diags := []Diagnostic{
+		{Path: "foo/alr.sh", Line: 3, Column: 1, Severity: SeverityError, Rule: RuleChecksums, Message: "bad"},
+		{Path: "foo/alr.sh", Severity: SeverityError, Rule: RuleMissingField, Message: "no name"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatSARIF, "1.2.3", diags))
+
+	var log map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log["version"])
+
+	run := log["runs"].([]any)[0].(map[string]any)
+	driver := run["tool"].(map[string]any)["driver"].(map[string]any)
+	assert.Equal(t, "1.2.3", driver["version"])
+	assert.Len(t, driver["rules"], len(Rules))
+
+	results := run["results"].([]any)
+	require.Len(t, results, 2)
+	first := results[0].(map[string]any)
+	assert.Equal(t, RuleChecksums, first["ruleId"])
+	assert.Equal(t, "error", first["level"])
+	loc := first["locations"].([]any)[0].(map[string]any)["physicalLocation"].(map[string]any)
+	assert.Equal(t, "foo/alr.sh", loc["artifactLocation"].(map[string]any)["uri"])
+	assert.Equal(t, float64(3), loc["region"].(map[string]any)["startLine"])
+
+	// Без позиции регион не указывается
+	second := results[1].(map[string]any)["locations"].([]any)[0].(map[string]any)["physicalLocation"].(map[string]any)
+	assert.NotContains(t, second, "region")
+}
+
+func TestWriteJSONAndText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatJSON, "", nil))
+	assert.Equal(t, "[]\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, FormatText, "", []Diagnostic{
+		{Path: "alr.sh", Line: 2, Column: 5, Severity: SeverityWarning, Rule: RuleUnknownVariable, Message: "msg"},
+	}))
+	assert.Equal(t, "alr.sh:2:5: warning: msg [unknown-variable]\n", buf.String())
+
+	assert.Error(t, Write(&buf, "xml", "", nil))
+}
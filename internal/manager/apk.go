@@ -20,15 +20,24 @@
 package manager
 
 import (
+	"archive/tar"
 	"bufio"
+	"compress/gzip"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
+// apkKeysDir - каталог доверенных ключей apk
+const apkKeysDir = "/etc/apk/keys"
+
 // APK represents the APK package manager
 type APK struct {
 	CommonPackageManager
+	index   *installedIndex
+	keysDir string
 }
 
 func NewAPK() *APK {
@@ -36,6 +45,8 @@ func NewAPK() *APK {
 		CommonPackageManager: CommonPackageManager{
 			noConfirmArg: "-i",
 		},
+		index:   newAPKIndex(apkInstalledPath),
+		keysDir: apkKeysDir,
 	}
 }
 
@@ -77,7 +88,14 @@ func (a *APK) Install(opts *Opts, pkgs ...string) error {
 
 func (a *APK) InstallLocal(opts *Opts, pkgs ...string) error {
 	opts = ensureOpts(opts)
-	cmd := a.getCmd(opts, "apk", "add", "--allow-untrusted")
+	cmd := a.getCmd(opts, "apk", "add")
+	// Проверку подписи отключаем, только если какой-то пакет не подписан доверенным ключом
+	for _, pkg := range pkgs {
+		if !a.isTrusted(pkg) {
+			cmd.Args = append(cmd.Args, "--allow-untrusted")
+			break
+		}
+	}
 	cmd.Args = append(cmd.Args, pkgs...)
 	setCmdEnv(cmd)
 	err := cmd.Run()
@@ -172,6 +190,10 @@ func (a *APK) IsAvailable(name string) (bool, error) {
 }
 
 func (a *APK) ListInstalled(opts *Opts) (map[string]string, error) {
+	if pkgs, ok := a.index.list(); ok {
+		return pkgs, nil
+	}
+
 	out := map[string]string{}
 	cmd := exec.Command("apk", "list", "-I")
 
@@ -209,6 +231,10 @@ func (a *APK) ListInstalled(opts *Opts) (map[string]string, error) {
 }
 
 func (a *APK) IsInstalled(pkg string) (bool, error) {
+	if installed, ok := a.index.isInstalled(pkg, false); ok {
+		return installed, nil
+	}
+
 	cmd := exec.Command("apk", "info", "--installed", pkg)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -224,6 +250,10 @@ func (a *APK) IsInstalled(pkg string) (bool, error) {
 }
 
 func (a *APK) GetInstalledVersion(pkg string) (string, error) {
+	if version, ok := a.index.version(pkg, false); ok {
+		return version, nil
+	}
+
 	cmd := exec.Command("apk", "info", "--installed", pkg)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -252,3 +282,40 @@ func (a *APK) GetInstalledVersion(pkg string) (string, error) {
 
 	return line[lastDash+1:], nil
 }
+
+// isTrusted сообщает, подписан ли пакет по path ключом из keysDir
+func (a *APK) isTrusted(path string) bool {
+	keyName, err := apkSigningKey(path)
+	if err != nil || keyName == "" {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(a.keysDir, keyName))
+	return err == nil
+}
+
+// apkSigningKey возвращает имя ключа, которым подписан пакет apk.
+// Подпись хранится в первом потоке gzip в файле .SIGN.RSA.<ключ>.
+func apkSigningKey(path string) (string, error) {
+	fl, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer fl.Close()
+
+	gr, err := gzip.NewReader(fl)
+	if err != nil {
+		return "", err
+	}
+	gr.Multistream(false)
+
+	hdr, err := tar.NewReader(gr).Next()
+	if err != nil {
+		return "", err
+	}
+	for _, prefix := range []string{".SIGN.RSA.", ".SIGN.RSA256.", ".SIGN.RSA512."} {
+		if name, ok := strings.CutPrefix(hdr.Name, prefix); ok {
+			return filepath.Base(name), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,87 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package manager
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/nfpm/v2"
+	_ "github.com/goreleaser/nfpm/v2/apk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildAPK(t *testing.T, dir, name, keyFile string) string {
+	t.Helper()
+
+	info := nfpm.WithDefaults(&nfpm.Info{
+		Name:       name,
+		Arch:       "amd64",
+		Version:    "1.0",
+		Maintainer: "ALR <alr@example.com>",
+	})
+	if keyFile != "" {
+		info.APK.Signature.KeyFile = keyFile
+		info.APK.Signature.KeyName = "alr.rsa.pub"
+	}
+
+	packager, err := nfpm.Get("apk")
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, name+".apk")
+	fl, err := os.Create(path)
+	require.NoError(t, err)
+	defer fl.Close()
+	require.NoError(t, packager.Package(info, fl))
+	return path
+}
+
+func TestAPKIsTrusted(t *testing.T) {
+	dir := t.TempDir()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "alr.rsa")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}), 0o600))
+
+	signed := buildAPK(t, dir, "signed", keyFile)
+	unsigned := buildAPK(t, dir, "unsigned", "")
+
+	name, err := apkSigningKey(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "alr.rsa.pub", name)
+
+	keysDir := filepath.Join(dir, "keys")
+	require.NoError(t, os.MkdirAll(keysDir, 0o755))
+	apk := &APK{keysDir: keysDir}
+
+	// Ключа ещё нет среди доверенных
+	assert.False(t, apk.isTrusted(signed))
+
+	require.NoError(t, os.WriteFile(filepath.Join(keysDir, "alr.rsa.pub"), nil, 0o644))
+	assert.True(t, apk.isTrusted(signed))
+	assert.False(t, apk.isTrusted(unsigned))
+	assert.False(t, apk.isTrusted(filepath.Join(dir, "missing.apk")))
+}
@@ -29,6 +29,7 @@ import (
 // APT represents the APT package manager
 type APT struct {
 	CommonPackageManager
+	index *installedIndex
 }
 
 func NewAPT() *APT {
@@ -36,6 +37,7 @@ func NewAPT() *APT {
 		CommonPackageManager: CommonPackageManager{
 			noConfirmArg: "-y",
 		},
+		index: newDpkgIndex(dpkgStatusPath),
 	}
 }
 
@@ -149,6 +151,10 @@ func (a *APT) UpgradeAll(opts *Opts) error {
 }
 
 func (a *APT) ListInstalled(opts *Opts) (map[string]string, error) {
+	if pkgs, ok := a.index.list(); ok {
+		return pkgs, nil
+	}
+
 	out := map[string]string{}
 	cmd := exec.Command("dpkg-query", "-f", "${Package}\u200b${Version}\\n", "-W")
 
@@ -180,6 +186,10 @@ func (a *APT) ListInstalled(opts *Opts) (map[string]string, error) {
 }
 
 func (a *APT) IsInstalled(pkg string) (bool, error) {
+	if installed, ok := a.index.isInstalled(pkg, true); ok {
+		return installed, nil
+	}
+
 	resolved := a.resolvePackageName(pkg)
 	cmd := exec.Command("dpkg-query", "-f", "${Status}", "-W", resolved)
 	output, err := cmd.CombinedOutput()
@@ -214,6 +224,10 @@ func (a *APT) IsAvailable(name string) (bool, error) {
 }
 
 func (a *APT) GetInstalledVersion(pkg string) (string, error) {
+	if version, ok := a.index.version(pkg, true); ok {
+		return version, nil
+	}
+
 	resolved := a.resolvePackageName(pkg)
 	cmd := exec.Command("dpkg-query", "-f", "${Version}", "-W", resolved)
 	output, err := cmd.CombinedOutput()
@@ -33,6 +33,9 @@ func NewAPTRpm() *APTRpm {
 		CommonPackageManager: CommonPackageManager{
 			noConfirmArg: "-y",
 		},
+		CommonRPM: CommonRPM{
+			index: newRPMIndex(),
+		},
 	}
 }
 
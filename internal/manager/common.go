@@ -43,7 +43,7 @@ func (m *CommonPackageManager) getCmd(opts *Opts, mgrCmd string, args ...string)
 	cmd.Args = append(cmd.Args, opts.Args...)
 	cmd.Args = append(cmd.Args, args...)
 
-	if opts.NoConfirm {
+	if opts.NoConfirm && m.noConfirmArg != "" {
 		cmd.Args = append(cmd.Args, m.noConfirmArg)
 	}
 
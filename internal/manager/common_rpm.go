@@ -23,9 +23,15 @@ import (
 	"strings"
 )
 
-type CommonRPM struct{}
+type CommonRPM struct {
+	index *installedIndex
+}
 
 func (c *CommonRPM) ListInstalled(opts *Opts) (map[string]string, error) {
+	if pkgs, ok := c.index.list(); ok {
+		return pkgs, nil
+	}
+
 	out := map[string]string{}
 	cmd := exec.Command("rpm", "-qa", "--queryformat", "%{NAME}\u200b%|EPOCH?{%{EPOCH}:}:{}|%{VERSION}-%{RELEASE}\\n")
 
@@ -58,6 +64,13 @@ func (c *CommonRPM) ListInstalled(opts *Opts) (map[string]string, error) {
 }
 
 func (a *CommonRPM) IsInstalled(pkg string) (bool, error) {
+	// Пути к файлам (rpm -q --whatprovides /usr/bin/foo) индекс не покрывает
+	if !strings.HasPrefix(pkg, "/") {
+		if installed, ok := a.index.isInstalled(pkg, true); ok {
+			return installed, nil
+		}
+	}
+
 	cmd := exec.Command("rpm", "-q", "--whatprovides", pkg)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -72,6 +85,10 @@ func (a *CommonRPM) IsInstalled(pkg string) (bool, error) {
 }
 
 func (a *CommonRPM) GetInstalledVersion(pkg string) (string, error) {
+	if version, ok := a.index.version(pkg, false); ok {
+		return version, nil
+	}
+
 	cmd := exec.Command("rpm", "-q", "--queryformat", "%|EPOCH?{%{EPOCH}:}:{}|%{VERSION}-%{RELEASE}", pkg)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
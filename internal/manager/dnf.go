@@ -35,6 +35,9 @@ func NewDNF() *DNF {
 		CommonPackageManager: CommonPackageManager{
 			noConfirmArg: "-y",
 		},
+		CommonRPM: CommonRPM{
+			index: newRPMIndex(),
+		},
 	}
 }
 
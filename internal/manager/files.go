@@ -0,0 +1,271 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package manager
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// FileQuerier реализуют менеджеры, которые умеют перечислять файлы
+// локальных пакетов и находить владельцев установленных файлов.
+// Используется для проверки конфликтов файлов перед установкой.
+type FileQuerier interface {
+	// PackageFiles возвращает абсолютные пути файлов (без каталогов)
+	// локального пакета path
+	PackageFiles(path string) ([]string, error)
+	// FileOwners возвращает имена установленных пакетов, которым
+	// принадлежат пути paths. Пути без владельца в ответ не попадают.
+	FileOwners(paths []string) (map[string][]string, error)
+}
+
+// AsFileQuerier возвращает FileQuerier для менеджера m, если он его поддерживает
+func AsFileQuerier(m Manager) (FileQuerier, bool) {
+	if c, ok := m.(*CachedManager); ok {
+		m = c.Manager
+	}
+	fq, ok := m.(FileQuerier)
+	return fq, ok
+}
+
+// ownerQueryChunk ограничивает число путей в одном вызове менеджера
+const ownerQueryChunk = 256
+
+// queryOwners вызывает команду name с аргументами args и порциями путей
+// paths, разбирая вывод функцией parse. Код возврата 1 означает, что
+// для части путей владелец не найден, и ошибкой не считается.
+func queryOwners(
+	paths []string,
+	parse func(out []byte, owners map[string][]string),
+	name string,
+	args ...string,
+) (map[string][]string, error) {
+	owners := map[string][]string{}
+	for start := 0; start < len(paths); start += ownerQueryChunk {
+		end := min(start+ownerQueryChunk, len(paths))
+
+		cmd := exec.Command(name, append(args, paths[start:end]...)...)
+		cmd.Env = append(os.Environ(), "LC_ALL=C")
+		out, err := cmd.Output()
+		var exitErr *exec.ExitError
+		if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+			return nil, err
+		}
+		parse(out, owners)
+	}
+	return owners, nil
+}
+
+// parseDpkgSearch разбирает вывод dpkg-query -S:
+// "pkg1:amd64, pkg2: /usr/bin/foo"
+func parseDpkgSearch(out []byte, owners map[string][]string) {
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "diversion by ") {
+			continue
+		}
+		pkgs, file, ok := strings.Cut(line, ": ")
+		if !ok || !strings.HasPrefix(file, "/") {
+			continue
+		}
+		for _, pkg := range strings.Split(pkgs, ",") {
+			pkg, _, _ = strings.Cut(strings.TrimSpace(pkg), ":")
+			owners[file] = append(owners[file], pkg)
+		}
+	}
+}
+
+var pacmanOwnedRegex = regexp.MustCompile(`^(/.*) is owned by (\S+) \S+$`)
+
+// parsePacmanOwns разбирает вывод pacman -Qo:
+// "/usr/bin/ls is owned by coreutils 9.4-3"
+func parsePacmanOwns(out []byte, owners map[string][]string) {
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		m := pacmanOwnedRegex.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		owners[m[1]] = append(owners[m[1]], m[2])
+	}
+}
+
+var apkOwnedRegex = regexp.MustCompile(`^(/.*) is owned by (.+)-[^-]+-r\d+$`)
+
+// parseAPKOwns разбирает вывод apk info --who-owns:
+// "/bin/busybox is owned by busybox-1.36.1-r15"
+func parseAPKOwns(out []byte, owners map[string][]string) {
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		m := apkOwnedRegex.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		owners[m[1]] = append(owners[m[1]], m[2])
+	}
+}
+
+// tarFiles возвращает абсолютные пути файлов и ссылок архива tar.
+// Служебные файлы в корне архива (.PKGINFO, .SIGN.* и т.п.) пропускаются.
+func tarFiles(r io.Reader) ([]string, error) {
+	var files []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		name := strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+		if !strings.Contains(name, "/") && strings.HasPrefix(name, ".") {
+			continue
+		}
+		files = append(files, "/"+name)
+	}
+}
+
+func (a *APT) PackageFiles(pkgPath string) ([]string, error) {
+	cmd := exec.Command("dpkg-deb", "--fsys-tarfile", pkgPath)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	files, err := tarFiles(stdout)
+	if err != nil {
+		_ = cmd.Wait()
+		return nil, err
+	}
+	return files, cmd.Wait()
+}
+
+func (a *APT) FileOwners(paths []string) (map[string][]string, error) {
+	return queryOwners(paths, parseDpkgSearch, "dpkg-query", "-S")
+}
+
+func (a *APK) PackageFiles(pkgPath string) ([]string, error) {
+	fl, err := os.Open(pkgPath)
+	if err != nil {
+		return nil, err
+	}
+	defer fl.Close()
+
+	// Пакет apk - это несколько потоков gzip, образующих один архив tar
+	gr, err := gzip.NewReader(fl)
+	if err != nil {
+		return nil, err
+	}
+	return tarFiles(gr)
+}
+
+func (a *APK) FileOwners(paths []string) (map[string][]string, error) {
+	return queryOwners(paths, parseAPKOwns, "apk", "info", "--who-owns")
+}
+
+func (p *Pacman) PackageFiles(pkgPath string) ([]string, error) {
+	cmd := exec.Command("pacman", "-Qlpq", pkgPath)
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line == "" || strings.HasSuffix(line, "/") {
+			continue
+		}
+		files = append(files, line)
+	}
+	return files, nil
+}
+
+func (p *Pacman) FileOwners(paths []string) (map[string][]string, error) {
+	return queryOwners(paths, parsePacmanOwns, "pacman", "-Qo")
+}
+
+// rpmFileListFormat выводит права и путь каждого файла пакета
+const rpmFileListFormat = "[%{FILEMODES:perms}\t%{FILENAMES}\n]"
+
+// parseRPMFileList разбирает вывод rpm с форматом rpmFileListFormat,
+// пропуская каталоги
+func parseRPMFileList(out []byte) []string {
+	var files []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		perms, file, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok || strings.HasPrefix(perms, "d") || !strings.HasPrefix(file, "/") {
+			continue
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+func (c *CommonRPM) PackageFiles(pkgPath string) ([]string, error) {
+	cmd := exec.Command("rpm", "-qp", "--nosignature", "--nodigest", "--queryformat", rpmFileListFormat, pkgPath)
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseRPMFileList(out), nil
+}
+
+// FileOwners для rpm перебирает файлы всех установленных пакетов за один
+// вызов: rpm -qf не позволяет сопоставить пакет с запрошенным путём,
+// если у пути несколько владельцев.
+func (c *CommonRPM) FileOwners(paths []string) (map[string][]string, error) {
+	wanted := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		wanted[p] = true
+	}
+
+	cmd := exec.Command("rpm", "-qa", "--queryformat", "[%{FILENAMES}\t%{NAME}\n]")
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	owners := map[string][]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		file, name, ok := strings.Cut(scanner.Text(), "\t")
+		if ok && wanted[file] {
+			owners[file] = append(owners[file], name)
+		}
+	}
+	return owners, scanner.Err()
+}
@@ -0,0 +1,86 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/goreleaser/nfpm/v2/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPKPackageFiles(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "foo")
+	require.NoError(t, os.WriteFile(src, []byte("#!/bin/sh\n"), 0o755))
+
+	info := nfpm.WithDefaults(&nfpm.Info{
+		Name:       "foo",
+		Arch:       "amd64",
+		Version:    "1.0",
+		Maintainer: "ALR <alr@example.com>",
+		Overridables: nfpm.Overridables{
+			Contents: files.Contents{
+				{Source: src, Destination: "/usr/bin/foo"},
+				{Destination: "/usr/share/foo", Type: files.TypeDir},
+				{Source: "/usr/bin/foo", Destination: "/usr/bin/bar", Type: files.TypeSymlink},
+			},
+		},
+	})
+	packager, err := nfpm.Get("apk")
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "foo.apk")
+	fl, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, packager.Package(info, fl))
+	require.NoError(t, fl.Close())
+
+	got, err := (&APK{}).PackageFiles(path)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"/usr/bin/foo", "/usr/bin/bar"}, got)
+}
+
+func TestParseFileOwners(t *testing.T) {
+	owners := map[string][]string{}
+	parseDpkgSearch([]byte(
+		"diversion by dash from: /bin/sh\n"+
+			"coreutils: /usr/bin/ls\n"+
+			"libfoo:amd64, libfoo:i386: /usr/share/doc/libfoo/copyright\n",
+	), owners)
+	assert.Equal(t, map[string][]string{
+		"/usr/bin/ls":                     {"coreutils"},
+		"/usr/share/doc/libfoo/copyright": {"libfoo", "libfoo"},
+	}, owners)
+
+	owners = map[string][]string{}
+	parsePacmanOwns([]byte("/usr/bin/ls is owned by coreutils 9.4-3\n"), owners)
+	assert.Equal(t, map[string][]string{"/usr/bin/ls": {"coreutils"}}, owners)
+
+	owners = map[string][]string{}
+	parseAPKOwns([]byte("/bin/busybox is owned by busybox-binsh-1.36.1-r15\n"), owners)
+	assert.Equal(t, map[string][]string{"/bin/busybox": {"busybox-binsh"}}, owners)
+
+	assert.Equal(t, []string{"/usr/bin/foo"}, parseRPMFileList([]byte(
+		"drwxr-xr-x\t/usr/share/foo\n"+
+			"-rwxr-xr-x\t/usr/bin/foo\n",
+	)))
+}
@@ -0,0 +1,292 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package manager
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	_ "modernc.org/sqlite"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/rpmheader"
+)
+
+// Расположение баз установленных пакетов
+var (
+	dpkgStatusPath     = "/var/lib/dpkg/status"
+	pacmanLocalDir     = "/var/lib/pacman/local"
+	apkInstalledPath   = "/lib/apk/db/installed"
+	rpmdbSQLiteDirs    = []string{"/usr/lib/sysimage/rpm", "/var/lib/rpm"}
+	rpmdbSQLiteName    = "rpmdb.sqlite"
+	maxControlLineSize = 1024 * 1024
+)
+
+func newDpkgIndex(path string) *installedIndex {
+	return newInstalledIndex("dpkg", func() (*installedSnapshot, error) {
+		return readDpkgStatus(path)
+	}, path)
+}
+
+func newPacmanIndex(dir string) *installedIndex {
+	return newInstalledIndex("pacman", func() (*installedSnapshot, error) {
+		return readPacmanLocal(dir)
+	}, dir)
+}
+
+func newAPKIndex(path string) *installedIndex {
+	return newInstalledIndex("apk", func() (*installedSnapshot, error) {
+		return readAPKInstalled(path)
+	}, path)
+}
+
+// newRPMIndex ищет rpmdb в формате sqlite. Базы в форматах bdb и ndb
+// (например, в ALT Linux и старых openSUSE) не поддерживаются,
+// для них индекс недоступен.
+func newRPMIndex() *installedIndex {
+	for _, dir := range rpmdbSQLiteDirs {
+		path := filepath.Join(dir, rpmdbSQLiteName)
+		if _, err := os.Stat(path); err == nil {
+			return newRPMIndexAt(path)
+		}
+	}
+	return nil
+}
+
+func newRPMIndexAt(path string) *installedIndex {
+	return newInstalledIndex("rpm", func() (*installedSnapshot, error) {
+		return readRPMDB(path)
+	}, path, path+"-wal")
+}
+
+// forEachStanza вызывает fn для каждого абзаца файла из строк "Ключ:значение",
+// разделённых пустыми строками. Сохраняются только ключи из keys, строки
+// продолжения (начинающиеся с пробела) присоединяются к предыдущему значению.
+func forEachStanza(r io.Reader, keys []string, fn func(fields map[string]string)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxControlLineSize)
+
+	fields := map[string]string{}
+	lastKey := ""
+	flush := func() {
+		if len(fields) > 0 {
+			fn(fields)
+		}
+		fields = map[string]string{}
+		lastKey = ""
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if lastKey != "" {
+				fields[lastKey] += " " + strings.TrimSpace(line)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || !slices.Contains(keys, key) {
+			lastKey = ""
+			continue
+		}
+		lastKey = key
+		fields[key] = strings.TrimSpace(value)
+	}
+	flush()
+
+	return scanner.Err()
+}
+
+// readDpkgStatus читает /var/lib/dpkg/status
+func readDpkgStatus(path string) (*installedSnapshot, error) {
+	fl, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fl.Close()
+
+	snap := newInstalledSnapshot()
+	err = forEachStanza(fl, []string{"Package", "Status", "Version", "Provides"}, func(fields map[string]string) {
+		// Status состоит из трёх слов: желаемое действие, флаг и состояние.
+		// Установленными считаются пакеты в состоянии installed, в том числе
+		// зафиксированные (hold) и помеченные на удаление (deinstall).
+		status := strings.Fields(fields["Status"])
+		if len(status) != 3 || status[2] != "installed" {
+			return
+		}
+
+		var provides []string
+		for _, p := range strings.Split(fields["Provides"], ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(p), " ")
+			provides = append(provides, name)
+		}
+
+		snap.add(fields["Package"], fields["Version"], provides...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// readPacmanLocal читает файлы desc в каталоге /var/lib/pacman/local
+func readPacmanLocal(dir string) (*installedSnapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := newInstalledSnapshot()
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		desc, err := os.ReadFile(filepath.Join(dir, entry.Name(), "desc"))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+
+		sections := parsePacmanDesc(desc)
+		if len(sections["NAME"]) == 0 || len(sections["VERSION"]) == 0 {
+			return nil, fmt.Errorf("pacman: invalid desc file in %s", entry.Name())
+		}
+
+		var provides []string
+		for _, p := range sections["PROVIDES"] {
+			provides = append(provides, stripVersionConstraint(p))
+		}
+
+		snap.add(sections["NAME"][0], sections["VERSION"][0], provides...)
+	}
+	return snap, nil
+}
+
+// parsePacmanDesc разбирает файл desc из секций "%NAME%" со значениями по одному в строке
+func parsePacmanDesc(data []byte) map[string][]string {
+	out := map[string][]string{}
+	section := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			section = ""
+		case len(line) > 2 && line[0] == '%' && line[len(line)-1] == '%':
+			section = line[1 : len(line)-1]
+		case section != "":
+			out[section] = append(out[section], line)
+		}
+	}
+	return out
+}
+
+// readAPKInstalled читает /lib/apk/db/installed
+func readAPKInstalled(path string) (*installedSnapshot, error) {
+	fl, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fl.Close()
+
+	snap := newInstalledSnapshot()
+	err = forEachStanza(fl, []string{"P", "V", "p"}, func(fields map[string]string) {
+		name, version := fields["P"], fields["V"]
+		if name == "" || version == "" {
+			return
+		}
+
+		var provides []string
+		for _, p := range strings.Fields(fields["p"]) {
+			provides = append(provides, stripVersionConstraint(p))
+		}
+
+		snap.add(name, version, provides...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// stripVersionConstraint отбрасывает ограничение версии ("foo=1.0", "foo>=1.0")
+func stripVersionConstraint(s string) string {
+	if i := strings.IndexAny(s, "<>="); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// readRPMDB читает заголовки пакетов из rpmdb.sqlite
+func readRPMDB(path string) (*installedSnapshot, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT blob FROM Packages")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snap := newInstalledSnapshot()
+	for rows.Next() {
+		var blob []byte
+		if err := rows.Scan(&blob); err != nil {
+			return nil, err
+		}
+
+		hdr, err := rpmheader.ParseBlob(blob)
+		if err != nil {
+			return nil, fmt.Errorf("rpm: %w", err)
+		}
+
+		name := hdr.String(rpmheader.TagName)
+		if name == "" {
+			continue
+		}
+
+		version := hdr.String(rpmheader.TagVersion) + "-" + hdr.String(rpmheader.TagRelease)
+		// Как и rpm --queryformat %|EPOCH?{%{EPOCH}:}:{}|, эпоха
+		// добавляется всегда, когда тег есть, в том числе нулевая
+		if epoch := hdr.Ints(rpmheader.TagEpoch); len(epoch) > 0 {
+			version = strconv.FormatInt(epoch[0], 10) + ":" + version
+		}
+
+		snap.add(name, version, hdr.Strings(rpmheader.TagProvideName)...)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return snap, nil
+}
@@ -0,0 +1,261 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package manager
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/rpmheader"
+)
+
+const dpkgStatus = `Package: libc6
+Status: install ok installed
+Priority: optional
+Architecture: amd64
+Version: 2.36-9+deb12u4
+Description: GNU C Library: Shared libraries
+ Contains the standard libraries that are used by nearly all programs on
+ the system.
+
+Package: removed-pkg
+Status: deinstall ok config-files
+Version: 1.0-1
+
+Package: foo+alr-default
+Status: install ok installed
+Version: 1:2.0-1
+Provides: foo (= 2.0), foo-virtual
+
+Package: held-pkg
+Status: hold ok installed
+Version: 3.1-2
+
+Package: deselected-pkg
+Status: deinstall ok installed
+Version: 0.9-1
+
+Package: half-pkg
+Status: install reinstreq half-installed
+Version: 4.0-1
+`
+
+func TestDpkgIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status")
+	require.NoError(t, os.WriteFile(path, []byte(dpkgStatus), 0o644))
+
+	apt := &APT{index: newDpkgIndex(path)}
+
+	installed, err := apt.ListInstalled(nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"libc6":           "2.36-9+deb12u4",
+		"foo+alr-default": "1:2.0-1",
+		"held-pkg":        "3.1-2",
+		"deselected-pkg":  "0.9-1",
+	}, installed)
+
+	ok, err := apt.IsInstalled("held-pkg")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = apt.IsInstalled("half-pkg")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	version, err := apt.GetInstalledVersion("deselected-pkg")
+	require.NoError(t, err)
+	assert.Equal(t, "0.9-1", version)
+
+	ok, err = apt.IsInstalled("foo-virtual")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = apt.IsInstalled("removed-pkg")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	version, err = apt.GetInstalledVersion("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "1:2.0-1", version)
+
+	version, err = apt.GetInstalledVersion("missing")
+	require.NoError(t, err)
+	assert.Equal(t, "", version)
+}
+
+func TestInstalledIndexReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status")
+	require.NoError(t, os.WriteFile(path, []byte(dpkgStatus), 0o644))
+
+	index := newDpkgIndex(path)
+	ok, available := index.isInstalled("bar", false)
+	require.True(t, available)
+	assert.False(t, ok)
+
+	// После изменения файла базы индекс должен перечитаться
+	updated := dpkgStatus + "\nPackage: bar\nStatus: install ok installed\nVersion: 3.0\n"
+	require.NoError(t, os.WriteFile(path, []byte(updated), 0o644))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, future, future))
+
+	version, available := index.version("bar", false)
+	require.True(t, available)
+	assert.Equal(t, "3.0", version)
+}
+
+func TestInstalledIndexUnavailable(t *testing.T) {
+	var nilIndex *installedIndex
+	_, ok := nilIndex.list()
+	assert.False(t, ok)
+
+	missing := newDpkgIndex(filepath.Join(t.TempDir(), "missing"))
+	_, ok = missing.list()
+	assert.False(t, ok)
+}
+
+func TestPacmanIndex(t *testing.T) {
+	dir := t.TempDir()
+	writeDesc := func(entry, desc string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, entry), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, entry, "desc"), []byte(desc), 0o644))
+	}
+	writeDesc("bash-5.2.026-2", "%NAME%\nbash\n\n%VERSION%\n5.2.026-2\n\n%PROVIDES%\nsh\n\n")
+	writeDesc("foo+alr-default-1.0-1", "%NAME%\nfoo+alr-default\n\n%VERSION%\n1:1.0-1\n\n%PROVIDES%\nfoo=1.0\n")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ALPM_DB_VERSION"), []byte("9\n"), 0o644))
+
+	pacman := &Pacman{index: newPacmanIndex(dir)}
+
+	installed, err := pacman.ListInstalled(nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"bash":            "5.2.026-2",
+		"foo+alr-default": "1:1.0-1",
+	}, installed)
+
+	// pacman -Q не учитывает provides
+	ok, err := pacman.IsInstalled("bash")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = pacman.IsInstalled("sh")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestAPKIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "installed")
+	db := "C:Q1abc=\nP:musl\nV:1.2.4-r2\nA:x86_64\np:so:libc.musl-x86_64.so.1=1\nF:lib\nR:ld-musl-x86_64.so.1\n\n" +
+		"P:foo+alr-default\nV:1.0-r1\nF:usr/bin\nR:foo\n"
+	require.NoError(t, os.WriteFile(path, []byte(db), 0o644))
+
+	apk := &APK{index: newAPKIndex(path)}
+
+	installed, err := apk.ListInstalled(nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"musl":            "1.2.4-r2",
+		"foo+alr-default": "1.0-r1",
+	}, installed)
+
+	version, err := apk.GetInstalledVersion("foo+alr-default")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0-r1", version)
+}
+
+// rpmHeaderBlob собирает заголовок rpm в формате хранения rpmdb.
+// При epoch < 0 тег эпохи не записывается.
+func rpmHeaderBlob(t *testing.T, name, version, release string, epoch int32, provides ...string) []byte {
+	t.Helper()
+
+	var index, data bytes.Buffer
+	count := 0
+	addEntry := func(tag int32, typ uint32, n uint32, payload []byte) {
+		for typ == rpmheader.TypeInt32 && data.Len()%4 != 0 {
+			data.WriteByte(0)
+		}
+		e := rpmheader.Entry{Tag: tag, Type: typ, Offset: int32(data.Len()), Count: n}
+		require.NoError(t, binary.Write(&index, binary.BigEndian, e))
+		data.Write(payload)
+		count++
+	}
+	str := func(s string) []byte { return append([]byte(s), 0) }
+
+	addEntry(rpmheader.TagName, rpmheader.TypeString, 1, str(name))
+	addEntry(rpmheader.TagVersion, rpmheader.TypeString, 1, str(version))
+	addEntry(rpmheader.TagRelease, rpmheader.TypeString, 1, str(release))
+	if epoch >= 0 {
+		addEntry(rpmheader.TagEpoch, rpmheader.TypeInt32, 1, binary.BigEndian.AppendUint32(nil, uint32(epoch)))
+	}
+	if len(provides) > 0 {
+		var payload []byte
+		for _, p := range provides {
+			payload = append(payload, str(p)...)
+		}
+		addEntry(rpmheader.TagProvideName, rpmheader.TypeStringArray, uint32(len(provides)), payload)
+	}
+
+	var blob bytes.Buffer
+	require.NoError(t, binary.Write(&blob, binary.BigEndian, uint32(count)))
+	require.NoError(t, binary.Write(&blob, binary.BigEndian, uint32(data.Len())))
+	blob.Write(index.Bytes())
+	blob.Write(data.Bytes())
+	return blob.Bytes()
+}
+
+func TestRPMIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rpmdb.sqlite")
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec("CREATE TABLE Packages (hnum INTEGER PRIMARY KEY AUTOINCREMENT, blob BLOB NOT NULL)")
+	require.NoError(t, err)
+	for _, blob := range [][]byte{
+		rpmHeaderBlob(t, "bash", "5.2.26", "1.fc40", -1, "bash", "/bin/sh"),
+		rpmHeaderBlob(t, "zlib", "1.3", "1.fc40", 0),
+		rpmHeaderBlob(t, "foo+alr-default", "1.0", "1.fc40", 2, "foo", "foo(x86-64)"),
+	} {
+		_, err = db.Exec("INSERT INTO Packages (blob) VALUES (?)", blob)
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+
+	rpm := &CommonRPM{index: newRPMIndexAt(path)}
+
+	installed, err := rpm.ListInstalled(nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"bash":            "5.2.26-1.fc40",
+		"zlib":            "0:1.3-1.fc40",
+		"foo+alr-default": "2:1.0-1.fc40",
+	}, installed)
+
+	ok, err := rpm.IsInstalled("foo(x86-64)")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	version, err := rpm.GetInstalledVersion("bash")
+	require.NoError(t, err)
+	assert.Equal(t, "5.2.26-1.fc40", version)
+}
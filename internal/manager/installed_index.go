@@ -0,0 +1,177 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package manager
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"strings"
+	"sync"
+)
+
+// installedSnapshot - прочитанное состояние базы установленных пакетов
+type installedSnapshot struct {
+	// versions сопоставляет имя пакета с его версией в формате,
+	// который возвращает ListInstalled соответствующего менеджера
+	versions map[string]string
+	// provides сопоставляет виртуальное имя с предоставляющим его пакетом
+	provides map[string]string
+}
+
+func newInstalledSnapshot() *installedSnapshot {
+	return &installedSnapshot{
+		versions: map[string]string{},
+		provides: map[string]string{},
+	}
+}
+
+func (s *installedSnapshot) add(name, version string, provides ...string) {
+	s.versions[name] = version
+	for _, p := range provides {
+		if _, ok := s.provides[p]; !ok && p != "" {
+			s.provides[p] = name
+		}
+	}
+}
+
+// resolve возвращает имя установленного пакета по имени или виртуальному имени
+func (s *installedSnapshot) resolve(name string) (string, bool) {
+	if _, ok := s.versions[name]; ok {
+		return name, true
+	}
+	pkg, ok := s.provides[name]
+	return pkg, ok
+}
+
+// installedIndex читает базу установленных пакетов без запуска внешних команд.
+// Индекс загружается при первом обращении и перечитывается только тогда,
+// когда меняются файлы базы, поэтому остаётся актуальным после установки
+// пакетов, в том числе выполненной другим процессом.
+//
+// Нулевое значение (и nil) означает, что индекс недоступен, и менеджер
+// должен использовать запасной путь через внешние команды.
+type installedIndex struct {
+	name string
+	// paths - файлы и каталоги, изменение которых означает изменение базы.
+	// Первый путь обязан существовать, иначе индекс считается недоступным.
+	paths []string
+	parse func() (*installedSnapshot, error)
+
+	mu     sync.Mutex
+	stamp  string
+	snap   *installedSnapshot
+	failed string
+}
+
+func newInstalledIndex(name string, parse func() (*installedSnapshot, error), paths ...string) *installedIndex {
+	return &installedIndex{
+		name:  name,
+		paths: paths,
+		parse: parse,
+	}
+}
+
+// snapshot возвращает актуальное состояние базы или false, если индекс недоступен
+func (i *installedIndex) snapshot() (*installedSnapshot, bool) {
+	if i == nil || i.parse == nil || len(i.paths) == 0 {
+		return nil, false
+	}
+
+	stamp, ok := i.currentStamp()
+	if !ok {
+		return nil, false
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.snap != nil && i.stamp == stamp {
+		return i.snap, true
+	}
+	if i.failed == stamp {
+		return nil, false
+	}
+
+	snap, err := i.parse()
+	if err != nil {
+		slog.Debug("Failed to read installed packages database, falling back to package manager", "index", i.name, "err", err)
+		i.snap = nil
+		i.failed = stamp
+		return nil, false
+	}
+
+	slog.Debug("Loaded installed packages database", "index", i.name, "count", len(snap.versions))
+	i.snap = snap
+	i.stamp = stamp
+	return snap, true
+}
+
+func (i *installedIndex) currentStamp() (string, bool) {
+	var sb strings.Builder
+	for n, path := range i.paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			if n == 0 {
+				return "", false
+			}
+			sb.WriteString("-;")
+			continue
+		}
+		fmt.Fprintf(&sb, "%d:%d;", fi.ModTime().UnixNano(), fi.Size())
+	}
+	return sb.String(), true
+}
+
+// list возвращает копию всех установленных пакетов
+func (i *installedIndex) list() (map[string]string, bool) {
+	snap, ok := i.snapshot()
+	if !ok {
+		return nil, false
+	}
+	return maps.Clone(snap.versions), true
+}
+
+// isInstalled проверяет наличие пакета по имени и, если withProvides, по виртуальному имени
+func (i *installedIndex) isInstalled(name string, withProvides bool) (installed, ok bool) {
+	snap, ok := i.snapshot()
+	if !ok {
+		return false, false
+	}
+	if withProvides {
+		_, installed = snap.resolve(name)
+		return installed, true
+	}
+	_, installed = snap.versions[name]
+	return installed, true
+}
+
+// version возвращает версию пакета (пустую, если он не установлен)
+func (i *installedIndex) version(name string, withProvides bool) (version string, ok bool) {
+	snap, ok := i.snapshot()
+	if !ok {
+		return "", false
+	}
+	if withProvides {
+		if pkg, found := snap.resolve(name); found {
+			return snap.versions[pkg], true
+		}
+		return "", true
+	}
+	return snap.versions[name], true
+}
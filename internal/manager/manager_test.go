@@ -57,3 +57,47 @@ func TestManagersOrder(t *testing.T) {
 		t.Errorf("APT-RPM (index %d) should come before APT (index %d)", aptRpmIndex, aptIndex)
 	}
 }
+
+func TestParseXBPSPkgver(t *testing.T) {
+	tests := []struct {
+		pkgver  string
+		name    string
+		version string
+		ok      bool
+	}{
+		{"curl-8.5.0_1", "curl", "8.5.0-1", true},
+		{"foo+alr-default-1.0_2", "foo+alr-default", "1.0-2", true},
+		{"python3-pip-23.3.1_1", "python3-pip", "23.3.1-1", true},
+		{"curl-8.5.0", "", "", false},
+	}
+
+	for _, tt := range tests {
+		name, version, ok := parseXBPSPkgver(tt.pkgver)
+		if ok != tt.ok || name != tt.name || version != tt.version {
+			t.Errorf("parseXBPSPkgver(%q) = (%q, %q, %v), expected (%q, %q, %v)",
+				tt.pkgver, name, version, ok, tt.name, tt.version, tt.ok)
+		}
+	}
+
+	if got := xbpsPkgverFromFile("/tmp/foo+alr-default-1.0_1.x86_64.xbps"); got != "foo+alr-default-1.0_1" {
+		t.Errorf("xbpsPkgverFromFile returned %q", got)
+	}
+}
+
+func TestNewOPKGReturnsCorrectType(t *testing.T) {
+	o := NewOPKG()
+	if o.Name() != "opkg" {
+		t.Errorf("Expected name 'opkg', got '%s'", o.Name())
+	}
+	if o.Format() != "ipk" {
+		t.Errorf("Expected format 'ipk', got '%s'", o.Format())
+	}
+
+	// opkg не поддерживает флаг подтверждения, пустой аргумент не должен добавляться
+	cmd := o.getCmd(&Opts{NoConfirm: true}, "opkg", "install")
+	for _, arg := range cmd.Args {
+		if arg == "" {
+			t.Errorf("Unexpected empty argument in %v", cmd.Args)
+		}
+	}
+}
@@ -22,6 +22,8 @@ package manager
 import (
 	"os"
 	"os/exec"
+
+	"slices"
 )
 
 var Args []string
@@ -43,6 +45,8 @@ var managers = []Manager{
 	NewYUM(),
 	NewAPK(),
 	NewZypper(),
+	NewXBPS(),
+	NewOPKG(),
 }
 
 // Register registers a new package manager
@@ -86,9 +90,15 @@ type Manager interface {
 	IsAvailable(name string) (bool, error)
 }
 
+// allManagers returns external plugin managers followed by the built-in ones.
+// Plugins go first so that a site-provided manager takes precedence.
+func allManagers() []Manager {
+	return append(slices.Clone(loadedPlugins()), managers...)
+}
+
 // Detect returns the package manager detected on the system
 func Detect() Manager {
-	for _, mgr := range managers {
+	for _, mgr := range allManagers() {
 		if mgr.Exists() {
 			return mgr
 		}
@@ -98,7 +108,7 @@ func Detect() Manager {
 
 // Get returns the package manager with the given name
 func Get(name string) Manager {
-	for _, mgr := range managers {
+	for _, mgr := range allManagers() {
 		if mgr.Name() == name {
 			return mgr
 		}
@@ -0,0 +1,254 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package manager
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// OPKG represents the opkg package manager (OpenWrt, Yocto)
+type OPKG struct {
+	CommonPackageManager
+}
+
+func NewOPKG() *OPKG {
+	// opkg никогда не запрашивает подтверждение
+	return &OPKG{}
+}
+
+func (*OPKG) Exists() bool {
+	_, err := exec.LookPath("opkg")
+	return err == nil
+}
+
+func (*OPKG) Name() string {
+	return "opkg"
+}
+
+func (*OPKG) Format() string {
+	return "ipk"
+}
+
+func (o *OPKG) Sync(opts *Opts) error {
+	opts = ensureOpts(opts)
+	cmd := o.getCmd(opts, "opkg", "update")
+	setCmdEnv(cmd)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("opkg: sync: %w", err)
+	}
+	return nil
+}
+
+func (o *OPKG) Install(opts *Opts, pkgs ...string) error {
+	opts = ensureOpts(opts)
+	cmd := o.getCmd(opts, "opkg", "install")
+	cmd.Args = append(cmd.Args, pkgs...)
+	setCmdEnv(cmd)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("opkg: install: %w", err)
+	}
+	return nil
+}
+
+func (o *OPKG) InstallLocal(opts *Opts, pkgs ...string) error {
+	opts = ensureOpts(opts)
+	cmd := o.getCmd(opts, "opkg", "install")
+	cmd.Args = append(cmd.Args, pkgs...)
+	setCmdEnv(cmd)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("opkg: installlocal: %w", err)
+	}
+	return nil
+}
+
+func (o *OPKG) Remove(opts *Opts, pkgs ...string) error {
+	opts = ensureOpts(opts)
+	cmd := o.getCmd(opts, "opkg", "remove")
+	cmd.Args = append(cmd.Args, pkgs...)
+	setCmdEnv(cmd)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("opkg: remove: %w", err)
+	}
+	return nil
+}
+
+func (o *OPKG) Upgrade(opts *Opts, pkgs ...string) error {
+	opts = ensureOpts(opts)
+	cmd := o.getCmd(opts, "opkg", "upgrade")
+	cmd.Args = append(cmd.Args, pkgs...)
+	setCmdEnv(cmd)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("opkg: upgrade: %w", err)
+	}
+	return nil
+}
+
+// UpgradeAll обновляет все пакеты. У opkg нет отдельной команды для этого,
+// поэтому обновляются пакеты из вывода list-upgradable.
+func (o *OPKG) UpgradeAll(opts *Opts) error {
+	opts = ensureOpts(opts)
+	output, err := exec.Command("opkg", "list-upgradable").Output()
+	if err != nil {
+		return fmt.Errorf("opkg: upgradeall: %w", err)
+	}
+
+	var pkgs []string
+	for _, line := range strings.Split(string(output), "\n") {
+		// Формат: "name - old-version - new-version"
+		name, _, ok := strings.Cut(line, " - ")
+		if !ok {
+			continue
+		}
+		pkgs = append(pkgs, name)
+	}
+
+	if len(pkgs) == 0 {
+		return nil
+	}
+
+	return o.Upgrade(opts, pkgs...)
+}
+
+func (o *OPKG) ListAvailable(prefix string) ([]string, error) {
+	cmd := exec.Command("opkg", "list", prefix+"*")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("opkg: listavailable: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("opkg: listavailable: %w", err)
+	}
+
+	seen := make(map[string]struct{})
+	var pkgs []string
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		// opkg list возвращает строки вида "name - version - description"
+		name, _, ok := strings.Cut(scanner.Text(), " - ")
+		if !ok {
+			continue
+		}
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			pkgs = append(pkgs, name)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("opkg: listavailable: %w", err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("opkg: listavailable: %w", err)
+	}
+
+	return pkgs, nil
+}
+
+// IsAvailable проверяет, доступен ли конкретный пакет в репозиториях
+func (o *OPKG) IsAvailable(name string) (bool, error) {
+	output, err := exec.Command("opkg", "info", name).Output()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return false, nil
+		}
+		return false, fmt.Errorf("opkg: isavailable: %w", err)
+	}
+	// opkg info завершается успешно и без вывода, если пакет не найден
+	return strings.Contains(string(output), "Package: "), nil
+}
+
+func (o *OPKG) ListInstalled(opts *Opts) (map[string]string, error) {
+	out := map[string]string{}
+	cmd := exec.Command("opkg", "list-installed")
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		// Формат: "name - version"
+		name, version, ok := strings.Cut(scanner.Text(), " - ")
+		if !ok {
+			continue
+		}
+		version, _, _ = strings.Cut(version, " - ")
+		out[name] = version
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (o *OPKG) IsInstalled(pkg string) (bool, error) {
+	version, err := o.GetInstalledVersion(pkg)
+	if err != nil {
+		return false, fmt.Errorf("opkg: isinstalled: %w", err)
+	}
+	return version != "", nil
+}
+
+func (o *OPKG) GetInstalledVersion(pkg string) (string, error) {
+	cmd := exec.Command("opkg", "status", pkg)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("opkg: getinstalledversion: %w, output: %s", err, output)
+	}
+
+	// opkg status выводит поля в формате control-файла и ничего не выводит,
+	// если пакет не установлен
+	var version string
+	installed := false
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Version":
+			version = value
+		case "Status":
+			installed = strings.HasSuffix(value, " installed")
+		}
+	}
+
+	if !installed {
+		return "", nil
+	}
+	return version, nil
+}
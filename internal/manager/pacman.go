@@ -29,6 +29,7 @@ import (
 // Pacman represents the Pacman package manager
 type Pacman struct {
 	CommonPackageManager
+	index *installedIndex
 }
 
 func NewPacman() *Pacman {
@@ -36,6 +37,7 @@ func NewPacman() *Pacman {
 		CommonPackageManager: CommonPackageManager{
 			noConfirmArg: "--noconfirm",
 		},
+		index: newPacmanIndex(pacmanLocalDir),
 	}
 }
 
@@ -165,6 +167,10 @@ func (p *Pacman) IsAvailable(name string) (bool, error) {
 }
 
 func (p *Pacman) ListInstalled(opts *Opts) (map[string]string, error) {
+	if pkgs, ok := p.index.list(); ok {
+		return pkgs, nil
+	}
+
 	out := map[string]string{}
 	cmd := exec.Command("pacman", "-Q")
 
@@ -196,6 +202,10 @@ func (p *Pacman) ListInstalled(opts *Opts) (map[string]string, error) {
 }
 
 func (p *Pacman) IsInstalled(pkg string) (bool, error) {
+	if installed, ok := p.index.isInstalled(pkg, false); ok {
+		return installed, nil
+	}
+
 	cmd := exec.Command("pacman", "-Q", pkg)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -211,6 +221,10 @@ func (p *Pacman) IsInstalled(pkg string) (bool, error) {
 }
 
 func (p *Pacman) GetInstalledVersion(pkg string) (string, error) {
+	if version, ok := p.index.version(pkg, false); ok {
+		return version, nil
+	}
+
 	cmd := exec.Command("pacman", "-Q", pkg)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
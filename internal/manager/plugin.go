@@ -0,0 +1,375 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/logger"
+)
+
+// PluginsDir - каталог, в котором ищутся исполняемые файлы внешних менеджеров пакетов
+var PluginsDir = "/usr/lib/alr/managers"
+
+// pluginsOwnerUID - владелец (root), которому должны принадлежать плагины и их каталог
+var pluginsOwnerUID uint32
+
+// PluginName - имя плагина менеджера пакетов в go-plugin
+const PluginName = "manager"
+
+// PluginHandshakeConfig используется для рукопожатия между ALR и плагином менеджера пакетов
+var PluginHandshakeConfig = plugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "ALR_MANAGER_PLUGIN",
+	MagicCookieValue: PluginName,
+}
+
+// ServePlugin запускает сервер плагина для указанной реализации Manager.
+// Вызывается из функции main исполняемого файла плагина.
+func ServePlugin(m Manager) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: PluginHandshakeConfig,
+		Plugins: map[string]plugin.Plugin{
+			PluginName: &ManagerExecutorPlugin{Impl: &managerExecutor{m: m}},
+		},
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Name:        "manager-plugin",
+			Output:      os.Stderr,
+			Level:       hclog.Trace,
+			JSONFormat:  true,
+			DisableTime: true,
+		}),
+	})
+}
+
+// PluginManager реализует Manager поверх внешнего плагина
+type PluginManager struct {
+	path   string
+	name   string
+	format string
+	exists bool
+
+	impl   ManagerExecutor
+	client *plugin.Client
+}
+
+// LoadPlugin запускает плагин менеджера пакетов и запрашивает его имя, формат и наличие в системе
+func LoadPlugin(path string) (*PluginManager, error) {
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: PluginHandshakeConfig,
+		Plugins: map[string]plugin.Plugin{
+			PluginName: &ManagerExecutorPlugin{},
+		},
+		Cmd:              exec.Command(path),
+		Logger:           logger.GetHCLoggerAdapter(),
+		Managed:          true,
+		UnixSocketConfig: &plugin.UnixSocketConfig{},
+		SyncStderr:       os.Stderr,
+	})
+
+	impl, err := dispenseManager(client)
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("manager plugin %s: %w", path, err)
+	}
+
+	pm, err := newPluginManager(impl)
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("manager plugin %s: %w", path, err)
+	}
+	pm.path = path
+	pm.client = client
+
+	return pm, nil
+}
+
+func dispenseManager(client *plugin.Client) (ManagerExecutor, error) {
+	rpcClient, err := client.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := rpcClient.Dispense(PluginName)
+	if err != nil {
+		return nil, err
+	}
+
+	impl, ok := raw.(ManagerExecutor)
+	if !ok {
+		return nil, fmt.Errorf("dispensed object is not a ManagerExecutor (got %T)", raw)
+	}
+	return impl, nil
+}
+
+func newPluginManager(impl ManagerExecutor) (*PluginManager, error) {
+	ctx := context.Background()
+	pm := &PluginManager{impl: impl}
+
+	var err error
+	if pm.name, err = impl.Name(ctx); err != nil {
+		return nil, err
+	}
+	if pm.format, err = impl.Format(ctx); err != nil {
+		return nil, err
+	}
+	if pm.exists, err = impl.Exists(ctx); err != nil {
+		return nil, err
+	}
+	return pm, nil
+}
+
+// Close завершает процесс плагина
+func (p *PluginManager) Close() {
+	if p.client != nil {
+		p.client.Kill()
+	}
+}
+
+// Path возвращает путь к исполняемому файлу плагина
+func (p *PluginManager) Path() string {
+	return p.path
+}
+
+func (p *PluginManager) Name() string {
+	return p.name
+}
+
+func (p *PluginManager) Format() string {
+	return p.format
+}
+
+func (p *PluginManager) Exists() bool {
+	return p.exists
+}
+
+func (p *PluginManager) Sync(opts *Opts) error {
+	return p.wrap("sync", p.impl.Sync(context.Background(), ensureOpts(opts)))
+}
+
+func (p *PluginManager) Install(opts *Opts, pkgs ...string) error {
+	return p.wrap("install", p.impl.Install(context.Background(), ensureOpts(opts), pkgs))
+}
+
+func (p *PluginManager) Remove(opts *Opts, pkgs ...string) error {
+	return p.wrap("remove", p.impl.Remove(context.Background(), ensureOpts(opts), pkgs))
+}
+
+func (p *PluginManager) Upgrade(opts *Opts, pkgs ...string) error {
+	return p.wrap("upgrade", p.impl.Upgrade(context.Background(), ensureOpts(opts), pkgs))
+}
+
+func (p *PluginManager) InstallLocal(opts *Opts, pkgs ...string) error {
+	return p.wrap("installlocal", p.impl.InstallLocal(context.Background(), ensureOpts(opts), pkgs))
+}
+
+func (p *PluginManager) UpgradeAll(opts *Opts) error {
+	return p.wrap("upgradeall", p.impl.UpgradeAll(context.Background(), ensureOpts(opts)))
+}
+
+func (p *PluginManager) ListInstalled(opts *Opts) (map[string]string, error) {
+	out, err := p.impl.ListInstalled(context.Background(), opts)
+	return out, p.wrap("listinstalled", err)
+}
+
+func (p *PluginManager) IsInstalled(pkg string) (bool, error) {
+	ok, err := p.impl.IsInstalled(context.Background(), pkg)
+	return ok, p.wrap("isinstalled", err)
+}
+
+func (p *PluginManager) GetInstalledVersion(pkg string) (string, error) {
+	version, err := p.impl.GetInstalledVersion(context.Background(), pkg)
+	return version, p.wrap("getinstalledversion", err)
+}
+
+func (p *PluginManager) ListAvailable(prefix string) ([]string, error) {
+	pkgs, err := p.impl.ListAvailable(context.Background(), prefix)
+	return pkgs, p.wrap("listavailable", err)
+}
+
+func (p *PluginManager) IsAvailable(name string) (bool, error) {
+	ok, err := p.impl.IsAvailable(context.Background(), name)
+	return ok, p.wrap("isavailable", err)
+}
+
+func (p *PluginManager) wrap(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %s: %w", p.name, op, err)
+}
+
+// managerExecutor адаптирует Manager к ManagerExecutor на стороне плагина
+type managerExecutor struct {
+	m Manager
+}
+
+func (e *managerExecutor) Name(ctx context.Context) (string, error) {
+	return e.m.Name(), nil
+}
+
+func (e *managerExecutor) Format(ctx context.Context) (string, error) {
+	return e.m.Format(), nil
+}
+
+func (e *managerExecutor) Exists(ctx context.Context) (bool, error) {
+	return e.m.Exists(), nil
+}
+
+func (e *managerExecutor) Sync(ctx context.Context, opts *Opts) error {
+	return e.m.Sync(opts)
+}
+
+func (e *managerExecutor) Install(ctx context.Context, opts *Opts, pkgs []string) error {
+	return e.m.Install(opts, pkgs...)
+}
+
+func (e *managerExecutor) Remove(ctx context.Context, opts *Opts, pkgs []string) error {
+	return e.m.Remove(opts, pkgs...)
+}
+
+func (e *managerExecutor) Upgrade(ctx context.Context, opts *Opts, pkgs []string) error {
+	return e.m.Upgrade(opts, pkgs...)
+}
+
+func (e *managerExecutor) InstallLocal(ctx context.Context, opts *Opts, pkgs []string) error {
+	return e.m.InstallLocal(opts, pkgs...)
+}
+
+func (e *managerExecutor) UpgradeAll(ctx context.Context, opts *Opts) error {
+	return e.m.UpgradeAll(opts)
+}
+
+func (e *managerExecutor) ListInstalled(ctx context.Context, opts *Opts) (map[string]string, error) {
+	return e.m.ListInstalled(opts)
+}
+
+func (e *managerExecutor) IsInstalled(ctx context.Context, pkg string) (bool, error) {
+	return e.m.IsInstalled(pkg)
+}
+
+func (e *managerExecutor) GetInstalledVersion(ctx context.Context, pkg string) (string, error) {
+	return e.m.GetInstalledVersion(pkg)
+}
+
+func (e *managerExecutor) ListAvailable(ctx context.Context, prefix string) ([]string, error) {
+	return e.m.ListAvailable(prefix)
+}
+
+func (e *managerExecutor) IsAvailable(ctx context.Context, name string) (bool, error) {
+	return e.m.IsAvailable(name)
+}
+
+var (
+	pluginsOnce sync.Once
+	plugins     []Manager
+)
+
+// loadedPlugins возвращает менеджеры пакетов из PluginsDir. Каталог
+// просматривается один раз; плагины, сообщившие об отсутствии своего
+// менеджера в системе, сразу завершаются и не учитываются.
+func loadedPlugins() []Manager {
+	pluginsOnce.Do(func() {
+		plugins = loadPlugins(PluginsDir)
+	})
+	return plugins
+}
+
+func loadPlugins(dir string) []Manager {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("Failed to read manager plugins directory", "path", dir, "err", err)
+		}
+		return nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	var out []Manager
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if !isTrustedExecutable(path) {
+			continue
+		}
+
+		pm, err := LoadPlugin(path)
+		if err != nil {
+			slog.Warn("Failed to load manager plugin", "path", path, "err", err)
+			continue
+		}
+
+		if !pm.Exists() {
+			slog.Debug("Manager plugin is not available on this system", "path", path, "name", pm.Name())
+			pm.Close()
+			continue
+		}
+
+		slog.Debug("Loaded manager plugin", "path", path, "name", pm.Name(), "format", pm.Format())
+		out = append(out, pm)
+	}
+	return out
+}
+
+// isTrustedExecutable проверяет, что файл является исполняемым, а он сам
+// и содержащий его каталог принадлежат root и не могут быть изменены
+// группой или другими пользователями. Плагин запускается от имени root
+// при установке и обновлении пакетов.
+func isTrustedExecutable(path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil || !fi.Mode().IsRegular() {
+		return false
+	}
+	if fi.Mode().Perm()&0o111 == 0 {
+		return false
+	}
+	if !isTrustedOwner(path, fi) {
+		slog.Warn("Skipping manager plugin not owned by root or writable by group or others", "path", path)
+		return false
+	}
+
+	dir := filepath.Dir(path)
+	di, err := os.Stat(dir)
+	if err != nil || !isTrustedOwner(dir, di) {
+		slog.Warn("Skipping manager plugin in directory not owned by root or writable by group or others", "path", path)
+		return false
+	}
+	return true
+}
+
+// isTrustedOwner сообщает, принадлежит ли файл pluginsOwnerUID и закрыт ли он
+// на запись для группы и других пользователей
+func isTrustedOwner(path string, fi os.FileInfo) bool {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok || st.Uid != pluginsOwnerUID {
+		return false
+	}
+	return fi.Mode().Perm()&0o022 == 0
+}
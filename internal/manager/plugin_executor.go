@@ -0,0 +1,43 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package manager
+
+import (
+	"context"
+)
+
+//go:generate go run ../../generators/plugin-generator ManagerExecutor
+
+// ManagerExecutor - RPC-представление интерфейса Manager для внешних плагинов.
+// Методы должны принимать context.Context первым параметром,
+// иначе plugin-generator не сможет сгенерировать для них код.
+type ManagerExecutor interface {
+	Name(ctx context.Context) (string, error)
+	Format(ctx context.Context) (string, error)
+	Exists(ctx context.Context) (bool, error)
+	Sync(ctx context.Context, opts *Opts) error
+	Install(ctx context.Context, opts *Opts, pkgs []string) error
+	Remove(ctx context.Context, opts *Opts, pkgs []string) error
+	Upgrade(ctx context.Context, opts *Opts, pkgs []string) error
+	InstallLocal(ctx context.Context, opts *Opts, pkgs []string) error
+	UpgradeAll(ctx context.Context, opts *Opts) error
+	ListInstalled(ctx context.Context, opts *Opts) (map[string]string, error)
+	IsInstalled(ctx context.Context, pkg string) (bool, error)
+	GetInstalledVersion(ctx context.Context, pkg string) (string, error)
+	ListAvailable(ctx context.Context, prefix string) ([]string, error)
+	IsAvailable(ctx context.Context, name string) (bool, error)
+}
@@ -0,0 +1,447 @@
+// DO NOT EDIT MANUALLY. This file is generated.
+
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package manager
+
+import (
+	"net/rpc"
+
+	"context"
+	"github.com/hashicorp/go-plugin"
+)
+
+type ManagerExecutorPlugin struct {
+	Impl ManagerExecutor
+}
+
+type ManagerExecutorRPCServer struct {
+	Impl ManagerExecutor
+}
+
+type ManagerExecutorRPC struct {
+	client *rpc.Client
+}
+
+func (p *ManagerExecutorPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &ManagerExecutorRPC{client: c}, nil
+}
+
+func (p *ManagerExecutorPlugin) Server(*plugin.MuxBroker) (interface{}, error) {
+	return &ManagerExecutorRPCServer{Impl: p.Impl}, nil
+}
+
+type ManagerExecutorNameArgs struct {
+}
+
+type ManagerExecutorNameResp struct {
+	Result0 string
+}
+
+func (s *ManagerExecutorRPC) Name(ctx context.Context) (string, error) {
+	var resp *ManagerExecutorNameResp
+	err := s.client.Call("Plugin.Name", &ManagerExecutorNameArgs{}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.Result0, nil
+}
+
+func (s *ManagerExecutorRPCServer) Name(args *ManagerExecutorNameArgs, resp *ManagerExecutorNameResp) error {
+	result0, err := s.Impl.Name(context.Background())
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorNameResp{
+		Result0: result0,
+	}
+	return nil
+}
+
+type ManagerExecutorFormatArgs struct {
+}
+
+type ManagerExecutorFormatResp struct {
+	Result0 string
+}
+
+func (s *ManagerExecutorRPC) Format(ctx context.Context) (string, error) {
+	var resp *ManagerExecutorFormatResp
+	err := s.client.Call("Plugin.Format", &ManagerExecutorFormatArgs{}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.Result0, nil
+}
+
+func (s *ManagerExecutorRPCServer) Format(args *ManagerExecutorFormatArgs, resp *ManagerExecutorFormatResp) error {
+	result0, err := s.Impl.Format(context.Background())
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorFormatResp{
+		Result0: result0,
+	}
+	return nil
+}
+
+type ManagerExecutorExistsArgs struct {
+}
+
+type ManagerExecutorExistsResp struct {
+	Result0 bool
+}
+
+func (s *ManagerExecutorRPC) Exists(ctx context.Context) (bool, error) {
+	var resp *ManagerExecutorExistsResp
+	err := s.client.Call("Plugin.Exists", &ManagerExecutorExistsArgs{}, &resp)
+	if err != nil {
+		return false, err
+	}
+	return resp.Result0, nil
+}
+
+func (s *ManagerExecutorRPCServer) Exists(args *ManagerExecutorExistsArgs, resp *ManagerExecutorExistsResp) error {
+	result0, err := s.Impl.Exists(context.Background())
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorExistsResp{
+		Result0: result0,
+	}
+	return nil
+}
+
+type ManagerExecutorSyncArgs struct {
+	Opts *Opts
+}
+
+type ManagerExecutorSyncResp struct {
+}
+
+func (s *ManagerExecutorRPC) Sync(ctx context.Context, opts *Opts) error {
+	var resp *ManagerExecutorSyncResp
+	err := s.client.Call("Plugin.Sync", &ManagerExecutorSyncArgs{
+		Opts: opts,
+	}, &resp)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ManagerExecutorRPCServer) Sync(args *ManagerExecutorSyncArgs, resp *ManagerExecutorSyncResp) error {
+	err := s.Impl.Sync(context.Background(), args.Opts)
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorSyncResp{}
+	return nil
+}
+
+type ManagerExecutorInstallArgs struct {
+	Opts *Opts
+	Pkgs []string
+}
+
+type ManagerExecutorInstallResp struct {
+}
+
+func (s *ManagerExecutorRPC) Install(ctx context.Context, opts *Opts, pkgs []string) error {
+	var resp *ManagerExecutorInstallResp
+	err := s.client.Call("Plugin.Install", &ManagerExecutorInstallArgs{
+		Opts: opts,
+		Pkgs: pkgs,
+	}, &resp)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ManagerExecutorRPCServer) Install(args *ManagerExecutorInstallArgs, resp *ManagerExecutorInstallResp) error {
+	err := s.Impl.Install(context.Background(), args.Opts, args.Pkgs)
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorInstallResp{}
+	return nil
+}
+
+type ManagerExecutorRemoveArgs struct {
+	Opts *Opts
+	Pkgs []string
+}
+
+type ManagerExecutorRemoveResp struct {
+}
+
+func (s *ManagerExecutorRPC) Remove(ctx context.Context, opts *Opts, pkgs []string) error {
+	var resp *ManagerExecutorRemoveResp
+	err := s.client.Call("Plugin.Remove", &ManagerExecutorRemoveArgs{
+		Opts: opts,
+		Pkgs: pkgs,
+	}, &resp)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ManagerExecutorRPCServer) Remove(args *ManagerExecutorRemoveArgs, resp *ManagerExecutorRemoveResp) error {
+	err := s.Impl.Remove(context.Background(), args.Opts, args.Pkgs)
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorRemoveResp{}
+	return nil
+}
+
+type ManagerExecutorUpgradeArgs struct {
+	Opts *Opts
+	Pkgs []string
+}
+
+type ManagerExecutorUpgradeResp struct {
+}
+
+func (s *ManagerExecutorRPC) Upgrade(ctx context.Context, opts *Opts, pkgs []string) error {
+	var resp *ManagerExecutorUpgradeResp
+	err := s.client.Call("Plugin.Upgrade", &ManagerExecutorUpgradeArgs{
+		Opts: opts,
+		Pkgs: pkgs,
+	}, &resp)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ManagerExecutorRPCServer) Upgrade(args *ManagerExecutorUpgradeArgs, resp *ManagerExecutorUpgradeResp) error {
+	err := s.Impl.Upgrade(context.Background(), args.Opts, args.Pkgs)
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorUpgradeResp{}
+	return nil
+}
+
+type ManagerExecutorInstallLocalArgs struct {
+	Opts *Opts
+	Pkgs []string
+}
+
+type ManagerExecutorInstallLocalResp struct {
+}
+
+func (s *ManagerExecutorRPC) InstallLocal(ctx context.Context, opts *Opts, pkgs []string) error {
+	var resp *ManagerExecutorInstallLocalResp
+	err := s.client.Call("Plugin.InstallLocal", &ManagerExecutorInstallLocalArgs{
+		Opts: opts,
+		Pkgs: pkgs,
+	}, &resp)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ManagerExecutorRPCServer) InstallLocal(args *ManagerExecutorInstallLocalArgs, resp *ManagerExecutorInstallLocalResp) error {
+	err := s.Impl.InstallLocal(context.Background(), args.Opts, args.Pkgs)
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorInstallLocalResp{}
+	return nil
+}
+
+type ManagerExecutorUpgradeAllArgs struct {
+	Opts *Opts
+}
+
+type ManagerExecutorUpgradeAllResp struct {
+}
+
+func (s *ManagerExecutorRPC) UpgradeAll(ctx context.Context, opts *Opts) error {
+	var resp *ManagerExecutorUpgradeAllResp
+	err := s.client.Call("Plugin.UpgradeAll", &ManagerExecutorUpgradeAllArgs{
+		Opts: opts,
+	}, &resp)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ManagerExecutorRPCServer) UpgradeAll(args *ManagerExecutorUpgradeAllArgs, resp *ManagerExecutorUpgradeAllResp) error {
+	err := s.Impl.UpgradeAll(context.Background(), args.Opts)
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorUpgradeAllResp{}
+	return nil
+}
+
+type ManagerExecutorListInstalledArgs struct {
+	Opts *Opts
+}
+
+type ManagerExecutorListInstalledResp struct {
+	Result0 map[string]string
+}
+
+func (s *ManagerExecutorRPC) ListInstalled(ctx context.Context, opts *Opts) (map[string]string, error) {
+	var resp *ManagerExecutorListInstalledResp
+	err := s.client.Call("Plugin.ListInstalled", &ManagerExecutorListInstalledArgs{
+		Opts: opts,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result0, nil
+}
+
+func (s *ManagerExecutorRPCServer) ListInstalled(args *ManagerExecutorListInstalledArgs, resp *ManagerExecutorListInstalledResp) error {
+	result0, err := s.Impl.ListInstalled(context.Background(), args.Opts)
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorListInstalledResp{
+		Result0: result0,
+	}
+	return nil
+}
+
+type ManagerExecutorIsInstalledArgs struct {
+	Pkg string
+}
+
+type ManagerExecutorIsInstalledResp struct {
+	Result0 bool
+}
+
+func (s *ManagerExecutorRPC) IsInstalled(ctx context.Context, pkg string) (bool, error) {
+	var resp *ManagerExecutorIsInstalledResp
+	err := s.client.Call("Plugin.IsInstalled", &ManagerExecutorIsInstalledArgs{
+		Pkg: pkg,
+	}, &resp)
+	if err != nil {
+		return false, err
+	}
+	return resp.Result0, nil
+}
+
+func (s *ManagerExecutorRPCServer) IsInstalled(args *ManagerExecutorIsInstalledArgs, resp *ManagerExecutorIsInstalledResp) error {
+	result0, err := s.Impl.IsInstalled(context.Background(), args.Pkg)
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorIsInstalledResp{
+		Result0: result0,
+	}
+	return nil
+}
+
+type ManagerExecutorGetInstalledVersionArgs struct {
+	Pkg string
+}
+
+type ManagerExecutorGetInstalledVersionResp struct {
+	Result0 string
+}
+
+func (s *ManagerExecutorRPC) GetInstalledVersion(ctx context.Context, pkg string) (string, error) {
+	var resp *ManagerExecutorGetInstalledVersionResp
+	err := s.client.Call("Plugin.GetInstalledVersion", &ManagerExecutorGetInstalledVersionArgs{
+		Pkg: pkg,
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.Result0, nil
+}
+
+func (s *ManagerExecutorRPCServer) GetInstalledVersion(args *ManagerExecutorGetInstalledVersionArgs, resp *ManagerExecutorGetInstalledVersionResp) error {
+	result0, err := s.Impl.GetInstalledVersion(context.Background(), args.Pkg)
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorGetInstalledVersionResp{
+		Result0: result0,
+	}
+	return nil
+}
+
+type ManagerExecutorListAvailableArgs struct {
+	Prefix string
+}
+
+type ManagerExecutorListAvailableResp struct {
+	Result0 []string
+}
+
+func (s *ManagerExecutorRPC) ListAvailable(ctx context.Context, prefix string) ([]string, error) {
+	var resp *ManagerExecutorListAvailableResp
+	err := s.client.Call("Plugin.ListAvailable", &ManagerExecutorListAvailableArgs{
+		Prefix: prefix,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result0, nil
+}
+
+func (s *ManagerExecutorRPCServer) ListAvailable(args *ManagerExecutorListAvailableArgs, resp *ManagerExecutorListAvailableResp) error {
+	result0, err := s.Impl.ListAvailable(context.Background(), args.Prefix)
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorListAvailableResp{
+		Result0: result0,
+	}
+	return nil
+}
+
+type ManagerExecutorIsAvailableArgs struct {
+	Name string
+}
+
+type ManagerExecutorIsAvailableResp struct {
+	Result0 bool
+}
+
+func (s *ManagerExecutorRPC) IsAvailable(ctx context.Context, name string) (bool, error) {
+	var resp *ManagerExecutorIsAvailableResp
+	err := s.client.Call("Plugin.IsAvailable", &ManagerExecutorIsAvailableArgs{
+		Name: name,
+	}, &resp)
+	if err != nil {
+		return false, err
+	}
+	return resp.Result0, nil
+}
+
+func (s *ManagerExecutorRPCServer) IsAvailable(args *ManagerExecutorIsAvailableArgs, resp *ManagerExecutorIsAvailableResp) error {
+	result0, err := s.Impl.IsAvailable(context.Background(), args.Name)
+	if err != nil {
+		return err
+	}
+	*resp = ManagerExecutorIsAvailableResp{
+		Result0: result0,
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/logger"
+)
+
+const testPluginEnv = "ALR_TEST_MANAGER_PLUGIN"
+
+// fakeManager - подставной менеджер пакетов, обслуживаемый тестовым плагином
+type fakeManager struct {
+	exists bool
+}
+
+func (*fakeManager) Name() string                   { return "fake" }
+func (*fakeManager) Format() string                 { return "deb" }
+func (m *fakeManager) Exists() bool                 { return m.exists }
+func (*fakeManager) Sync(*Opts) error               { return nil }
+func (*fakeManager) UpgradeAll(*Opts) error         { return nil }
+func (*fakeManager) Upgrade(*Opts, ...string) error { return nil }
+func (*fakeManager) Remove(*Opts, ...string) error  { return nil }
+
+func (*fakeManager) Install(opts *Opts, pkgs ...string) error {
+	if len(pkgs) == 0 {
+		return errors.New("no packages")
+	}
+	return nil
+}
+
+func (*fakeManager) InstallLocal(opts *Opts, pkgs ...string) error {
+	return fmt.Errorf("cannot install %v", pkgs)
+}
+
+func (*fakeManager) ListInstalled(*Opts) (map[string]string, error) {
+	return map[string]string{"foo+alr-default": "1.0-1"}, nil
+}
+
+func (*fakeManager) IsInstalled(pkg string) (bool, error) {
+	return pkg == "foo+alr-default", nil
+}
+
+func (*fakeManager) GetInstalledVersion(pkg string) (string, error) {
+	if pkg == "foo+alr-default" {
+		return "1.0-1", nil
+	}
+	return "", nil
+}
+
+func (*fakeManager) ListAvailable(prefix string) ([]string, error) {
+	return []string{prefix + "-a", prefix + "-b"}, nil
+}
+
+func (*fakeManager) IsAvailable(name string) (bool, error) {
+	return name == "bar", nil
+}
+
+func TestMain(m *testing.M) {
+	// Тестовый бинарный файл сам выступает в роли плагина менеджера пакетов
+	if v := os.Getenv(testPluginEnv); v != "" {
+		ServePlugin(&fakeManager{exists: v == "exists"})
+		os.Exit(0)
+	}
+	logger.SetupDefault()
+	// Тестовые плагины принадлежат пользователю, запустившему тесты
+	pluginsOwnerUID = uint32(os.Getuid())
+	os.Exit(m.Run())
+}
+
+func assertFakeManager(t *testing.T, m Manager) {
+	t.Helper()
+
+	assert.Equal(t, "fake", m.Name())
+	assert.Equal(t, "deb", m.Format())
+
+	require.NoError(t, m.Install(nil, "foo"))
+	assert.ErrorContains(t, m.Install(nil), "no packages")
+	assert.ErrorContains(t, m.InstallLocal(nil, "/tmp/foo.deb"), "cannot install [/tmp/foo.deb]")
+
+	installed, err := m.ListInstalled(nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"foo+alr-default": "1.0-1"}, installed)
+
+	ok, err := m.IsInstalled("foo+alr-default")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	version, err := m.GetInstalledVersion("foo+alr-default")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0-1", version)
+
+	available, err := m.ListAvailable("lib")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lib-a", "lib-b"}, available)
+
+	ok, err = m.IsAvailable("bar")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestPluginManagerRPC(t *testing.T) {
+	client, _ := plugin.TestPluginRPCConn(t, map[string]plugin.Plugin{
+		PluginName: &ManagerExecutorPlugin{Impl: &managerExecutor{m: &fakeManager{exists: true}}},
+	}, nil)
+	defer client.Close()
+
+	raw, err := client.Dispense(PluginName)
+	require.NoError(t, err)
+
+	pm, err := newPluginManager(raw.(ManagerExecutor))
+	require.NoError(t, err)
+	assert.True(t, pm.Exists())
+
+	assertFakeManager(t, pm)
+}
+
+// writePluginWrapper создает исполняемый скрипт, запускающий тестовый бинарный файл как плагин
+func writePluginWrapper(t *testing.T, dir, name, mode string) string {
+	t.Helper()
+
+	executable, err := os.Executable()
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, name)
+	script := fmt.Sprintf("#!/bin/sh\n%s=%s exec %q \"$@\"\n", testPluginEnv, mode, executable)
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+	require.NoError(t, os.Chmod(path, 0o755))
+	return path
+}
+
+func TestLoadPlugins(t *testing.T) {
+	dir := t.TempDir()
+
+	writePluginWrapper(t, dir, "10-fake", "exists")
+	writePluginWrapper(t, dir, "20-missing", "missing")
+
+	// Файлы без права исполнения и доступные на запись всем пропускаются
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("not a plugin"), 0o644))
+	insecure := writePluginWrapper(t, dir, "30-insecure", "exists")
+	require.NoError(t, os.Chmod(insecure, 0o777))
+
+	loaded := loadPlugins(dir)
+	t.Cleanup(func() {
+		for _, m := range loaded {
+			m.(*PluginManager).Close()
+		}
+	})
+
+	require.Len(t, loaded, 1)
+	assert.Equal(t, filepath.Join(dir, "10-fake"), loaded[0].(*PluginManager).Path())
+	assertFakeManager(t, loaded[0])
+}
+
+func TestLoadPluginsMissingDir(t *testing.T) {
+	assert.Empty(t, loadPlugins(filepath.Join(t.TempDir(), "missing")))
+}
+
+func TestIsTrustedExecutable(t *testing.T) {
+	dir := t.TempDir()
+	path := writePluginWrapper(t, dir, "10-fake", "exists")
+	assert.True(t, isTrustedExecutable(path))
+
+	// Каталог, доступный на запись другим пользователям
+	require.NoError(t, os.Chmod(dir, 0o777))
+	assert.False(t, isTrustedExecutable(path))
+	require.NoError(t, os.Chmod(dir, 0o755))
+
+	// Плагин, принадлежащий другому пользователю
+	old := pluginsOwnerUID
+	pluginsOwnerUID = old + 1
+	defer func() { pluginsOwnerUID = old }()
+	assert.False(t, isTrustedExecutable(path))
+}
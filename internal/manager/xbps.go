@@ -0,0 +1,294 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package manager
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/xbps"
+)
+
+// XBPS represents the XBPS package manager (Void Linux)
+type XBPS struct {
+	CommonPackageManager
+}
+
+func NewXBPS() *XBPS {
+	return &XBPS{
+		CommonPackageManager: CommonPackageManager{
+			noConfirmArg: "-y",
+		},
+	}
+}
+
+func (*XBPS) Exists() bool {
+	_, err := exec.LookPath("xbps-install")
+	return err == nil
+}
+
+func (*XBPS) Name() string {
+	return "xbps"
+}
+
+func (*XBPS) Format() string {
+	return "xbps"
+}
+
+func (x *XBPS) Sync(opts *Opts) error {
+	opts = ensureOpts(opts)
+	cmd := x.getCmd(opts, "xbps-install", "-S")
+	setCmdEnv(cmd)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("xbps: sync: %w", err)
+	}
+	return nil
+}
+
+func (x *XBPS) Install(opts *Opts, pkgs ...string) error {
+	opts = ensureOpts(opts)
+	cmd := x.getCmd(opts, "xbps-install")
+	cmd.Args = append(cmd.Args, pkgs...)
+	setCmdEnv(cmd)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("xbps: install: %w", err)
+	}
+	return nil
+}
+
+// InstallLocal устанавливает пакеты из файлов. xbps не умеет устанавливать
+// файлы напрямую, поэтому каталоги с пакетами индексируются как локальные
+// репозитории, а пакеты устанавливаются по имени из этих репозиториев.
+func (x *XBPS) InstallLocal(opts *Opts, pkgs ...string) error {
+	opts = ensureOpts(opts)
+
+	var repoArgs, names []string
+	seen := make(map[string]struct{})
+	for _, pkg := range pkgs {
+		dir := filepath.Dir(pkg)
+		if _, ok := seen[dir]; !ok {
+			seen[dir] = struct{}{}
+			repoArgs = append(repoArgs, "--repository="+dir)
+		}
+
+		name, _, ok := parseXBPSPkgver(xbpsPkgverFromFile(pkg))
+		if !ok {
+			return fmt.Errorf("xbps: installlocal: unexpected package file name %q", filepath.Base(pkg))
+		}
+		names = append(names, name)
+	}
+
+	rindex := exec.Command("xbps-rindex", append([]string{"-a"}, pkgs...)...)
+	setCmdEnv(rindex)
+	if err := rindex.Run(); err != nil {
+		return fmt.Errorf("xbps: installlocal: %w", err)
+	}
+
+	cmd := x.getCmd(opts, "xbps-install", repoArgs...)
+	cmd.Args = append(cmd.Args, names...)
+	setCmdEnv(cmd)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("xbps: installlocal: %w", err)
+	}
+	return nil
+}
+
+func (x *XBPS) Remove(opts *Opts, pkgs ...string) error {
+	opts = ensureOpts(opts)
+	cmd := x.getCmd(opts, "xbps-remove")
+	cmd.Args = append(cmd.Args, pkgs...)
+	setCmdEnv(cmd)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("xbps: remove: %w", err)
+	}
+	return nil
+}
+
+func (x *XBPS) Upgrade(opts *Opts, pkgs ...string) error {
+	opts = ensureOpts(opts)
+	cmd := x.getCmd(opts, "xbps-install", "-u")
+	cmd.Args = append(cmd.Args, pkgs...)
+	setCmdEnv(cmd)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("xbps: upgrade: %w", err)
+	}
+	return nil
+}
+
+func (x *XBPS) UpgradeAll(opts *Opts) error {
+	opts = ensureOpts(opts)
+	cmd := x.getCmd(opts, "xbps-install", "-Su")
+	setCmdEnv(cmd)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("xbps: upgradeall: %w", err)
+	}
+	return nil
+}
+
+func (x *XBPS) ListAvailable(prefix string) ([]string, error) {
+	cmd := exec.Command("xbps-query", "-Rs", prefix)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("xbps: listavailable: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("xbps: listavailable: %w", err)
+	}
+
+	seen := make(map[string]struct{})
+	var pkgs []string
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		// xbps-query -Rs возвращает строки вида "[-] name-version_revision description"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		name, _, ok := parseXBPSPkgver(fields[1])
+		if !ok || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			pkgs = append(pkgs, name)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("xbps: listavailable: %w", err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("xbps: listavailable: %w", err)
+	}
+
+	return pkgs, nil
+}
+
+// IsAvailable проверяет, доступен ли конкретный пакет в репозиториях
+func (x *XBPS) IsAvailable(name string) (bool, error) {
+	cmd := exec.Command("xbps-query", "-R", "-p", "pkgver", name)
+	err := cmd.Run()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return false, nil
+		}
+		return false, fmt.Errorf("xbps: isavailable: %w", err)
+	}
+	return true, nil
+}
+
+func (x *XBPS) ListInstalled(opts *Opts) (map[string]string, error) {
+	out := map[string]string{}
+	cmd := exec.Command("xbps-query", "-l")
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		// xbps-query -l возвращает строки вида "ii name-version_revision description"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		name, version, ok := parseXBPSPkgver(fields[1])
+		if !ok {
+			continue
+		}
+
+		out[name] = version
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (x *XBPS) IsInstalled(pkg string) (bool, error) {
+	cmd := exec.Command("xbps-query", "-p", "pkgver", pkg)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		// xbps-query возвращает ненулевой код, если пакет не установлен
+		if _, ok := err.(*exec.ExitError); ok {
+			return false, nil
+		}
+		return false, fmt.Errorf("xbps: isinstalled: %w, output: %s", err, output)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+func (x *XBPS) GetInstalledVersion(pkg string) (string, error) {
+	cmd := exec.Command("xbps-query", "-p", "pkgver", pkg)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return "", nil
+		}
+		return "", fmt.Errorf("xbps: getinstalledversion: %w, output: %s", err, output)
+	}
+
+	// Output format: "name-version_revision" (e.g., "curl-8.5.0_1")
+	_, version, ok := parseXBPSPkgver(strings.TrimSpace(string(output)))
+	if !ok {
+		return "", nil
+	}
+	return version, nil
+}
+
+// parseXBPSPkgver разбирает pkgver xbps ("name-version_revision") на имя и версию.
+// Версия возвращается в формате ALR "version-release", чтобы её можно было
+// сравнивать с версиями из репозиториев ALR.
+func parseXBPSPkgver(pkgver string) (name, version string, ok bool) {
+	matches := xbps.RegexpPkgver.FindStringSubmatch(pkgver)
+	if matches == nil {
+		return "", "", false
+	}
+	name = matches[xbps.RegexpPkgver.SubexpIndex("name")]
+	version = matches[xbps.RegexpPkgver.SubexpIndex("version")] + "-" + matches[xbps.RegexpPkgver.SubexpIndex("revision")]
+	return name, version, true
+}
+
+// xbpsPkgverFromFile извлекает pkgver из имени файла "name-version_revision.arch.xbps"
+func xbpsPkgverFromFile(path string) string {
+	base := strings.TrimSuffix(filepath.Base(path), ".xbps")
+	if i := strings.LastIndex(base, "."); i > 0 {
+		base = base[:i]
+	}
+	return base
+}
@@ -36,6 +36,9 @@ func NewYUM() *YUM {
 		CommonPackageManager: CommonPackageManager{
 			noConfirmArg: "-y",
 		},
+		CommonRPM: CommonRPM{
+			index: newRPMIndex(),
+		},
 	}
 }
 
@@ -37,6 +37,9 @@ func NewZypper() *Zypper {
 		CommonPackageManager: CommonPackageManager{
 			noConfirmArg: "-y",
 		},
+		CommonRPM: CommonRPM{
+			index: newRPMIndex(),
+		},
 	}
 }
 
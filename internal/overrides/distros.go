@@ -0,0 +1,85 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package overrides
+
+import (
+	"golang.org/x/exp/slices"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+)
+
+// KnownDistros - дистрибутивы, для которых обычно пишутся переопределения,
+// с ID и ID_LIKE из их os-release
+var KnownDistros = []distro.OSRelease{
+	{ID: "alpine"},
+	{ID: "altlinux"},
+	{ID: "arch"},
+	{ID: "manjaro", Like: []string{"arch"}},
+	{ID: "endeavouros", Like: []string{"arch"}},
+	{ID: "debian"},
+	{ID: "ubuntu", Like: []string{"debian"}},
+	{ID: "linuxmint", Like: []string{"ubuntu", "debian"}},
+	{ID: "astra", Like: []string{"debian"}},
+	{ID: "fedora"},
+	{ID: "rhel", Like: []string{"fedora"}},
+	{ID: "centos", Like: []string{"rhel", "fedora"}},
+	{ID: "rocky", Like: []string{"rhel", "centos", "fedora"}},
+	{ID: "almalinux", Like: []string{"rhel", "centos", "fedora"}},
+	{ID: "redos", Like: []string{"fedora"}},
+	{ID: "opensuse-leap", Like: []string{"suse", "opensuse"}},
+	{ID: "opensuse-tumbleweed", Like: []string{"opensuse", "suse"}},
+	{ID: "gentoo"},
+	{ID: "void"},
+	{ID: "nixos"},
+	{ID: "openwrt", Like: []string{"lede", "openwrt"}},
+	{ID: "postmarketos", Like: []string{"alpine"}},
+}
+
+// KnownDistro возвращает описание известного дистрибутива id.
+// Для неизвестного дистрибутива возвращается описание только с ID.
+func KnownDistro(id string) distro.OSRelease {
+	for _, info := range KnownDistros {
+		if info.ID == id {
+			return info
+		}
+	}
+	return distro.OSRelease{ID: id}
+}
+
+// KnownDistroIDs возвращает ID и ID_LIKE известных дистрибутивов, которые
+// могут использоваться в именах переопределений
+func KnownDistroIDs() []string {
+	var out []string
+	for _, info := range KnownDistros {
+		for _, id := range append([]string{info.ID}, info.Like...) {
+			if isOverrideName(id) && !slices.Contains(out, id) {
+				out = append(out, id)
+			}
+		}
+	}
+	return out
+}
+
+// isOverrideName сообщает, может ли id быть частью имени переменной оболочки
+func isOverrideName(id string) bool {
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return id != ""
+}
@@ -38,6 +38,9 @@ type Opts struct {
 	LikeDistros  bool
 	Languages    []string
 	LanguageTags []language.Tag
+	// Архитектура, для которой разрешаются переопределения
+	// (по умолчанию - архитектура системы)
+	Arch string
 }
 
 var DefaultOpts = &Opts{
@@ -61,14 +64,25 @@ func Resolve(info *distro.OSRelease, opts *Opts) ([]string, error) {
 		return nil, err
 	}
 
-	architectures, err := cpu.CompatibleArches(cpu.Arch())
+	arch := opts.Arch
+	if arch == "" {
+		arch = cpu.Arch()
+	}
+
+	architectures, err := cpu.CompatibleArches(arch)
 	if err != nil {
 		return nil, err
 	}
 
 	distros := []string{info.ID}
 	if opts.LikeDistros {
-		distros = append(distros, info.Like...)
+		// Некоторые дистрибутивы указывают собственный ID и в ID_LIKE
+		// (например, OpenWrt: ID_LIKE="lede openwrt"), поэтому пропускаем повторы
+		for _, like := range info.Like {
+			if like != "" && !slices.Contains(distros, like) {
+				distros = append(distros, like)
+			}
+		}
 	}
 
 	var out []string
@@ -133,6 +147,14 @@ func (o *Opts) WithLikeDistros(v bool) *Opts {
 	return out
 }
 
+func (o *Opts) WithArch(arch string) *Opts {
+	out := &Opts{}
+	*out = *o
+
+	out.Arch = arch
+	return out
+}
+
 func (o *Opts) WithLanguages(langs []string) *Opts {
 	out := &Opts{}
 	*out = *o
@@ -168,6 +190,19 @@ func parseLangs(langs []string, tags []language.Tag) ([]string, error) {
 	return out, nil
 }
 
+// VersionPlatformSpecific возвращает версию пакета в том виде, в котором
+// её сообщает пакетный менеджер: "epoch:version-release"
+func VersionPlatformSpecific(version string, release int, epoch uint, info *distro.OSRelease) string {
+	if release == 0 {
+		return version
+	}
+	ver := fmt.Sprintf("%s-%s", version, ReleasePlatformSpecific(release, info))
+	if epoch != 0 {
+		ver = fmt.Sprintf("%d:%s", epoch, ver)
+	}
+	return ver
+}
+
 func ReleasePlatformSpecific(release int, info *distro.OSRelease) string {
 	if info.ID == "altlinux" {
 		return fmt.Sprintf("alt%d", release)
@@ -129,6 +129,66 @@ func TestResolveArch(t *testing.T) {
 	}
 }
 
+func TestResolveTargetArch(t *testing.T) {
+	names, err := overrides.Resolve(info, overrides.DefaultOpts.
+		WithName("deps").
+		WithLikeDistros(false).
+		WithLanguages(nil).
+		WithArch("arm64"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	expected := []string{
+		"deps_arm64_centos",
+		"deps_centos",
+		"deps_arm64",
+		"deps",
+	}
+
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestResolveVoidOpenWrt(t *testing.T) {
+	type testCase struct {
+		info     *distro.OSRelease
+		expected []string
+	}
+
+	for _, tc := range []testCase{
+		{
+			info: &distro.OSRelease{ID: "void"},
+			expected: []string{
+				"deps_amd64_void",
+				"deps_void",
+				"deps_amd64",
+				"deps",
+			},
+		},
+		{
+			info: &distro.OSRelease{ID: "openwrt", Like: []string{"lede", "openwrt"}},
+			expected: []string{
+				"deps_amd64_openwrt",
+				"deps_openwrt",
+				"deps_amd64_lede",
+				"deps_lede",
+				"deps_amd64",
+				"deps",
+			},
+		},
+	} {
+		names, err := overrides.Resolve(tc.info, &overrides.Opts{
+			Name:        "deps",
+			Overrides:   true,
+			LikeDistros: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, names)
+	}
+}
+
 func TestResolveNoLikeDistros(t *testing.T) {
 	names, err := overrides.Resolve(info, &overrides.Opts{
 		Overrides:   true,
@@ -238,3 +298,11 @@ func TestReleasePlatformSpecific(t *testing.T) {
 		assert.Equal(t, 1, release)
 	}
 }
+
+func TestVersionPlatformSpecific(t *testing.T) {
+	alt := &distro.OSRelease{ID: "altlinux"}
+
+	assert.Equal(t, "1.0", overrides.VersionPlatformSpecific("1.0", 0, 0, alt))
+	assert.Equal(t, "1.0-alt2", overrides.VersionPlatformSpecific("1.0", 2, 0, alt))
+	assert.Equal(t, "3:1.0-alt2", overrides.VersionPlatformSpecific("1.0", 2, 3, alt))
+}
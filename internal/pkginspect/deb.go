@@ -0,0 +1,179 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkginspect
+
+import (
+	"archive/tar"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// debScripts - сценарии сопровождающего в control.tar
+var debScripts = []string{"preinst", "postinst", "prerm", "postrm", "config"}
+
+// readDeb читает пакет deb: архив ar с control.tar.* и data.tar.*
+func readDeb(r io.Reader, pkg *Package) error {
+	magic := make([]byte, len(debMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != string(debMagic) {
+		return errors.New("deb: not an ar archive")
+	}
+
+	var control, data bool
+	hdr := make([]byte, 60)
+	for {
+		_, err := io.ReadFull(r, hdr)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("deb: %w", err)
+		}
+
+		name := strings.TrimSuffix(strings.TrimSpace(string(hdr[:16])), "/")
+		size, err := strconv.ParseInt(strings.TrimSpace(string(hdr[48:58])), 10, 64)
+		if err != nil {
+			return fmt.Errorf("deb: invalid size of %s: %w", name, err)
+		}
+
+		member := io.LimitReader(r, size)
+		switch {
+		case strings.HasPrefix(name, "control.tar"):
+			control = true
+			err = readDebControlTar(name, member, pkg)
+		case strings.HasPrefix(name, "data.tar"):
+			data = true
+			err = readDebDataTar(name, member, pkg)
+		}
+		if err != nil {
+			return fmt.Errorf("deb: %s: %w", name, err)
+		}
+
+		// Остаток члена архива и выравнивание до чётного размера
+		if _, err := io.Copy(io.Discard, member); err != nil {
+			return fmt.Errorf("deb: %w", err)
+		}
+		if size%2 == 1 {
+			if _, err := io.CopyN(io.Discard, r, 1); err != nil && err != io.EOF {
+				return fmt.Errorf("deb: %w", err)
+			}
+		}
+	}
+
+	if !control || !data {
+		return errors.New("deb: control.tar or data.tar is missing")
+	}
+	return nil
+}
+
+func readDebControlTar(name string, r io.Reader, pkg *Package) error {
+	dr, err := decompress(name, r)
+	if err != nil {
+		return err
+	}
+	defer dr.Close()
+
+	return walkTar(dr, func(name string, hdr *tar.Header, tr *tar.Reader) error {
+		if hdr.Typeflag != tar.TypeReg {
+			return nil
+		}
+		base := path.Base(name)
+		if base == "control" {
+			return parseDebControl(tr, pkg)
+		}
+		for _, script := range debScripts {
+			if base == script {
+				content, err := io.ReadAll(tr)
+				if err != nil {
+					return err
+				}
+				pkg.Scripts[script] = string(content)
+			}
+		}
+		return nil
+	})
+}
+
+func readDebDataTar(name string, r io.Reader, pkg *Package) error {
+	dr, err := decompress(name, r)
+	if err != nil {
+		return err
+	}
+	defer dr.Close()
+
+	return walkTar(dr, func(name string, hdr *tar.Header, _ *tar.Reader) error {
+		pkg.Files = append(pkg.Files, tarFile(name, hdr))
+		return nil
+	})
+}
+
+// parseDebControl разбирает файл control в формате RFC 822
+// с продолжением значений на строках, начинающихся с пробела
+func parseDebControl(r io.Reader, pkg *Package) error {
+	fields := map[string]string{}
+	var last string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if last != "" {
+				fields[last] += "\n" + strings.TrimSpace(line)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		last = key
+		fields[key] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	pkg.Name = fields["Package"]
+	pkg.Version = fields["Version"]
+	pkg.Arch = fields["Architecture"]
+	pkg.Description, _, _ = strings.Cut(fields["Description"], "\n")
+	pkg.Maintainer = fields["Maintainer"]
+	pkg.Homepage = fields["Homepage"]
+	pkg.Depends = debList(fields["Pre-Depends"], fields["Depends"])
+	pkg.OptDepends = debList(fields["Recommends"], fields["Suggests"])
+	pkg.Provides = debList(fields["Provides"])
+	pkg.Conflicts = debList(fields["Conflicts"], fields["Breaks"])
+	pkg.Replaces = debList(fields["Replaces"])
+	return nil
+}
+
+// debList разбирает списки зависимостей, разделённые запятыми
+func debList(values ...string) []string {
+	var out []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.Join(strings.Fields(item), " "); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
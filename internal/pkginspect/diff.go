@@ -0,0 +1,253 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkginspect
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Diff - различия между двумя сборками пакета
+type Diff struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+
+	Metadata     []FieldChange  `json:"metadata,omitempty"`
+	AddedFiles   []File         `json:"added_files,omitempty"`
+	RemovedFiles []File         `json:"removed_files,omitempty"`
+	ChangedFiles []FileChange   `json:"changed_files,omitempty"`
+	Dependencies []ListChange   `json:"dependencies,omitempty"`
+	Scripts      []ScriptChange `json:"scripts,omitempty"`
+}
+
+// FieldChange - изменение поля метаданных
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// FileChange - файл, у которого изменились атрибуты
+type FileChange struct {
+	Path string `json:"path"`
+	Old  File   `json:"old"`
+	New  File   `json:"new"`
+}
+
+// ListChange - изменения списка зависимостей одного вида
+type ListChange struct {
+	Field   string   `json:"field"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Состояния сценария в ScriptChange
+const (
+	ScriptAdded   = "added"
+	ScriptRemoved = "removed"
+	ScriptChanged = "changed"
+)
+
+// ScriptChange - изменение сценария сопровождающего
+type ScriptChange struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Lines - изменённые строки с префиксом "-" или "+"
+	Lines []string `json:"lines,omitempty"`
+}
+
+// Label возвращает краткое обозначение пакета "имя версия"
+func (p *Package) Label() string {
+	return p.Name + " " + p.Version
+}
+
+// Compare сравнивает две сборки пакета
+func Compare(oldPkg, newPkg *Package) *Diff {
+	d := &Diff{Old: oldPkg.Label(), New: newPkg.Label()}
+
+	for _, f := range []struct {
+		name     string
+		old, new string
+	}{
+		{"format", oldPkg.Format, newPkg.Format},
+		{"name", oldPkg.Name, newPkg.Name},
+		{"version", oldPkg.Version, newPkg.Version},
+		{"arch", oldPkg.Arch, newPkg.Arch},
+		{"description", oldPkg.Description, newPkg.Description},
+		{"maintainer", oldPkg.Maintainer, newPkg.Maintainer},
+		{"license", oldPkg.License, newPkg.License},
+		{"homepage", oldPkg.Homepage, newPkg.Homepage},
+	} {
+		if f.old != f.new {
+			d.Metadata = append(d.Metadata, FieldChange{Field: f.name, Old: f.old, New: f.new})
+		}
+	}
+
+	d.compareFiles(oldPkg.Files, newPkg.Files)
+
+	for _, l := range []struct {
+		name     string
+		old, new []string
+	}{
+		{"depends", oldPkg.Depends, newPkg.Depends},
+		{"opt_depends", oldPkg.OptDepends, newPkg.OptDepends},
+		{"provides", oldPkg.Provides, newPkg.Provides},
+		{"conflicts", oldPkg.Conflicts, newPkg.Conflicts},
+		{"replaces", oldPkg.Replaces, newPkg.Replaces},
+	} {
+		change := ListChange{
+			Field:   l.name,
+			Added:   missing(l.new, l.old),
+			Removed: missing(l.old, l.new),
+		}
+		if len(change.Added) > 0 || len(change.Removed) > 0 {
+			d.Dependencies = append(d.Dependencies, change)
+		}
+	}
+
+	d.compareScripts(oldPkg.Scripts, newPkg.Scripts)
+	return d
+}
+
+// Empty сообщает, что сборки не отличаются
+func (d *Diff) Empty() bool {
+	return len(d.Metadata) == 0 &&
+		len(d.AddedFiles) == 0 &&
+		len(d.RemovedFiles) == 0 &&
+		len(d.ChangedFiles) == 0 &&
+		len(d.Dependencies) == 0 &&
+		len(d.Scripts) == 0
+}
+
+func (d *Diff) compareFiles(oldFiles, newFiles []File) {
+	oldByPath := make(map[string]File, len(oldFiles))
+	for _, f := range oldFiles {
+		oldByPath[f.Path] = f
+	}
+	newByPath := make(map[string]File, len(newFiles))
+	for _, f := range newFiles {
+		newByPath[f.Path] = f
+	}
+
+	for _, f := range newFiles {
+		old, ok := oldByPath[f.Path]
+		switch {
+		case !ok:
+			d.AddedFiles = append(d.AddedFiles, f)
+		case old != f:
+			d.ChangedFiles = append(d.ChangedFiles, FileChange{Path: f.Path, Old: old, New: f})
+		}
+	}
+	for _, f := range oldFiles {
+		if _, ok := newByPath[f.Path]; !ok {
+			d.RemovedFiles = append(d.RemovedFiles, f)
+		}
+	}
+}
+
+func (d *Diff) compareScripts(oldScripts, newScripts map[string]string) {
+	names := map[string]bool{}
+	for name := range oldScripts {
+		names[name] = true
+	}
+	for name := range newScripts {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		old, hadOld := oldScripts[name]
+		cur, hasNew := newScripts[name]
+		switch {
+		case !hadOld:
+			d.Scripts = append(d.Scripts, ScriptChange{Name: name, Status: ScriptAdded, Lines: prefixLines("+", cur)})
+		case !hasNew:
+			d.Scripts = append(d.Scripts, ScriptChange{Name: name, Status: ScriptRemoved, Lines: prefixLines("-", old)})
+		case old != cur:
+			d.Scripts = append(d.Scripts, ScriptChange{Name: name, Status: ScriptChanged, Lines: diffLines(old, cur)})
+		}
+	}
+}
+
+// missing возвращает элементы a, отсутствующие в b
+func missing(a, b []string) []string {
+	var out []string
+	for _, item := range a {
+		if !slices.Contains(b, item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func prefixLines(prefix, s string) []string {
+	lines := splitLines(s)
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return lines
+}
+
+// diffLines возвращает удалённые и добавленные строки по наибольшей
+// общей подпоследовательности. Сценарии короткие, поэтому квадратичная
+// сложность допустима.
+func diffLines(oldText, newText string) []string {
+	a, b := splitLines(oldText), splitLines(newText)
+
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			out = append(out, "-"+a[i])
+			i++
+		default:
+			out = append(out, "+"+b[j])
+			j++
+		}
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkginspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	oldPkg := &Package{
+		Format:  FormatDeb,
+		Name:    "foo",
+		Version: "1.0-1",
+		Depends: []string{"libc6", "libold"},
+		Scripts: map[string]string{
+			"postinst": "set -e\nldconfig\n",
+			"prerm":    "echo bye\n",
+		},
+		Files: []File{
+			{Path: "/usr/bin/foo", Mode: "-rwxr-xr-x", Owner: "root", Group: "root", Size: 10},
+			{Path: "/usr/lib/libold.so", Mode: "-rw-r--r--", Owner: "root", Group: "root", Size: 5},
+		},
+	}
+	newPkg := &Package{
+		Format:  FormatDeb,
+		Name:    "foo",
+		Version: "1.1-1",
+		Depends: []string{"libc6", "libnew"},
+		Scripts: map[string]string{
+			"postinst": "set -e\nsystemctl daemon-reload\nldconfig\n",
+		},
+		Files: []File{
+			{Path: "/usr/bin/foo", Mode: "-rwxr-x---", Owner: "root", Group: "root", Size: 12},
+			{Path: "/usr/lib/libnew.so", Mode: "-rw-r--r--", Owner: "root", Group: "root", Size: 7},
+		},
+	}
+
+	d := Compare(oldPkg, newPkg)
+	assert.False(t, d.Empty())
+	assert.Equal(t, []FieldChange{{Field: "version", Old: "1.0-1", New: "1.1-1"}}, d.Metadata)
+	assert.Equal(t, []File{newPkg.Files[1]}, d.AddedFiles)
+	assert.Equal(t, []File{oldPkg.Files[1]}, d.RemovedFiles)
+	require.Len(t, d.ChangedFiles, 1)
+	assert.Equal(t, "mode -rwxr-xr-x -> -rwxr-x---, size 10 -> 12", fileChanges(d.ChangedFiles[0]))
+	assert.Equal(t, []ListChange{{Field: "depends", Added: []string{"libnew"}, Removed: []string{"libold"}}}, d.Dependencies)
+	assert.Equal(t, []ScriptChange{
+		{Name: "postinst", Status: ScriptChanged, Lines: []string{"+systemctl daemon-reload"}},
+		{Name: "prerm", Status: ScriptRemoved, Lines: []string{"-echo bye"}},
+	}, d.Scripts)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDiff(&buf, FormatText, d))
+	assert.Contains(t, buf.String(), "--- foo 1.0-1\n+++ foo 1.1-1\n")
+	assert.Contains(t, buf.String(), "+ -rw-r--r-- root/root          7 /usr/lib/libnew.so\n")
+	assert.Contains(t, buf.String(), "depends:\n- libold\n+ libnew\n")
+
+	buf.Reset()
+	require.NoError(t, WriteDiff(&buf, FormatJSON, d))
+	var decoded Diff
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, *d, decoded)
+
+	assert.True(t, Compare(oldPkg, oldPkg).Empty())
+}
+
+func TestDiffLines(t *testing.T) {
+	assert.Equal(t,
+		[]string{"-b", "+x", "+d"},
+		diffLines("a\nb\nc\n", "a\nx\nc\nd\n"),
+	)
+}
@@ -0,0 +1,172 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkginspect
+
+import (
+	"archive/tar"
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// apkScripts сопоставляет служебные файлы apk с именами сценариев
+var apkScripts = map[string]string{
+	"/.pre-install":    "pre-install",
+	"/.post-install":   "post-install",
+	"/.pre-deinstall":  "pre-deinstall",
+	"/.post-deinstall": "post-deinstall",
+	"/.pre-upgrade":    "pre-upgrade",
+	"/.post-upgrade":   "post-upgrade",
+	"/.trigger":        "trigger",
+}
+
+// archScripts сопоставляет служебные файлы archlinux с именами сценариев
+var archScripts = map[string]string{
+	"/.INSTALL": "install",
+}
+
+// readAPK читает пакет apk: потоки gzip подписи, метаданных и данных,
+// вместе образующие один архив tar
+func readAPK(r io.Reader, pkg *Package) error {
+	dr, err := decompress(".gz", r)
+	if err != nil {
+		return err
+	}
+	defer dr.Close()
+
+	fields, err := readPkgInfoTar(dr, pkg, apkScripts)
+	if err != nil {
+		return err
+	}
+
+	pkg.Name = first(fields["pkgname"])
+	pkg.Version = first(fields["pkgver"])
+	pkg.Arch = first(fields["arch"])
+	pkg.Description = first(fields["pkgdesc"])
+	pkg.Maintainer = first(fields["maintainer"])
+	pkg.License = first(fields["license"])
+	pkg.Homepage = first(fields["url"])
+	for _, dep := range fields["depend"] {
+		// В apk конфликт записывается зависимостью с восклицательным знаком
+		if name, ok := strings.CutPrefix(dep, "!"); ok {
+			pkg.Conflicts = append(pkg.Conflicts, name)
+		} else {
+			pkg.Depends = append(pkg.Depends, dep)
+		}
+	}
+	pkg.Provides = fields["provides"]
+	pkg.Replaces = fields["replaces"]
+	return nil
+}
+
+// readArchLinux читает пакет archlinux: сжатый архив tar с .PKGINFO
+func readArchLinux(r io.Reader, pkg *Package) error {
+	br := bufio.NewReader(r)
+	header, _ := br.Peek(6)
+
+	name := ".tar"
+	switch {
+	case hasPrefix(header, zstdMagic):
+		name = ".zst"
+	case hasPrefix(header, xzMagic):
+		name = ".xz"
+	case hasPrefix(header, gzipMagic):
+		name = ".gz"
+	}
+	dr, err := decompress(name, br)
+	if err != nil {
+		return err
+	}
+	defer dr.Close()
+
+	fields, err := readPkgInfoTar(dr, pkg, archScripts)
+	if err != nil {
+		return err
+	}
+
+	pkg.Name = first(fields["pkgname"])
+	pkg.Version = first(fields["pkgver"])
+	pkg.Arch = first(fields["arch"])
+	pkg.Description = first(fields["pkgdesc"])
+	pkg.Maintainer = first(fields["packager"])
+	pkg.License = strings.Join(fields["license"], " ")
+	pkg.Homepage = first(fields["url"])
+	pkg.Depends = fields["depend"]
+	pkg.OptDepends = fields["optdepend"]
+	pkg.Provides = fields["provides"]
+	pkg.Conflicts = fields["conflict"]
+	pkg.Replaces = fields["replaces"]
+	return nil
+}
+
+// readPkgInfoTar читает архив с метаданными в .PKGINFO (apk и archlinux).
+// Служебные файлы в корне архива в список файлов не попадают.
+func readPkgInfoTar(r io.Reader, pkg *Package, scripts map[string]string) (map[string][]string, error) {
+	var fields map[string][]string
+	err := walkTar(r, func(name string, hdr *tar.Header, tr *tar.Reader) error {
+		if strings.HasPrefix(name, "/.") && strings.Count(name, "/") == 1 {
+			switch {
+			case name == "/.PKGINFO":
+				var err error
+				fields, err = parsePkgInfo(tr)
+				return err
+			case scripts[name] != "":
+				content, err := io.ReadAll(tr)
+				if err != nil {
+					return err
+				}
+				pkg.Scripts[scripts[name]] = string(content)
+			}
+			return nil
+		}
+		pkg.Files = append(pkg.Files, tarFile(name, hdr))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return nil, errors.New(".PKGINFO is missing")
+	}
+	return fields, nil
+}
+
+// parsePkgInfo разбирает строки "ключ = значение"; ключи могут повторяться
+func parsePkgInfo(r io.Reader) (map[string][]string, error) {
+	fields := map[string][]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		fields[strings.TrimSpace(key)] = append(fields[strings.TrimSpace(key)], strings.TrimSpace(value))
+	}
+	return fields, scanner.Err()
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
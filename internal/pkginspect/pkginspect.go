@@ -0,0 +1,320 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package pkginspect читает собранные пакеты deb, rpm, apk и archlinux
+// без внешних утилит и сравнивает их между собой
+package pkginspect
+
+import (
+	"archive/tar"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mholt/archiver/v4"
+)
+
+// Package описывает содержимое собранного пакета
+type Package struct {
+	Path        string `json:"path"`
+	Format      string `json:"format"`
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Arch        string `json:"arch"`
+	Description string `json:"description,omitempty"`
+	Maintainer  string `json:"maintainer,omitempty"`
+	License     string `json:"license,omitempty"`
+	Homepage    string `json:"homepage,omitempty"`
+
+	Depends    []string `json:"depends,omitempty"`
+	OptDepends []string `json:"opt_depends,omitempty"`
+	Provides   []string `json:"provides,omitempty"`
+	Conflicts  []string `json:"conflicts,omitempty"`
+	Replaces   []string `json:"replaces,omitempty"`
+
+	// Scripts - сценарии сопровождающего по именам, принятым в формате
+	// (postinst для deb, postin для rpm, post-install для apk, install для archlinux)
+	Scripts map[string]string `json:"scripts,omitempty"`
+	Files   []File            `json:"files"`
+}
+
+// File - запись в списке файлов пакета
+type File struct {
+	Path  string `json:"path"`
+	Mode  string `json:"mode"`
+	Owner string `json:"owner"`
+	Group string `json:"group"`
+	Size  int64  `json:"size"`
+	// Link - цель символической ссылки
+	Link string `json:"link,omitempty"`
+}
+
+// Форматы пакетов, совпадающие с именами упаковщиков nfpm
+const (
+	FormatDeb       = "deb"
+	FormatRPM       = "rpm"
+	FormatAPK       = "apk"
+	FormatArchLinux = "archlinux"
+)
+
+var (
+	debMagic  = []byte("!<arch>\n")
+	rpmMagic  = []byte{0xed, 0xab, 0xee, 0xdb}
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	xzMagic   = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+)
+
+// ErrUnknownFormat возвращается для файлов, не похожих на поддерживаемые пакеты
+var ErrUnknownFormat = errors.New("unknown package format")
+
+// DetectFormat определяет формат пакета по имени файла, а если оно
+// ничего не говорит - по первым байтам содержимого
+func DetectFormat(name string, header []byte) (string, error) {
+	base := filepath.Base(name)
+	switch {
+	case strings.HasSuffix(base, ".deb"):
+		return FormatDeb, nil
+	case strings.HasSuffix(base, ".rpm"):
+		return FormatRPM, nil
+	case strings.HasSuffix(base, ".apk"):
+		return FormatAPK, nil
+	case strings.Contains(base, ".pkg.tar"):
+		return FormatArchLinux, nil
+	}
+
+	switch {
+	case hasPrefix(header, debMagic):
+		return FormatDeb, nil
+	case hasPrefix(header, rpmMagic):
+		return FormatRPM, nil
+	case hasPrefix(header, gzipMagic):
+		return FormatAPK, nil
+	case hasPrefix(header, zstdMagic), hasPrefix(header, xzMagic):
+		return FormatArchLinux, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+func hasPrefix(b, prefix []byte) bool {
+	return len(b) >= len(prefix) && string(b[:len(prefix)]) == string(prefix)
+}
+
+// Open читает пакет по пути path
+func Open(path string) (*Package, error) {
+	fl, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fl.Close()
+
+	br := bufio.NewReader(fl)
+	header, _ := br.Peek(8)
+	format, err := DetectFormat(path, header)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	pkg := &Package{Path: path, Format: format, Scripts: map[string]string{}}
+	switch format {
+	case FormatDeb:
+		err = readDeb(br, pkg)
+	case FormatRPM:
+		err = readRPM(br, pkg)
+	case FormatAPK:
+		err = readAPK(br, pkg)
+	case FormatArchLinux:
+		err = readArchLinux(br, pkg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	sort.Slice(pkg.Files, func(i, j int) bool {
+		return pkg.Files[i].Path < pkg.Files[j].Path
+	})
+	return pkg, nil
+}
+
+// FindArtifact ищет в каталоге собранных пакетов pkgsDir (по подкаталогу
+// на базовый пакет) последний собранный пакет с именем name. Пакеты ALR
+// называются name+repo, поэтому name может быть указано и без репозитория.
+func FindArtifact(pkgsDir, name string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(pkgsDir, "*", name+"*"))
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		found   string
+		foundAt int64
+	)
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if _, err := DetectFormat(match, nil); err != nil {
+			continue
+		}
+		pkg, err := Open(match)
+		if err != nil || !matchesName(pkg.Name, name) {
+			continue
+		}
+		if mtime := info.ModTime().UnixNano(); found == "" || mtime > foundAt {
+			found, foundAt = match, mtime
+		}
+	}
+
+	if found == "" {
+		return "", fmt.Errorf("no built package %q found in %s", name, pkgsDir)
+	}
+	return found, nil
+}
+
+// matchesName сообщает, соответствует ли имя собранного пакета pkgName
+// имени name, указанному с репозиторием (foo+alr-default) или без него (foo)
+func matchesName(pkgName, name string) bool {
+	if pkgName == name {
+		return true
+	}
+	base, _, ok := strings.Cut(pkgName, "+")
+	return ok && base == name
+}
+
+// decompress открывает сжатый поток r по расширению name
+func decompress(name string, r io.Reader) (io.ReadCloser, error) {
+	switch {
+	case strings.HasSuffix(name, ".gz"):
+		return archiver.Gz{}.OpenReader(r)
+	case strings.HasSuffix(name, ".xz"):
+		return archiver.Xz{}.OpenReader(r)
+	case strings.HasSuffix(name, ".zst"):
+		return archiver.Zstd{}.OpenReader(r)
+	case strings.HasSuffix(name, ".bz2"):
+		return archiver.Bz2{}.OpenReader(r)
+	default:
+		return io.NopCloser(r), nil
+	}
+}
+
+// walkTar вызывает fn для каждой записи архива с нормализованным
+// абсолютным путём
+func walkTar(r io.Reader, fn func(name string, hdr *tar.Header, tr *tar.Reader) error) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := path.Clean("/" + hdr.Name)
+		if name == "/" {
+			continue
+		}
+		if err := fn(name, hdr, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// tarFile преобразует заголовок tar в запись списка файлов
+func tarFile(name string, hdr *tar.Header) File {
+	f := File{
+		Path:  name,
+		Mode:  modeString(hdr.FileInfo().Mode()),
+		Owner: hdr.Uname,
+		Group: hdr.Gname,
+		Size:  hdr.Size,
+		Link:  hdr.Linkname,
+	}
+	if f.Owner == "" {
+		f.Owner = idName(hdr.Uid)
+	}
+	if f.Group == "" {
+		f.Group = idName(hdr.Gid)
+	}
+	return f
+}
+
+// idName подставляет имя для идентификатора без имени в архиве.
+// Достоверно известно только имя root, остальные выводятся числами.
+func idName(id int) string {
+	if id == 0 {
+		return "root"
+	}
+	return fmt.Sprint(id)
+}
+
+// modeString выводит права в виде ls -l: -rwxr-xr-x, drwxr-xr-x, lrwxrwxrwx
+func modeString(mode fs.FileMode) string {
+	var typ byte
+	switch {
+	case mode.IsDir():
+		typ = 'd'
+	case mode&fs.ModeSymlink != 0:
+		// Права ссылок в Linux не используются и всегда выводятся как rwxrwxrwx
+		typ = 'l'
+		mode |= 0o777
+	case mode&fs.ModeCharDevice != 0:
+		typ = 'c'
+	case mode&fs.ModeDevice != 0:
+		typ = 'b'
+	case mode&fs.ModeNamedPipe != 0:
+		typ = 'p'
+	case mode&fs.ModeSocket != 0:
+		typ = 's'
+	default:
+		typ = '-'
+	}
+
+	const rwx = "rwxrwxrwx"
+	buf := []byte{typ}
+	for i := 0; i < 9; i++ {
+		if mode.Perm()&(1<<uint(8-i)) != 0 {
+			buf = append(buf, rwx[i])
+		} else {
+			buf = append(buf, '-')
+		}
+	}
+	if mode&fs.ModeSetuid != 0 {
+		buf[3] = setBit(buf[3], 's')
+	}
+	if mode&fs.ModeSetgid != 0 {
+		buf[6] = setBit(buf[6], 's')
+	}
+	if mode&fs.ModeSticky != 0 {
+		buf[9] = setBit(buf[9], 't')
+	}
+	return string(buf)
+}
+
+// setBit заменяет x в строке прав на c, а - на заглавную C
+func setBit(cur, c byte) byte {
+	if cur == '-' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
@@ -0,0 +1,150 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkginspect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/nfpm/v2"
+	_ "github.com/goreleaser/nfpm/v2/apk"
+	_ "github.com/goreleaser/nfpm/v2/arch"
+	_ "github.com/goreleaser/nfpm/v2/deb"
+	"github.com/goreleaser/nfpm/v2/files"
+	_ "github.com/goreleaser/nfpm/v2/rpm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildPackage собирает тестовый пакет foo из репозитория alr-default
+// в формате format
+func buildPackage(t *testing.T, format string) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	bin := filepath.Join(dir, "foo")
+	require.NoError(t, os.WriteFile(bin, []byte("#!/bin/sh\necho foo\n"), 0o755))
+	script := filepath.Join(dir, "postinstall.sh")
+	require.NoError(t, os.WriteFile(script, []byte("echo installed\n"), 0o644))
+
+	info := nfpm.WithDefaults(&nfpm.Info{
+		Name:        "foo+alr-default",
+		Arch:        "amd64",
+		Platform:    "linux",
+		Version:     "1.2.3",
+		Release:     "1",
+		Maintainer:  "ALR <alr@example.com>",
+		Description: "Test package",
+		Homepage:    "https://example.com",
+		License:     "GPL-3.0-or-later",
+		Overridables: nfpm.Overridables{
+			Depends:   []string{"bash"},
+			Provides:  []string{"foo-bin"},
+			Conflicts: []string{"oldfoo"},
+			Replaces:  []string{"oldfoo"},
+			Contents: files.Contents{
+				{Source: bin, Destination: "/usr/bin/foo", FileInfo: &files.ContentFileInfo{Mode: 0o755}},
+				{Source: "/usr/bin/foo", Destination: "/usr/bin/foo-link", Type: files.TypeSymlink},
+			},
+			Scripts: nfpm.Scripts{PostInstall: script},
+		},
+	})
+
+	packager, err := nfpm.Get(format)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, packager.ConventionalFileName(info))
+	fl, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, packager.Package(info, fl))
+	require.NoError(t, fl.Close())
+	return path
+}
+
+func TestOpen(t *testing.T) {
+	for _, format := range []string{FormatDeb, FormatRPM, FormatAPK, FormatArchLinux} {
+		t.Run(format, func(t *testing.T) {
+			pkg, err := Open(buildPackage(t, format))
+			require.NoError(t, err)
+
+			assert.Equal(t, format, pkg.Format)
+			assert.Equal(t, "foo+alr-default", pkg.Name)
+			assert.Contains(t, pkg.Version, "1.2.3")
+			assert.NotEmpty(t, pkg.Arch)
+			assert.Equal(t, "Test package", pkg.Description)
+			assert.Contains(t, pkg.Depends, "bash")
+			assert.Contains(t, pkg.Replaces, "oldfoo")
+			if format != FormatAPK {
+				assert.Contains(t, pkg.Conflicts, "oldfoo")
+			}
+			assert.Len(t, pkg.Scripts, 1)
+			for _, content := range pkg.Scripts {
+				assert.Contains(t, content, "echo installed")
+			}
+
+			byPath := map[string]File{}
+			for _, f := range pkg.Files {
+				byPath[f.Path] = f
+			}
+			require.Contains(t, byPath, "/usr/bin/foo")
+			assert.Equal(t, "-rwxr-xr-x", byPath["/usr/bin/foo"].Mode)
+			assert.Equal(t, "root", byPath["/usr/bin/foo"].Owner)
+			assert.EqualValues(t, len("#!/bin/sh\necho foo\n"), byPath["/usr/bin/foo"].Size)
+			require.Contains(t, byPath, "/usr/bin/foo-link")
+			assert.Equal(t, "/usr/bin/foo", byPath["/usr/bin/foo-link"].Link)
+		})
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	format, err := DetectFormat("foo_1.0_amd64.deb", nil)
+	require.NoError(t, err)
+	assert.Equal(t, FormatDeb, format)
+
+	format, err = DetectFormat("foo-1.0-1-x86_64.pkg.tar.zst", nil)
+	require.NoError(t, err)
+	assert.Equal(t, FormatArchLinux, format)
+
+	format, err = DetectFormat("artifact", []byte{0xed, 0xab, 0xee, 0xdb, 0x03})
+	require.NoError(t, err)
+	assert.Equal(t, FormatRPM, format)
+
+	_, err = DetectFormat("README", []byte("hello"))
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestFindArtifact(t *testing.T) {
+	built := buildPackage(t, FormatDeb)
+	pkgsDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pkgsDir, "foo"), 0o755))
+	dest := filepath.Join(pkgsDir, "foo", filepath.Base(built))
+	data, err := os.ReadFile(built)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dest, data, 0o644))
+
+	for _, name := range []string{"foo", "foo+alr-default"} {
+		path, err := FindArtifact(pkgsDir, name)
+		require.NoError(t, err, name)
+		assert.Equal(t, dest, path, name)
+	}
+
+	for _, name := range []string{"bar", "fo", "foo+other"} {
+		_, err = FindArtifact(pkgsDir, name)
+		assert.Error(t, err, name)
+	}
+}
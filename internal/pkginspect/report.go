@@ -0,0 +1,204 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkginspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Форматы вывода
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// WritePackage выводит содержимое пакета в указанном формате
+func WritePackage(w io.Writer, format string, pkg *Package) error {
+	switch format {
+	case "", FormatText:
+		return writePackageText(w, pkg)
+	case FormatJSON:
+		return writeJSON(w, pkg)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// WriteDiff выводит различия сборок в указанном формате
+func WriteDiff(w io.Writer, format string, d *Diff) error {
+	switch format {
+	case "", FormatText:
+		return writeDiffText(w, d)
+	case FormatJSON:
+		return writeJSON(w, d)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// textWriter запоминает первую ошибку записи, чтобы не проверять каждую строку
+type textWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (t *textWriter) printf(format string, args ...any) {
+	if t.err == nil {
+		_, t.err = fmt.Fprintf(t.w, format, args...)
+	}
+}
+
+func writePackageText(w io.Writer, pkg *Package) error {
+	t := &textWriter{w: w}
+
+	for _, f := range []struct{ name, value string }{
+		{"Name", pkg.Name},
+		{"Version", pkg.Version},
+		{"Architecture", pkg.Arch},
+		{"Format", pkg.Format},
+		{"Description", pkg.Description},
+		{"Maintainer", pkg.Maintainer},
+		{"License", pkg.License},
+		{"Homepage", pkg.Homepage},
+	} {
+		if f.value != "" {
+			t.printf("%-13s %s\n", f.name+":", f.value)
+		}
+	}
+
+	for _, l := range []struct {
+		name   string
+		values []string
+	}{
+		{"Depends", pkg.Depends},
+		{"OptDepends", pkg.OptDepends},
+		{"Provides", pkg.Provides},
+		{"Conflicts", pkg.Conflicts},
+		{"Replaces", pkg.Replaces},
+	} {
+		if len(l.values) > 0 {
+			t.printf("%-13s %s\n", l.name+":", strings.Join(l.values, ", "))
+		}
+	}
+
+	if len(pkg.Scripts) > 0 {
+		names := make([]string, 0, len(pkg.Scripts))
+		for name := range pkg.Scripts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		t.printf("\nScripts:\n")
+		for _, name := range names {
+			t.printf("  %s:\n", name)
+			for _, line := range splitLines(pkg.Scripts[name]) {
+				t.printf("    %s\n", line)
+			}
+		}
+	}
+
+	t.printf("\nFiles (%d):\n", len(pkg.Files))
+	for _, f := range pkg.Files {
+		t.printf("  %s\n", fileLine(f))
+	}
+	return t.err
+}
+
+// fileLine выводит файл в виде строки ls -l: права, владелец, размер и путь
+func fileLine(f File) string {
+	line := fmt.Sprintf("%s %s/%s %10d %s", f.Mode, f.Owner, f.Group, f.Size, f.Path)
+	if f.Link != "" {
+		line += " -> " + f.Link
+	}
+	return line
+}
+
+func writeDiffText(w io.Writer, d *Diff) error {
+	t := &textWriter{w: w}
+	t.printf("--- %s\n+++ %s\n", d.Old, d.New)
+
+	if d.Empty() {
+		t.printf("No differences\n")
+		return t.err
+	}
+
+	for _, c := range d.Metadata {
+		t.printf("%s: %s -> %s\n", c.Field, c.Old, c.New)
+	}
+
+	if len(d.AddedFiles)+len(d.RemovedFiles)+len(d.ChangedFiles) > 0 {
+		t.printf("\nFiles:\n")
+		for _, f := range d.RemovedFiles {
+			t.printf("- %s\n", fileLine(f))
+		}
+		for _, f := range d.AddedFiles {
+			t.printf("+ %s\n", fileLine(f))
+		}
+		for _, c := range d.ChangedFiles {
+			t.printf("~ %s: %s\n", c.Path, fileChanges(c))
+		}
+	}
+
+	for _, c := range d.Dependencies {
+		t.printf("\n%s:\n", c.Field)
+		for _, dep := range c.Removed {
+			t.printf("- %s\n", dep)
+		}
+		for _, dep := range c.Added {
+			t.printf("+ %s\n", dep)
+		}
+	}
+
+	if len(d.Scripts) > 0 {
+		t.printf("\nScripts:\n")
+		for _, s := range d.Scripts {
+			t.printf("%s (%s)\n", s.Name, s.Status)
+			for _, line := range s.Lines {
+				t.printf("  %s\n", line)
+			}
+		}
+	}
+	return t.err
+}
+
+// fileChanges перечисляет изменившиеся атрибуты файла
+func fileChanges(c FileChange) string {
+	var changes []string
+	if c.Old.Mode != c.New.Mode {
+		changes = append(changes, fmt.Sprintf("mode %s -> %s", c.Old.Mode, c.New.Mode))
+	}
+	if c.Old.Owner != c.New.Owner || c.Old.Group != c.New.Group {
+		changes = append(changes, fmt.Sprintf("owner %s/%s -> %s/%s", c.Old.Owner, c.Old.Group, c.New.Owner, c.New.Group))
+	}
+	if c.Old.Size != c.New.Size {
+		changes = append(changes, fmt.Sprintf("size %d -> %d", c.Old.Size, c.New.Size))
+	}
+	if c.Old.Link != c.New.Link {
+		changes = append(changes, fmt.Sprintf("link %s -> %s", c.Old.Link, c.New.Link))
+	}
+	return strings.Join(changes, ", ")
+}
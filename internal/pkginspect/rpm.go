@@ -0,0 +1,193 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkginspect
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/rpmheader"
+)
+
+// Флаги зависимостей rpm
+const (
+	rpmSenseLess    = 1 << 1
+	rpmSenseGreater = 1 << 2
+	rpmSenseEqual   = 1 << 3
+	rpmSenseRPMLib  = 1 << 24
+)
+
+// rpmScripts сопоставляет теги сценариев с их именами
+var rpmScripts = map[int32]string{
+	rpmheader.TagPreIn:     "prein",
+	rpmheader.TagPostIn:    "postin",
+	rpmheader.TagPreUn:     "preun",
+	rpmheader.TagPostUn:    "postun",
+	rpmheader.TagPreTrans:  "pretrans",
+	rpmheader.TagPostTrans: "posttrans",
+}
+
+// readRPM читает заголовок пакета rpm. Список файлов берётся из заголовка,
+// поэтому распаковывать полезную нагрузку не требуется.
+func readRPM(r io.Reader, pkg *Package) error {
+	hdr, err := rpmheader.ReadPackage(r)
+	if err != nil {
+		return fmt.Errorf("rpm: %w", err)
+	}
+
+	pkg.Name = hdr.String(rpmheader.TagName)
+	pkg.Version = hdr.String(rpmheader.TagVersion) + "-" + hdr.String(rpmheader.TagRelease)
+	if epoch := hdr.Ints(rpmheader.TagEpoch); len(epoch) > 0 && epoch[0] != 0 {
+		pkg.Version = fmt.Sprintf("%d:%s", epoch[0], pkg.Version)
+	}
+	pkg.Arch = hdr.String(rpmheader.TagArch)
+	pkg.Description = hdr.String(rpmheader.TagSummary)
+	pkg.Maintainer = hdr.String(rpmheader.TagPackager)
+	pkg.License = hdr.String(rpmheader.TagLicense)
+	pkg.Homepage = hdr.String(rpmheader.TagURL)
+
+	pkg.Depends = rpmDeps(hdr, rpmheader.TagRequireName, rpmheader.TagRequireFlags, rpmheader.TagRequireVersion)
+	pkg.OptDepends = append(
+		rpmDeps(hdr, rpmheader.TagRecommendName, rpmheader.TagRecommendFlags, rpmheader.TagRecommendVer),
+		rpmDeps(hdr, rpmheader.TagSuggestName, rpmheader.TagSuggestFlags, rpmheader.TagSuggestVer)...,
+	)
+	pkg.Provides = rpmDeps(hdr, rpmheader.TagProvideName, rpmheader.TagProvideFlags, rpmheader.TagProvideVersion)
+	pkg.Conflicts = rpmDeps(hdr, rpmheader.TagConflictName, rpmheader.TagConflictFlags, rpmheader.TagConflictVer)
+	pkg.Replaces = rpmDeps(hdr, rpmheader.TagObsoleteName, rpmheader.TagObsoleteFlags, rpmheader.TagObsoleteVer)
+
+	for tag, name := range rpmScripts {
+		if script := hdr.String(tag); script != "" {
+			pkg.Scripts[name] = script
+		}
+	}
+
+	pkg.Files = rpmFiles(hdr)
+	return nil
+}
+
+// rpmDeps собирает зависимости вида "имя >= версия", пропуская
+// служебные зависимости rpmlib()
+func rpmDeps(h *rpmheader.Header, nameTag, flagsTag, versionTag int32) []string {
+	names := h.Strings(nameTag)
+	flags := h.Ints(flagsTag)
+	versions := h.Strings(versionTag)
+
+	var out []string
+	for i, name := range names {
+		var flag int64
+		if i < len(flags) {
+			flag = flags[i]
+		}
+		if flag&rpmSenseRPMLib != 0 || strings.HasPrefix(name, "rpmlib(") {
+			continue
+		}
+
+		dep := name
+		if i < len(versions) && versions[i] != "" {
+			op := ""
+			if flag&rpmSenseLess != 0 {
+				op += "<"
+			}
+			if flag&rpmSenseGreater != 0 {
+				op += ">"
+			}
+			if flag&rpmSenseEqual != 0 {
+				op += "="
+			}
+			dep = fmt.Sprintf("%s %s %s", name, op, versions[i])
+		}
+		out = append(out, dep)
+	}
+	return out
+}
+
+// rpmFiles собирает список файлов из тегов DIRNAMES/BASENAMES и атрибутов
+func rpmFiles(h *rpmheader.Header) []File {
+	baseNames := h.Strings(rpmheader.TagBaseNames)
+	dirNames := h.Strings(rpmheader.TagDirNames)
+	dirIndexes := h.Ints(rpmheader.TagDirIndexes)
+	modes := h.Ints(rpmheader.TagFileModes)
+	sizes := h.Ints(rpmheader.TagLongFileSizes)
+	if sizes == nil {
+		sizes = h.Ints(rpmheader.TagFileSizes)
+	}
+	users := h.Strings(rpmheader.TagFileUserName)
+	groups := h.Strings(rpmheader.TagFileGroupName)
+	links := h.Strings(rpmheader.TagFileLinkTos)
+
+	at := func(values []string, i int) string {
+		if i < len(values) {
+			return values[i]
+		}
+		return ""
+	}
+
+	files := make([]File, 0, len(baseNames))
+	for i, base := range baseNames {
+		dir := ""
+		if i < len(dirIndexes) && int(dirIndexes[i]) < len(dirNames) {
+			dir = dirNames[dirIndexes[i]]
+		}
+
+		f := File{
+			Path:  path.Clean("/" + dir + base),
+			Owner: at(users, i),
+			Group: at(groups, i),
+			Link:  at(links, i),
+		}
+		if i < len(modes) {
+			f.Mode = modeString(unixMode(uint32(modes[i])))
+		}
+		if i < len(sizes) {
+			f.Size = sizes[i]
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// unixMode преобразует режим файла st_mode в fs.FileMode
+func unixMode(mode uint32) fs.FileMode {
+	m := fs.FileMode(mode & 0o777)
+	switch mode & 0o170000 {
+	case 0o040000:
+		m |= fs.ModeDir
+	case 0o120000:
+		m |= fs.ModeSymlink
+	case 0o020000:
+		m |= fs.ModeDevice | fs.ModeCharDevice
+	case 0o060000:
+		m |= fs.ModeDevice
+	case 0o010000:
+		m |= fs.ModeNamedPipe
+	case 0o140000:
+		m |= fs.ModeSocket
+	}
+	if mode&0o4000 != 0 {
+		m |= fs.ModeSetuid
+	}
+	if mode&0o2000 != 0 {
+		m |= fs.ModeSetgid
+	}
+	if mode&0o1000 != 0 {
+		m |= fs.ModeSticky
+	}
+	return m
+}
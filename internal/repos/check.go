@@ -0,0 +1,371 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repos
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"git.alr-pkg.ru/xpamych/vercmp"
+	"github.com/leonelquinteros/gotext"
+	"github.com/pelletier/go-toml/v2"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/config"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/lint"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/overrides"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/depver"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+// DefaultCheckDistros - ID дистрибутивов, для которых по умолчанию проверяется репозиторий
+var DefaultCheckDistros = []string{"debian", "ubuntu", "fedora", "altlinux", "arch", "alpine", "opensuse-tumbleweed"}
+
+// CheckOptions задаёт параметры проверки репозитория
+type CheckOptions struct {
+	// Distros - профили дистрибутивов, для которых выполняется первый проход скриптов
+	Distros []distro.OSRelease
+	// Known - известные системные пакеты по ID дистрибутива (или ID_LIKE).
+	// Пакеты с пустым ключом известны во всех дистрибутивах.
+	// Зависимости проверяются только для дистрибутивов, у которых есть список.
+	Known map[string][]string
+	// Lint включает статический анализ каждого скрипта
+	Lint bool
+}
+
+// CheckDistros возвращает профили дистрибутивов по их ID.
+// Неизвестные ID используются как есть, без ID_LIKE.
+func CheckDistros(ids []string) []distro.OSRelease {
+	out := make([]distro.OSRelease, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, overrides.KnownDistro(id))
+	}
+	return out
+}
+
+// ReadKnownPackages читает список системных пакетов: по одному имени на строку,
+// пустые строки и строки, начинающиеся с #, пропускаются
+func ReadKnownPackages(path string) ([]string, error) {
+	fl, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fl.Close()
+
+	var out []string
+	sc := bufio.NewScanner(fl)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		out = append(out, line)
+	}
+	return out, sc.Err()
+}
+
+// checkedScript - результаты первого прохода одного скрипта
+type checkedScript struct {
+	path string
+	// pkgs - пакеты скрипта по ID дистрибутива
+	pkgs map[string][]*alrsh.Package
+}
+
+// Check проверяет каталог репозитория: alr-repo.toml, первый проход каждого alr.sh
+// для всех профилей дистрибутивов, уникальность имён пакетов и разрешимость зависимостей.
+func Check(ctx context.Context, dir string, opts CheckOptions) ([]lint.Diagnostic, error) {
+	diags := checkRepoConfig(dir)
+
+	paths, err := FindScripts(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		diags = append(diags, lint.NewDiagnostic(dir, lint.RuleRepoConfig, gotext.Get("No alr.sh files found in repository")))
+		return diags, nil
+	}
+
+	var scripts []checkedScript
+	for _, path := range paths {
+		if opts.Lint {
+			found, err := lint.LintFile(path)
+			if err != nil {
+				return nil, err
+			}
+			diags = append(diags, found...)
+		}
+
+		sf, err := alrsh.ReadFromLocal(path)
+		if err != nil {
+			// Синтаксическая ошибка уже отражена линтером
+			if !opts.Lint {
+				diags = append(diags, lint.NewDiagnostic(path, lint.RuleEvaluation, err.Error()))
+			}
+			continue
+		}
+
+		cs := checkedScript{path: path, pkgs: map[string][]*alrsh.Package{}}
+		for _, info := range opts.Distros {
+			_, pkgs, err := sf.ParseBuildVars(ctx, &info, nil)
+			if err != nil {
+				diags = append(diags, lint.NewDiagnostic(path, lint.RuleEvaluation, gotext.Get("Error parsing script for %s: %s", info.ID, err)))
+				continue
+			}
+			cs.pkgs[info.ID] = pkgs
+		}
+		scripts = append(scripts, cs)
+	}
+
+	diags = append(diags, checkDuplicates(scripts)...)
+	diags = append(diags, checkDependencies(scripts, opts)...)
+	return diags, nil
+}
+
+// FindScripts находит скрипты репозитория так же, как processRepoFull:
+// корневой alr.sh или alr.sh в каталогах первого уровня
+func FindScripts(dir string) ([]string, error) {
+	rootScript := filepath.Join(dir, "alr.sh")
+	if fi, err := os.Stat(rootScript); err == nil && !fi.IsDir() {
+		return []string{rootScript}, nil
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "*/alr.sh"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(matches)
+	return matches, nil
+}
+
+// scriptNames возвращает имена и provides пакетов скрипта во всех профилях
+func (cs checkedScript) scriptNames() (names, provides []string) {
+	for _, pkgs := range cs.pkgs {
+		for _, pkg := range pkgs {
+			if !slices.Contains(names, pkg.Name) {
+				names = append(names, pkg.Name)
+			}
+			for _, p := range pkg.Provides {
+				name := depver.Parse(p).Name
+				if !slices.Contains(provides, name) {
+					provides = append(provides, name)
+				}
+			}
+		}
+	}
+	slices.Sort(names)
+	slices.Sort(provides)
+	return names, provides
+}
+
+// checkDuplicates ищет одинаковые имена пакетов и provides в разных скриптах
+func checkDuplicates(scripts []checkedScript) []lint.Diagnostic {
+	var diags []lint.Diagnostic
+	owners := map[string]string{}
+	providers := map[string]string{}
+	for _, cs := range scripts {
+		names, provides := cs.scriptNames()
+		for _, name := range names {
+			if other, ok := owners[name]; ok {
+				diags = append(diags, lint.NewDiagnostic(cs.path, lint.RuleDuplicatePackage, gotext.Get("package %s is already defined in %s", name, other)))
+				continue
+			}
+			owners[name] = cs.path
+		}
+		for _, name := range provides {
+			if slices.Contains(names, name) {
+				continue
+			}
+			if other, ok := providers[name]; ok {
+				diags = append(diags, lint.NewDiagnostic(cs.path, lint.RuleDuplicateProvides, gotext.Get("%s is also provided by %s", name, other)))
+				continue
+			}
+			providers[name] = cs.path
+		}
+	}
+	return diags
+}
+
+// checkDependencies проверяет, что зависимости являются пакетами репозитория
+// или известными системными пакетами соответствующего дистрибутива
+func checkDependencies(scripts []checkedScript, opts CheckOptions) []lint.Diagnostic {
+	alrNames := map[string]bool{}
+	for _, cs := range scripts {
+		names, provides := cs.scriptNames()
+		for _, name := range append(names, provides...) {
+			alrNames[name] = true
+		}
+	}
+
+	var diags []lint.Diagnostic
+	for _, cs := range scripts {
+		// Для каждой зависимости собираем дистрибутивы, где она не найдена,
+		// чтобы сообщить о ней один раз
+		missing := map[string][]string{}
+		var order []string
+
+		for _, info := range opts.Distros {
+			known, ok := knownFor(info, opts.Known)
+			if !ok {
+				continue
+			}
+			for _, pkg := range cs.pkgs[info.ID] {
+				deps := slices.Concat(pkg.Depends.Resolved(), pkg.BuildDepends.Resolved())
+				for _, opt := range pkg.OptDepends.Resolved() {
+					name, _, _ := strings.Cut(opt, ":")
+					deps = append(deps, strings.TrimSpace(name))
+				}
+
+				for _, dep := range deps {
+					name := depver.Parse(dep).Name
+					if name == "" || alrNames[name] || known[name] {
+						continue
+					}
+					if _, seen := missing[name]; !seen {
+						order = append(order, name)
+					}
+					if !slices.Contains(missing[name], info.ID) {
+						missing[name] = append(missing[name], info.ID)
+					}
+				}
+			}
+		}
+
+		for _, name := range order {
+			diags = append(diags, lint.NewDiagnostic(cs.path, lint.RuleUnresolvedDep, gotext.Get(
+				"dependency %s is neither an ALR package nor a known system package on %s",
+				name, strings.Join(missing[name], ", "),
+			)))
+		}
+	}
+	return diags
+}
+
+// knownFor объединяет списки системных пакетов для дистрибутива и его ID_LIKE.
+// Второе значение ложно, если для дистрибутива нет ни одного списка.
+func knownFor(info distro.OSRelease, lists map[string][]string) (map[string]bool, bool) {
+	out := map[string]bool{}
+	found := false
+	for _, id := range append([]string{"", info.ID}, info.Like...) {
+		names, ok := lists[id]
+		if !ok {
+			continue
+		}
+		found = true
+		for _, name := range names {
+			out[name] = true
+		}
+	}
+	return out, found
+}
+
+var repoVersionRegex = regexp.MustCompile(`^v?\d+(\.\d+)*([-+~][0-9A-Za-z.+~-]*)?$`)
+
+// scpLikeURLRegex соответствует адресам git вида user@host:path
+var scpLikeURLRegex = regexp.MustCompile(`^[\w.-]+@[\w.-]+:.+$`)
+
+// checkRepoConfig проверяет alr-repo.toml
+func checkRepoConfig(dir string) []lint.Diagnostic {
+	path := filepath.Join(dir, "alr-repo.toml")
+	fl, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		d := lint.NewDiagnostic(path, lint.RuleRepoConfig, gotext.Get("Git repository does not appear to be a valid ALR repo"))
+		d.Severity = lint.SeverityWarning
+		return []lint.Diagnostic{d}
+	} else if err != nil {
+		return []lint.Diagnostic{lint.NewDiagnostic(path, lint.RuleRepoConfig, err.Error())}
+	}
+	defer fl.Close()
+
+	var diags []lint.Diagnostic
+	report := func(row, col int, message string) {
+		d := lint.NewDiagnostic(path, lint.RuleRepoConfig, message)
+		d.Line, d.Column = uint(row), uint(col)
+		diags = append(diags, d)
+	}
+
+	var repoCfg types.RepoConfig
+	err = toml.NewDecoder(fl).DisallowUnknownFields().Decode(&repoCfg)
+	var strictErr *toml.StrictMissingError
+	var decodeErr *toml.DecodeError
+	switch {
+	case errors.As(err, &strictErr):
+		// Неизвестные ключи не мешают чтению остальных настроек
+		for _, e := range strictErr.Errors {
+			row, col := e.Position()
+			report(row, col, gotext.Get("unknown key %s", strings.Join(e.Key(), ".")))
+		}
+	case errors.As(err, &decodeErr):
+		row, col := decodeErr.Position()
+		report(row, col, decodeErr.Error())
+		return diags
+	case err != nil:
+		report(0, 0, err.Error())
+		return diags
+	}
+
+	if v := repoCfg.Repo.MinVersion; v != "" {
+		if !repoVersionRegex.MatchString(v) {
+			report(0, 0, gotext.Get("minVersion %q is not a valid version", v))
+		} else if strings.HasPrefix(config.Version, "v") && vercmp.Compare(config.Version, v) == -1 {
+			d := lint.NewDiagnostic(path, lint.RuleRepoConfig, gotext.Get("ALR repo's minimum ALR version is greater than the current version. Try updating ALR if something doesn't work."))
+			d.Severity = lint.SeverityWarning
+			diags = append(diags, d)
+		}
+	}
+
+	if repoCfg.Repo.URL != "" && !isValidRepoURL(repoCfg.Repo.URL) {
+		report(0, 0, gotext.Get("url %q is not a valid repository URL", repoCfg.Repo.URL))
+	}
+	for i, mirror := range repoCfg.Repo.Mirrors {
+		switch {
+		case !isValidRepoURL(mirror):
+			report(0, 0, gotext.Get("mirror %q is not a valid repository URL", mirror))
+		case mirror == repoCfg.Repo.URL:
+			report(0, 0, gotext.Get("mirror %q is the same as the repository URL", mirror))
+		case slices.Contains(repoCfg.Repo.Mirrors[:i], mirror):
+			report(0, 0, gotext.Get("mirror %q is listed more than once", mirror))
+		}
+	}
+
+	return diags
+}
+
+// isValidRepoURL сообщает, может ли git клонировать репозиторий по адресу
+func isValidRepoURL(raw string) bool {
+	if scpLikeURLRegex.MatchString(raw) {
+		return true
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "http", "https", "ssh", "git":
+		return u.Host != ""
+	case "file":
+		return u.Path != ""
+	default:
+		return false
+	}
+}
@@ -0,0 +1,149 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repos
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/lint"
+)
+
+func writeRepoFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func diagsByRule(diags []lint.Diagnostic) map[string][]string {
+	out := map[string][]string{}
+	for _, d := range diags {
+		out[d.Rule] = append(out[d.Rule], d.Message)
+	}
+	return out
+}
+
+func TestCheck(t *testing.T) {
+	dir := writeRepoFiles(t, map[string]string{
+		"alr-repo.toml": "[repo]\nminVersion = \"v0.0.1\"\nmirrors = [\"https://example.com/alr.git\"]\n",
+		"foo/alr.sh": `name='foo'
+version='1.0'
+release='1'
+provides=('libfoo')
+deps=('bar>=1.0' 'libc6')
+deps_fedora=('bar' 'glibc' 'nosuch')
+package() { :; }
+`,
+		"bar/alr.sh": `name='bar'
+version='1.0'
+release='1'
+provides=('libfoo')
+package() { :; }
+`,
+		"dup/alr.sh": `name='foo'
+version='2.0'
+release='1'
+package() { :; }
+`,
+		"broken/alr.sh": `name='broken'
+version='1.0'
+release='1'
+exit 1
+`,
+	})
+
+	diags, err := Check(context.Background(), dir, CheckOptions{
+		Distros: CheckDistros([]string{"debian", "ubuntu", "fedora", "arch"}),
+		Known: map[string][]string{
+			"debian": {"libc6"},
+			"fedora": {"glibc"},
+		},
+	})
+	require.NoError(t, err)
+
+	byRule := diagsByRule(diags)
+	assert.NotContains(t, byRule, lint.RuleRepoConfig)
+	assert.Equal(t, []string{"package foo is already defined in " + filepath.Join(dir, "dup", "alr.sh")}, byRule[lint.RuleDuplicatePackage])
+	assert.Equal(t, []string{"libfoo is also provided by " + filepath.Join(dir, "bar", "alr.sh")}, byRule[lint.RuleDuplicateProvides])
+	// ubuntu использует список debian через ID_LIKE, а для arch списка нет
+	assert.Equal(t, []string{"dependency nosuch is neither an ALR package nor a known system package on fedora"}, byRule[lint.RuleUnresolvedDep])
+	assert.Len(t, byRule[lint.RuleEvaluation], 4)
+	assert.True(t, lint.HasErrors(diags))
+}
+
+func TestCheckRootScript(t *testing.T) {
+	dir := writeRepoFiles(t, map[string]string{
+		"alr-repo.toml": "[repo]\n",
+		"alr.sh":        "name='foo'\nversion='1.0'\nrelease='1'\npackage() { :; }\n",
+		"sub/alr.sh":    "name='foo'\n",
+	})
+
+	diags, err := Check(context.Background(), dir, CheckOptions{Distros: CheckDistros([]string{"debian"}), Lint: true})
+	require.NoError(t, err)
+	assert.Empty(t, diags)
+}
+
+func TestCheckRepoConfig(t *testing.T) {
+	dir := writeRepoFiles(t, map[string]string{
+		"alr-repo.toml": `[repo]
+minVersion = "latest"
+url = "https://example.com/alr.git"
+mirrors = ["https://example.com/alr.git", "ftp://example.com/alr", "git@example.com:alr.git", "git@example.com:alr.git"]
+unknown = true
+`,
+	})
+
+	diags := checkRepoConfig(dir)
+	var messages []string
+	for _, d := range diags {
+		assert.Equal(t, lint.RuleRepoConfig, d.Rule)
+		messages = append(messages, d.Message)
+	}
+	assert.Equal(t, []string{
+		"unknown key repo.unknown",
+		`minVersion "latest" is not a valid version`,
+		`mirror "https://example.com/alr.git" is the same as the repository URL`,
+		`mirror "ftp://example.com/alr" is not a valid repository URL`,
+		`mirror "git@example.com:alr.git" is listed more than once`,
+	}, messages)
+	assert.Equal(t, uint(5), diags[0].Line)
+
+	diags = checkRepoConfig(t.TempDir())
+	require.Len(t, diags, 1)
+	assert.Equal(t, lint.SeverityWarning, diags[0].Severity)
+
+	diags = checkRepoConfig(writeRepoFiles(t, map[string]string{"alr-repo.toml": "[repo\n"}))
+	require.Len(t, diags, 1)
+	assert.Equal(t, uint(1), diags[0].Line)
+}
+
+func TestReadKnownPackages(t *testing.T) {
+	dir := writeRepoFiles(t, map[string]string{"known.txt": "# apt-cache pkgnames\nlibc6\n\n  bash  \n"})
+	names, err := ReadKnownPackages(filepath.Join(dir, "known.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"libc6", "bash"}, names)
+}
@@ -0,0 +1,218 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	database "git.alr-pkg.ru/Plemya-x/ALR/internal/db"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+var (
+	ErrRepoExists     = errors.New("repository already exists")
+	ErrRepoNotFound   = errors.New("repository does not exist")
+	ErrMirrorNotFound = errors.New("mirror does not exist")
+)
+
+// RepoExistsError сообщает, что репозиторий с таким именем или адресом уже добавлен.
+// Name - имя уже добавленного репозитория, которое может отличаться от запрошенного,
+// если совпал адрес.
+type RepoExistsError struct {
+	Name string
+}
+
+func (e *RepoExistsError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrRepoExists, e.Name)
+}
+
+func (e *RepoExistsError) Unwrap() error {
+	return ErrRepoExists
+}
+
+// WritableConfig - конфигурация, в которой можно изменить и сохранить список репозиториев
+type WritableConfig interface {
+	Config
+	SetRepos(repos []types.Repo)
+	SaveSystem() error
+}
+
+// RepoPuller клонирует новый репозиторий и дополняет его настройками из alr-repo.toml.
+// Реализуется как *Repos (через build.NewRepos), так и безопасным исполнителем плагина.
+type RepoPuller interface {
+	PullOneAndUpdateFromConfig(ctx context.Context, repo *types.Repo) (types.Repo, error)
+}
+
+// findRepo возвращает индекс репозитория с указанным именем или -1
+func findRepo(repos []types.Repo, name string) int {
+	for i, repo := range repos {
+		if repo.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// saveRepos записывает список репозиториев и сохраняет конфигурацию.
+// Если сохранить не удалось, в памяти восстанавливается прежний список.
+func saveRepos(cfg WritableConfig, newRepos []types.Repo) error {
+	oldRepos := cfg.Repos()
+	cfg.SetRepos(newRepos)
+	if err := cfg.SaveSystem(); err != nil {
+		cfg.SetRepos(oldRepos)
+		return fmt.Errorf("error saving config: %w", err)
+	}
+	return nil
+}
+
+// updateRepo применяет fn к репозиторию с указанным именем и сохраняет конфигурацию
+func updateRepo(cfg WritableConfig, name string, fn func(repo *types.Repo)) (types.Repo, error) {
+	reposSlice := cfg.Repos()
+	index := findRepo(reposSlice, name)
+	if index == -1 {
+		return types.Repo{}, fmt.Errorf("%w: %s", ErrRepoNotFound, name)
+	}
+
+	newRepos := slices.Clone(reposSlice)
+	fn(&newRepos[index])
+
+	if err := saveRepos(cfg, newRepos); err != nil {
+		return types.Repo{}, err
+	}
+
+	return newRepos[index], nil
+}
+
+// AddRepo клонирует новый репозиторий и добавляет его в системную конфигурацию.
+// Возвращает репозиторий с учётом настроек из alr-repo.toml.
+func AddRepo(ctx context.Context, cfg WritableConfig, puller RepoPuller, repo types.Repo) (types.Repo, error) {
+	reposSlice := cfg.Repos()
+	for _, r := range reposSlice {
+		if r.URL == repo.URL || r.Name == repo.Name {
+			return types.Repo{}, &RepoExistsError{Name: r.Name}
+		}
+	}
+
+	newRepo, err := puller.PullOneAndUpdateFromConfig(ctx, &repo)
+	if err != nil {
+		return types.Repo{}, err
+	}
+
+	if err := saveRepos(cfg, append(slices.Clone(reposSlice), newRepo)); err != nil {
+		return types.Repo{}, err
+	}
+
+	return newRepo, nil
+}
+
+// RemoveRepo удаляет репозиторий из конфигурации, его локальную копию и пакеты из базы данных
+func RemoveRepo(ctx context.Context, cfg WritableConfig, db *database.Database, name string) error {
+	reposSlice := cfg.Repos()
+	index := findRepo(reposSlice, name)
+	if index == -1 {
+		return fmt.Errorf("%w: %s", ErrRepoNotFound, name)
+	}
+
+	// Сначала сохраняется конфигурация: если это не удалось, репозиторий
+	// остаётся в ней вместе со своей локальной копией
+	if err := saveRepos(cfg, slices.Delete(slices.Clone(reposSlice), index, index+1)); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(cfg.GetPaths().RepoDir, name)); err != nil {
+		return fmt.Errorf("error removing repo directory: %w", err)
+	}
+	if err := db.DeletePkgs(ctx, "repository = ?", name); err != nil {
+		return fmt.Errorf("error removing packages from database: %w", err)
+	}
+
+	return nil
+}
+
+// SetRepoRef меняет ссылку (ветку, тег или коммит) репозитория.
+// Вызывающая сторона должна выполнить Pull для применения изменений.
+func SetRepoRef(cfg WritableConfig, name, ref string) (types.Repo, error) {
+	return updateRepo(cfg, name, func(repo *types.Repo) {
+		repo.Ref = ref
+	})
+}
+
+// SetRepoURL меняет основной адрес репозитория.
+// Вызывающая сторона должна выполнить Pull для применения изменений.
+func SetRepoURL(cfg WritableConfig, name, url string) (types.Repo, error) {
+	return updateRepo(cfg, name, func(repo *types.Repo) {
+		repo.URL = url
+	})
+}
+
+// SetRepoMirrors полностью заменяет список зеркал репозитория.
+// Повторяющиеся зеркала сохраняются один раз в порядке первого появления.
+func SetRepoMirrors(cfg WritableConfig, name string, mirrors []string) (types.Repo, error) {
+	return updateRepo(cfg, name, func(repo *types.Repo) {
+		repo.Mirrors = make([]string, 0, len(mirrors))
+		for _, mirror := range mirrors {
+			if !slices.Contains(repo.Mirrors, mirror) {
+				repo.Mirrors = append(repo.Mirrors, mirror)
+			}
+		}
+	})
+}
+
+// AddRepoMirror добавляет зеркало в конец списка зеркал репозитория.
+// Уже добавленное зеркало повторно не добавляется.
+func AddRepoMirror(cfg WritableConfig, name, url string) (types.Repo, error) {
+	return updateRepo(cfg, name, func(repo *types.Repo) {
+		if !slices.Contains(repo.Mirrors, url) {
+			repo.Mirrors = append(slices.Clone(repo.Mirrors), url)
+		}
+	})
+}
+
+// RemoveRepoMirrors удаляет зеркала, совпадающие с url (или содержащие его при partial).
+// Возвращает количество удалённых зеркал.
+func RemoveRepoMirrors(cfg WritableConfig, name, url string, partial bool) (int, error) {
+	reposSlice := cfg.Repos()
+	index := findRepo(reposSlice, name)
+	if index == -1 {
+		return 0, fmt.Errorf("%w: %s", ErrRepoNotFound, name)
+	}
+
+	mirrors := slices.DeleteFunc(slices.Clone(reposSlice[index].Mirrors), func(mirror string) bool {
+		if partial {
+			// Частичное совпадение - проверяем, содержит ли зеркало указанную строку
+			return strings.Contains(mirror, url)
+		}
+		return mirror == url
+	})
+
+	removed := len(reposSlice[index].Mirrors) - len(mirrors)
+	if removed == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrMirrorNotFound, url)
+	}
+
+	if _, err := SetRepoMirrors(cfg, name, mirrors); err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
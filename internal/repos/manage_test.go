@@ -0,0 +1,303 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package repos_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/config"
+	database "git.alr-pkg.ru/Plemya-x/ALR/internal/db"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/repos"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+type manageConfig struct {
+	repoDir string
+	repos   []types.Repo
+	saves   int
+	saveErr error
+}
+
+func (c *manageConfig) GetPaths() *config.Paths {
+	return &config.Paths{DBPath: ":memory:", RepoDir: c.repoDir}
+}
+
+func (c *manageConfig) Repos() []types.Repo         { return c.repos }
+func (c *manageConfig) SetRepos(repos []types.Repo) { c.repos = repos }
+
+func (c *manageConfig) SaveSystem() error {
+	if c.saveErr != nil {
+		return c.saveErr
+	}
+	c.saves++
+	return nil
+}
+
+type fakePuller struct {
+	pulled []string
+	err    error
+}
+
+func (p *fakePuller) PullOneAndUpdateFromConfig(_ context.Context, repo *types.Repo) (types.Repo, error) {
+	if p.err != nil {
+		return types.Repo{}, p.err
+	}
+	p.pulled = append(p.pulled, repo.Name)
+	return *repo, nil
+}
+
+func newManageConfig(t *testing.T) *manageConfig {
+	t.Helper()
+	return &manageConfig{
+		repoDir: t.TempDir(),
+		repos: []types.Repo{
+			{Name: "default", URL: "https://example.com/default.git", Mirrors: []string{"https://mirror.example.com/default.git"}},
+		},
+	}
+}
+
+func TestAddRepo(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		repo     types.Repo
+		existing string
+	}{
+		{"new repo", types.Repo{Name: "extra", URL: "https://example.com/extra.git"}, ""},
+		{"duplicate name", types.Repo{Name: "default", URL: "https://example.com/other.git"}, "default"},
+		{"duplicate url", types.Repo{Name: "other", URL: "https://example.com/default.git"}, "default"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newManageConfig(t)
+			puller := &fakePuller{}
+
+			repo, err := repos.AddRepo(context.Background(), cfg, puller, tc.repo)
+			if tc.existing == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.repo, repo)
+				assert.Equal(t, []string{tc.repo.Name}, puller.pulled)
+				assert.Len(t, cfg.repos, 2)
+				assert.Equal(t, 1, cfg.saves)
+				return
+			}
+
+			require.ErrorIs(t, err, repos.ErrRepoExists)
+			var existsErr *repos.RepoExistsError
+			require.ErrorAs(t, err, &existsErr)
+			assert.Equal(t, tc.existing, existsErr.Name)
+			assert.Empty(t, puller.pulled)
+			assert.Len(t, cfg.repos, 1)
+			assert.Zero(t, cfg.saves)
+		})
+	}
+}
+
+func TestAddRepoPullError(t *testing.T) {
+	cfg := newManageConfig(t)
+	pullErr := errors.New("clone failed")
+
+	_, err := repos.AddRepo(context.Background(), cfg, &fakePuller{err: pullErr}, types.Repo{Name: "extra", URL: "https://example.com/extra.git"})
+	assert.ErrorIs(t, err, pullErr)
+	assert.Len(t, cfg.repos, 1)
+	assert.Zero(t, cfg.saves)
+}
+
+func TestRemoveRepo(t *testing.T) {
+	ctx := context.Background()
+	cfg := newManageConfig(t)
+
+	db := database.New(cfg)
+	require.NoError(t, db.Init(ctx))
+	defer db.Close()
+	require.NoError(t, db.InsertPackage(ctx, alrsh.Package{Name: "foo", Repository: "default"}))
+
+	repoPath := filepath.Join(cfg.repoDir, "default")
+	require.NoError(t, os.MkdirAll(repoPath, 0o755))
+
+	err := repos.RemoveRepo(ctx, cfg, db, "missing")
+	assert.ErrorIs(t, err, repos.ErrRepoNotFound)
+	assert.Len(t, cfg.repos, 1)
+
+	require.NoError(t, repos.RemoveRepo(ctx, cfg, db, "default"))
+	assert.Empty(t, cfg.repos)
+	assert.NoDirExists(t, repoPath)
+
+	pkgs, err := db.GetPkgs(ctx, "repository = ?", "default")
+	require.NoError(t, err)
+	assert.Empty(t, pkgs)
+}
+
+func TestRemoveRepoSaveError(t *testing.T) {
+	ctx := context.Background()
+	cfg := newManageConfig(t)
+	cfg.saveErr = errors.New("read-only file system")
+	before := slices.Clone(cfg.repos)
+
+	db := database.New(cfg)
+	require.NoError(t, db.Init(ctx))
+	defer db.Close()
+	require.NoError(t, db.InsertPackage(ctx, alrsh.Package{Name: "foo", Repository: "default"}))
+
+	repoPath := filepath.Join(cfg.repoDir, "default")
+	require.NoError(t, os.MkdirAll(repoPath, 0o755))
+
+	// Конфигурация в памяти не должна расходиться с сохранённой,
+	// а репозиторий, оставшийся в конфигурации, - терять локальную копию
+	assert.ErrorIs(t, repos.RemoveRepo(ctx, cfg, db, "default"), cfg.saveErr)
+	assert.Equal(t, before, cfg.repos)
+	assert.DirExists(t, repoPath)
+	pkgs, err := db.GetPkgs(ctx, "repository = ?", "default")
+	require.NoError(t, err)
+	assert.Len(t, pkgs, 1)
+
+	_, err = repos.SetRepoRef(cfg, "default", "v1")
+	assert.ErrorIs(t, err, cfg.saveErr)
+	assert.Equal(t, before, cfg.repos)
+}
+
+func TestSetRepoNotFound(t *testing.T) {
+	for name, fn := range map[string]func(cfg repos.WritableConfig) error{
+		"ref": func(cfg repos.WritableConfig) error {
+			_, err := repos.SetRepoRef(cfg, "missing", "main")
+			return err
+		},
+		"url": func(cfg repos.WritableConfig) error {
+			_, err := repos.SetRepoURL(cfg, "missing", "https://example.com/x.git")
+			return err
+		},
+		"mirrors": func(cfg repos.WritableConfig) error {
+			_, err := repos.SetRepoMirrors(cfg, "missing", nil)
+			return err
+		},
+		"add mirror": func(cfg repos.WritableConfig) error {
+			_, err := repos.AddRepoMirror(cfg, "missing", "https://example.com/x.git")
+			return err
+		},
+		"remove mirrors": func(cfg repos.WritableConfig) error {
+			_, err := repos.RemoveRepoMirrors(cfg, "missing", "x", true)
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := newManageConfig(t)
+			assert.ErrorIs(t, fn(cfg), repos.ErrRepoNotFound)
+			assert.Zero(t, cfg.saves)
+		})
+	}
+}
+
+func TestSetRepoRefAndURL(t *testing.T) {
+	cfg := newManageConfig(t)
+
+	repo, err := repos.SetRepoRef(cfg, "default", "v1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0", repo.Ref)
+
+	repo, err = repos.SetRepoURL(cfg, "default", "https://example.com/moved.git")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/moved.git", repo.URL)
+	assert.Equal(t, "v1.0", cfg.repos[0].Ref)
+	assert.Equal(t, 2, cfg.saves)
+}
+
+func TestRepoMirrors(t *testing.T) {
+	const existing = "https://mirror.example.com/default.git"
+
+	for _, tc := range []struct {
+		name     string
+		update   func(cfg repos.WritableConfig) error
+		expected []string
+	}{
+		{
+			name: "add new mirror",
+			update: func(cfg repos.WritableConfig) error {
+				_, err := repos.AddRepoMirror(cfg, "default", "https://other.example.com/default.git")
+				return err
+			},
+			expected: []string{existing, "https://other.example.com/default.git"},
+		},
+		{
+			name: "add existing mirror",
+			update: func(cfg repos.WritableConfig) error {
+				_, err := repos.AddRepoMirror(cfg, "default", existing)
+				return err
+			},
+			expected: []string{existing},
+		},
+		{
+			name: "set mirrors with duplicates",
+			update: func(cfg repos.WritableConfig) error {
+				_, err := repos.SetRepoMirrors(cfg, "default", []string{"https://a.example.com", existing, "https://a.example.com"})
+				return err
+			},
+			expected: []string{"https://a.example.com", existing},
+		},
+		{
+			name: "clear mirrors",
+			update: func(cfg repos.WritableConfig) error {
+				_, err := repos.SetRepoMirrors(cfg, "default", nil)
+				return err
+			},
+			expected: []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newManageConfig(t)
+			require.NoError(t, tc.update(cfg))
+			assert.Equal(t, tc.expected, cfg.repos[0].Mirrors)
+		})
+	}
+}
+
+func TestRemoveRepoMirrors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		url      string
+		partial  bool
+		removed  int
+		expected []string
+		err      error
+	}{
+		{"exact", "https://a.example.com/repo.git", false, 1, []string{"https://b.example.com/repo.git"}, nil},
+		{"partial", "example.com", true, 2, []string{}, nil},
+		{"exact no match", "example.com", false, 0, nil, repos.ErrMirrorNotFound},
+		{"partial no match", "nowhere", true, 0, nil, repos.ErrMirrorNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newManageConfig(t)
+			cfg.repos[0].Mirrors = []string{"https://a.example.com/repo.git", "https://b.example.com/repo.git"}
+
+			removed, err := repos.RemoveRepoMirrors(cfg, "default", tc.url, tc.partial)
+			assert.Equal(t, tc.removed, removed)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Len(t, cfg.repos[0].Mirrors, 2)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cfg.repos[0].Mirrors)
+		})
+	}
+}
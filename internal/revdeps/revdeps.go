@@ -0,0 +1,265 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package revdeps строит граф зависимостей между установленными пакетами
+// ALR, чтобы не удалять пакеты, нужные другим пакетам, и находить пакеты,
+// установленные как зависимости и больше никому не нужные.
+package revdeps
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/build"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/db"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/manager"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/overrides"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/depver"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+)
+
+// Node - установленный пакет ALR
+type Node struct {
+	// FullName - имя пакета в пакетном менеджере (name+repo)
+	FullName   string
+	Name       string
+	Repository string
+	// Depends и Provides разрешены для текущего дистрибутива
+	Depends  []string
+	Provides []string
+	// UnknownDepends - пакета нет в базе репозиториев, поэтому его
+	// зависимости неизвестны
+	UnknownDepends bool
+	// Reason - причина установки, пустая, если она не записана
+	Reason string
+}
+
+// debugSuffix - суффикс имени пакета отладочной информации
+const debugSuffix = "-debug"
+
+// Graph - граф зависимостей установленных пакетов ALR
+type Graph struct {
+	nodes    []*Node
+	byName   map[string]*Node
+	provided map[string][]*Node
+}
+
+type Database interface {
+	GetPkg(where string, args ...any) (*alrsh.Package, error)
+	GetInstallReasons(ctx context.Context) ([]db.InstalledPackage, error)
+}
+
+// Load строит граф по пакетам ALR, установленным в системе. Зависимости
+// берутся из базы репозиториев, а для пакетов, которых там уже нет,
+// считаются неизвестными.
+func Load(ctx context.Context, mgr manager.Manager, database Database, info *distro.OSRelease) (*Graph, error) {
+	installed, err := mgr.ListInstalled(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons, err := database.GetInstallReasons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reasonOf := map[string]string{}
+	for _, r := range reasons {
+		reasonOf[r.Repository+"/"+r.Name] = r.Reason
+	}
+
+	names, err := overrides.Resolve(info, overrides.DefaultOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []*Node
+	for installedName := range installed {
+		name, repo, ok := ParseName(installedName)
+		if !ok {
+			continue
+		}
+		n := &Node{
+			FullName:   name + "+" + repo,
+			Name:       name,
+			Repository: repo,
+		}
+		n.Reason = reasonOf[n.Repository+"/"+n.Name]
+
+		pkg, err := database.GetPkg("name = ? AND repository = ?", n.Name, n.Repository)
+		if err != nil {
+			return nil, err
+		}
+		if pkg != nil {
+			alrsh.ResolvePackage(pkg, names)
+			n.Depends = pkg.Depends.Resolved()
+			n.Provides = pkg.Provides
+		} else if base, ok := strings.CutSuffix(n.Name, debugSuffix); ok {
+			// Пакет отладочной информации собирается вместе с основным
+			// и зависит только от него
+			n.Depends = []string{base}
+		} else {
+			n.UnknownDepends = true
+		}
+		nodes = append(nodes, n)
+	}
+
+	return New(nodes), nil
+}
+
+// ParseName разбирает имя установленного пакета ALR ("name+repo",
+// для xbps - с версией) на имя пакета и репозиторий
+func ParseName(installedName string) (name, repo string, ok bool) {
+	matches := build.RegexpALRPackageName.FindStringSubmatch(installedName)
+	if matches == nil {
+		return "", "", false
+	}
+	name = matches[build.RegexpALRPackageName.SubexpIndex("package")]
+	repo = matches[build.RegexpALRPackageName.SubexpIndex("repo")]
+	return name, repo, true
+}
+
+// New строит граф по списку установленных пакетов
+func New(nodes []*Node) *Graph {
+	slices.SortFunc(nodes, func(a, b *Node) int {
+		return strings.Compare(a.FullName, b.FullName)
+	})
+
+	g := &Graph{
+		nodes:    nodes,
+		byName:   map[string]*Node{},
+		provided: map[string][]*Node{},
+	}
+	for _, n := range nodes {
+		g.byName[n.FullName] = n
+		for _, p := range append([]string{n.Name}, n.Provides...) {
+			// Предоставляемые имена могут содержать версию ("libfoo=1.2")
+			name := depver.Parse(p).Name
+			if !slices.Contains(g.provided[name], n) {
+				g.provided[name] = append(g.provided[name], n)
+			}
+		}
+	}
+	return g
+}
+
+// Node возвращает установленный пакет по полному имени или nil
+func (g *Graph) Node(fullName string) *Node {
+	return g.byName[fullName]
+}
+
+// dependencies возвращает установленные пакеты ALR, от которых зависит n
+func (g *Graph) dependencies(n *Node) []*Node {
+	var out []*Node
+	for _, dep := range n.Depends {
+		for _, p := range g.provided[depver.Parse(dep).Name] {
+			if p != n && !slices.Contains(out, p) {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+// Dependents возвращает полные имена установленных пакетов, которые
+// напрямую зависят от пакета fullName
+func (g *Graph) Dependents(fullName string) []string {
+	target := g.byName[fullName]
+	if target == nil {
+		return nil
+	}
+
+	var out []string
+	for _, n := range g.nodes {
+		if slices.Contains(g.dependencies(n), target) {
+			out = append(out, n.FullName)
+		}
+	}
+	return out
+}
+
+// Blockers возвращает для каждого из удаляемых пакетов fullNames пакеты,
+// которые от него зависят и сами не удаляются
+func (g *Graph) Blockers(fullNames []string) map[string][]string {
+	out := map[string][]string{}
+	for _, name := range fullNames {
+		for _, dependent := range g.Dependents(name) {
+			if !slices.Contains(fullNames, dependent) {
+				out[name] = append(out[name], dependent)
+			}
+		}
+	}
+	return out
+}
+
+// Cascade дополняет fullNames всеми пакетами, которые прямо или
+// косвенно от них зависят
+func (g *Graph) Cascade(fullNames []string) []string {
+	out := slices.Clone(fullNames)
+	for i := 0; i < len(out); i++ {
+		for _, dependent := range g.Dependents(out[i]) {
+			if !slices.Contains(out, dependent) {
+				out = append(out, dependent)
+			}
+		}
+	}
+	return out
+}
+
+// UnknownDepends возвращает полные имена пакетов, зависимости которых неизвестны
+func (g *Graph) UnknownDepends() []string {
+	var out []string
+	for _, n := range g.nodes {
+		if n.UnknownDepends {
+			out = append(out, n.FullName)
+		}
+	}
+	return out
+}
+
+// Orphans возвращает пакеты, установленные только как зависимости,
+// которые не нужны ни одному пакету, установленному явно. Пакеты
+// без записанной причины установки считаются установленными явно.
+// Пакеты с неизвестными зависимостями не удерживают другие пакеты.
+func (g *Graph) Orphans() []string {
+	needed := map[*Node]bool{}
+	var queue []*Node
+	for _, n := range g.nodes {
+		if n.Reason != db.InstallReasonDependency {
+			needed[n] = true
+			queue = append(queue, n)
+		}
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, dep := range g.dependencies(n) {
+			if !needed[dep] {
+				needed[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	var out []string
+	for _, n := range g.nodes {
+		if !needed[n] {
+			out = append(out, n.FullName)
+		}
+	}
+	return out
+}
@@ -0,0 +1,162 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package revdeps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/db"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/manager"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+)
+
+// testGraph: app -> lib -> base, tool -> libfoo (предоставляется lib-alt),
+// старые зависимости stale и stale-dep больше никому не нужны
+func testGraph() *Graph {
+	return New([]*Node{
+		{FullName: "app+default", Name: "app", Repository: "default", Depends: []string{"lib>=1.0", "bash"}, Reason: db.InstallReasonExplicit},
+		{FullName: "lib+default", Name: "lib", Repository: "default", Depends: []string{"base"}, Reason: db.InstallReasonDependency},
+		{FullName: "base+default", Name: "base", Repository: "default", Reason: db.InstallReasonDependency},
+		{FullName: "tool+extra", Name: "tool", Repository: "extra", Depends: []string{"libfoo"}},
+		{FullName: "lib-alt+extra", Name: "lib-alt", Repository: "extra", Provides: []string{"libfoo=1.2"}, Reason: db.InstallReasonDependency},
+		{FullName: "stale+default", Name: "stale", Repository: "default", Depends: []string{"stale-dep"}, Reason: db.InstallReasonDependency},
+		{FullName: "stale-dep+default", Name: "stale-dep", Repository: "default", Depends: []string{"stale"}, Reason: db.InstallReasonDependency},
+	})
+}
+
+func TestDependents(t *testing.T) {
+	g := testGraph()
+	assert.Equal(t, []string{"app+default"}, g.Dependents("lib+default"))
+	assert.Equal(t, []string{"tool+extra"}, g.Dependents("lib-alt+extra"))
+	assert.Empty(t, g.Dependents("app+default"))
+	assert.Empty(t, g.Dependents("missing+default"))
+}
+
+func TestBlockersAndCascade(t *testing.T) {
+	g := testGraph()
+
+	assert.Equal(t, map[string][]string{
+		"base+default": {"lib+default"},
+	}, g.Blockers([]string{"base+default"}))
+	assert.Empty(t, g.Blockers([]string{"base+default", "lib+default", "app+default"}))
+
+	assert.Equal(t, []string{"base+default", "lib+default", "app+default"}, g.Cascade([]string{"base+default"}))
+}
+
+func TestOrphans(t *testing.T) {
+	g := testGraph()
+	assert.Equal(t, []string{"stale+default", "stale-dep+default"}, g.Orphans())
+
+	// После удаления app его зависимости становятся ненужными
+	var nodes []*Node
+	for _, n := range testGraph().nodes {
+		if n.Name != "app" {
+			nodes = append(nodes, n)
+		}
+	}
+	assert.Equal(t, []string{"base+default", "lib+default", "stale+default", "stale-dep+default"}, New(nodes).Orphans())
+}
+
+func TestOrphansUnknownDepends(t *testing.T) {
+	// Пакет, которого уже нет в базе репозиториев, не мешает
+	// находить ненужные пакеты, но и не удерживает их
+	nodes := append(testGraph().nodes, &Node{
+		FullName:       "gone+default",
+		Name:           "gone",
+		Repository:     "default",
+		UnknownDepends: true,
+		Reason:         db.InstallReasonExplicit,
+	}, &Node{
+		FullName:       "gone-dep+default",
+		Name:           "gone-dep",
+		Repository:     "default",
+		UnknownDepends: true,
+		Reason:         db.InstallReasonDependency,
+	})
+	g := New(nodes)
+
+	assert.Equal(t, []string{"gone+default", "gone-dep+default"}, g.UnknownDepends())
+	assert.Equal(t, []string{"gone-dep+default", "stale+default", "stale-dep+default"}, g.Orphans())
+	assert.Empty(t, testGraph().UnknownDepends())
+}
+
+type installedManager struct {
+	manager.Manager
+	installed map[string]string
+}
+
+func (m installedManager) ListInstalled(*manager.Opts) (map[string]string, error) {
+	return m.installed, nil
+}
+
+type testDB struct {
+	pkgs map[string]*alrsh.Package
+}
+
+func (d testDB) GetPkg(_ string, args ...any) (*alrsh.Package, error) {
+	return d.pkgs[args[0].(string)+"+"+args[1].(string)], nil
+}
+
+func (d testDB) GetInstallReasons(context.Context) ([]db.InstalledPackage, error) {
+	return []db.InstalledPackage{{Name: "lib", Repository: "default", Reason: db.InstallReasonDependency}}, nil
+}
+
+func TestLoad(t *testing.T) {
+	mgr := installedManager{installed: map[string]string{
+		"app+default":       "1.0-1",
+		"app-debug+default": "1.0-1",
+		"lib+default":       "1.0-1",
+		"gone+old":          "1.0-1",
+		"bash":              "5.2",
+	}}
+	database := testDB{pkgs: map[string]*alrsh.Package{
+		"app+default": {Name: "app", Repository: "default", Depends: alrsh.OverridableFromMap(map[string][]string{"": {"lib"}})},
+		"lib+default": {Name: "lib", Repository: "default"},
+	}}
+
+	g, err := Load(context.Background(), mgr, database, &distro.OSRelease{ID: "debian"})
+	require.NoError(t, err)
+
+	// Пакет отладочной информации удерживает основной пакет, а не
+	// считается пакетом с неизвестными зависимостями
+	assert.Equal(t, []string{"app"}, g.Node("app-debug+default").Depends)
+	assert.Equal(t, []string{"gone+old"}, g.UnknownDepends())
+	assert.Equal(t, []string{"app+default"}, g.Dependents("lib+default"))
+	assert.Equal(t, db.InstallReasonDependency, g.Node("lib+default").Reason)
+	assert.Empty(t, g.Orphans())
+}
+
+func TestParseName(t *testing.T) {
+	name, repo, ok := ParseName("foo+alr-default")
+	assert.True(t, ok)
+	assert.Equal(t, "foo", name)
+	assert.Equal(t, "alr-default", repo)
+
+	// xbps сообщает имя вместе с версией
+	name, repo, ok = ParseName("foo+alr-default-1.2.3_1")
+	assert.True(t, ok)
+	assert.Equal(t, "foo", name)
+	assert.Equal(t, "alr-default", repo)
+
+	_, _, ok = ParseName("bash")
+	assert.False(t, ok)
+}
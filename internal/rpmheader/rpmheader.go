@@ -0,0 +1,269 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package rpmheader разбирает заголовки пакетов rpm: как в файлах
+// пакетов, так и в формате хранения rpmdb.
+package rpmheader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Теги заголовка rpm
+const (
+	TagName           = 1000
+	TagVersion        = 1001
+	TagRelease        = 1002
+	TagEpoch          = 1003
+	TagSummary        = 1004
+	TagLicense        = 1014
+	TagPackager       = 1015
+	TagURL            = 1020
+	TagArch           = 1022
+	TagPreIn          = 1023
+	TagPostIn         = 1024
+	TagPreUn          = 1025
+	TagPostUn         = 1026
+	TagFileSizes      = 1028
+	TagFileModes      = 1030
+	TagFileLinkTos    = 1036
+	TagFileUserName   = 1039
+	TagFileGroupName  = 1040
+	TagProvideName    = 1047
+	TagRequireFlags   = 1048
+	TagRequireName    = 1049
+	TagRequireVersion = 1050
+	TagConflictFlags  = 1053
+	TagConflictName   = 1054
+	TagConflictVer    = 1055
+	TagObsoleteName   = 1090
+	TagProvideFlags   = 1112
+	TagProvideVersion = 1113
+	TagObsoleteFlags  = 1114
+	TagObsoleteVer    = 1115
+	TagDirIndexes     = 1116
+	TagBaseNames      = 1117
+	TagDirNames       = 1118
+	TagPreTrans       = 1151
+	TagPostTrans      = 1152
+	TagLongFileSizes  = 5008
+	TagRecommendName  = 5046
+	TagRecommendVer   = 5047
+	TagRecommendFlags = 5048
+	TagSuggestName    = 5049
+	TagSuggestVer     = 5050
+	TagSuggestFlags   = 5051
+)
+
+// Типы значений заголовка rpm
+const (
+	TypeInt16       = 3
+	TypeInt32       = 4
+	TypeInt64       = 5
+	TypeString      = 6
+	TypeStringArray = 8
+	TypeI18NString  = 9
+)
+
+// LeadSize - размер устаревшего заголовка lead в начале файла пакета
+const LeadSize = 96
+
+// Ограничения размера заголовка
+const (
+	maxEntries  = 1 << 16
+	maxDataSize = 1 << 28
+)
+
+var magic = []byte{0x8e, 0xad, 0xe8, 0x01}
+
+// Entry - запись индекса заголовка
+type Entry struct {
+	Tag    int32
+	Type   uint32
+	Offset int32
+	Count  uint32
+}
+
+// Header - разобранный заголовок rpm
+type Header struct {
+	entries map[int32]Entry
+	data    []byte
+}
+
+// ReadPackage читает основной заголовок файла пакета rpm,
+// пропуская lead и заголовок подписи
+func ReadPackage(r io.Reader) (*Header, error) {
+	if _, err := io.CopyN(io.Discard, r, LeadSize); err != nil {
+		return nil, err
+	}
+
+	// Заголовок подписи выровнен по 8 байтам
+	_, size, err := Read(r)
+	if err != nil {
+		return nil, err
+	}
+	if pad := (8 - size%8) % 8; pad != 0 {
+		if _, err := io.CopyN(io.Discard, r, int64(pad)); err != nil {
+			return nil, err
+		}
+	}
+
+	hdr, _, err := Read(r)
+	return hdr, err
+}
+
+// Read читает заголовок с магической последовательностью
+// и возвращает его вместе с размером в байтах
+func Read(r io.Reader) (*Header, int, error) {
+	intro := make([]byte, 16)
+	if _, err := io.ReadFull(r, intro); err != nil {
+		return nil, 0, err
+	}
+	if !bytes.Equal(intro[:4], magic) {
+		return nil, 0, errors.New("bad header magic")
+	}
+
+	il := binary.BigEndian.Uint32(intro[8:12])
+	dl := binary.BigEndian.Uint32(intro[12:16])
+	if err := checkSize(il, dl); err != nil {
+		return nil, 0, err
+	}
+
+	body := make([]byte, int(il)*16+int(dl))
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, 0, err
+	}
+	hdr, err := parse(il, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hdr, len(intro) + len(body), nil
+}
+
+// ParseBlob разбирает заголовок в формате хранения rpmdb
+// (без магической последовательности)
+func ParseBlob(blob []byte) (*Header, error) {
+	if len(blob) < 8 {
+		return nil, errors.New("header blob is too short")
+	}
+
+	il := binary.BigEndian.Uint32(blob[0:4])
+	dl := binary.BigEndian.Uint32(blob[4:8])
+	if err := checkSize(il, dl); err != nil {
+		return nil, err
+	}
+	if 8+int(il)*16+int(dl) > len(blob) {
+		return nil, errors.New("header blob is truncated")
+	}
+	return parse(il, blob[8:8+int(il)*16+int(dl)])
+}
+
+func checkSize(il, dl uint32) error {
+	if il > maxEntries || dl > maxDataSize {
+		return errors.New("header is too large")
+	}
+	return nil
+}
+
+// parse разбирает индекс из il записей и следующие за ним данные
+func parse(il uint32, body []byte) (*Header, error) {
+	index := make([]Entry, il)
+	if err := binary.Read(bytes.NewReader(body), binary.BigEndian, index); err != nil {
+		return nil, err
+	}
+
+	hdr := &Header{
+		entries: make(map[int32]Entry, il),
+		data:    body[il*16:],
+	}
+	for _, e := range index {
+		hdr.entries[e.Tag] = e
+	}
+	return hdr, nil
+}
+
+// Strings возвращает строковое значение тега в виде массива
+func (h *Header) Strings(tag int32) []string {
+	e, ok := h.entries[tag]
+	if !ok || e.Offset < 0 || int(e.Offset) >= len(h.data) {
+		return nil
+	}
+	switch e.Type {
+	case TypeString, TypeStringArray, TypeI18NString:
+	default:
+		return nil
+	}
+
+	var out []string
+	data := h.data[e.Offset:]
+	for n := uint32(0); n < e.Count; n++ {
+		end := bytes.IndexByte(data, 0)
+		if end < 0 {
+			break
+		}
+		out = append(out, string(data[:end]))
+		data = data[end+1:]
+	}
+	return out
+}
+
+// String возвращает первое строковое значение тега
+func (h *Header) String(tag int32) string {
+	if s := h.Strings(tag); len(s) > 0 {
+		return s[0]
+	}
+	return ""
+}
+
+// Ints возвращает целочисленный массив тега любого целого типа
+func (h *Header) Ints(tag int32) []int64 {
+	e, ok := h.entries[tag]
+	if !ok || e.Offset < 0 {
+		return nil
+	}
+
+	var width int
+	switch e.Type {
+	case TypeInt16:
+		width = 2
+	case TypeInt32:
+		width = 4
+	case TypeInt64:
+		width = 8
+	default:
+		return nil
+	}
+	if int(e.Offset)+int(e.Count)*width > len(h.data) {
+		return nil
+	}
+
+	out := make([]int64, e.Count)
+	data := h.data[e.Offset:]
+	for i := range out {
+		switch width {
+		case 2:
+			out[i] = int64(binary.BigEndian.Uint16(data[i*2:]))
+		case 4:
+			out[i] = int64(int32(binary.BigEndian.Uint32(data[i*4:])))
+		case 8:
+			out[i] = int64(binary.BigEndian.Uint64(data[i*8:]))
+		}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpmheader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBlob собирает заголовок в формате хранения rpmdb
+func testBlob(t *testing.T) []byte {
+	t.Helper()
+
+	var index, data bytes.Buffer
+	add := func(tag int32, typ uint32, n uint32, payload []byte) {
+		e := Entry{Tag: tag, Type: typ, Offset: int32(data.Len()), Count: n}
+		require.NoError(t, binary.Write(&index, binary.BigEndian, e))
+		data.Write(payload)
+	}
+	add(TagName, TypeString, 1, []byte("foo\x00"))
+	add(TagSummary, TypeI18NString, 1, []byte("Описание\x00"))
+	add(TagProvideName, TypeStringArray, 2, []byte("foo\x00libfoo.so\x00"))
+	add(TagEpoch, TypeInt32, 1, binary.BigEndian.AppendUint32(nil, 2))
+
+	var blob bytes.Buffer
+	require.NoError(t, binary.Write(&blob, binary.BigEndian, uint32(index.Len()/16)))
+	require.NoError(t, binary.Write(&blob, binary.BigEndian, uint32(data.Len())))
+	blob.Write(index.Bytes())
+	blob.Write(data.Bytes())
+	return blob.Bytes()
+}
+
+func TestParseBlob(t *testing.T) {
+	hdr, err := ParseBlob(testBlob(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", hdr.String(TagName))
+	assert.Equal(t, "Описание", hdr.String(TagSummary))
+	assert.Equal(t, []string{"foo", "libfoo.so"}, hdr.Strings(TagProvideName))
+	assert.Equal(t, []int64{2}, hdr.Ints(TagEpoch))
+	assert.Nil(t, hdr.Ints(TagName))
+	assert.Empty(t, hdr.String(TagVersion))
+}
+
+func TestRead(t *testing.T) {
+	blob := testBlob(t)
+	data := append([]byte{0x8e, 0xad, 0xe8, 0x01, 0, 0, 0, 0}, blob...)
+
+	hdr, size, err := Read(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, len(data), size)
+	assert.Equal(t, "foo", hdr.String(TagName))
+
+	data[0] = 0
+	_, _, err = Read(bytes.NewReader(data))
+	assert.Error(t, err)
+}
+
+func TestParseBlobInvalid(t *testing.T) {
+	for name, blob := range map[string][]byte{
+		"короткий":        {0, 0, 0},
+		"обрезанный":      {0, 0, 0, 5, 0, 0, 0, 10},
+		"слишком большой": {0, 1, 0, 1, 0, 0, 0, 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseBlob(blob)
+			assert.Error(t, err)
+		})
+	}
+}
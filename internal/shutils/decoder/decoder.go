@@ -64,11 +64,13 @@ type Decoder struct {
 	Overrides bool
 	// Enable using like distros for overrides
 	LikeDistros bool
+	// Architecture to resolve overrides for (the system architecture if empty)
+	Arch string
 }
 
 // New creates a new variable decoder
 func New(info *distro.OSRelease, runner *interp.Runner) *Decoder {
-	return &Decoder{info, runner, true, len(info.Like) > 0}
+	return &Decoder{info, runner, true, len(info.Like) > 0, ""}
 }
 
 func (d *Decoder) Info() *distro.OSRelease {
@@ -164,7 +166,7 @@ func (d *Decoder) DecodeVar(name string, val any) error {
 			return fmt.Errorf("method Resolve not found on OverridableField")
 		}
 
-		names, err := overrides.Resolve(d.info, overrides.DefaultOpts)
+		names, err := overrides.Resolve(d.info, overrides.DefaultOpts.WithArch(d.Arch))
 		if err != nil {
 			return err
 		}
@@ -297,7 +299,7 @@ func (d *Decoder) GetFuncWithSubshell(name string) (ScriptFuncWithSubshell, bool
 }
 
 func (d *Decoder) getFunc(name string) *syntax.Stmt {
-	names, err := overrides.Resolve(d.info, overrides.DefaultOpts.WithName(name))
+	names, err := overrides.Resolve(d.info, overrides.DefaultOpts.WithName(name).WithArch(d.Arch))
 	if err != nil {
 		return nil
 	}
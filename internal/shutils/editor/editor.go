@@ -0,0 +1,297 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package editor изменяет значения переменных верхнего уровня в скриптах
+// alr.sh, не затрагивая остальной текст и оформление.
+package editor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// ErrNotFound возвращается, если переменная не присваивается на верхнем уровне скрипта
+var ErrNotFound = errors.New("variable is not assigned at the top level")
+
+// Editor хранит текст скрипта и его синтаксическое дерево
+type Editor struct {
+	name string
+	src  []byte
+	file *syntax.File
+}
+
+// New разбирает скрипт
+func New(src []byte, name string) (*Editor, error) {
+	e := &Editor{name: name}
+	if err := e.reset(src); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *Editor) reset(src []byte) error {
+	file, err := syntax.NewParser(syntax.KeepComments(true), syntax.Variant(syntax.LangBash)).
+		Parse(bytes.NewReader(src), e.name)
+	if err != nil {
+		return err
+	}
+	e.src, e.file = src, file
+	return nil
+}
+
+// Bytes возвращает текущий текст скрипта
+func (e *Editor) Bytes() []byte {
+	return e.src
+}
+
+// find возвращает последнее (действующее) присваивание переменной верхнего уровня
+// и инструкцию, в которой оно находится
+func (e *Editor) find(name string) (*syntax.Assign, *syntax.Stmt) {
+	var found *syntax.Assign
+	var stmt *syntax.Stmt
+	for _, s := range e.file.Stmts {
+		if as := assignIn(s, name); as != nil {
+			found, stmt = as, s
+		}
+	}
+	return found, stmt
+}
+
+// findNested ищет присваивание переменной на верхнем уровне, а если его нет -
+// во вложенных блоках вне функций, например в проверке DISTRO_ID
+func (e *Editor) findNested(name string) (*syntax.Assign, *syntax.Stmt) {
+	found, stmt := e.find(name)
+	if found != nil {
+		return found, stmt
+	}
+	syntax.Walk(e.file, func(node syntax.Node) bool {
+		switch n := node.(type) {
+		case *syntax.FuncDecl:
+			return false
+		case *syntax.Stmt:
+			if as := assignIn(n, name); as != nil {
+				found, stmt = as, n
+			}
+		}
+		return true
+	})
+	return found, stmt
+}
+
+// assignIn возвращает последнее присваивание переменной в инструкции s,
+// если s состоит только из присваиваний
+func assignIn(s *syntax.Stmt, name string) *syntax.Assign {
+	call, ok := s.Cmd.(*syntax.CallExpr)
+	if !ok || len(call.Args) > 0 {
+		return nil
+	}
+	var found *syntax.Assign
+	for _, as := range call.Assigns {
+		if as.Name.Value == name && !as.Append {
+			found = as
+		}
+	}
+	return found
+}
+
+// Has сообщает, присваивается ли переменная на верхнем уровне
+func (e *Editor) Has(name string) bool {
+	as, _ := e.find(name)
+	return as != nil
+}
+
+// Literal возвращает значение переменной, если оно не содержит подстановок
+func (e *Editor) Literal(name string) (string, bool) {
+	as, _ := e.find(name)
+	if as == nil || as.Array != nil {
+		return "", false
+	}
+	if as.Value == nil {
+		return "", true
+	}
+
+	var sb strings.Builder
+	for _, part := range as.Value.Parts {
+		switch p := part.(type) {
+		case *syntax.Lit:
+			sb.WriteString(p.Value)
+		case *syntax.SglQuoted:
+			if p.Dollar {
+				return "", false
+			}
+			sb.WriteString(p.Value)
+		case *syntax.DblQuoted:
+			for _, dp := range p.Parts {
+				lit, ok := dp.(*syntax.Lit)
+				if !ok {
+					return "", false
+				}
+				sb.WriteString(lit.Value)
+			}
+		default:
+			return "", false
+		}
+	}
+	return sb.String(), true
+}
+
+// replace заменяет байты [start, end) и заново разбирает скрипт
+func (e *Editor) replace(start, end uint, text string) error {
+	var buf bytes.Buffer
+	buf.Write(e.src[:start])
+	buf.WriteString(text)
+	buf.Write(e.src[end:])
+	return e.reset(buf.Bytes())
+}
+
+// SetString присваивает переменной строковое значение в одинарных кавычках
+func (e *Editor) SetString(name, value string) error {
+	as, _ := e.find(name)
+	if as == nil {
+		return fmt.Errorf("%s: %w", name, ErrNotFound)
+	}
+
+	quoted, err := quote(value, '\'')
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case as.Array != nil:
+		return e.replace(as.Array.Lparen.Offset(), as.Array.Rparen.Offset()+1, quoted)
+	case as.Value != nil:
+		return e.replace(as.Value.Pos().Offset(), as.Value.End().Offset(), quoted)
+	default:
+		// Пустое присваивание вида "name="
+		end := as.Name.End().Offset() + 1
+		return e.replace(end, end, quoted)
+	}
+}
+
+// SetArray заменяет элементы массива, сохраняя его оформление: расположение
+// элементов по строкам, отступ и вид кавычек первого элемента. Если переменная
+// не присваивается, а after не пуст, массив добавляется на новой строке после
+// присваивания after с тем же отступом. Массивы ищутся и во вложенных блоках
+// (см. findNested), новый массив попадает в тот же блок, что и after.
+func (e *Editor) SetArray(name string, values []string, after string) error {
+	as, _ := e.findNested(name)
+	if as == nil {
+		if after == "" {
+			return fmt.Errorf("%s: %w", name, ErrNotFound)
+		}
+		_, anchor := e.findNested(after)
+		if anchor == nil {
+			return fmt.Errorf("%s: %w", after, ErrNotFound)
+		}
+		text, err := formatArray(values, '\'', "", "")
+		if err != nil {
+			return err
+		}
+		// Вставляем после конца строки, чтобы не отрывать комментарий от присваивания
+		end := uint(len(e.src))
+		if i := bytes.IndexByte(e.src[anchor.End().Offset():], '\n'); i >= 0 {
+			end = anchor.End().Offset() + uint(i)
+		}
+		return e.replace(end, end, "\n"+e.lineIndent(anchor.Pos())+name+"="+text)
+	}
+
+	if as.Array == nil {
+		// Скалярное значение заменяется массивом
+		text, err := formatArray(values, '\'', "", "")
+		if err != nil {
+			return err
+		}
+		start, end := as.Name.End().Offset()+1, as.Name.End().Offset()+1
+		if as.Value != nil {
+			start, end = as.Value.Pos().Offset(), as.Value.End().Offset()
+		}
+		return e.replace(start, end, text)
+	}
+
+	quoteChar := byte('\'')
+	indent, closeIndent := "", ""
+	if len(as.Array.Elems) > 0 {
+		first := as.Array.Elems[0]
+		if first.Value != nil && len(first.Value.Parts) > 0 {
+			switch first.Value.Parts[0].(type) {
+			case *syntax.DblQuoted:
+				quoteChar = '"'
+			case *syntax.Lit:
+				quoteChar = 0
+			}
+		}
+		// Массив записан в несколько строк
+		if first.Pos().Line() > as.Array.Lparen.Line() {
+			indent = e.lineIndent(first.Pos())
+			closeIndent = e.lineIndent(as.Pos())
+		}
+	}
+
+	text, err := formatArray(values, quoteChar, indent, closeIndent)
+	if err != nil {
+		return err
+	}
+	return e.replace(as.Array.Lparen.Offset(), as.Array.Rparen.Offset()+1, text)
+}
+
+// lineIndent возвращает пробельные символы в начале строки с позицией pos
+func (e *Editor) lineIndent(pos syntax.Pos) string {
+	line := e.lineText(pos)
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// lineText возвращает текст строки с позицией pos до неё самой
+func (e *Editor) lineText(pos syntax.Pos) string {
+	off := pos.Offset()
+	start := bytes.LastIndexByte(e.src[:off], '\n') + 1
+	return string(e.src[start:off])
+}
+
+// formatArray записывает массив в одну строку или, если задан отступ indent,
+// по одному элементу на строку
+func formatArray(values []string, quoteChar byte, indent, closeIndent string) (string, error) {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		q, err := quote(v, quoteChar)
+		if err != nil {
+			return "", err
+		}
+		quoted = append(quoted, q)
+	}
+
+	if indent == "" || len(quoted) == 0 {
+		return "(" + strings.Join(quoted, " ") + ")", nil
+	}
+	return "(\n" + indent + strings.Join(quoted, "\n"+indent) + "\n" + closeIndent + ")", nil
+}
+
+// quote заключает значение в кавычки заданного вида, если это не меняет его смысла,
+// иначе использует syntax.Quote
+func quote(value string, quoteChar byte) (string, error) {
+	switch {
+	case quoteChar == '\'' && !strings.Contains(value, "'"):
+		return "'" + value + "'", nil
+	case quoteChar == '"' && !strings.ContainsAny(value, "\"$`\\!"):
+		return "\"" + value + "\"", nil
+	case quoteChar == 0 && value != "" && !strings.ContainsAny(value, " \t\n'\"$`\\!*?[]{}()<>|&;#~="):
+		return value, nil
+	}
+	return syntax.Quote(value, syntax.LangBash)
+}
@@ -0,0 +1,134 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package editor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testScript = `name='foo'
+version="1.0" # текущая версия
+release=3
+sources=(
+    "https://example.com/foo-${version}.tar.gz"
+    "local:///fix.patch"
+)
+checksums=(SKIP SKIP)
+
+build() {
+	version=local
+}
+`
+
+func TestSetString(t *testing.T) {
+	ed, err := New([]byte(testScript), "alr.sh")
+	require.NoError(t, err)
+
+	require.NoError(t, ed.SetString("version", "1.1"))
+	require.NoError(t, ed.SetString("release", "1"))
+	assert.ErrorIs(t, ed.SetString("epoch", "1"), ErrNotFound)
+
+	out := string(ed.Bytes())
+	assert.Contains(t, out, "version='1.1' # текущая версия\nrelease='1'\n")
+	// Присваивания внутри функций не изменяются
+	assert.Contains(t, out, "\tversion=local\n")
+}
+
+func TestSetArray(t *testing.T) {
+	ed, err := New([]byte(testScript), "alr.sh")
+	require.NoError(t, err)
+
+	require.NoError(t, ed.SetArray("sources", []string{"https://example.com/a.tar.gz", "https://example.com/b.tar.gz"}, ""))
+	require.NoError(t, ed.SetArray("checksums", []string{"aa", "bb"}, ""))
+	require.NoError(t, ed.SetArray("checksums_amd64", []string{"cc"}, "checksums"))
+	assert.ErrorIs(t, ed.SetArray("checksums_arm64", []string{"dd"}, ""), ErrNotFound)
+
+	assert.Equal(t, `name='foo'
+version="1.0" # текущая версия
+release=3
+sources=(
+    "https://example.com/a.tar.gz"
+    "https://example.com/b.tar.gz"
+)
+checksums=(aa bb)
+checksums_amd64=('cc')
+
+build() {
+	version=local
+}
+`, string(ed.Bytes()))
+}
+
+func TestSetArrayNested(t *testing.T) {
+	ed, err := New([]byte(`sources=('a')
+if [[ "${DISTRO_ID}" == 'fedora' ]]; then
+	sources_fedora=('b') # fedora
+fi
+`), "alr.sh")
+	require.NoError(t, err)
+
+	require.NoError(t, ed.SetArray("checksums_fedora", []string{"bb"}, "sources_fedora"))
+	require.NoError(t, ed.SetArray("checksums", []string{"aa"}, "sources"))
+	assert.Equal(t, `sources=('a')
+checksums=('aa')
+if [[ "${DISTRO_ID}" == 'fedora' ]]; then
+	sources_fedora=('b') # fedora
+	checksums_fedora=('bb')
+fi
+`, string(ed.Bytes()))
+
+	// Массив во вложенном блоке изменяется на месте
+	require.NoError(t, ed.SetArray("checksums_fedora", []string{"cc"}, "sources_fedora"))
+	assert.Contains(t, string(ed.Bytes()), "\tchecksums_fedora=('cc')\nfi\n")
+}
+
+func TestLiteral(t *testing.T) {
+	ed, err := New([]byte("a='x'\nb=\"y\"z\nc=\"${a}\"\nd=(1)\ne=\n"), "alr.sh")
+	require.NoError(t, err)
+
+	for name, want := range map[string]string{"a": "x", "b": "yz", "e": ""} {
+		got, ok := ed.Literal(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, want, got, name)
+	}
+	for _, name := range []string{"c", "d", "missing"} {
+		_, ok := ed.Literal(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	for _, tc := range []struct {
+		value     string
+		quoteChar byte
+		want      string
+	}{
+		{"abc", '\'', "'abc'"},
+		{"it's", '\'', `"it's"`},
+		{"abc", '"', `"abc"`},
+		{"$x", '"', `'$x'`},
+		{"abc", 0, "abc"},
+		{"a b", 0, "'a b'"},
+	} {
+		got, err := quote(tc.value, tc.quoteChar)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got, tc.value)
+	}
+}
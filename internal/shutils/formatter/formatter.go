@@ -0,0 +1,503 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package formatter приводит скрипты alr.sh к единому виду: форматирование
+// mvdan.cc/sh, канонический порядок переменных и функций, оформление массивов
+// и кавычек.
+package formatter
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// VarOrder - канонический порядок переменных метаданных.
+// Переопределения (deps_debian и т.п.) следуют сразу за базовой переменной.
+var VarOrder = []string{
+	"name",
+	"basepkg_name",
+	"version",
+	"release",
+	"epoch",
+	"summary",
+	"desc",
+	"homepage",
+	"upstream",
+	"maintainer",
+	"group",
+	"architectures",
+	"license",
+	"provides",
+	"conflicts",
+	"replaces",
+	"deps",
+	"build_deps",
+	"multilib_build_deps",
+	"opt_deps",
+	"auto_req",
+	"auto_prov",
+	"auto_req_skiplist",
+	"auto_prov_skiplist",
+	"options",
+	"sources",
+	"checksums",
+	"backup",
+	"scripts",
+	"systemd_units",
+	"sysusers",
+	"tmpfiles",
+	"firejailed",
+	"firejail_profiles",
+}
+
+// FuncOrder - канонический порядок функций скрипта с одним пакетом
+var FuncOrder = []string{"prepare", "build", "package", "files"}
+
+// maxArrayLine - максимальная длина массива, записываемого в одну строку
+const maxArrayLine = 80
+
+// safeLiteralRegex соответствует значениям, которые можно заключить
+// в одинарные кавычки без изменения смысла
+var safeLiteralRegex = regexp.MustCompile(`^[A-Za-z0-9._+:/@%=,-]+$`)
+
+// Format форматирует скрипт. name используется в сообщениях об ошибках разбора.
+func Format(src []byte, name string) ([]byte, error) {
+	file, err := syntax.NewParser(syntax.KeepComments(true), syntax.Variant(syntax.LangBash)).
+		Parse(bytes.NewReader(src), name)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	printer := syntax.NewPrinter(syntax.Indent(0))
+
+	stmts := slices.Clone(file.Stmts)
+	if len(stmts) > 0 {
+		// Комментарий в начале файла, отделённый пустой строкой, остаётся наверху
+		if header := fileHeader(stmts[0]); len(header) > 0 {
+			for _, c := range header {
+				out.WriteString("#" + c.Text + "\n")
+			}
+			out.WriteString("\n")
+		}
+	}
+
+	names := packageNames(stmts)
+	var prev *syntax.Stmt
+	for _, run := range splitRuns(stmts) {
+		if isAssignStmt(run[0]) || isFuncStmt(run[0]) {
+			// Переменные метаданных идут перед функциями
+			run = append(
+				sortAssigns(slices.DeleteFunc(slices.Clone(run), isFuncStmt)),
+				sortFuncs(slices.DeleteFunc(slices.Clone(run), isAssignStmt), names)...,
+			)
+		}
+
+		for _, stmt := range run {
+			if prev != nil {
+				// Переменные пишутся подряд, остальные конструкции разделяются пустой строкой
+				if !isAssignStmt(prev) || !isAssignStmt(stmt) {
+					out.WriteString("\n")
+				}
+			}
+			if err := printStmt(&out, printer, stmt); err != nil {
+				return nil, err
+			}
+			out.WriteString("\n")
+			prev = stmt
+		}
+	}
+
+	if len(file.Last) > 0 {
+		if prev != nil {
+			out.WriteString("\n")
+		}
+		for _, c := range file.Last {
+			out.WriteString("#" + c.Text + "\n")
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+// fileHeader отделяет от первой инструкции комментарии, за которыми следует пустая строка
+func fileHeader(stmt *syntax.Stmt) []syntax.Comment {
+	end := 0
+	for i, c := range stmt.Comments {
+		if c.Pos().Line() >= stmt.Pos().Line() {
+			break
+		}
+		next := stmt.Pos().Line()
+		if i+1 < len(stmt.Comments) {
+			next = stmt.Comments[i+1].Pos().Line()
+		}
+		if next > c.Pos().Line()+1 {
+			end = i + 1
+		}
+	}
+	header := stmt.Comments[:end]
+	stmt.Comments = stmt.Comments[end:]
+	return header
+}
+
+// isAssignStmt сообщает, что инструкция только присваивает переменные
+func isAssignStmt(stmt *syntax.Stmt) bool {
+	call, ok := stmt.Cmd.(*syntax.CallExpr)
+	return ok && len(call.Args) == 0 && len(call.Assigns) > 0 &&
+		len(stmt.Redirs) == 0 && !stmt.Negated && !stmt.Background && !stmt.Coprocess
+}
+
+func isFuncStmt(stmt *syntax.Stmt) bool {
+	_, ok := stmt.Cmd.(*syntax.FuncDecl)
+	return ok && len(stmt.Redirs) == 0
+}
+
+// splitRuns разбивает инструкции на непрерывные последовательности объявлений
+// (присваиваний и функций) и прочие команды. Переставляются инструкции только
+// внутри последовательности, поэтому команды между ними сохраняют свой смысл.
+func splitRuns(stmts []*syntax.Stmt) [][]*syntax.Stmt {
+	var runs [][]*syntax.Stmt
+	inRun, seenFunc := false, false
+	for _, stmt := range stmts {
+		decl := isAssignStmt(stmt) || isFuncStmt(stmt)
+		// Присваивание с подстановкой команды может вызывать функцию,
+		// объявленную выше, и не должно переместиться перед ней
+		if decl && inRun && !(seenFunc && isAssignStmt(stmt) && hasCmdSubst(stmt)) {
+			runs[len(runs)-1] = append(runs[len(runs)-1], stmt)
+		} else {
+			runs = append(runs, []*syntax.Stmt{stmt})
+			seenFunc = false
+		}
+		inRun = decl
+		seenFunc = seenFunc || isFuncStmt(stmt)
+	}
+	return runs
+}
+
+// hasCmdSubst сообщает, что инструкция выполняет команды при присваивании
+func hasCmdSubst(stmt *syntax.Stmt) bool {
+	found := false
+	syntax.Walk(stmt, func(node syntax.Node) bool {
+		switch node.(type) {
+		case *syntax.CmdSubst, *syntax.ProcSubst:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// packageNames возвращает значения массива name для упорядочивания функций пакетов
+func packageNames(stmts []*syntax.Stmt) []string {
+	var names []string
+	for _, stmt := range stmts {
+		if !isAssignStmt(stmt) {
+			continue
+		}
+		for _, as := range stmt.Cmd.(*syntax.CallExpr).Assigns {
+			if as.Name.Value != "name" {
+				continue
+			}
+			names = nil
+			if as.Array != nil {
+				for _, elem := range as.Array.Elems {
+					names = append(names, literal(elem.Value))
+				}
+			} else if as.Value != nil {
+				names = append(names, literal(as.Value))
+			}
+		}
+	}
+	return names
+}
+
+// literal возвращает значение слова без кавычек или пустую строку, если в нём есть подстановки
+func literal(w *syntax.Word) string {
+	var sb strings.Builder
+	for _, part := range w.Parts {
+		switch p := part.(type) {
+		case *syntax.Lit:
+			sb.WriteString(p.Value)
+		case *syntax.SglQuoted:
+			sb.WriteString(p.Value)
+		case *syntax.DblQuoted:
+			if len(p.Parts) == 0 {
+				continue
+			}
+			lit, ok := p.Parts[0].(*syntax.Lit)
+			if len(p.Parts) != 1 || !ok {
+				return ""
+			}
+			sb.WriteString(lit.Value)
+		default:
+			return ""
+		}
+	}
+	return sb.String()
+}
+
+// baseRank возвращает позицию самого длинного базового имени из order,
+// для которого name является им самим или его переопределением
+func baseRank(name string, order []string) (rank int, variant bool) {
+	rank, best := len(order), -1
+	for i, base := range order {
+		if len(base) <= best {
+			continue
+		}
+		if name == base {
+			rank, best, variant = i, len(base), false
+		} else if strings.HasPrefix(name, base+"_") {
+			rank, best, variant = i, len(base), true
+		}
+	}
+	return rank, variant
+}
+
+// sortByRank упорядочивает инструкции по рангу, сохраняя исходный порядок
+// для инструкций, которые зависят друг от друга
+func sortByRank(stmts []*syntax.Stmt, less func(a, b int) bool, depends func(a, b int) bool) []*syntax.Stmt {
+	n := len(stmts)
+	placed := make([]bool, n)
+	out := make([]*syntax.Stmt, 0, n)
+	for len(out) < n {
+		best := -1
+		for j := 0; j < n; j++ {
+			if placed[j] {
+				continue
+			}
+			ready := true
+			for i := 0; i < j; i++ {
+				if !placed[i] && depends(i, j) {
+					ready = false
+					break
+				}
+			}
+			if ready && (best == -1 || less(j, best)) {
+				best = j
+			}
+		}
+		placed[best] = true
+		out = append(out, stmts[best])
+	}
+	return out
+}
+
+// sortAssigns упорядочивает присваивания по VarOrder. Присваивание, использующее
+// переменную, остаётся после её присваивания.
+func sortAssigns(stmts []*syntax.Stmt) []*syntax.Stmt {
+	type info struct {
+		rank    int
+		variant bool
+		assigns map[string]bool
+		refs    map[string]bool
+	}
+	infos := make([]info, len(stmts))
+	for i, stmt := range stmts {
+		in := info{assigns: map[string]bool{}, refs: map[string]bool{}}
+		for j, as := range stmt.Cmd.(*syntax.CallExpr).Assigns {
+			if j == 0 {
+				in.rank, in.variant = baseRank(as.Name.Value, VarOrder)
+			}
+			in.assigns[as.Name.Value] = true
+		}
+		syntax.Walk(stmt, func(node syntax.Node) bool {
+			if pe, ok := node.(*syntax.ParamExp); ok && pe.Param != nil {
+				in.refs[pe.Param.Value] = true
+			}
+			return true
+		})
+		infos[i] = in
+	}
+
+	intersects := func(a, b map[string]bool) bool {
+		for k := range a {
+			if b[k] {
+				return true
+			}
+		}
+		return false
+	}
+
+	return sortByRank(stmts,
+		func(a, b int) bool {
+			ia, ib := infos[a], infos[b]
+			if ia.rank != ib.rank {
+				return ia.rank < ib.rank
+			}
+			if ia.variant != ib.variant {
+				return !ia.variant
+			}
+			return a < b
+		},
+		func(a, b int) bool {
+			ia, ib := infos[a], infos[b]
+			return intersects(ia.assigns, ib.refs) || intersects(ia.refs, ib.assigns) || intersects(ia.assigns, ib.assigns)
+		},
+	)
+}
+
+// sortFuncs упорядочивает функции: prepare, build, package, files, затем
+// meta_, package_ и files_ каждого пакета, затем вспомогательные функции
+func sortFuncs(stmts []*syntax.Stmt, names []string) []*syntax.Stmt {
+	order := slices.Clone(FuncOrder)
+	if len(names) > 1 {
+		for _, name := range names {
+			order = append(order, "meta_"+name, "package_"+name, "files_"+name)
+		}
+	}
+
+	type info struct {
+		rank    int
+		variant bool
+	}
+	infos := make([]info, len(stmts))
+	for i, stmt := range stmts {
+		infos[i].rank, infos[i].variant = baseRank(stmt.Cmd.(*syntax.FuncDecl).Name.Value, order)
+	}
+
+	return sortByRank(stmts,
+		func(a, b int) bool {
+			ia, ib := infos[a], infos[b]
+			if ia.rank != ib.rank {
+				return ia.rank < ib.rank
+			}
+			if ia.variant != ib.variant {
+				return !ia.variant
+			}
+			return a < b
+		},
+		func(a, b int) bool {
+			// Одноимённые функции переопределяют друг друга
+			return stmts[a].Cmd.(*syntax.FuncDecl).Name.Value == stmts[b].Cmd.(*syntax.FuncDecl).Name.Value
+		},
+	)
+}
+
+// printStmt печатает инструкцию, оформляя присваивания массивов по правилам ALR
+func printStmt(out *bytes.Buffer, printer *syntax.Printer, stmt *syntax.Stmt) error {
+	if !isAssignStmt(stmt) {
+		return printer.Print(out, stmt)
+	}
+
+	call := stmt.Cmd.(*syntax.CallExpr)
+	for _, as := range call.Assigns {
+		normalizeQuotes(as)
+	}
+
+	as := call.Assigns[0]
+	if len(call.Assigns) > 1 || as.Array == nil || slices.ContainsFunc(as.Array.Elems, func(e *syntax.ArrayElem) bool {
+		return len(e.Comments) > 0
+	}) {
+		return printer.Print(out, stmt)
+	}
+
+	var trailing []string
+	for _, c := range stmt.Comments {
+		if c.Pos().Line() < stmt.Pos().Line() {
+			out.WriteString("#" + c.Text + "\n")
+		} else {
+			trailing = append(trailing, " #"+c.Text)
+		}
+	}
+
+	elems := make([]string, 0, len(as.Array.Elems))
+	for _, elem := range as.Array.Elems {
+		var sb strings.Builder
+		if elem.Index != nil {
+			sb.WriteString("[")
+			if err := printer.Print(&sb, elem.Index.(syntax.Node)); err != nil {
+				return err
+			}
+			sb.WriteString("]=")
+		}
+		if elem.Value != nil {
+			if err := printer.Print(&sb, elem.Value); err != nil {
+				return err
+			}
+		}
+		elems = append(elems, sb.String())
+	}
+
+	op := "=("
+	if as.Append {
+		op = "+=("
+	}
+	line := as.Name.Value + op + strings.Join(elems, " ") + ")"
+	if len(elems) <= 1 || len(line) <= maxArrayLine {
+		out.WriteString(line)
+	} else {
+		out.WriteString(as.Name.Value + op + "\n")
+		for _, elem := range elems {
+			out.WriteString("\t" + elem + "\n")
+		}
+		out.WriteString(")")
+	}
+	out.WriteString(strings.Join(trailing, ""))
+	return nil
+}
+
+// normalizeQuotes заключает в одинарные кавычки значения без подстановок
+func normalizeQuotes(as *syntax.Assign) {
+	if as.Value != nil {
+		quoteWord(as.Value)
+	}
+	if as.Array != nil {
+		for _, elem := range as.Array.Elems {
+			if elem.Value != nil {
+				quoteWord(elem.Value)
+			}
+		}
+	}
+}
+
+func quoteWord(w *syntax.Word) {
+	if len(w.Parts) != 1 {
+		return
+	}
+
+	var value string
+	switch p := w.Parts[0].(type) {
+	case *syntax.Lit:
+		if !safeLiteralRegex.MatchString(p.Value) {
+			return
+		}
+		value = p.Value
+	case *syntax.DblQuoted:
+		if p.Dollar {
+			return
+		}
+		switch len(p.Parts) {
+		case 0:
+		case 1:
+			lit, ok := p.Parts[0].(*syntax.Lit)
+			// Экранирование внутри двойных кавычек меняет значение
+			if !ok || strings.ContainsAny(lit.Value, `'\`) {
+				return
+			}
+			value = lit.Value
+		default:
+			return
+		}
+	default:
+		return
+	}
+
+	w.Parts = []syntax.WordPart{&syntax.SglQuoted{Left: w.Pos(), Right: w.End(), Value: value}}
+}
@@ -0,0 +1,175 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func format(t *testing.T, src string) string {
+	t.Helper()
+	out, err := Format([]byte(src), "alr.sh")
+	require.NoError(t, err)
+
+	// Повторное форматирование ничего не меняет
+	again, err := Format(out, "alr.sh")
+	require.NoError(t, err)
+	assert.Equal(t, string(out), string(again))
+	return string(out)
+}
+
+func TestFormat(t *testing.T) {
+	out := format(t, `# Maintainer: Someone <someone@example.com>
+
+package() {
+    install -Dm755 foo "$pkgdir/usr/bin/foo"
+}
+# сборка
+build() { make; }
+helper() { :; }
+version=1.0
+sources=("https://example.com/foo-${version}.tar.gz" "https://example.com/patches/foo-${version}-fix-build.patch")
+checksums=(SKIP SKIP)
+deps_debian=("libbar1")
+deps=(libbar)   # runtime
+desc="Foo tool"
+name="foo"
+release=1
+package_debian() { :; }
+prepare() { :; }
+`)
+
+	assert.Equal(t, `# Maintainer: Someone <someone@example.com>
+
+name='foo'
+version='1.0'
+release='1'
+desc='Foo tool'
+deps=('libbar') # runtime
+deps_debian=('libbar1')
+sources=(
+	"https://example.com/foo-${version}.tar.gz"
+	"https://example.com/patches/foo-${version}-fix-build.patch"
+)
+checksums=('SKIP' 'SKIP')
+
+prepare() { :; }
+
+# сборка
+build() { make; }
+
+package() {
+	install -Dm755 foo "$pkgdir/usr/bin/foo"
+}
+
+package_debian() { :; }
+
+helper() { :; }
+`, out)
+}
+
+func TestFormatKeepsDependencies(t *testing.T) {
+	out := format(t, `_ver=2.1
+version="${_ver}.0"
+name=foo
+`)
+
+	// version использует _ver и не может оказаться выше него
+	assert.Equal(t, "name='foo'\n_ver='2.1'\nversion=\"${_ver}.0\"\n", out)
+}
+
+func TestFormatCommandsAreBarriers(t *testing.T) {
+	out := format(t, `release=1
+name=foo
+if [ "$DISTRO_ID" = debian ]; then
+	deps=(libfoo)
+fi
+build_deps=(gcc)
+version=1
+_date=$(helper)
+helper() { date; }
+`)
+
+	assert.Equal(t, `name='foo'
+release='1'
+
+if [ "$DISTRO_ID" = debian ]; then
+	deps=(libfoo)
+fi
+
+version='1'
+build_deps=('gcc')
+_date=$(helper)
+
+helper() { date; }
+`, out)
+}
+
+func TestFormatMultiPackage(t *testing.T) {
+	out := format(t, `name=(foo foo-doc)
+basepkg_name=foo
+version=1
+release=1
+package_foo-doc() { :; }
+meta_foo-doc() { :; }
+package_foo() { :; }
+meta_foo() { :; }
+build() { :; }
+`)
+
+	assert.Equal(t, `name=('foo' 'foo-doc')
+basepkg_name='foo'
+version='1'
+release='1'
+
+build() { :; }
+
+meta_foo() { :; }
+
+package_foo() { :; }
+
+meta_foo-doc() { :; }
+
+package_foo-doc() { :; }
+`, out)
+}
+
+func TestFormatQuotes(t *testing.T) {
+	out := format(t, `name=foo
+desc="It's fine"
+homepage=https://example.com
+license=("GPL-3.0-or-later" custom:*)
+maintainer="\$HOME"
+scripts=([postinstall]="postinstall.sh")
+`)
+
+	assert.Equal(t, `name='foo'
+desc="It's fine"
+homepage='https://example.com'
+maintainer="\$HOME"
+license=('GPL-3.0-or-later' custom:*)
+scripts=([postinstall]='postinstall.sh')
+`, out)
+}
+
+func TestFormatSyntaxError(t *testing.T) {
+	_, err := Format([]byte("name=(\n"), "alr.sh")
+	assert.Error(t, err)
+}
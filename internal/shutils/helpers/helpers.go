@@ -35,6 +35,7 @@ import (
 	"mvdan.cc/sh/v3/interp"
 
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/shutils/handlers"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
 )
 
 var (
@@ -91,6 +92,24 @@ var Helpers = handlers.ExecFuncs{
 	"ff-desktop":      filesFindDesktopCmd,
 	"ff-dbus":         filesFindDbusCmd,
 	"ff-polkit":       filesFindPolkitCmd,
+
+	"split-files":              splitCmd(filesFindCmd),
+	"split-files-lang":         splitCmd(filesFindLangCmd),
+	"split-files-doc":          splitCmd(filesFindDocCmd),
+	"split-files-bin":          splitCmd(filesFindBinCmd),
+	"split-files-lib":          splitCmd(filesFindLibCmd),
+	"split-files-include":      splitCmd(filesFindIncludeCmd),
+	"split-files-share":        splitCmd(filesFindShareCmd),
+	"split-files-man":          splitCmd(filesFindManCmd),
+	"split-files-config":       splitCmd(filesFindConfigCmd),
+	"split-files-systemd":      splitCmd(filesFindSystemdCmd),
+	"split-files-systemd-user": splitCmd(filesFindSystemdUserCmd),
+	"split-files-license":      splitCmd(filesFindLicenseCmd),
+	"split-files-sbin":         splitCmd(filesFindSbinCmd),
+	"split-files-icons":        splitCmd(filesFindIconsCmd),
+	"split-files-desktop":      splitCmd(filesFindDesktopCmd),
+	"split-files-dbus":         splitCmd(filesFindDbusCmd),
+	"split-files-polkit":       splitCmd(filesFindPolkitCmd),
 }
 
 // Restricted contains restricted read-only helper commands
@@ -268,6 +287,13 @@ func getLibPrefix(hc interp.HandlerContext) string {
 	distroID := hc.Env.Get("DISTRO_ID").Str
 	distroLike := strings.Split(hc.Env.Get("DISTRO_ID_LIKE").Str, " ")
 
+	// 32-битные библиотеки multilib-сборки кладутся в каталог,
+	// принятый в дистрибутиве для lib32
+	if hc.Env.Get("MULTILIB").Str == "1" {
+		info := &distro.OSRelease{ID: distroID, Like: distroLike}
+		return info.MultilibLibDir()
+	}
+
 	for _, usrLibDistro := range usrLibDistros {
 		if distroID == usrLibDistro || slices.Contains(distroLike, usrLibDistro) {
 			return out
@@ -27,6 +27,8 @@ import (
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/google/shlex"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mvdan.cc/sh/v3/expand"
 	"mvdan.cc/sh/v3/interp"
 	"mvdan.cc/sh/v3/syntax"
 
@@ -381,3 +383,34 @@ files-find ` + tc.args
 		})
 	}
 }
+
+func TestInstallLibraryMultilib(t *testing.T) {
+	for _, tc := range []struct {
+		id, like, expected string
+	}{
+		{"debian", "", "usr/lib/i386-linux-gnu/libfoo.so"},
+		{"manjaro", "arch", "usr/lib32/libfoo.so"},
+		{"fedora", "", "usr/lib/libfoo.so"},
+	} {
+		srcDir, pkgDir := t.TempDir(), t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(srcDir, "libfoo.so"), nil, 0o644))
+
+		runner, err := interp.New(
+			interp.Dir(srcDir),
+			interp.Env(expand.ListEnviron(
+				"pkgdir="+pkgDir,
+				"DISTRO_ID="+tc.id,
+				"DISTRO_ID_LIKE="+tc.like,
+				"ARCH=386",
+				"MULTILIB=1",
+			)),
+			interp.ExecHandler(Helpers.ExecHandler(interp.DefaultExecHandler(1000))),
+		)
+		require.NoError(t, err)
+
+		file, err := syntax.NewParser().Parse(strings.NewReader("install-library libfoo.so"), "")
+		require.NoError(t, err)
+		require.NoError(t, runner.Run(context.Background(), file), tc.id)
+		assert.FileExists(t, filepath.Join(pkgDir, tc.expected), tc.id)
+	}
+}
@@ -0,0 +1,133 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package helpers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/shlex"
+	"mvdan.cc/sh/v3/interp"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/shutils/handlers"
+)
+
+var ErrNoStageDir = errors.New("$stagedir is not set")
+
+// splitCmd оборачивает команду поиска find из семейства files-find-*:
+// поиск выполняется в общем каталоге установки $stagedir, а найденные
+// пути переносятся в $pkgdir текущего подпакета с сохранением структуры.
+//
+//	build() { make DESTDIR="$stagedir" install; }
+//	package_foo-dev() { split-files-include; split-files 'usr/lib/*.so'; }
+func splitCmd(find handlers.ExecFunc) handlers.ExecFunc {
+	return func(hc interp.HandlerContext, cmd string, args []string) error {
+		stageDir := hc.Env.Get("stagedir").Str
+		if stageDir == "" {
+			return fmt.Errorf("%s: %w", cmd, ErrNoStageDir)
+		}
+		pkgDir := hc.Env.Get("pkgdir").Str
+
+		buf := &bytes.Buffer{}
+		findHC := hc
+		findHC.Dir = stageDir
+		findHC.Stdout = buf
+		if err := find(findHC, cmd, args); err != nil {
+			return fmt.Errorf("%s: %w", cmd, err)
+		}
+
+		// files-find-* экранирует пути так же, как для функции files()
+		paths, err := shlex.Split(buf.String())
+		if err != nil {
+			return fmt.Errorf("%s: %w", cmd, err)
+		}
+
+		for _, p := range paths {
+			if err := movePath(stageDir, pkgDir, p); err != nil {
+				return fmt.Errorf("%s: %w", cmd, err)
+			}
+		}
+		return nil
+	}
+}
+
+// movePath переносит путь rel из каталога from в каталог to.
+// Пути, уже перенесённые вместе с родительским каталогом, пропускаются,
+// а опустевшие каталоги в from удаляются.
+func movePath(from, to, rel string) error {
+	src := filepath.Join(from, rel)
+	dst := filepath.Join(to, rel)
+
+	srcInfo, err := os.Lstat(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	dstInfo, err := os.Lstat(dst)
+	switch {
+	case os.IsNotExist(err):
+		if err := os.Rename(src, dst); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case srcInfo.IsDir() && dstInfo.IsDir():
+		// Каталог уже есть в $pkgdir: переносим содержимое по одному
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := movePath(from, to, filepath.Join(rel, entry.Name())); err != nil {
+				return err
+			}
+		}
+		pruneEmptyDirs(from, src)
+		return nil
+	default:
+		if err := os.RemoveAll(dst); err != nil {
+			return err
+		}
+		if err := os.Rename(src, dst); err != nil {
+			return err
+		}
+	}
+
+	pruneEmptyDirs(from, filepath.Dir(src))
+	return nil
+}
+
+// pruneEmptyDirs удаляет пустые каталоги от dir вверх до root (не включая его)
+func pruneEmptyDirs(root, dir string) {
+	root = filepath.Clean(root)
+	for dir = filepath.Clean(dir); dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
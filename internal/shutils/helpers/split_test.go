@@ -0,0 +1,88 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package helpers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mvdan.cc/sh/v3/expand"
+	"mvdan.cc/sh/v3/interp"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+func runSplit(t *testing.T, stageDir, pkgDir, script string) error {
+	t.Helper()
+	runner, err := interp.New(
+		interp.Dir(t.TempDir()),
+		interp.Env(expand.ListEnviron("stagedir="+stageDir, "pkgdir="+pkgDir)),
+		interp.StdIO(os.Stdin, os.Stderr, os.Stderr),
+		interp.ExecHandler(Helpers.ExecHandler(interp.DefaultExecHandler(1000))),
+	)
+	require.NoError(t, err)
+
+	file, err := syntax.NewParser().Parse(strings.NewReader(script), "")
+	require.NoError(t, err)
+	return runner.Run(context.Background(), file)
+}
+
+func TestSplitFiles(t *testing.T) {
+	stageDir := t.TempDir()
+	for _, file := range []string{
+		"usr/bin/foo",
+		"usr/include/foo/foo.h",
+		"usr/lib/libfoo.so",
+		"usr/lib/libfoo.so.1",
+		"usr/share/doc/foo/README",
+		"usr/share/locale/ru/LC_MESSAGES/foo.mo",
+	} {
+		path := filepath.Join(stageDir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(file), 0o644))
+	}
+
+	devDir := filepath.Join(t.TempDir(), "pkg_foo-dev")
+	require.NoError(t, runSplit(t, stageDir, devDir, `split-files-include; split-files 'usr/lib/*.so'`))
+	assert.FileExists(t, filepath.Join(devDir, "usr/include/foo/foo.h"))
+	assert.FileExists(t, filepath.Join(devDir, "usr/lib/libfoo.so"))
+	assert.NoFileExists(t, filepath.Join(devDir, "usr/lib/libfoo.so.1"))
+	assert.NoDirExists(t, filepath.Join(stageDir, "usr/include"))
+
+	docDir := filepath.Join(t.TempDir(), "pkg_foo-doc")
+	require.NoError(t, runSplit(t, stageDir, docDir, `split-files-doc; split-files-lang foo`))
+	assert.FileExists(t, filepath.Join(docDir, "usr/share/doc/foo/README"))
+	assert.FileExists(t, filepath.Join(docDir, "usr/share/locale/ru/LC_MESSAGES/foo.mo"))
+	assert.NoDirExists(t, filepath.Join(stageDir, "usr/share"))
+
+	// Остаток переносится в основной пакет, в том числе в существующий каталог
+	mainDir := filepath.Join(t.TempDir(), "pkg_foo")
+	require.NoError(t, os.MkdirAll(filepath.Join(mainDir, "usr/lib"), 0o755))
+	require.NoError(t, runSplit(t, stageDir, mainDir, `split-files 'usr'`))
+	assert.FileExists(t, filepath.Join(mainDir, "usr/bin/foo"))
+	assert.FileExists(t, filepath.Join(mainDir, "usr/lib/libfoo.so.1"))
+	assert.NoDirExists(t, filepath.Join(stageDir, "usr"))
+}
+
+func TestSplitFilesNoStageDir(t *testing.T) {
+	err := runSplit(t, "", t.TempDir(), `split-files 'usr/bin/*'`)
+	assert.ErrorIs(t, err, ErrNoStageDir)
+}
@@ -0,0 +1,278 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package signing настраивает подпись собранных пакетов и экспортирует
+// открытые ключи в виде, который ожидают пакетные менеджеры.
+package signing
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/goreleaser/nfpm/v2"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+var (
+	ErrNotConfigured = errors.New("signing key is not configured")
+	ErrKeyNotFound   = errors.New("no matching key found")
+)
+
+// Passphrase возвращает пароль ключа по источнику из конфигурации:
+// "env:ИМЯ" читает переменную окружения, "file:ПУТЬ" - первую строку файла.
+// Пустой источник означает ключ без пароля.
+func Passphrase(source string) (string, error) {
+	kind, value, _ := strings.Cut(source, ":")
+	switch kind {
+	case "":
+		return "", nil
+	case "env":
+		pass, ok := os.LookupEnv(value)
+		if !ok {
+			return "", fmt.Errorf("passphrase environment variable %s is not set", value)
+		}
+		return pass, nil
+	case "file":
+		data, err := os.ReadFile(value)
+		if err != nil {
+			return "", err
+		}
+		line, _, _ := strings.Cut(string(data), "\n")
+		return strings.TrimSuffix(line, "\r"), nil
+	default:
+		return "", fmt.Errorf("unknown passphrase source %q, expected env:NAME or file:PATH", source)
+	}
+}
+
+// Key - параметры подписи вместе с паролем ключа, уже прочитанным
+// из источника signing.passphrase
+type Key struct {
+	types.SigningConfig
+	Pass string
+}
+
+// Resolve читает пароль ключа из источника в конфигурации. Вызывается в
+// основном процессе alr: окружение процесса сборки очищается, и переменные
+// для env:ИМЯ в нём недоступны. Без ключей пароль не читается.
+func Resolve(cfg types.SigningConfig) (Key, error) {
+	key := Key{SigningConfig: cfg}
+	if cfg.KeyFile == "" && cfg.APKKeyFile == "" {
+		return key, nil
+	}
+
+	pass, err := Passphrase(cfg.Passphrase)
+	if err != nil {
+		return Key{}, err
+	}
+	key.Pass = pass
+	return key, nil
+}
+
+// Enabled сообщает, подписываются ли пакеты формата format
+func Enabled(cfg types.SigningConfig, format string) bool {
+	switch format {
+	case "deb", "rpm":
+		return cfg.KeyFile != ""
+	case "apk":
+		return cfg.APKKeyFile != ""
+	default:
+		return false
+	}
+}
+
+// Apply заполняет параметры подписи nfpm для формата format.
+// Форматы без поддержки подписи и форматы без ключа остаются без изменений.
+func Apply(info *nfpm.Info, format string, key Key) {
+	if !Enabled(key.SigningConfig, format) {
+		return
+	}
+
+	var keyID *string
+	if key.KeyID != "" {
+		keyID = &key.KeyID
+	}
+
+	switch format {
+	case "deb":
+		info.Deb.Signature.KeyFile = key.KeyFile
+		info.Deb.Signature.KeyID = keyID
+		info.Deb.Signature.KeyPassphrase = key.Pass
+	case "rpm":
+		info.RPM.Signature.KeyFile = key.KeyFile
+		info.RPM.Signature.KeyID = keyID
+		info.RPM.Signature.KeyPassphrase = key.Pass
+	case "apk":
+		info.APK.Signature.KeyFile = key.APKKeyFile
+		info.APK.Signature.KeyName = APKKeyName(key.SigningConfig)
+		info.APK.Signature.KeyPassphrase = key.Pass
+	}
+}
+
+// APKKeyName возвращает имя открытого ключа в /etc/apk/keys,
+// под которым apk ищет ключ подписи
+func APKKeyName(cfg types.SigningConfig) string {
+	name := cfg.APKKeyName
+	if name == "" {
+		name = "alr"
+	}
+	if !strings.HasSuffix(name, ".rsa.pub") {
+		name += ".rsa.pub"
+	}
+	return name
+}
+
+// ExportPGP записывает открытую часть PGP-ключа. Для rpm ключ записывается
+// в ASCII-armor (rpm --import), для deb - в двоичном виде (/etc/apt/trusted.gpg.d).
+func ExportPGP(w io.Writer, cfg types.SigningConfig, armored bool) error {
+	if cfg.KeyFile == "" {
+		return ErrNotConfigured
+	}
+
+	entity, err := readPGPEntity(cfg.KeyFile, cfg.KeyID)
+	if err != nil {
+		return err
+	}
+
+	if !armored {
+		return entity.Serialize(w)
+	}
+
+	aw, err := armor.Encode(w, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return err
+	}
+	if err := entity.Serialize(aw); err != nil {
+		return err
+	}
+	if err := aw.Close(); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// ExportRSA записывает открытый RSA-ключ подписи apk в формате PEM
+func ExportRSA(w io.Writer, cfg types.SigningConfig) error {
+	if cfg.APKKeyFile == "" {
+		return ErrNotConfigured
+	}
+
+	data, err := os.ReadFile(cfg.APKKeyFile)
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("%s: no PEM data found", cfg.APKKeyFile)
+	}
+
+	der := block.Bytes
+	if x509.IsEncryptedPEMBlock(block) { //nolint:staticcheck
+		pass, err := Passphrase(cfg.Passphrase)
+		if err != nil {
+			return err
+		}
+		der, err = x509.DecryptPEMBlock(block, []byte(pass)) //nolint:staticcheck
+		if err != nil {
+			return err
+		}
+	}
+
+	var key any
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(der)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(der)
+	default:
+		return fmt.Errorf("%s: unsupported key type %q", cfg.APKKeyFile, block.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("%s: key has no public part", cfg.APKKeyFile)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return err
+	}
+	return pem.Encode(w, &pem.Block{Type: "PUBLIC KEY", Bytes: pub})
+}
+
+// readPGPEntity читает связку ключей и выбирает ключ по keyID
+// (полный или короткий идентификатор в hex) или единственный ключ подписи
+func readPGPEntity(path, keyID string) (*openpgp.Entity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entities openpgp.EntityList
+	if bytes.Contains(data, []byte("-----BEGIN PGP")) {
+		entities, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	} else {
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if keyID != "" {
+		hexID := strings.TrimPrefix(keyID, "0x")
+		id, err := strconv.ParseUint(hexID, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key id %q: %w", keyID, err)
+		}
+		// Короткий идентификатор сравнивается с младшими 32 битами
+		matches := func(pk *packet.PublicKey) bool {
+			if len(hexID) <= 8 {
+				return pk.KeyId&0xffffffff == id
+			}
+			return pk.KeyId == id
+		}
+		for _, e := range entities {
+			if matches(e.PrimaryKey) {
+				return e, nil
+			}
+			for _, sub := range e.Subkeys {
+				if matches(sub.PublicKey) {
+					return e, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("%s: %w: %s", path, ErrKeyNotFound, keyID)
+	}
+
+	if len(entities) != 1 {
+		return nil, fmt.Errorf("%s: %w: expected a single key, found %d; set signing.keyId", path, ErrKeyNotFound, len(entities))
+	}
+	return entities[0], nil
+}
@@ -0,0 +1,221 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package signing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/goreleaser/nfpm/v2"
+	_ "github.com/goreleaser/nfpm/v2/deb"
+	_ "github.com/goreleaser/nfpm/v2/rpm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
+)
+
+// writePGPKeys записывает закрытые ключи в ASCII-armor, при непустом
+// passphrase ключи шифруются
+func writePGPKeys(t *testing.T, passphrase string, names ...string) (string, []*openpgp.Entity) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "key.asc")
+	fl, err := os.Create(path)
+	require.NoError(t, err)
+	defer fl.Close()
+
+	w, err := armor.Encode(fl, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+
+	var entities []*openpgp.Entity
+	for _, name := range names {
+		e, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+		require.NoError(t, err)
+		if passphrase != "" {
+			require.NoError(t, e.EncryptPrivateKeys([]byte(passphrase), nil))
+		}
+		require.NoError(t, e.SerializePrivateWithoutSigning(w, nil))
+		entities = append(entities, e)
+	}
+	require.NoError(t, w.Close())
+	return path, entities
+}
+
+func TestPassphrase(t *testing.T) {
+	t.Setenv("ALR_TEST_PASSPHRASE", "secret")
+	file := filepath.Join(t.TempDir(), "pass")
+	require.NoError(t, os.WriteFile(file, []byte("from-file\nignored\n"), 0o600))
+
+	for source, want := range map[string]string{
+		"":                        "",
+		"env:ALR_TEST_PASSPHRASE": "secret",
+		"file:" + file:            "from-file",
+	} {
+		got, err := Passphrase(source)
+		require.NoError(t, err, source)
+		assert.Equal(t, want, got, source)
+	}
+
+	for _, source := range []string{"env:ALR_TEST_MISSING", "file:/nonexistent", "secret"} {
+		_, err := Passphrase(source)
+		assert.Error(t, err, source)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("ALR_TEST_PASSPHRASE", "secret")
+
+	key, err := Resolve(types.SigningConfig{KeyFile: "/k", Passphrase: "env:ALR_TEST_PASSPHRASE"})
+	require.NoError(t, err)
+	assert.Equal(t, "secret", key.Pass)
+	assert.Equal(t, "/k", key.KeyFile)
+
+	// Без ключей источник пароля не читается
+	key, err = Resolve(types.SigningConfig{Passphrase: "env:ALR_TEST_MISSING"})
+	require.NoError(t, err)
+	assert.Empty(t, key.Pass)
+
+	_, err = Resolve(types.SigningConfig{KeyFile: "/k", Passphrase: "env:ALR_TEST_MISSING"})
+	assert.Error(t, err)
+	_, err = Resolve(types.SigningConfig{APKKeyFile: "/k", Passphrase: "bad"})
+	assert.Error(t, err)
+}
+
+func TestApply(t *testing.T) {
+	key := Key{
+		SigningConfig: types.SigningConfig{
+			KeyFile:    "/keys/alr.asc",
+			KeyID:      "bc8acdd415bd80b3",
+			APKKeyFile: "/keys/alr.rsa",
+		},
+		Pass: "secret",
+	}
+
+	info := &nfpm.Info{}
+	Apply(info, "deb", key)
+	assert.Equal(t, "/keys/alr.asc", info.Deb.Signature.KeyFile)
+	assert.Equal(t, "bc8acdd415bd80b3", *info.Deb.Signature.KeyID)
+	assert.Equal(t, "secret", info.Deb.Signature.KeyPassphrase)
+	assert.Empty(t, info.RPM.Signature.KeyFile)
+
+	Apply(info, "apk", key)
+	assert.Equal(t, "/keys/alr.rsa", info.APK.Signature.KeyFile)
+	assert.Equal(t, "alr.rsa.pub", info.APK.Signature.KeyName)
+
+	// Формат без подписи и незаданный ключ ничего не меняют
+	info = &nfpm.Info{}
+	Apply(info, "archlinux", key)
+	Apply(info, "rpm", Key{Pass: "secret"})
+	assert.Equal(t, &nfpm.Info{}, info)
+}
+
+func TestSignPackage(t *testing.T) {
+	t.Setenv("ALR_TEST_PASSPHRASE", "secret")
+	keyFile, _ := writePGPKeys(t, "secret", "alr")
+
+	key, err := Resolve(types.SigningConfig{
+		KeyFile:    keyFile,
+		Passphrase: "env:ALR_TEST_PASSPHRASE",
+	})
+	require.NoError(t, err)
+
+	for _, format := range []string{"deb", "rpm"} {
+		info := nfpm.WithDefaults(&nfpm.Info{
+			Name:       "foo",
+			Arch:       "amd64",
+			Version:    "1.0",
+			Maintainer: "ALR <alr@example.com>",
+		})
+		Apply(info, format, key)
+
+		packager, err := nfpm.Get(format)
+		require.NoError(t, err)
+		require.NoError(t, packager.Package(info, io.Discard), format)
+	}
+}
+
+func TestExportPGP(t *testing.T) {
+	keyFile, entities := writePGPKeys(t, "", "first", "second")
+
+	var buf bytes.Buffer
+	err := ExportPGP(&buf, types.SigningConfig{KeyFile: keyFile}, true)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	second := entities[1].PrimaryKey
+	for _, keyID := range []string{second.KeyIdString(), "0x" + second.KeyIdShortString()} {
+		buf.Reset()
+		require.NoError(t, ExportPGP(&buf, types.SigningConfig{KeyFile: keyFile, KeyID: keyID}, true))
+		assert.Contains(t, buf.String(), "-----BEGIN PGP PUBLIC KEY BLOCK-----")
+
+		keys, err := openpgp.ReadArmoredKeyRing(&buf)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, second.KeyId, keys[0].PrimaryKey.KeyId)
+		assert.Nil(t, keys[0].PrivateKey)
+	}
+
+	buf.Reset()
+	require.NoError(t, ExportPGP(&buf, types.SigningConfig{KeyFile: keyFile, KeyID: entities[0].PrimaryKey.KeyIdString()}, false))
+	keys, err := openpgp.ReadKeyRing(&buf)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, entities[0].PrimaryKey.KeyId, keys[0].PrimaryKey.KeyId)
+
+	err = ExportPGP(&buf, types.SigningConfig{KeyFile: keyFile, KeyID: "deadbeef"}, true)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	assert.ErrorIs(t, ExportPGP(&buf, types.SigningConfig{}, true), ErrNotConfigured)
+}
+
+func TestExportRSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	files := map[string]*pem.Block{
+		"pkcs1.pem": {Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)},
+		"pkcs8.pem": {Type: "PRIVATE KEY", Bytes: pkcs8},
+	}
+
+	for name, block := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(block), 0o600))
+
+		var buf bytes.Buffer
+		require.NoError(t, ExportRSA(&buf, types.SigningConfig{APKKeyFile: path}), name)
+
+		block, _ := pem.Decode(buf.Bytes())
+		require.NotNil(t, block, name)
+		assert.Equal(t, "PUBLIC KEY", block.Type)
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		require.NoError(t, err)
+		assert.True(t, key.PublicKey.Equal(pub), name)
+	}
+
+	assert.ErrorIs(t, ExportRSA(io.Discard, types.SigningConfig{}), ErrNotConfigured)
+}
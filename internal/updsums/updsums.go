@@ -0,0 +1,241 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package updsums пересчитывает контрольные суммы источников скрипта alr.sh,
+// загружая их через pkg/dl.
+package updsums
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/shutils/editor"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/dl"
+)
+
+// DefaultAlgorithm - алгоритм, контрольные суммы которого записываются без префикса
+const DefaultAlgorithm = "sha256"
+
+// Options задаёт параметры пересчёта
+type Options struct {
+	// Info - дистрибутив, для которого вычисляются источники
+	Info *distro.OSRelease
+	// Distros - дополнительные профили дистрибутивов. Скрипт вычисляется для
+	// каждого из них, чтобы учесть источники, зависящие от DISTRO_ID.
+	Distros []distro.OSRelease
+	// Algorithm - алгоритм для всех контрольных сумм. Если пуст, сохраняется
+	// алгоритм текущего значения (по умолчанию DefaultAlgorithm).
+	Algorithm string
+	// Progress получает ход загрузки (может быть nil)
+	Progress io.Writer
+}
+
+// Update пересчитывает checksums и checksums_<переопределение> скрипта по path
+// и записывает их в файл. Значения SKIP заменяются суммами файлов и остаются
+// только для источников-каталогов (например, git).
+func Update(ctx context.Context, path string, opts Options) error {
+	if opts.Algorithm != "" {
+		if _, err := (dl.Options{HashAlgorithm: opts.Algorithm}).NewHash(); err != nil {
+			return err
+		}
+	}
+
+	profiles := make([]*distro.OSRelease, 0, len(opts.Distros)+1)
+	if opts.Info != nil {
+		profiles = append(profiles, opts.Info)
+	}
+	for i := range opts.Distros {
+		profiles = append(profiles, &opts.Distros[i])
+	}
+	if len(profiles) == 0 {
+		return errors.New("no distribution to evaluate the script for")
+	}
+
+	sets := map[string]sourceSet{}
+	for _, info := range profiles {
+		if err := sourceSets(ctx, path, info, sets); err != nil {
+			return fmt.Errorf("%s: %w", info.ID, err)
+		}
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	ed, err := editor.New(src, path)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "alr-updsums-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	keys := make([]string, 0, len(sets))
+	for key := range sets {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	// Одинаковые источники разных переопределений загружаются один раз
+	computed := map[[2]string]string{}
+	for _, key := range keys {
+		set := sets[key]
+		sums := make([]string, len(set.sources))
+		for i, source := range set.sources {
+			algo := opts.Algorithm
+			if algo == "" {
+				algo = DefaultAlgorithm
+				if i < len(set.checksums) {
+					if a, _, ok := strings.Cut(set.checksums[i], ":"); ok {
+						algo = a
+					}
+				}
+			}
+
+			if sum, ok := computed[[2]string{source, algo}]; ok {
+				sums[i] = sum
+				continue
+			}
+			sum, err := Compute(ctx, source, algo, dl.Options{
+				Name:     fmt.Sprintf("%s[%d]", varName("sources", key), i),
+				LocalDir: filepath.Dir(path),
+				Progress: opts.Progress,
+			}, filepath.Join(tmpDir, key, strconv.Itoa(i)))
+			if err != nil {
+				return err
+			}
+			computed[[2]string{source, algo}] = sum
+			sums[i] = sum
+		}
+
+		// Отсутствующий массив добавляется сразу после своего массива
+		// источников, в том же блоке (например, в проверке DISTRO_ID)
+		if err := ed.SetArray(varName("checksums", key), sums, varName("sources", key)); err != nil {
+			return err
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, ed.Bytes(), info.Mode().Perm())
+}
+
+// Compute загружает источник в каталог dest и возвращает его контрольную сумму
+// в формате массива checksums. Для каталогов возвращается SKIP.
+func Compute(ctx context.Context, source, algo string, opts dl.Options, dest string) (string, error) {
+	// Репозитории git всегда загружаются в каталог, клонировать их незачем
+	if strings.HasPrefix(source, "git+") {
+		return "SKIP", nil
+	}
+
+	opts.URL = source
+	opts.Destination = dest
+	opts.CacheDisabled = true
+	// Контрольная сумма считается от загруженного файла, а не от распакованного архива
+	opts.PostprocDisabled = true
+	opts.HashAlgorithm = algo
+
+	h, err := opts.NewHash()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return "", err
+	}
+	if err := dl.Download(ctx, opts); err != nil {
+		return "", err
+	}
+
+	entries, err := os.ReadDir(dest)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) != 1 {
+		return "", fmt.Errorf("%s: expected a single downloaded entry, got %d", source, len(entries))
+	}
+	if entries[0].IsDir() {
+		return "SKIP", nil
+	}
+
+	fl, err := os.Open(filepath.Join(dest, entries[0].Name()))
+	if err != nil {
+		return "", err
+	}
+	defer fl.Close()
+
+	if _, err := io.Copy(h, fl); err != nil {
+		return "", err
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	if algo == DefaultAlgorithm {
+		return sum, nil
+	}
+	return algo + ":" + sum, nil
+}
+
+// sourceSet - источники и текущие контрольные суммы одного переопределения
+type sourceSet struct {
+	sources   []string
+	checksums []string
+}
+
+// sourceSets вычисляет скрипт для info и добавляет в sets источники по суффиксу
+// переопределения ("" для sources, "amd64" для sources_amd64 и т.д.)
+func sourceSets(ctx context.Context, path string, info *distro.OSRelease, sets map[string]sourceSet) error {
+	script, err := alrsh.ReadFromLocal(path)
+	if err != nil {
+		return err
+	}
+	_, pkgs, err := script.ParseBuildVars(ctx, info, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, pkg := range pkgs {
+		checksums := pkg.Checksums.All()
+		for key, sources := range pkg.Sources.All() {
+			set := sourceSet{sources: sources, checksums: checksums[key]}
+			if prev, ok := sets[key]; ok && !slices.Equal(prev.sources, set.sources) {
+				return fmt.Errorf("%s differs between packages or distributions and cannot be updated", varName("sources", key))
+			}
+			sets[key] = set
+		}
+	}
+	return nil
+}
+
+func varName(base, key string) string {
+	if key == "" {
+		return base
+	}
+	return base + "_" + key
+}
@@ -0,0 +1,157 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package updsums
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/dl"
+)
+
+func writeScript(t *testing.T, script string, files ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, name := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644))
+	}
+	path := filepath.Join(dir, "alr.sh")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o644))
+	return path
+}
+
+func sha256Of(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func blake2bOf(s string) string {
+	sum := blake2b.Sum256([]byte(s))
+	return "blake2b-256:" + hex.EncodeToString(sum[:])
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestUpdate(t *testing.T) {
+	path := writeScript(t, `name='foo'
+version='1.0'
+release='1'
+sources=(
+	'local:///a.txt'
+	'local:///b.txt'
+)
+checksums=(
+	'SKIP'
+	'blake2b-256:0000'
+)
+sources_amd64=('local:///a.txt' 'local:///c.txt')
+if [[ "${DISTRO_ID}" == 'fedora' ]]; then
+	sources_fedora=('local:///d.txt')
+fi
+package() { :; }
+`, "a.txt", "b.txt", "c.txt", "d.txt")
+
+	err := Update(context.Background(), path, Options{
+		Info:    &distro.OSRelease{ID: "debian"},
+		Distros: []distro.OSRelease{{ID: "fedora"}},
+	})
+	require.NoError(t, err)
+
+	// Алгоритм существующих значений сохраняется, SKIP заменяется суммой
+	assert.Equal(t, `name='foo'
+version='1.0'
+release='1'
+sources=(
+	'local:///a.txt'
+	'local:///b.txt'
+)
+checksums=(
+	'`+sha256Of("a.txt")+`'
+	'`+blake2bOf("b.txt")+`'
+)
+sources_amd64=('local:///a.txt' 'local:///c.txt')
+checksums_amd64=('`+sha256Of("a.txt")+`' '`+sha256Of("c.txt")+`')
+if [[ "${DISTRO_ID}" == 'fedora' ]]; then
+	sources_fedora=('local:///d.txt')
+	checksums_fedora=('`+sha256Of("d.txt")+`')
+fi
+package() { :; }
+`, readFile(t, path))
+
+	// Повторный запуск обновляет массивы на месте, в том числе внутри блока
+	before := readFile(t, path)
+	err = Update(context.Background(), path, Options{
+		Info:    &distro.OSRelease{ID: "debian"},
+		Distros: []distro.OSRelease{{ID: "fedora"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, before, readFile(t, path))
+}
+
+func TestUpdateAlgorithm(t *testing.T) {
+	path := writeScript(t, `name='foo'
+version='1.0'
+release='1'
+sources=("local:///a.txt" "git+https://example.com/foo.git")
+checksums=("SKIP" "SKIP")
+`, "a.txt")
+
+	err := Update(context.Background(), path, Options{
+		Info:      &distro.OSRelease{ID: "debian"},
+		Algorithm: "blake2b-256",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, readFile(t, path), `checksums=("`+blake2bOf("a.txt")+`" "SKIP")`)
+
+	err = Update(context.Background(), path, Options{
+		Info:      &distro.OSRelease{ID: "debian"},
+		Algorithm: "crc32",
+	})
+	assert.ErrorIs(t, err, dl.ErrNoSuchHashAlgo)
+}
+
+func TestUpdateConflict(t *testing.T) {
+	path := writeScript(t, `name='foo'
+version='1.0'
+release='1'
+sources=("local:///${DISTRO_ID}.txt")
+checksums=('SKIP')
+`, "debian.txt", "fedora.txt")
+	orig := readFile(t, path)
+
+	err := Update(context.Background(), path, Options{
+		Info:    &distro.OSRelease{ID: "debian"},
+		Distros: []distro.OSRelease{{ID: "fedora"}},
+	})
+	assert.ErrorContains(t, err, "sources differs")
+	assert.Equal(t, orig, readFile(t, path))
+}
@@ -0,0 +1,110 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package upstream
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/shutils/editor"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/updsums"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/alrsh"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+)
+
+// Result - результат проверки одного скрипта
+type Result struct {
+	Path     string `json:"path"`
+	Name     string `json:"name"`
+	Upstream string `json:"upstream"`
+	Current  string `json:"current"`
+	Latest   string `json:"latest"`
+}
+
+// Outdated сообщает, что у разработчиков вышла более новая версия
+func (r Result) Outdated() bool {
+	return r.Latest != "" && IsNewer(r.Latest, r.Current)
+}
+
+// CheckScript читает скрипт и запрашивает последнюю версию из его upstream.
+// Если upstream не задан, Result.Upstream пуст, а ошибка не возвращается.
+func CheckScript(ctx context.Context, path string, info *distro.OSRelease) (Result, error) {
+	res := Result{Path: path}
+
+	script, err := alrsh.ReadFromLocal(path)
+	if err != nil {
+		return res, err
+	}
+	baseName, pkgs, err := script.ParseBuildVars(ctx, info, nil)
+	if err != nil {
+		return res, err
+	}
+	if len(pkgs) == 0 {
+		return res, nil
+	}
+
+	res.Name = baseName
+	res.Current = pkgs[0].Version
+	res.Upstream = pkgs[0].Upstream
+	if res.Upstream == "" {
+		return res, nil
+	}
+
+	src, err := Parse(res.Upstream)
+	if err != nil {
+		return res, err
+	}
+	res.Latest, err = src.Latest(ctx)
+	return res, err
+}
+
+// UpdateScript записывает в скрипт новую версию, сбрасывает release до 1
+// и пересчитывает контрольные суммы источников
+func UpdateScript(ctx context.Context, path, version string, opts updsums.Options) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	ed, err := editor.New(src, path)
+	if err != nil {
+		return err
+	}
+
+	// Версия, вычисляемая из других переменных, не может быть заменена надёжно
+	if _, ok := ed.Literal("version"); !ok {
+		return errors.New("version is not a literal value and must be updated manually")
+	}
+	if err := ed.SetString("version", version); err != nil {
+		return err
+	}
+	if ed.Has("release") {
+		if err := ed.SetString("release", "1"); err != nil {
+			return err
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, ed.Bytes(), info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	return updsums.Update(ctx, path, opts)
+}
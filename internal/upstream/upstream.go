@@ -0,0 +1,277 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package upstream определяет последнюю версию пакета у его разработчиков
+// по значению переменной upstream скрипта alr.sh.
+//
+// Поддерживаемые значения:
+//
+//	github:owner/repo                  последний релиз (или тег) на GitHub
+//	gitea:https://host/owner/repo      последний релиз (или тег) на Gitea/Forgejo
+//	pypi:name                          последняя версия на PyPI
+//	crates:name                        последняя стабильная версия на crates.io
+//	git:url                            наибольший тег git-репозитория
+//	regex:url pattern                  наибольшее совпадение (первая группа) на странице
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"git.alr-pkg.ru/xpamych/vercmp"
+	"github.com/go-git/go-git/v5"
+	gitConfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/memory"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/webapi"
+)
+
+// Адреса API по умолчанию (изменяются в тестах)
+var (
+	GitHubAPIURL = webapi.ForgeDefaultURLs[webapi.ForgeGitHub]
+	PyPIURL      = "https://pypi.org"
+	CratesIOURL  = webapi.CratesIOURL
+)
+
+// Source - источник версий пакета
+type Source interface {
+	// Latest возвращает последнюю версию без префиксов тегов (например, "1.2.3")
+	Latest(ctx context.Context) (string, error)
+}
+
+// Parse разбирает значение переменной upstream
+func Parse(spec string) (Source, error) {
+	kind, arg, ok := strings.Cut(strings.TrimSpace(spec), ":")
+	if !ok || arg == "" {
+		return nil, fmt.Errorf("invalid upstream %q: expected <kind>:<value>", spec)
+	}
+
+	switch kind {
+	case "github":
+		owner, repo, ok := strings.Cut(arg, "/")
+		if !ok || owner == "" || repo == "" {
+			return nil, fmt.Errorf("invalid upstream %q: expected github:owner/repo", spec)
+		}
+		return newForgeSource(webapi.ForgeGitHub, GitHubAPIURL, owner, repo)
+	case "gitea":
+		u, err := url.Parse(arg)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid upstream %q: expected gitea:https://host/owner/repo", spec)
+		}
+		owner, repo, ok := strings.Cut(strings.Trim(strings.TrimSuffix(u.Path, ".git"), "/"), "/")
+		if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			return nil, fmt.Errorf("invalid upstream %q: expected gitea:https://host/owner/repo", spec)
+		}
+		return newForgeSource(webapi.ForgeGitea, u.Scheme+"://"+u.Host, owner, repo)
+	case "pypi":
+		return pypiSource{name: arg}, nil
+	case "crates":
+		return cratesSource{name: arg}, nil
+	case "git":
+		return gitSource{url: arg}, nil
+	case "regex":
+		rawURL, pattern, ok := strings.Cut(arg, " ")
+		if !ok || strings.TrimSpace(pattern) == "" {
+			return nil, fmt.Errorf("invalid upstream %q: expected regex:url pattern", spec)
+		}
+		re, err := regexp.Compile(strings.TrimSpace(pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid upstream %q: %w", spec, err)
+		}
+		return regexSource{url: rawURL, re: re}, nil
+	default:
+		return nil, fmt.Errorf("unknown upstream kind %q", kind)
+	}
+}
+
+// IsNewer сообщает, что версия latest новее current
+func IsNewer(latest, current string) bool {
+	return vercmp.Compare(latest, current) == 1
+}
+
+var (
+	tagVersionRegex  = regexp.MustCompile(`\d.*$`)
+	prereleaseRegex  = regexp.MustCompile(`(?i)(alpha|beta|rc|pre|dev|snapshot|nightly)`)
+	errNoVersions    = errors.New("no versions found")
+	errEmptyResponse = errors.New("empty version in response")
+)
+
+// VersionFromTag извлекает версию из имени тега: "v1.2.3" -> "1.2.3",
+// "foo-1.2" -> "1.2", "curl-8_5_0" -> "8.5.0". Возвращает пустую строку,
+// если тег не содержит версии.
+func VersionFromTag(tag string) string {
+	v := tagVersionRegex.FindString(tag)
+	if !strings.Contains(v, ".") {
+		v = strings.ReplaceAll(v, "_", ".")
+	}
+	return v
+}
+
+// latestOf выбирает наибольшую версию, пропуская предварительные,
+// если есть хотя бы одна стабильная
+func latestOf(versions []string) (string, error) {
+	var latest, latestPre string
+	for _, v := range versions {
+		if v == "" {
+			continue
+		}
+		if prereleaseRegex.MatchString(v) {
+			if latestPre == "" || IsNewer(v, latestPre) {
+				latestPre = v
+			}
+			continue
+		}
+		if latest == "" || IsNewer(v, latest) {
+			latest = v
+		}
+	}
+	if latest == "" {
+		latest = latestPre
+	}
+	if latest == "" {
+		return "", errNoVersions
+	}
+	return latest, nil
+}
+
+// forgeSource использует общий для GitHub и Gitea API релизов и тегов
+type forgeSource struct {
+	api *webapi.Forge
+}
+
+func newForgeSource(forge, baseURL, owner, repo string) (Source, error) {
+	api, err := webapi.NewForge(forge, baseURL, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return forgeSource{api: api}, nil
+}
+
+func (s forgeSource) Latest(ctx context.Context) (string, error) {
+	release, err := s.api.Release(ctx, "")
+	if err == nil && release.TagName != "" {
+		return VersionFromTag(release.TagName), nil
+	}
+	if err != nil && !errors.Is(err, webapi.ErrNotFound) {
+		return "", err
+	}
+
+	// Репозиторий без релизов: используем теги
+	tags, err := s.api.Tags(ctx)
+	if err != nil {
+		return "", err
+	}
+	versions := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		versions = append(versions, VersionFromTag(tag.Name))
+	}
+	return latestOf(versions)
+}
+
+type pypiSource struct {
+	name string
+}
+
+func (s pypiSource) Latest(ctx context.Context) (string, error) {
+	var resp struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+	}
+	if err := webapi.GetJSON(ctx, fmt.Sprintf("%s/pypi/%s/json", PyPIURL, url.PathEscape(s.name)), "", &resp); err != nil {
+		return "", err
+	}
+	if resp.Info.Version == "" {
+		return "", errEmptyResponse
+	}
+	return resp.Info.Version, nil
+}
+
+type cratesSource struct {
+	name string
+}
+
+func (s cratesSource) Latest(ctx context.Context) (string, error) {
+	resp, err := webapi.FetchCrate(ctx, CratesIOURL, s.name)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case resp.Crate.MaxStableVersion != "":
+		return resp.Crate.MaxStableVersion, nil
+	case resp.Crate.NewestVersion != "":
+		return resp.Crate.NewestVersion, nil
+	default:
+		return "", errEmptyResponse
+	}
+}
+
+type gitSource struct {
+	url string
+}
+
+func (s gitSource) Latest(ctx context.Context) (string, error) {
+	remote := git.NewRemote(memory.NewStorage(), &gitConfig.RemoteConfig{
+		Name: "origin",
+		URLs: []string{s.url},
+	})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	var versions []string
+	for _, ref := range refs {
+		if ref.Name().IsTag() {
+			versions = append(versions, VersionFromTag(ref.Name().Short()))
+		}
+	}
+	return latestOf(versions)
+}
+
+type regexSource struct {
+	url string
+	re  *regexp.Regexp
+}
+
+func (s regexSource) Latest(ctx context.Context) (string, error) {
+	res, err := webapi.Get(ctx, s.url, "")
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	// Страницы со списками файлов бывают большими, но не настолько
+	data, err := io.ReadAll(io.LimitReader(res.Body, 16<<20))
+	if err != nil {
+		return "", err
+	}
+
+	var versions []string
+	for _, m := range s.re.FindAllStringSubmatch(string(data), -1) {
+		if len(m) > 1 {
+			versions = append(versions, m[1])
+		} else {
+			versions = append(versions, m[0])
+		}
+	}
+	return latestOf(versions)
+}
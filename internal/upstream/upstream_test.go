@@ -0,0 +1,203 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package upstream
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/updsums"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+)
+
+func newAPIStub(t *testing.T, routes map[string]string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	for p, body := range routes {
+		mux.HandleFunc(p, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		})
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func latest(t *testing.T, spec string) string {
+	t.Helper()
+	src, err := Parse(spec)
+	require.NoError(t, err)
+	v, err := src.Latest(context.Background())
+	require.NoError(t, err)
+	return v
+}
+
+func TestForgeSources(t *testing.T) {
+	srv := newAPIStub(t, map[string]string{
+		"/repos/owner/app/releases/latest":        `{"tag_name":"v2.4.1"}`,
+		"/repos/owner/norel/tags":                 `[{"name":"v1.9.0"},{"name":"v1.10.0"},{"name":"v2.0.0-rc1"},{"name":"nightly"}]`,
+		"/api/v1/repos/owner/app/releases/latest": `{"tag_name":"app-3.0"}`,
+	})
+	oldURL := GitHubAPIURL
+	GitHubAPIURL = srv.URL
+	t.Cleanup(func() { GitHubAPIURL = oldURL })
+
+	assert.Equal(t, "2.4.1", latest(t, "github:owner/app"))
+	// Без релизов используется наибольший стабильный тег
+	assert.Equal(t, "1.10.0", latest(t, "github:owner/norel"))
+	assert.Equal(t, "3.0", latest(t, "gitea:"+srv.URL+"/owner/app.git"))
+}
+
+func TestPackageIndexSources(t *testing.T) {
+	srv := newAPIStub(t, map[string]string{
+		"/pypi/requests/json":    `{"info":{"version":"2.32.3"}}`,
+		"/api/v1/crates/ripgrep": `{"crate":{"max_stable_version":"14.1.1","newest_version":"15.0.0-beta.1"}}`,
+		"/downloads/": `<a href="foo-1.2.9.tar.gz">foo-1.2.9.tar.gz</a>
+<a href="foo-1.2.10.tar.gz">foo-1.2.10.tar.gz</a>
+<a href="foo-1.3.0-beta.tar.gz">foo-1.3.0-beta.tar.gz</a>`,
+	})
+	oldPyPI, oldCrates := PyPIURL, CratesIOURL
+	PyPIURL, CratesIOURL = srv.URL, srv.URL
+	t.Cleanup(func() { PyPIURL, CratesIOURL = oldPyPI, oldCrates })
+
+	assert.Equal(t, "2.32.3", latest(t, "pypi:requests"))
+	assert.Equal(t, "14.1.1", latest(t, "crates:ripgrep"))
+	assert.Equal(t, "1.2.10", latest(t, `regex:`+srv.URL+`/downloads/ foo-([0-9.]+[0-9a-z-]*)\.tar\.gz`))
+
+	src, err := Parse("pypi:missing")
+	require.NoError(t, err)
+	_, err = src.Latest(context.Background())
+	assert.Error(t, err)
+}
+
+func TestGitSource(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("x"), 0o644))
+	_, err = wt.Add("README")
+	require.NoError(t, err)
+	hash, err := wt.Commit("init", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	for _, tag := range []string{"v0.9.0", "release-1_2_0", "v1.1.0"} {
+		_, err = repo.CreateTag(tag, hash, nil)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, "1.2.0", latest(t, "git:"+dir))
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"github",
+		"github:owner",
+		"gitea:owner/repo",
+		"regex:https://example.com",
+		"regex:https://example.com (",
+		"svn:https://example.com",
+	} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestVersionFromTag(t *testing.T) {
+	for tag, want := range map[string]string{
+		"v1.2.3":      "1.2.3",
+		"1.0":         "1.0",
+		"foo-2.1":     "2.1",
+		"curl-8_5_0":  "8.5.0",
+		"v2.0.0-rc.1": "2.0.0-rc.1",
+		"latest":      "",
+	} {
+		assert.Equal(t, want, VersionFromTag(tag), tag)
+	}
+}
+
+func TestCheckAndUpdateScript(t *testing.T) {
+	srv := newAPIStub(t, map[string]string{
+		"/repos/owner/foo/releases/latest": `{"tag_name":"v1.1.0"}`,
+	})
+	oldURL := GitHubAPIURL
+	GitHubAPIURL = srv.URL
+	t.Cleanup(func() { GitHubAPIURL = oldURL })
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "alr.sh")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.patch"), []byte("patch"), 0o644))
+	require.NoError(t, os.WriteFile(script, []byte(`name='foo'
+version='1.0.0'
+release='4'
+upstream='github:owner/foo'
+sources=('local:///foo.patch')
+checksums=('SKIP')
+package() { :; }
+`), 0o644))
+
+	info := &distro.OSRelease{ID: "debian"}
+	res, err := CheckScript(context.Background(), script, info)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Path: script, Name: "foo", Upstream: "github:owner/foo", Current: "1.0.0", Latest: "1.1.0"}, res)
+	assert.True(t, res.Outdated())
+
+	require.NoError(t, UpdateScript(context.Background(), script, res.Latest, updsums.Options{Info: info}))
+
+	sum := sha256.Sum256([]byte("patch"))
+	data, err := os.ReadFile(script)
+	require.NoError(t, err)
+	assert.Equal(t, `name='foo'
+version='1.1.0'
+release='1'
+upstream='github:owner/foo'
+sources=('local:///foo.patch')
+checksums=('`+hex.EncodeToString(sum[:])+`')
+package() { :; }
+`, string(data))
+
+	// Без upstream проверка пропускается
+	require.NoError(t, os.WriteFile(script, []byte("name='foo'\nversion='1'\nrelease='1'\n"), 0o644))
+	res, err = CheckScript(context.Background(), script, info)
+	require.NoError(t, err)
+	assert.Empty(t, res.Upstream)
+	assert.False(t, res.Outdated())
+}
+
+func TestUpdateScriptComputedVersion(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "alr.sh")
+	require.NoError(t, os.WriteFile(script, []byte("_v=1\nname='foo'\nversion=\"${_v}.0\"\nrelease='1'\n"), 0o644))
+
+	err := UpdateScript(context.Background(), script, "2.0", updsums.Options{Info: &distro.OSRelease{}})
+	assert.ErrorContains(t, err, "not a literal")
+}
@@ -0,0 +1,68 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package webapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// CratesIOURL - адрес crates.io по умолчанию
+const CratesIOURL = "https://crates.io"
+
+// Crate представляет ответ /api/v1/crates/{name}
+type Crate struct {
+	Crate    CrateInfo      `json:"crate"`
+	Versions []CrateVersion `json:"versions"`
+}
+
+// CrateInfo содержит общую информацию о crate
+type CrateInfo struct {
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	Homepage         string `json:"homepage"`
+	Repository       string `json:"repository"`
+	MaxStableVersion string `json:"max_stable_version"`
+	NewestVersion    string `json:"newest_version"`
+}
+
+// CrateVersion содержит информацию об одной версии crate
+type CrateVersion struct {
+	Num      string   `json:"num"`
+	Checksum string   `json:"checksum"`
+	License  string   `json:"license"`
+	DlPath   string   `json:"dl_path"`
+	BinNames []string `json:"bin_names"`
+	Yanked   bool     `json:"yanked"`
+}
+
+// FetchCrate запрашивает информацию о crate из API crates.io по адресу baseURL
+func FetchCrate(ctx context.Context, baseURL, name string) (*Crate, error) {
+	apiURL := fmt.Sprintf("%s/api/v1/crates/%s", strings.TrimSuffix(baseURL, "/"), url.PathEscape(name))
+
+	var crate Crate
+	err := GetJSON(ctx, apiURL, "", &crate)
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("crate '%s' %w on crates.io", name, ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("crates.io: %w", err)
+	}
+	return &crate, nil
+}
@@ -0,0 +1,163 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package webapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Поддерживаемые forge-платформы
+const (
+	ForgeGitHub = "github"
+	ForgeGitea  = "gitea"
+)
+
+// ForgeDefaultURLs - адреса API по умолчанию
+var ForgeDefaultURLs = map[string]string{
+	ForgeGitHub: "https://api.github.com",
+	ForgeGitea:  "https://gitea.com",
+}
+
+// Repo - общая для GitHub и Gitea часть ответа о репозитории
+type Repo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	HTMLURL     string `json:"html_url"`
+	Homepage    string `json:"homepage"` // GitHub
+	Website     string `json:"website"`  // Gitea
+	License     *struct {
+		SPDXID string `json:"spdx_id"`
+	} `json:"license"` // GitHub
+	Licenses []string `json:"licenses"` // Gitea
+}
+
+// Release - общая для GitHub и Gitea часть ответа о релизе
+type Release struct {
+	TagName string  `json:"tag_name"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset - файл релиза
+type Asset struct {
+	Name   string `json:"name"`
+	URL    string `json:"browser_download_url"`
+	Digest string `json:"digest"` // "sha256:..." (только GitHub)
+}
+
+// Tag - тег репозитория
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Forge выполняет запросы к общему для GitHub и Gitea API репозитория
+type Forge struct {
+	forge   string
+	baseURL string
+	owner   string
+	repo    string
+	token   string
+}
+
+// NewForge создаёт клиент для репозитория owner/repo. Токен берётся
+// из GITHUB_TOKEN или GITEA_TOKEN в зависимости от платформы.
+func NewForge(forge, baseURL, owner, repo string) (*Forge, error) {
+	f := &Forge{
+		forge:   forge,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		owner:   url.PathEscape(owner),
+		repo:    url.PathEscape(repo),
+	}
+	switch forge {
+	case ForgeGitHub:
+		f.token = os.Getenv("GITHUB_TOKEN")
+	case ForgeGitea:
+		f.token = os.Getenv("GITEA_TOKEN")
+	default:
+		return nil, fmt.Errorf("unsupported forge: %s", forge)
+	}
+	return f, nil
+}
+
+// RepoURL возвращает адрес API репозитория
+func (f *Forge) RepoURL() string {
+	if f.forge == ForgeGitea {
+		return fmt.Sprintf("%s/api/v1/repos/%s/%s", f.baseURL, f.owner, f.repo)
+	}
+	return fmt.Sprintf("%s/repos/%s/%s", f.baseURL, f.owner, f.repo)
+}
+
+// ReleaseURL возвращает адрес API релиза с тегом tag или последнего релиза
+func (f *Forge) ReleaseURL(tag string) string {
+	suffix := "/releases/latest"
+	if tag != "" {
+		suffix = "/releases/tags/" + url.PathEscape(tag)
+	}
+	return f.RepoURL() + suffix
+}
+
+// Request выполняет GET-запрос. Токен передаётся только самому API,
+// но не серверам с файлами релизов.
+func (f *Forge) Request(ctx context.Context, rawURL string) (*http.Response, error) {
+	token := ""
+	if strings.HasPrefix(rawURL, f.baseURL+"/") {
+		token = f.token
+	}
+	res, err := Get(ctx, rawURL, token)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.forge, err)
+	}
+	return res, nil
+}
+
+func (f *Forge) getJSON(ctx context.Context, rawURL string, v any) error {
+	if err := GetJSON(ctx, rawURL, f.token, v); err != nil {
+		return fmt.Errorf("%s: %w", f.forge, err)
+	}
+	return nil
+}
+
+// Repo возвращает информацию о репозитории
+func (f *Forge) Repo(ctx context.Context) (*Repo, error) {
+	var repo Repo
+	if err := f.getJSON(ctx, f.RepoURL(), &repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+// Release возвращает релиз с тегом tag или последний релиз, если tag пуст
+func (f *Forge) Release(ctx context.Context, tag string) (*Release, error) {
+	var release Release
+	if err := f.getJSON(ctx, f.ReleaseURL(tag), &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// Tags возвращает теги репозитория
+func (f *Forge) Tags(ctx context.Context) ([]Tag, error) {
+	var tags []Tag
+	if err := f.getJSON(ctx, f.RepoURL()+"/tags", &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
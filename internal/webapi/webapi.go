@@ -0,0 +1,73 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package webapi содержит общий HTTP-клиент для API GitHub, Gitea и crates.io,
+// используемый генераторами шаблонов и проверкой версий upstream.
+package webapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// UserAgent передаётся во всех запросах, crates.io отклоняет запросы без него
+const UserAgent = "ALR-CLI/1.0 (https://alr-pkg.ru)"
+
+// ErrNotFound возвращается, если сервер ответил 404
+var ErrNotFound = errors.New("not found")
+
+// Get выполняет GET-запрос. Токен, если указан, передаётся в заголовке Authorization.
+// Ответ с кодом, отличным от 200, считается ошибкой.
+func Get(ctx context.Context, rawURL, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		if res.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%s: %w", rawURL, ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %s", rawURL, res.Status)
+	}
+	return res, nil
+}
+
+// GetJSON выполняет GET-запрос и декодирует ответ в v
+func GetJSON(ctx context.Context, rawURL, token string, v any) error {
+	res, err := Get(ctx, rawURL, token)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("%s: failed to decode response: %w", rawURL, err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package webapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForge(t *testing.T) {
+	var auth []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/owner/app/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		assert.Equal(t, UserAgent, r.UserAgent())
+		_, _ = w.Write([]byte(`{"tag_name":"v1.0","assets":[{"name":"app.tar.gz","browser_download_url":"https://files.example.com/app.tar.gz"}]}`))
+	})
+	mux.HandleFunc("/api/v1/repos/owner/app/tags", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"name":"v1.0"},{"name":"v0.9"}]`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	t.Setenv("GITEA_TOKEN", "secret")
+
+	api, err := NewForge(ForgeGitea, srv.URL+"/", "owner", "app")
+	require.NoError(t, err)
+
+	release, err := api.Release(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0", release.TagName)
+	assert.Equal(t, []Asset{{Name: "app.tar.gz", URL: "https://files.example.com/app.tar.gz"}}, release.Assets)
+	assert.Equal(t, []string{"token secret"}, auth)
+
+	tags, err := api.Tags(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Tag{{Name: "v1.0"}, {Name: "v0.9"}}, tags)
+
+	_, err = api.Release(context.Background(), "v2.0")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = NewForge("gitlab", srv.URL, "owner", "app")
+	assert.Error(t, err)
+}
+
+func TestForgeTokenScope(t *testing.T) {
+	var auth string
+	files := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	}))
+	t.Cleanup(files.Close)
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	api, err := NewForge(ForgeGitHub, "https://api.github.invalid", "owner", "app")
+	require.NoError(t, err)
+
+	// Токен не передаётся серверам с файлами релизов
+	res, err := api.Request(context.Background(), files.URL+"/app.tar.gz")
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Empty(t, auth)
+}
+
+func TestFetchCrate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/crates/ripgrep" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"crate":{"name":"ripgrep","max_stable_version":"14.1.1"},"versions":[{"num":"14.1.1","bin_names":["rg"]}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	crate, err := FetchCrate(context.Background(), srv.URL, "ripgrep")
+	require.NoError(t, err)
+	assert.Equal(t, "14.1.1", crate.Crate.MaxStableVersion)
+	assert.Equal(t, []CrateVersion{{Num: "14.1.1", BinNames: []string{"rg"}}}, crate.Versions)
+
+	_, err = FetchCrate(context.Background(), srv.URL, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorContains(t, err, "crate 'missing' not found on crates.io")
+}
@@ -0,0 +1,129 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v2"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/build"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
+	appbuilder "git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils/app_builder"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/manager"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/signing"
+)
+
+func KeysCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "keys",
+		Usage: gotext.Get("Manage package signing keys"),
+		Subcommands: []*cli.Command{
+			KeysExportCmd(),
+			KeysHelpCmd(),
+		},
+	}
+}
+
+func KeysHelpCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "help",
+		Aliases:   []string{"h"},
+		Usage:     gotext.Get("Shows a list of commands or help for one command"),
+		ArgsUsage: "[command]",
+		Action: func(cCtx *cli.Context) error {
+			args := cCtx.Args()
+			if args.Present() {
+				return cli.ShowCommandHelp(cCtx, args.First())
+			}
+			cli.ShowSubcommandHelp(cCtx)
+			return nil
+		},
+	}
+}
+
+func KeysExportCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: gotext.Get("Export the public signing key for the package manager"),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   gotext.Get("Package format: deb, rpm or apk (default: the format of this system)"),
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   gotext.Get("Write the key to a file instead of stdout"),
+			},
+		},
+		Action: func(c *cli.Context) error {
+			deps, err := appbuilder.
+				New(c.Context).
+				WithConfig().
+				Build()
+			if err != nil {
+				return err
+			}
+			defer deps.Defer()
+
+			format := c.String("format")
+			if format == "" {
+				mgr := manager.Detect()
+				if mgr == nil {
+					return cliutils.FormatCliExit(gotext.Get("Unable to detect a supported package manager on the system"), nil)
+				}
+				format = build.GetPkgFormat(mgr)
+			}
+
+			cfg := deps.Cfg.Signing()
+			var buf bytes.Buffer
+			var hint string
+			switch format {
+			case "deb":
+				err = signing.ExportPGP(&buf, cfg, false)
+				hint = gotext.Get("Install the key to /etc/apt/trusted.gpg.d/alr.gpg")
+			case "rpm":
+				err = signing.ExportPGP(&buf, cfg, true)
+				hint = gotext.Get("Import the key with rpm --import")
+			case "apk":
+				err = signing.ExportRSA(&buf, cfg)
+				hint = gotext.Get("Install the key to %s", "/etc/apk/keys/"+signing.APKKeyName(cfg))
+			default:
+				return cliutils.FormatCliExit(gotext.Get("Package format %s does not support signing", format), nil)
+			}
+			if err != nil {
+				return cliutils.FormatCliExit(gotext.Get("Error exporting signing key"), err)
+			}
+
+			if output := c.String("output"); output != "" {
+				if err := os.WriteFile(output, buf.Bytes(), 0o644); err != nil {
+					return cliutils.FormatCliExit(gotext.Get("Error writing key"), err)
+				}
+			} else if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+				return cliutils.FormatCliExit(gotext.Get("Error writing key"), err)
+			}
+
+			fmt.Fprintln(os.Stderr, hint)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,111 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v2"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/config"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/lint"
+)
+
+func LintCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "lint",
+		Usage:     gotext.Get("Check alr.sh scripts for mistakes without running them"),
+		ArgsUsage: gotext.Get("[path...]"),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Value:   lint.FormatText,
+				Usage:   gotext.Get("Output format: text, json or sarif"),
+			},
+			&cli.BoolFlag{
+				Name:  "strict",
+				Usage: gotext.Get("Exit with an error code on warnings too"),
+			},
+		},
+		Action: func(c *cli.Context) error {
+			paths := c.Args().Slice()
+			if len(paths) == 0 {
+				paths = []string{"alr.sh"}
+			}
+
+			scripts, err := findScripts(paths)
+			if err != nil {
+				return cliutils.FormatCliExitWithCode(gotext.Get("Error finding scripts"), err, 2)
+			}
+
+			var diags []lint.Diagnostic
+			for _, script := range scripts {
+				found, err := lint.LintFile(script)
+				if err != nil {
+					return cliutils.FormatCliExitWithCode(gotext.Get("Error reading script"), err, 2)
+				}
+				diags = append(diags, found...)
+			}
+
+			if err := lint.Write(os.Stdout, c.String("output"), config.Version, diags); err != nil {
+				return cliutils.FormatCliExitWithCode(gotext.Get("Error writing results"), err, 2)
+			}
+
+			if lint.HasErrors(diags) || c.Bool("strict") && len(diags) > 0 {
+				return cli.Exit("", 1)
+			}
+			return nil
+		},
+	}
+}
+
+// findScripts раскрывает каталоги в список файлов alr.sh внутри них
+func findScripts(paths []string) ([]string, error) {
+	var out []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			out = append(out, p)
+			continue
+		}
+
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if !d.IsDir() && d.Name() == "alr.sh" {
+				out = append(out, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
@@ -27,6 +27,7 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/hashicorp/go-plugin"
 	"github.com/leonelquinteros/gotext"
 	"github.com/mattn/go-isatty"
 	"github.com/urfave/cli/v2"
@@ -86,15 +87,23 @@ func GetApp() *cli.App {
 		Commands: []*cli.Command{
 			InstallCmd(),
 			RemoveCmd(),
+			AutoremoveCmd(),
 			UpgradeCmd(),
 			InfoCmd(),
 			ListCmd(),
 			BuildCmd(),
+			InspectCmd(),
+			DiffCmd(),
 			LegacyAddRepoCmd(),
 			LegacyRemoveRepoCmd(),
 			RefreshCmd(),
 			FixCmd(),
 			GenCmd(),
+			LintCmd(),
+			FmtCmd(),
+			OutdatedCmd(),
+			UpdsumsCmd(),
+			KeysCmd(),
 			HelperCmd(),
 			VersionCmd(),
 			SearchCmd(),
@@ -117,6 +126,11 @@ func GetApp() *cli.App {
 		},
 		EnableBashCompletion: true,
 		ExitErrHandler: func(cCtx *cli.Context, err error) {
+			// HandleExitCoder завершает процесс через os.Exit, и отложенные
+			// вызовы main не выполняются, поэтому плагины завершаются здесь
+			if err != nil {
+				plugin.CleanupClients()
+			}
 			cliutils.HandleExitCoder(err)
 		},
 	}
@@ -179,6 +193,9 @@ func main() {
 	cli.SubcommandHelpTemplate = cliutils.GetSubcommandHelpTemplate()
 	cli.HelpFlag.(*cli.BoolFlag).Usage = gotext.Get("Show help")
 
+	// Завершаем процессы внешних плагинов менеджеров пакетов
+	defer plugin.CleanupClients()
+
 	err = app.RunContext(ctx, os.Args)
 	if err != nil {
 		slog.Error(gotext.Get("Error while running app"), "err", err)
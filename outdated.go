@@ -0,0 +1,127 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v2"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
+	appbuilder "git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils/app_builder"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/repos"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/updsums"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/upstream"
+)
+
+func OutdatedCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "outdated",
+		Usage:     gotext.Get("Check packages for newer upstream versions"),
+		ArgsUsage: gotext.Get("[repo|dir]"),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "update",
+				Usage: gotext.Get("Update version, release and checksums in outdated scripts"),
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   gotext.Get("Format output using a Go template"),
+			},
+		},
+		Action: func(c *cli.Context) error {
+			ctx := c.Context
+
+			deps, err := appbuilder.
+				New(ctx).
+				WithConfig().
+				WithDistroInfo().
+				Build()
+			if err != nil {
+				return err
+			}
+			defer deps.Defer()
+
+			// Аргумент - каталог с репозиторием или имя репозитория из конфигурации
+			var dirs []string
+			arg := c.Args().First()
+			if fi, err := os.Stat(arg); arg != "" && err == nil && fi.IsDir() {
+				dirs = append(dirs, arg)
+			} else {
+				for _, repo := range deps.Cfg.Repos() {
+					if arg == "" || repo.Name == arg {
+						dirs = append(dirs, filepath.Join(deps.Cfg.GetPaths().RepoDir, repo.Name))
+					}
+				}
+				if len(dirs) == 0 {
+					return cliutils.FormatCliExit(gotext.Get("Repo \"%s\" does not exist", arg), nil)
+				}
+			}
+
+			format := c.String("format")
+			if format == "" {
+				format = "{{.Name}} {{.Current}} -> {{.Latest}}\n"
+			}
+			tmpl, err := template.New("format").Parse(format)
+			if err != nil {
+				return cliutils.FormatCliExit(gotext.Get("Error parsing format template"), err)
+			}
+
+			// Ошибка обновления одного скрипта не прерывает проверку остальных
+			failed := 0
+			for _, dir := range dirs {
+				scripts, err := repos.FindScripts(dir)
+				if err != nil {
+					return cliutils.FormatCliExit(gotext.Get("Error finding scripts"), err)
+				}
+
+				for _, script := range scripts {
+					res, err := upstream.CheckScript(ctx, script, deps.Info)
+					if err != nil {
+						slog.Warn(gotext.Get("Error checking upstream version"), "script", script, "err", err)
+						continue
+					}
+					if !res.Outdated() {
+						continue
+					}
+
+					if err := tmpl.Execute(os.Stdout, res); err != nil {
+						return cliutils.FormatCliExit(gotext.Get("Error executing template"), err)
+					}
+
+					if c.Bool("update") {
+						err := upstream.UpdateScript(ctx, script, res.Latest, updsums.Options{Info: deps.Info, Progress: os.Stderr})
+						if err != nil {
+							slog.Error(gotext.Get("Error updating script"), "script", script, "err", err)
+							failed++
+						}
+					}
+				}
+			}
+
+			if failed > 0 {
+				return cliutils.FormatCliExit(gotext.Get("Failed to update %d outdated scripts", failed), nil)
+			}
+			return nil
+		},
+	}
+}
@@ -44,7 +44,7 @@ type ScriptFile struct {
 	path string
 }
 
-func createBuildEnvVars(info *distro.OSRelease, dirs types.Directories) []string {
+func createBuildEnvVars(info *distro.OSRelease, arch string, dirs types.Directories) []string {
 	env := os.Environ()
 
 	env = append(
@@ -54,9 +54,9 @@ func createBuildEnvVars(info *distro.OSRelease, dirs types.Directories) []string
 		"DISTRO_ID="+info.ID,
 		"DISTRO_VERSION_ID="+info.VersionID,
 		"DISTRO_ID_LIKE="+strings.Join(info.Like, " "),
-		"ARCH="+cpu.Arch(),
 		"NCPU="+strconv.Itoa(runtime.NumCPU()),
 	)
+	env = append(env, cpu.TargetEnv(arch, info.IsMusl(), arch != cpu.Arch())...)
 
 	if dirs.ScriptDir != "" {
 		env = append(env, "scriptdir="+dirs.ScriptDir)
@@ -70,11 +70,26 @@ func createBuildEnvVars(info *distro.OSRelease, dirs types.Directories) []string
 		env = append(env, "srcdir="+dirs.SrcDir)
 	}
 
+	if dirs.StageDir != "" {
+		env = append(env, "stagedir="+dirs.StageDir)
+	}
+
 	return env
 }
 
 func (s *ScriptFile) ParseBuildVars(ctx context.Context, info *distro.OSRelease, packages []string) (string, []*Package, error) {
-	runner, err := s.createRunner(info)
+	return s.ParseBuildVarsForArch(ctx, info, "", packages)
+}
+
+// ParseBuildVarsForArch разбирает переменные скрипта для сборки под
+// архитектуру arch: переопределения разрешаются для неё, а ARCH, CHOST
+// и другие переменные описывают её. Пустая arch означает архитектуру системы.
+func (s *ScriptFile) ParseBuildVarsForArch(ctx context.Context, info *distro.OSRelease, arch string, packages []string) (string, []*Package, error) {
+	if arch == "" {
+		arch = cpu.Arch()
+	}
+
+	runner, err := s.createRunner(info, arch)
 	if err != nil {
 		return "", nil, err
 	}
@@ -84,6 +99,7 @@ func (s *ScriptFile) ParseBuildVars(ctx context.Context, info *distro.OSRelease,
 	}
 
 	dec := newDecoder(info, runner)
+	dec.Arch = arch
 
 	pkgNames, err := ParseNames(dec)
 	if err != nil {
@@ -112,9 +128,9 @@ func (s *ScriptFile) ParseBuildVars(ctx context.Context, info *distro.OSRelease,
 	return baseName, varsOfPackages, nil
 }
 
-func (s *ScriptFile) createRunner(info *distro.OSRelease) (*interp.Runner, error) {
+func (s *ScriptFile) createRunner(info *distro.OSRelease, arch string) (*interp.Runner, error) {
 	scriptDir := filepath.Dir(s.path)
-	env := createBuildEnvVars(info, types.Directories{ScriptDir: scriptDir})
+	env := createBuildEnvVars(info, arch, types.Directories{ScriptDir: scriptDir})
 
 	return interp.New(
 		interp.Env(expand.ListEnviron(env...)),
@@ -190,6 +206,7 @@ func (s *ScriptFile) createPackageFromMeta(
 	}
 
 	metaDecoder := decoder.New(dec.Info(), metaRunner)
+	metaDecoder.Arch = dec.Arch
 
 	var vars Package
 	if err := metaDecoder.DecodeVars(&vars); err != nil {
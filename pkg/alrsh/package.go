@@ -54,23 +54,29 @@ type Package struct {
 	Provides      []string `sh:"provides" xorm:"json 'provides'" json:"provides"`
 	Conflicts     []string `sh:"conflicts" xorm:"json 'conflicts'" json:"conflicts"`
 	Replaces      []string `sh:"replaces" xorm:"json 'replaces'" json:"replaces"`
-
-	Summary          OverridableField[string]   `sh:"summary" xorm:"'summary'" json:"summary"`
-	Description      OverridableField[string]   `sh:"desc" xorm:"'description'" json:"description"`
-	Group            OverridableField[string]   `sh:"group" xorm:"'group_name'" json:"group"`
-	Homepage         OverridableField[string]   `sh:"homepage" xorm:"'homepage'" json:"homepage"`
-	Maintainer       OverridableField[string]   `sh:"maintainer" xorm:"'maintainer'" json:"maintainer"`
-	Depends          OverridableField[[]string] `sh:"deps" xorm:"'depends'" json:"deps"`
-	BuildDepends     OverridableField[[]string] `sh:"build_deps" xorm:"'builddepends'" json:"build_deps"`
-	OptDepends       OverridableField[[]string] `sh:"opt_deps" xorm:"'optdepends'" json:"opt_deps,omitempty"`
-	Sources          OverridableField[[]string] `sh:"sources" xorm:"-" json:"sources"`
-	Checksums        OverridableField[[]string] `sh:"checksums" xorm:"-" json:"checksums,omitempty"`
-	Backup           OverridableField[[]string] `sh:"backup" xorm:"-" json:"backup"`
-	Scripts          OverridableField[Scripts]  `sh:"scripts" xorm:"-" json:"scripts,omitempty"`
-	AutoReq          OverridableField[[]string] `sh:"auto_req" xorm:"-" json:"auto_req"`
-	AutoProv         OverridableField[[]string] `sh:"auto_prov" xorm:"-" json:"auto_prov"`
-	AutoReqSkipList  OverridableField[[]string] `sh:"auto_req_skiplist" xorm:"-" json:"auto_req_skiplist,omitempty"`
-	AutoProvSkipList OverridableField[[]string] `sh:"auto_prov_skiplist" xorm:"-" json:"auto_prov_skiplist,omitempty"`
+	Upstream      string   `sh:"upstream" xorm:"'upstream'" json:"upstream,omitempty"`
+
+	Summary              OverridableField[string]   `sh:"summary" xorm:"'summary'" json:"summary"`
+	Description          OverridableField[string]   `sh:"desc" xorm:"'description'" json:"description"`
+	Group                OverridableField[string]   `sh:"group" xorm:"'group_name'" json:"group"`
+	Homepage             OverridableField[string]   `sh:"homepage" xorm:"'homepage'" json:"homepage"`
+	Maintainer           OverridableField[string]   `sh:"maintainer" xorm:"'maintainer'" json:"maintainer"`
+	Depends              OverridableField[[]string] `sh:"deps" xorm:"'depends'" json:"deps"`
+	BuildDepends         OverridableField[[]string] `sh:"build_deps" xorm:"'builddepends'" json:"build_deps"`
+	OptDepends           OverridableField[[]string] `sh:"opt_deps" xorm:"'optdepends'" json:"opt_deps,omitempty"`
+	MultilibBuildDepends OverridableField[[]string] `sh:"multilib_build_deps" xorm:"-" json:"multilib_build_deps,omitempty"`
+	Sources              OverridableField[[]string] `sh:"sources" xorm:"-" json:"sources"`
+	Checksums            OverridableField[[]string] `sh:"checksums" xorm:"-" json:"checksums,omitempty"`
+	Backup               OverridableField[[]string] `sh:"backup" xorm:"-" json:"backup"`
+	Scripts              OverridableField[Scripts]  `sh:"scripts" xorm:"-" json:"scripts,omitempty"`
+	AutoReq              OverridableField[[]string] `sh:"auto_req" xorm:"-" json:"auto_req"`
+	AutoProv             OverridableField[[]string] `sh:"auto_prov" xorm:"-" json:"auto_prov"`
+	AutoReqSkipList      OverridableField[[]string] `sh:"auto_req_skiplist" xorm:"-" json:"auto_req_skiplist,omitempty"`
+	AutoProvSkipList     OverridableField[[]string] `sh:"auto_prov_skiplist" xorm:"-" json:"auto_prov_skiplist,omitempty"`
+	Options              OverridableField[[]string] `sh:"options" xorm:"-" json:"options,omitempty"`
+	SystemdUnits         OverridableField[[]string] `sh:"systemd_units" xorm:"-" json:"systemd_units,omitempty"`
+	SysUsers             OverridableField[[]string] `sh:"sysusers" xorm:"-" json:"sysusers,omitempty"`
+	TmpFiles             OverridableField[[]string] `sh:"tmpfiles" xorm:"-" json:"tmpfiles,omitempty"`
 
 	FireJailed       OverridableField[bool]              `sh:"firejailed" xorm:"-" json:"firejailed"`
 	FireJailProfiles OverridableField[map[string]string] `sh:"firejail_profiles" xorm:"-" json:"firejail_profiles,omitempty"`
@@ -18,68 +18,80 @@
 package alrsh
 
 type packageResolved struct {
-	Repository       string            `json:"repository"`
-	Name             string            `json:"name"`
-	BasePkgName      string            `json:"basepkg_name"`
-	Version          string            `json:"version"`
-	Release          int               `json:"release"`
-	Epoch            uint              `json:"epoch"`
-	Architectures    []string          `json:"architectures"`
-	Licenses         []string          `json:"license"`
-	Provides         []string          `json:"provides"`
-	Conflicts        []string          `json:"conflicts"`
-	Replaces         []string          `json:"replaces"`
-	Summary          string            `json:"summary"`
-	Description      string            `json:"description"`
-	Group            string            `json:"group"`
-	Homepage         string            `json:"homepage"`
-	Maintainer       string            `json:"maintainer"`
-	Depends          []string          `json:"deps"`
-	BuildDepends     []string          `json:"build_deps"`
-	OptDepends       []string          `json:"opt_deps,omitempty"`
-	Sources          []string          `json:"sources"`
-	Checksums        []string          `json:"checksums,omitempty"`
-	Backup           []string          `json:"backup"`
-	Scripts          Scripts           `json:"scripts,omitempty"`
-	AutoReq          []string          `json:"auto_req"`
-	AutoProv         []string          `json:"auto_prov"`
-	AutoReqSkipList  []string          `json:"auto_req_skiplist,omitempty"`
-	AutoProvSkipList []string          `json:"auto_prov_skiplist,omitempty"`
-	FireJailed       bool              `json:"firejailed"`
-	FireJailProfiles map[string]string `json:"firejail_profiles,omitempty"`
+	Repository           string            `json:"repository"`
+	Name                 string            `json:"name"`
+	BasePkgName          string            `json:"basepkg_name"`
+	Version              string            `json:"version"`
+	Release              int               `json:"release"`
+	Epoch                uint              `json:"epoch"`
+	Architectures        []string          `json:"architectures"`
+	Licenses             []string          `json:"license"`
+	Provides             []string          `json:"provides"`
+	Conflicts            []string          `json:"conflicts"`
+	Replaces             []string          `json:"replaces"`
+	Upstream             string            `json:"upstream,omitempty"`
+	Summary              string            `json:"summary"`
+	Description          string            `json:"description"`
+	Group                string            `json:"group"`
+	Homepage             string            `json:"homepage"`
+	Maintainer           string            `json:"maintainer"`
+	Depends              []string          `json:"deps"`
+	BuildDepends         []string          `json:"build_deps"`
+	OptDepends           []string          `json:"opt_deps,omitempty"`
+	MultilibBuildDepends []string          `json:"multilib_build_deps,omitempty"`
+	Sources              []string          `json:"sources"`
+	Checksums            []string          `json:"checksums,omitempty"`
+	Backup               []string          `json:"backup"`
+	Scripts              Scripts           `json:"scripts,omitempty"`
+	AutoReq              []string          `json:"auto_req"`
+	AutoProv             []string          `json:"auto_prov"`
+	AutoReqSkipList      []string          `json:"auto_req_skiplist,omitempty"`
+	AutoProvSkipList     []string          `json:"auto_prov_skiplist,omitempty"`
+	Options              []string          `json:"options,omitempty"`
+	SystemdUnits         []string          `json:"systemd_units,omitempty"`
+	SysUsers             []string          `json:"sysusers,omitempty"`
+	TmpFiles             []string          `json:"tmpfiles,omitempty"`
+	FireJailed           bool              `json:"firejailed"`
+	FireJailProfiles     map[string]string `json:"firejail_profiles,omitempty"`
 }
 
 func PackageToResolved(src *Package) packageResolved {
 	return packageResolved{
-		Repository:       src.Repository,
-		Name:             src.Name,
-		BasePkgName:      src.BasePkgName,
-		Version:          src.Version,
-		Release:          src.Release,
-		Epoch:            src.Epoch,
-		Architectures:    src.Architectures,
-		Licenses:         src.Licenses,
-		Provides:         src.Provides,
-		Conflicts:        src.Conflicts,
-		Replaces:         src.Replaces,
-		Summary:          src.Summary.Resolved(),
-		Description:      src.Description.Resolved(),
-		Group:            src.Group.Resolved(),
-		Homepage:         src.Homepage.Resolved(),
-		Maintainer:       src.Maintainer.Resolved(),
-		Depends:          src.Depends.Resolved(),
-		BuildDepends:     src.BuildDepends.Resolved(),
-		OptDepends:       src.OptDepends.Resolved(),
-		Sources:          src.Sources.Resolved(),
-		Checksums:        src.Checksums.Resolved(),
-		Backup:           src.Backup.Resolved(),
-		Scripts:          src.Scripts.Resolved(),
-		AutoReq:          src.AutoReq.Resolved(),
-		AutoProv:         src.AutoProv.Resolved(),
-		AutoReqSkipList:  src.AutoReqSkipList.Resolved(),
-		AutoProvSkipList: src.AutoProvSkipList.Resolved(),
-		FireJailed:       src.FireJailed.Resolved(),
-		FireJailProfiles: src.FireJailProfiles.Resolved(),
+		Repository:           src.Repository,
+		Name:                 src.Name,
+		BasePkgName:          src.BasePkgName,
+		Version:              src.Version,
+		Release:              src.Release,
+		Epoch:                src.Epoch,
+		Architectures:        src.Architectures,
+		Licenses:             src.Licenses,
+		Provides:             src.Provides,
+		Conflicts:            src.Conflicts,
+		Replaces:             src.Replaces,
+		Upstream:             src.Upstream,
+		Summary:              src.Summary.Resolved(),
+		Description:          src.Description.Resolved(),
+		Group:                src.Group.Resolved(),
+		Homepage:             src.Homepage.Resolved(),
+		Maintainer:           src.Maintainer.Resolved(),
+		Depends:              src.Depends.Resolved(),
+		BuildDepends:         src.BuildDepends.Resolved(),
+		OptDepends:           src.OptDepends.Resolved(),
+		MultilibBuildDepends: src.MultilibBuildDepends.Resolved(),
+		Sources:              src.Sources.Resolved(),
+		Checksums:            src.Checksums.Resolved(),
+		Backup:               src.Backup.Resolved(),
+		Scripts:              src.Scripts.Resolved(),
+		AutoReq:              src.AutoReq.Resolved(),
+		AutoProv:             src.AutoProv.Resolved(),
+		AutoReqSkipList:      src.AutoReqSkipList.Resolved(),
+		AutoProvSkipList:     src.AutoProvSkipList.Resolved(),
+		Options:              src.Options.Resolved(),
+		SystemdUnits:         src.SystemdUnits.Resolved(),
+		SysUsers:             src.SysUsers.Resolved(),
+		TmpFiles:             src.TmpFiles.Resolved(),
+		FireJailed:           src.FireJailed.Resolved(),
+		FireJailProfiles:     src.FireJailProfiles.Resolved(),
 	}
 }
 
@@ -92,6 +104,7 @@ func ResolvePackage(pkg *Package, overrides []string) {
 	pkg.Depends.Resolve(overrides)
 	pkg.BuildDepends.Resolve(overrides)
 	pkg.OptDepends.Resolve(overrides)
+	pkg.MultilibBuildDepends.Resolve(overrides)
 	pkg.Sources.Resolve(overrides)
 	pkg.Checksums.Resolve(overrides)
 	pkg.Backup.Resolve(overrides)
@@ -100,6 +113,10 @@ func ResolvePackage(pkg *Package, overrides []string) {
 	pkg.AutoProv.Resolve(overrides)
 	pkg.AutoReqSkipList.Resolve(overrides)
 	pkg.AutoProvSkipList.Resolve(overrides)
+	pkg.Options.Resolve(overrides)
+	pkg.SystemdUnits.Resolve(overrides)
+	pkg.SysUsers.Resolve(overrides)
+	pkg.TmpFiles.Resolve(overrides)
 	pkg.FireJailed.Resolve(overrides)
 	pkg.FireJailProfiles.Resolve(overrides)
 }
@@ -273,6 +273,8 @@ func TestDependency_ForManager(t *testing.T) {
 		{"apk", "gcc>=5.0"},
 		{"zypper", "gcc >= 5.0"},
 		{"apt-rpm", "gcc >= 5.0"},
+		{"xbps", "gcc>=5.0"},
+		{"opkg", "gcc"},
 		{"unknown", "gcc>=5.0"},
 	}
 
@@ -304,6 +306,8 @@ func TestDependency_ForNfpm(t *testing.T) {
 		{"rpm", "gcc >= 5.0"},
 		{"apk", "gcc>=5.0"},
 		{"archlinux", "gcc>=5.0"},
+		{"ipk", "gcc (>= 5.0)"},
+		{"xbps", "gcc>=5.0"},
 		{"unknown", "gcc>=5.0"},
 	}
 
@@ -27,6 +27,8 @@ import "fmt"
 //	apk (Alpine):      "gcc>=5.0" (no changes)
 //	zypper (openSUSE): "gcc >= 5.0" (with spaces)
 //	apt-rpm (ALT):     "gcc >= 5.0" (with spaces)
+//	xbps (Void):       "gcc>=5.0" (no changes)
+//	opkg (OpenWrt):    "gcc" (version ignored for install command)
 func (d Dependency) ForManager(managerName string) string {
 	if d.Name == "" {
 		return ""
@@ -43,6 +45,10 @@ func (d Dependency) ForManager(managerName string) string {
 		// Versions are checked after installation
 		return d.Name
 
+	case "opkg":
+		// opkg doesn't support version constraints in 'opkg install' command either
+		return d.Name
+
 	case "pacman":
 		// Pacman uses PKGBUILD-style: package>=version (no spaces)
 		return fmt.Sprintf("%s%s%s", d.Name, d.Operator, d.Version)
@@ -51,6 +57,10 @@ func (d Dependency) ForManager(managerName string) string {
 		// Alpine APK uses similar syntax to pacman
 		return fmt.Sprintf("%s%s%s", d.Name, d.Operator, d.Version)
 
+	case "xbps":
+		// xbps-install accepts package patterns: package>=version (no spaces)
+		return fmt.Sprintf("%s%s%s", d.Name, d.Operator, d.Version)
+
 	case "dnf", "yum":
 		// DNF/YUM use RPM-style: "package >= version" (with spaces)
 		return fmt.Sprintf("%s %s %s", d.Name, d.Operator, d.Version)
@@ -87,6 +97,8 @@ func ForManagerMultiple(deps []Dependency, managerName string) []string {
 //	rpm:       "package >= version"
 //	apk:       "package>=version"
 //	archlinux: "package>=version"
+//	ipk:       "package (>= version)"
+//	xbps:      "package>=version"
 func (d Dependency) ForNfpm(pkgFormat string) string {
 	if d.Name == "" {
 		return ""
@@ -98,8 +110,8 @@ func (d Dependency) ForNfpm(pkgFormat string) string {
 	}
 
 	switch pkgFormat {
-	case "deb":
-		// Debian uses: package (>= version)
+	case "deb", "ipk":
+		// Debian and opkg use: package (>= version)
 		return fmt.Sprintf("%s (%s %s)", d.Name, d.Operator, d.Version)
 
 	case "rpm":
@@ -114,6 +126,10 @@ func (d Dependency) ForNfpm(pkgFormat string) string {
 		// Arch uses: package>=version
 		return fmt.Sprintf("%s%s%s", d.Name, d.Operator, d.Version)
 
+	case "xbps":
+		// Void uses package patterns: package>=version
+		return fmt.Sprintf("%s%s%s", d.Name, d.Operator, d.Version)
+
 	default:
 		// Default: no spaces
 		return fmt.Sprintf("%s%s%s", d.Name, d.Operator, d.Version)
@@ -121,3 +121,37 @@ func ParseOSRelease(ctx context.Context) (*OSRelease, error) {
 	parsed = out
 	return out, nil
 }
+
+// IsMusl reports whether the distribution is based on the musl C library
+func (o *OSRelease) IsMusl() bool {
+	return o.isLike("alpine")
+}
+
+// MultilibLibDir returns the directory for 32-bit x86 libraries
+// on a 64-bit x86 installation of the distribution
+func (o *OSRelease) MultilibLibDir() string {
+	switch {
+	case o.isLike("debian", "ubuntu"):
+		return "/usr/lib/i386-linux-gnu"
+	case o.isLike("arch", "void"):
+		return "/usr/lib32"
+	default:
+		// Fedora, openSUSE and other lib64 distributions keep 32-bit libraries in /usr/lib
+		return "/usr/lib"
+	}
+}
+
+// isLike reports whether the distribution is one of ids or is based on one of them
+func (o *OSRelease) isLike(ids ...string) bool {
+	for _, id := range ids {
+		if o.ID == id {
+			return true
+		}
+		for _, like := range o.Like {
+			if like == id {
+				return true
+			}
+		}
+	}
+	return false
+}
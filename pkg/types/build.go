@@ -22,6 +22,16 @@ package types
 type BuildOpts struct {
 	Clean       bool
 	Interactive bool
+	// Архитектура, для которой собирается пакет (пусто - архитектура системы)
+	TargetArch string
+	// Корневая файловая система целевой архитектуры, в которой через qemu-user
+	// собираются скрипты без поддержки кросс-компиляции (пусто - не эмулировать)
+	EmulateRoot string
+	// Собирать 32-битный (lib32/multilib) вариант пакета на x86_64
+	Multilib bool
+	// Форматы пакетов, в которые упаковывается результат одной сборки
+	// (пусто - только формат системы)
+	Formats []string
 }
 
 type Scripts struct {
@@ -40,4 +50,7 @@ type Directories struct {
 	SrcDir    string
 	PkgDir    string
 	ScriptDir string
+	// StageDir - общий каталог установки, из которого split-files
+	// раскладывает файлы по подпакетам
+	StageDir string
 }
@@ -21,15 +21,30 @@ package types
 
 // Config represents the ALR configuration file
 type Config struct {
-	RootCmd               string   `json:"rootCmd" koanf:"rootCmd"`
-	UseRootCmd            bool     `json:"useRootCmd" koanf:"useRootCmd"`
-	PagerStyle            string   `json:"pagerStyle" koanf:"pagerStyle"`
-	IgnorePkgUpdates      []string `json:"ignorePkgUpdates" koanf:"ignorePkgUpdates"`
-	Repos                 []Repo   `json:"repo" koanf:"repo"`
-	AutoPull              bool     `json:"autoPull" koanf:"autoPull"`
-	LogLevel              string   `json:"logLevel" koanf:"logLevel"`
-	UpdateSystemOnUpgrade bool     `json:"updateSystemOnUpgrade" koanf:"updateSystemOnUpgrade"`
-	PreferALRDeps         bool     `json:"preferALRDeps" koanf:"preferALRDeps"`
+	RootCmd               string        `json:"rootCmd" koanf:"rootCmd"`
+	UseRootCmd            bool          `json:"useRootCmd" koanf:"useRootCmd"`
+	PagerStyle            string        `json:"pagerStyle" koanf:"pagerStyle"`
+	IgnorePkgUpdates      []string      `json:"ignorePkgUpdates" koanf:"ignorePkgUpdates"`
+	Repos                 []Repo        `json:"repo" koanf:"repo"`
+	AutoPull              bool          `json:"autoPull" koanf:"autoPull"`
+	LogLevel              string        `json:"logLevel" koanf:"logLevel"`
+	UpdateSystemOnUpgrade bool          `json:"updateSystemOnUpgrade" koanf:"updateSystemOnUpgrade"`
+	PreferALRDeps         bool          `json:"preferALRDeps" koanf:"preferALRDeps"`
+	Signing               SigningConfig `json:"signing" koanf:"signing"`
+}
+
+// SigningConfig represents the keys used to sign built packages
+type SigningConfig struct {
+	// KeyFile is a PGP secret key used for deb and rpm packages
+	KeyFile string `json:"keyFile" koanf:"keyFile"`
+	// KeyID selects a key from KeyFile if it contains several keys
+	KeyID string `json:"keyId" koanf:"keyId"`
+	// APKKeyFile is an RSA private key in PEM format used for apk packages
+	APKKeyFile string `json:"apkKeyFile" koanf:"apkKeyFile"`
+	// APKKeyName is the name of the public key in /etc/apk/keys
+	APKKeyName string `json:"apkKeyName" koanf:"apkKeyName"`
+	// Passphrase is the source of the key passphrase: env:NAME or file:PATH
+	Passphrase string `json:"passphrase" koanf:"passphrase"`
 }
 
 // Repo represents a ALR repo within a configuration file
@@ -0,0 +1,499 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package xbps implements nfpm.Packager for binary packages of the
+// X Binary Package System used by Void Linux.
+//
+// A .xbps file is a compressed tarball that starts with the metadata
+// entries (INSTALL, REMOVE, props.plist and files.plist) followed by
+// the package payload.
+package xbps
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/goreleaser/nfpm/v2/files"
+)
+
+const packagerName = "xbps"
+
+func init() {
+	nfpm.RegisterPackager(packagerName, Default)
+}
+
+var archToXBPS = map[string]string{
+	"all":     "noarch",
+	"amd64":   "x86_64",
+	"386":     "i686",
+	"arm64":   "aarch64",
+	"arm5":    "armv5tel",
+	"arm6":    "armv6l",
+	"arm7":    "armv7l",
+	"ppc64le": "ppc64le",
+	"riscv64": "riscv64",
+}
+
+// RegexpPkgver соответствует строке pkgver xbps вида "name-version_revision"
+var RegexpPkgver = regexp.MustCompile(`^(?P<name>.+)-(?P<version>[^-_]+)_(?P<revision>\d+)$`)
+
+var versionReplacer = strings.NewReplacer("-", ".", "_", ".")
+
+func ensureValidArch(info *nfpm.Info) *nfpm.Info {
+	if arch, ok := archToXBPS[info.Arch]; ok {
+		info.Arch = arch
+	}
+	return info
+}
+
+// Default xbps packager
+var Default = &XBPS{}
+
+// XBPS is a xbps packager implementation
+type XBPS struct{}
+
+// Version возвращает версию пакета в формате xbps "version_revision".
+// xbps не поддерживает эпохи, а дефисы и подчёркивания в версии зарезервированы,
+// поэтому эпоха отбрасывается, а эти символы заменяются точками.
+// Ревизия должна быть числом, по умолчанию 1.
+func Version(info *nfpm.Info) string {
+	version := versionReplacer.Replace(info.Version)
+	if info.Prerelease != "" {
+		version += "." + versionReplacer.Replace(info.Prerelease)
+	}
+
+	revision := 1
+	if r, err := strconv.Atoi(info.Release); err == nil && r > 0 {
+		revision = r
+	}
+
+	return fmt.Sprintf("%s_%d", version, revision)
+}
+
+// ConventionalFileName returns a file name according
+// to the conventions for xbps packages: name-version_revision.arch.xbps
+func (*XBPS) ConventionalFileName(info *nfpm.Info) string {
+	info = ensureValidArch(info)
+	return fmt.Sprintf("%s-%s.%s.xbps", info.Name, Version(info), info.Arch)
+}
+
+// ConventionalExtension returns the file name conventionally used for xbps packages
+func (*XBPS) ConventionalExtension() string {
+	return ".xbps"
+}
+
+// Package writes a new xbps package to the given writer using the given info.
+func (*XBPS) Package(info *nfpm.Info, w io.Writer) error {
+	info = ensureValidArch(info)
+
+	if err := nfpm.PrepareForPackager(info, packagerName); err != nil {
+		return err
+	}
+
+	mtime := info.MTime
+	if mtime.IsZero() {
+		mtime = time.Now()
+	}
+
+	entries, err := collectEntries(info)
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	install, err := createScript(info.Scripts.PreInstall, info.Scripts.PostInstall)
+	if err != nil {
+		return err
+	}
+	if install != nil {
+		if err := writeMeta(tw, "./INSTALL", install, 0o755, mtime); err != nil {
+			return err
+		}
+	}
+
+	remove, err := createScript(info.Scripts.PreRemove, info.Scripts.PostRemove)
+	if err != nil {
+		return err
+	}
+	if remove != nil {
+		if err := writeMeta(tw, "./REMOVE", remove, 0o755, mtime); err != nil {
+			return err
+		}
+	}
+
+	if err := writeMeta(tw, "./props.plist", createProps(info, entries), 0o644, mtime); err != nil {
+		return err
+	}
+	if err := writeMeta(tw, "./files.plist", createFiles(entries), 0o644, mtime); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := writeEntry(tw, entry, mtime); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// entry описывает один объект полезной нагрузки пакета
+type entry struct {
+	content *files.Content
+	sha256  string
+	size    int64
+	conf    bool
+}
+
+func collectEntries(info *nfpm.Info) ([]entry, error) {
+	var out []entry
+	for _, content := range info.Contents {
+		switch content.Type {
+		case files.TypeDir, files.TypeImplicitDir, files.TypeSymlink:
+			out = append(out, entry{content: content})
+		case files.TypeFile, files.TypeTree, files.TypeConfig, files.TypeConfigNoReplace, files.TypeConfigMissingOK:
+			sum, size, err := hashFile(content.Source)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, entry{
+				content: content,
+				sha256:  sum,
+				size:    size,
+				conf:    strings.HasPrefix(content.Type, files.TypeConfig),
+			})
+		default:
+			// остальные типы (например, специфичные для RPM) игнорируются
+		}
+	}
+	return out, nil
+}
+
+func hashFile(path string) (string, int64, error) {
+	fl, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer fl.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, fl)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// createScript объединяет pre и post скрипты в один скрипт INSTALL/REMOVE,
+// который xbps вызывает с действием в первом аргументе.
+func createScript(pre, post string) ([]byte, error) {
+	if pre == "" && post == "" {
+		return nil, nil
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("#!/bin/sh\n")
+	buf.WriteString("ACTION=\"$1\"\n")
+	buf.WriteString("case \"${ACTION}\" in\n")
+
+	for _, script := range []struct {
+		action string
+		path   string
+	}{{"pre", pre}, {"post", post}} {
+		if script.path == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(script.path)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(buf, "%s)\n", script.action)
+		buf.WriteString("\t/bin/sh -s \"$@\" <<'ALR_XBPS_SCRIPT_EOF'\n")
+		buf.Write(data)
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString("ALR_XBPS_SCRIPT_EOF\n")
+		buf.WriteString("\t;;\n")
+	}
+
+	buf.WriteString("esac\n")
+	return buf.Bytes(), nil
+}
+
+// pattern приводит зависимость к шаблону xbps: имя без ограничения версии
+// превращается в "name>=0", а pkgver (name-version_revision) задаёт точную версию
+func pattern(dep string) string {
+	dep = strings.ReplaceAll(dep, " ", "")
+	if strings.ContainsAny(dep, "<>=") || RegexpPkgver.MatchString(dep) {
+		return dep
+	}
+	return dep + ">=0"
+}
+
+// provide приводит виртуальный пакет к формату pkgver, которого требует xbps
+func provide(p, version string) string {
+	p = strings.ReplaceAll(p, " ", "")
+	if name, ver, ok := strings.Cut(p, "="); ok {
+		return name + "-" + versionReplacer.Replace(ver) + "_1"
+	}
+	if RegexpPkgver.MatchString(p) {
+		return p
+	}
+	return p + "-" + version
+}
+
+func createProps(info *nfpm.Info, entries []entry) []byte {
+	version := Version(info)
+
+	var installedSize int64
+	var confFiles []string
+	for _, e := range entries {
+		installedSize += e.size
+		if e.conf {
+			confFiles = append(confFiles, e.content.Destination)
+		}
+	}
+
+	shortDesc, _, _ := strings.Cut(info.Description, "\n")
+
+	p := newPlist()
+	p.str("architecture", info.Arch)
+	p.array("conf_files", confFiles)
+	p.array("conflicts", mapStrings(info.Conflicts, pattern))
+	p.str("homepage", info.Homepage)
+	p.integer("installed_size", installedSize)
+	p.str("license", info.License)
+	p.str("long_desc", info.Description)
+	p.str("maintainer", info.Maintainer)
+	p.str("pkgname", info.Name)
+	p.str("pkgver", info.Name+"-"+version)
+	p.array("provides", mapStrings(info.Provides, func(s string) string { return provide(s, version) }))
+	p.array("replaces", mapStrings(info.Replaces, pattern))
+	p.array("run_depends", mapStrings(info.Depends, pattern))
+	p.str("short_desc", shortDesc)
+	p.str("version", version)
+	return p.bytes()
+}
+
+func createFiles(entries []entry) []byte {
+	var fileDicts, confDicts, linkDicts, dirDicts []map[string]string
+	for _, e := range entries {
+		dest := "/" + strings.TrimPrefix(e.content.Destination, "/")
+		switch e.content.Type {
+		case files.TypeDir, files.TypeImplicitDir:
+			dirDicts = append(dirDicts, map[string]string{"file": dest})
+		case files.TypeSymlink:
+			linkDicts = append(linkDicts, map[string]string{"file": dest, "target": e.content.Source})
+		default:
+			d := map[string]string{"file": dest, "sha256": e.sha256}
+			if e.conf {
+				confDicts = append(confDicts, d)
+			} else {
+				fileDicts = append(fileDicts, d)
+			}
+		}
+	}
+
+	p := newPlist()
+	p.dicts("conf_files", confDicts)
+	p.dicts("dirs", dirDicts)
+	p.dicts("files", fileDicts)
+	p.dicts("links", linkDicts)
+	return p.bytes()
+}
+
+func writeMeta(tw *tar.Writer, name string, data []byte, mode int64, mtime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Typeflag: tar.TypeReg,
+		Mode:     mode,
+		Size:     int64(len(data)),
+		ModTime:  mtime,
+		Format:   tar.FormatGNU,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+func writeEntry(tw *tar.Writer, e entry, mtime time.Time) error {
+	content := e.content
+	name := files.AsExplicitRelativePath(content.Destination)
+
+	hdr := &tar.Header{
+		Name:    name,
+		ModTime: mtime,
+		Format:  tar.FormatGNU,
+	}
+	if content.FileInfo != nil {
+		hdr.Mode = tarMode(content.FileInfo.Mode)
+		hdr.Uname = content.FileInfo.Owner
+		hdr.Gname = content.FileInfo.Group
+	}
+
+	switch content.Type {
+	case files.TypeDir, files.TypeImplicitDir:
+		hdr.Typeflag = tar.TypeDir
+		if hdr.Mode == 0 {
+			hdr.Mode = 0o755
+		}
+		return tw.WriteHeader(hdr)
+	case files.TypeSymlink:
+		hdr.Typeflag = tar.TypeSymlink
+		hdr.Linkname = content.Source
+		hdr.Mode = 0o777
+		return tw.WriteHeader(hdr)
+	}
+
+	hdr.Typeflag = tar.TypeReg
+	hdr.Size = e.size
+	if hdr.Mode == 0 {
+		hdr.Mode = 0o644
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	fl, err := os.Open(content.Source)
+	if err != nil {
+		return err
+	}
+	defer fl.Close()
+
+	_, err = io.CopyN(tw, fl, e.size)
+	return err
+}
+
+// tarMode переводит os.FileMode в биты режима tar
+func tarMode(mode os.FileMode) int64 {
+	out := int64(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		out |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		out |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		out |= 0o1000
+	}
+	return out
+}
+
+func mapStrings(in []string, fn func(string) string) []string {
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if s == "" {
+			continue
+		}
+		out = append(out, fn(s))
+	}
+	return out
+}
+
+// plist - минимальный генератор XML property list для метаданных xbps.
+// Ключи должны добавляться в алфавитном порядке, пустые значения пропускаются.
+type plist struct {
+	buf bytes.Buffer
+}
+
+func newPlist() *plist {
+	p := &plist{}
+	p.buf.WriteString(xml.Header)
+	p.buf.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple Computer//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	p.buf.WriteString("<plist version=\"1.0\">\n<dict>\n")
+	return p
+}
+
+func (p *plist) key(key string) {
+	fmt.Fprintf(&p.buf, "\t<key>%s</key>\n", escape(key))
+}
+
+func (p *plist) str(key, value string) {
+	if value == "" {
+		return
+	}
+	p.key(key)
+	fmt.Fprintf(&p.buf, "\t<string>%s</string>\n", escape(value))
+}
+
+func (p *plist) integer(key string, value int64) {
+	p.key(key)
+	fmt.Fprintf(&p.buf, "\t<integer>%d</integer>\n", value)
+}
+
+func (p *plist) array(key string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	p.key(key)
+	p.buf.WriteString("\t<array>\n")
+	for _, v := range values {
+		fmt.Fprintf(&p.buf, "\t\t<string>%s</string>\n", escape(v))
+	}
+	p.buf.WriteString("\t</array>\n")
+}
+
+func (p *plist) dicts(key string, values []map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	p.key(key)
+	p.buf.WriteString("\t<array>\n")
+	for _, d := range values {
+		p.buf.WriteString("\t\t<dict>\n")
+		for _, k := range []string{"file", "sha256", "target"} {
+			v, ok := d[k]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&p.buf, "\t\t\t<key>%s</key>\n\t\t\t<string>%s</string>\n", k, escape(v))
+		}
+		p.buf.WriteString("\t\t</dict>\n")
+	}
+	p.buf.WriteString("\t</array>\n")
+}
+
+func (p *plist) bytes() []byte {
+	p.buf.WriteString("</dict>\n</plist>\n")
+	return p.buf.Bytes()
+}
+
+func escape(s string) string {
+	buf := &bytes.Buffer{}
+	_ = xml.EscapeText(buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,153 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package xbps
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/goreleaser/nfpm/v2/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConventionalFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     nfpm.Info
+		expected string
+	}{
+		{
+			name:     "обычная версия",
+			info:     nfpm.Info{Name: "foo+alr-default", Arch: "amd64", Version: "1.2.3", Release: "2"},
+			expected: "foo+alr-default-1.2.3_2.x86_64.xbps",
+		},
+		{
+			name:     "эпоха и дефис в версии",
+			info:     nfpm.Info{Name: "bar", Arch: "arm64", Version: "1.0-rc1", Release: "1", Epoch: "3"},
+			expected: "bar-1.0.rc1_1.aarch64.xbps",
+		},
+		{
+			name:     "нечисловая ревизия",
+			info:     nfpm.Info{Name: "baz", Arch: "all", Version: "0.1", Release: "alt1"},
+			expected: "baz-0.1_1.noarch.xbps",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := tt.info
+			assert.Equal(t, tt.expected, Default.ConventionalFileName(&info))
+		})
+	}
+}
+
+func TestRegexpPkgver(t *testing.T) {
+	matches := RegexpPkgver.FindStringSubmatch("foo+alr-default-1.2.3_2")
+	require.NotNil(t, matches)
+	assert.Equal(t, "foo+alr-default", matches[RegexpPkgver.SubexpIndex("name")])
+	assert.Equal(t, "1.2.3", matches[RegexpPkgver.SubexpIndex("version")])
+	assert.Equal(t, "2", matches[RegexpPkgver.SubexpIndex("revision")])
+
+	assert.False(t, RegexpPkgver.MatchString("foo-1.2.3"))
+}
+
+func TestPackage(t *testing.T) {
+	dir := t.TempDir()
+
+	binPath := filepath.Join(dir, "foo")
+	require.NoError(t, os.WriteFile(binPath, []byte("#!/bin/sh\necho foo\n"), 0o755))
+	confPath := filepath.Join(dir, "foo.conf")
+	require.NoError(t, os.WriteFile(confPath, []byte("key=value\n"), 0o644))
+	postinstPath := filepath.Join(dir, "postinst.sh")
+	require.NoError(t, os.WriteFile(postinstPath, []byte("echo installed"), 0o755))
+
+	info := &nfpm.Info{
+		Name:        "foo+alr-default",
+		Arch:        "amd64",
+		Platform:    "linux",
+		Version:     "1.0",
+		Release:     "1",
+		Description: "Foo tool\nLonger description",
+		Maintainer:  "Foo <foo@example.com>",
+		Overridables: nfpm.Overridables{
+			Depends:   []string{"bar", "baz>=2.0", "qux-1.2_3"},
+			Provides:  []string{"foo"},
+			Conflicts: []string{"foo"},
+			Contents: files.Contents{
+				{Source: binPath, Destination: "/usr/bin/foo", FileInfo: &files.ContentFileInfo{Mode: 0o755}},
+				{Source: confPath, Destination: "/etc/foo.conf", Type: files.TypeConfig},
+				{Source: "foo", Destination: "/usr/bin/foo-link", Type: files.TypeSymlink},
+			},
+			Scripts: nfpm.Scripts{PostInstall: postinstPath},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, Default.Package(info, buf))
+
+	gr, err := gzip.NewReader(buf)
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	var names []string
+	contents := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		contents[hdr.Name] = string(data)
+	}
+
+	// Метаданные должны идти перед полезной нагрузкой
+	require.GreaterOrEqual(t, len(names), 3)
+	assert.Equal(t, []string{"./INSTALL", "./props.plist", "./files.plist"}, names[:3])
+	assert.Contains(t, names, "./usr/bin/foo")
+	assert.Contains(t, names, "./etc/foo.conf")
+	assert.Contains(t, names, "./usr/bin/foo-link")
+
+	props := contents["./props.plist"]
+	assert.Contains(t, props, "<string>foo+alr-default-1.0_1</string>")
+	assert.Contains(t, props, "<string>x86_64</string>")
+	assert.Contains(t, props, "<string>Foo tool</string>")
+	assert.Contains(t, props, "<string>bar&gt;=0</string>")
+	assert.Contains(t, props, "<string>baz&gt;=2.0</string>")
+	assert.Contains(t, props, "<string>qux-1.2_3</string>")
+	assert.Contains(t, props, "<string>foo-1.0_1</string>")
+	assert.Contains(t, props, "<string>/etc/foo.conf</string>")
+
+	filesPlist := contents["./files.plist"]
+	assert.Contains(t, filesPlist, "<key>conf_files</key>")
+	assert.Contains(t, filesPlist, "<string>/usr/bin/foo</string>")
+	assert.Contains(t, filesPlist, "<key>target</key>")
+
+	install := contents["./INSTALL"]
+	assert.Contains(t, install, "post)")
+	assert.Contains(t, install, "echo installed\n")
+	assert.NotContains(t, install, "pre)")
+}
@@ -20,18 +20,16 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/leonelquinteros/gotext"
 	"github.com/urfave/cli/v2"
-	"golang.org/x/exp/slices"
 
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/build"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
 	appbuilder "git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils/app_builder"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/repos"
 	"git.alr-pkg.ru/Plemya-x/ALR/internal/utils"
 	"git.alr-pkg.ru/Plemya-x/ALR/pkg/types"
 )
@@ -46,6 +44,7 @@ func RepoCmd() *cli.Command {
 			SetRepoRefCmd(),
 			RepoMirrorCmd(),
 			SetUrlCmd(),
+			RepoCheckCmd(),
 			RepoHelpCmd(),
 		},
 	}
@@ -100,41 +99,6 @@ func RemoveRepoCmd() *cli.Command {
 			deps, err := appbuilder.
 				New(ctx).
 				WithConfig().
-				Build()
-			if err != nil {
-				return err
-			}
-			defer deps.Defer()
-
-			cfg := deps.Cfg
-
-			found := false
-			index := 0
-			reposSlice := cfg.Repos()
-			for i, repo := range reposSlice {
-				if repo.Name == name {
-					index = i
-					found = true
-				}
-			}
-			if !found {
-				return cliutils.FormatCliExit(gotext.Get("Repo \"%s\" does not exist", name), nil)
-			}
-
-			cfg.SetRepos(slices.Delete(reposSlice, index, index+1))
-
-			err = os.RemoveAll(filepath.Join(cfg.GetPaths().RepoDir, name))
-			if err != nil {
-				return cliutils.FormatCliExit(gotext.Get("Error removing repo directory"), err)
-			}
-			err = cfg.System.Save()
-			if err != nil {
-				return cliutils.FormatCliExit(gotext.Get("Error saving config"), err)
-			}
-
-			deps, err = appbuilder.
-				New(ctx).
-				UseConfig(cfg).
 				WithDB().
 				Build()
 			if err != nil {
@@ -142,9 +106,9 @@ func RemoveRepoCmd() *cli.Command {
 			}
 			defer deps.Defer()
 
-			err = deps.DB.DeletePkgs(ctx, "repository = ?", name)
+			err = repos.RemoveRepo(ctx, deps.Cfg, deps.DB, name)
 			if err != nil {
-				return cliutils.FormatCliExit(gotext.Get("Error removing packages from database"), err)
+				return repoCliExit(name, err)
 			}
 
 			return nil
@@ -176,37 +140,18 @@ func AddRepoCmd() *cli.Command {
 			}
 			defer deps.Defer()
 
-			cfg := deps.Cfg
-
-			reposSlice := cfg.Repos()
-			for _, repo := range reposSlice {
-				if repo.URL == repoURL || repo.Name == name {
-					return cliutils.FormatCliExit(gotext.Get("Repo \"%s\" already exists", repo.Name), nil)
-				}
-			}
-
-			newRepo := types.Repo{
-				Name: name,
-				URL:  repoURL,
-			}
-
 			r, close, err := build.GetSafeReposExecutor()
 			if err != nil {
 				return err
 			}
 			defer close()
 
-			newRepo, err = r.PullOneAndUpdateFromConfig(c.Context, &newRepo)
-			if err != nil {
-				return err
-			}
-
-			reposSlice = append(reposSlice, newRepo)
-			cfg.SetRepos(reposSlice)
-
-			err = cfg.System.Save()
+			_, err = repos.AddRepo(ctx, deps.Cfg, r, types.Repo{
+				Name: name,
+				URL:  repoURL,
+			})
 			if err != nil {
-				return cliutils.FormatCliExit(gotext.Get("Error saving config"), err)
+				return repoCliExit(name, err)
 			}
 
 			return nil
@@ -253,21 +198,12 @@ func SetRepoRefCmd() *cli.Command {
 			}
 			defer deps.Defer()
 
-			repos := deps.Cfg.Repos()
-			newRepos := []types.Repo{}
-			for _, repo := range repos {
-				if repo.Name == name {
-					repo.Ref = ref
-				}
-				newRepos = append(newRepos, repo)
-			}
-			deps.Cfg.SetRepos(newRepos)
-			err = deps.Cfg.System.Save()
+			repo, err := repos.SetRepoRef(deps.Cfg, name, ref)
 			if err != nil {
-				return cliutils.FormatCliExit(gotext.Get("Error saving config"), err)
+				return repoCliExit(name, err)
 			}
 
-			err = deps.Repos.Pull(c.Context, newRepos)
+			err = deps.Repos.Pull(c.Context, []types.Repo{repo})
 			if err != nil {
 				return cliutils.FormatCliExit(gotext.Get("Error pulling repositories"), err)
 			}
@@ -316,21 +252,12 @@ func SetUrlCmd() *cli.Command {
 			}
 			defer deps.Defer()
 
-			repos := deps.Cfg.Repos()
-			newRepos := []types.Repo{}
-			for _, repo := range repos {
-				if repo.Name == name {
-					repo.URL = repoUrl
-				}
-				newRepos = append(newRepos, repo)
-			}
-			deps.Cfg.SetRepos(newRepos)
-			err = deps.Cfg.System.Save()
+			repo, err := repos.SetRepoURL(deps.Cfg, name, repoUrl)
 			if err != nil {
-				return cliutils.FormatCliExit(gotext.Get("Error saving config"), err)
+				return repoCliExit(name, err)
 			}
 
-			err = deps.Repos.Pull(c.Context, newRepos)
+			err = deps.Repos.Pull(c.Context, []types.Repo{repo})
 			if err != nil {
 				return cliutils.FormatCliExit(gotext.Get("Error pulling repositories"), err)
 			}
@@ -408,17 +335,9 @@ func AddMirror() *cli.Command {
 			}
 			defer deps.Defer()
 
-			repos := deps.Cfg.Repos()
-			for i, repo := range repos {
-				if repo.Name == name {
-					repos[i].Mirrors = append(repos[i].Mirrors, url)
-					break
-				}
-			}
-			deps.Cfg.SetRepos(repos)
-			err = deps.Cfg.System.Save()
+			_, err = repos.AddRepoMirror(deps.Cfg, name, url)
 			if err != nil {
-				return cliutils.FormatCliExit(gotext.Get("Error saving config"), err)
+				return repoCliExit(name, err)
 			}
 
 			return nil
@@ -478,66 +397,22 @@ func RemoveMirror() *cli.Command {
 			}
 			defer deps.Defer()
 
-			reposSlice := deps.Cfg.Repos()
-			repoIndex := -1
-			urlIndicesToRemove := []int{}
-
-			// Находим репозиторий
-			for i, repo := range reposSlice {
-				if repo.Name == name {
-					repoIndex = i
-					break
-				}
-			}
-
-			if repoIndex == -1 {
-				if ignoreMissing {
-					return nil // Тихо завершаем, если репозиторий не найден
-				}
-				return cliutils.FormatCliExit(gotext.Get("Repo \"%s\" does not exist", name), nil)
-			}
-
-			// Ищем зеркала для удаления
-			repo := reposSlice[repoIndex]
-			for j, mirror := range repo.Mirrors {
-				var match bool
-				if partialMatch {
-					// Частичное совпадение - проверяем, содержит ли зеркало указанную строку
-					match = strings.Contains(mirror, urlToRemove)
-				} else {
-					// Точное совпадение
-					match = mirror == urlToRemove
-				}
-
-				if match {
-					urlIndicesToRemove = append(urlIndicesToRemove, j)
-				}
-			}
-
-			if len(urlIndicesToRemove) == 0 {
-				if ignoreMissing {
-					return nil
-				}
-				if partialMatch {
-					return cliutils.FormatCliExit(gotext.Get("No mirrors containing \"%s\" found in repo \"%s\"", urlToRemove, name), nil)
-				} else {
-					return cliutils.FormatCliExit(gotext.Get("URL \"%s\" does not exist in repo \"%s\"", urlToRemove, name), nil)
-				}
-			}
-
-			for i := len(urlIndicesToRemove) - 1; i >= 0; i-- {
-				urlIndex := urlIndicesToRemove[i]
-				reposSlice[repoIndex].Mirrors = slices.Delete(reposSlice[repoIndex].Mirrors, urlIndex, urlIndex+1)
-			}
-
-			deps.Cfg.SetRepos(reposSlice)
-			err = deps.Cfg.System.Save()
-			if err != nil {
-				return cliutils.FormatCliExit(gotext.Get("Error saving config"), err)
+			removed, err := repos.RemoveRepoMirrors(deps.Cfg, name, urlToRemove, partialMatch)
+			switch {
+			case err == nil:
+			case ignoreMissing && (errors.Is(err, repos.ErrRepoNotFound) || errors.Is(err, repos.ErrMirrorNotFound)):
+				// Тихо завершаем, если репозиторий или зеркало не найдены
+				return nil
+			case errors.Is(err, repos.ErrMirrorNotFound) && partialMatch:
+				return cliutils.FormatCliExit(gotext.Get("No mirrors containing \"%s\" found in repo \"%s\"", urlToRemove, name), nil)
+			case errors.Is(err, repos.ErrMirrorNotFound):
+				return cliutils.FormatCliExit(gotext.Get("URL \"%s\" does not exist in repo \"%s\"", urlToRemove, name), nil)
+			default:
+				return repoCliExit(name, err)
 			}
 
-			if len(urlIndicesToRemove) > 1 {
-				fmt.Println(gotext.Get("Removed %d mirrors from repo \"%s\"\n", len(urlIndicesToRemove), name))
+			if removed > 1 {
+				fmt.Println(gotext.Get("Removed %d mirrors from repo \"%s\"\n", removed, name))
 			}
 
 			return nil
@@ -584,32 +459,9 @@ func ClearMirrors() *cli.Command {
 			}
 			defer deps.Defer()
 
-			reposSlice := deps.Cfg.Repos()
-			repoIndex := -1
-			urlIndicesToRemove := []int{}
-
-			// Находим репозиторий
-			for i, repo := range reposSlice {
-				if repo.Name == name {
-					repoIndex = i
-					break
-				}
-			}
-
-			if repoIndex == -1 {
-				return cliutils.FormatCliExit(gotext.Get("Repo \"%s\" does not exist", name), nil)
-			}
-
-			reposSlice[repoIndex].Mirrors = []string{}
-
-			deps.Cfg.SetRepos(reposSlice)
-			err = deps.Cfg.System.Save()
+			_, err = repos.SetRepoMirrors(deps.Cfg, name, []string{})
 			if err != nil {
-				return cliutils.FormatCliExit(gotext.Get("Error saving config"), err)
-			}
-
-			if len(urlIndicesToRemove) > 1 {
-				fmt.Println(gotext.Get("Removed %d mirrors from repo \"%s\"\n", len(urlIndicesToRemove), name))
+				return repoCliExit(name, err)
 			}
 
 			return nil
@@ -617,6 +469,23 @@ func ClearMirrors() *cli.Command {
 	}
 }
 
+// repoCliExit преобразует ошибки управления репозиториями в сообщения для CLI
+func repoCliExit(name string, err error) error {
+	switch {
+	case errors.Is(err, repos.ErrRepoNotFound):
+		return cliutils.FormatCliExit(gotext.Get("Repo \"%s\" does not exist", name), nil)
+	case errors.Is(err, repos.ErrRepoExists):
+		// Совпасть мог адрес репозитория с другим именем
+		var existsErr *repos.RepoExistsError
+		if errors.As(err, &existsErr) {
+			name = existsErr.Name
+		}
+		return cliutils.FormatCliExit(gotext.Get("Repo \"%s\" already exists", name), nil)
+	default:
+		return cliutils.FormatCliExit(gotext.Get("Error managing repository"), err)
+	}
+}
+
 // TODO: remove
 //
 // Deprecated: use "alr repo add"
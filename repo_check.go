@@ -0,0 +1,101 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v2"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/config"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/lint"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/repos"
+)
+
+func RepoCheckCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "check",
+		Usage:     gotext.Get("Validate all packages of a repository checkout"),
+		ArgsUsage: gotext.Get("[dir]"),
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:    "distro",
+				Aliases: []string{"d"},
+				Value:   cli.NewStringSlice(repos.DefaultCheckDistros...),
+				Usage:   gotext.Get("Distribution IDs to read the scripts for"),
+			},
+			&cli.StringSliceFlag{
+				Name:  "known",
+				Usage: gotext.Get("File with known system package names, one per line, optionally prefixed with a distribution ID: [distro=]file"),
+			},
+			&cli.BoolFlag{
+				Name:  "lint",
+				Usage: gotext.Get("Also run the linter on every script"),
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Value:   lint.FormatText,
+				Usage:   gotext.Get("Output format: text, json or sarif"),
+			},
+			&cli.BoolFlag{
+				Name:  "strict",
+				Usage: gotext.Get("Exit with an error code on warnings too"),
+			},
+		},
+		Action: func(c *cli.Context) error {
+			dir := "."
+			if c.Args().Present() {
+				dir = c.Args().First()
+			}
+
+			known := map[string][]string{}
+			for _, arg := range c.StringSlice("known") {
+				id, path, ok := strings.Cut(arg, "=")
+				if !ok {
+					id, path = "", arg
+				}
+				names, err := repos.ReadKnownPackages(path)
+				if err != nil {
+					return cliutils.FormatCliExitWithCode(gotext.Get("Error reading known packages"), err, 2)
+				}
+				known[id] = append(known[id], names...)
+			}
+
+			diags, err := repos.Check(c.Context, dir, repos.CheckOptions{
+				Distros: repos.CheckDistros(c.StringSlice("distro")),
+				Known:   known,
+				Lint:    c.Bool("lint"),
+			})
+			if err != nil {
+				return cliutils.FormatCliExitWithCode(gotext.Get("Error checking repository"), err, 2)
+			}
+
+			if err := lint.Write(os.Stdout, c.String("output"), config.Version, diags); err != nil {
+				return cliutils.FormatCliExitWithCode(gotext.Get("Error writing results"), err, 2)
+			}
+
+			if lint.HasErrors(diags) || c.Bool("strict") && len(diags) > 0 {
+				return cli.Exit("", 1)
+			}
+			return nil
+		},
+	}
+}
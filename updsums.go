@@ -0,0 +1,78 @@
+// ALR - Any Linux Repository
+// Copyright (C) 2025 The ALR Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v2"
+
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/cliutils"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/repos"
+	"git.alr-pkg.ru/Plemya-x/ALR/internal/updsums"
+	"git.alr-pkg.ru/Plemya-x/ALR/pkg/distro"
+)
+
+func UpdsumsCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "updsums",
+		Usage:     gotext.Get("Download sources and update checksums in alr.sh scripts"),
+		ArgsUsage: gotext.Get("[path...]"),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "algo",
+				Usage: gotext.Get("Checksum algorithm, e.g. sha256 or blake2b-256 (default: keep the current one)"),
+			},
+			&cli.StringSliceFlag{
+				Name:    "distro",
+				Aliases: []string{"d"},
+				Value:   cli.NewStringSlice(repos.DefaultCheckDistros...),
+				Usage:   gotext.Get("Distribution IDs to read the scripts for"),
+			},
+		},
+		Action: func(c *cli.Context) error {
+			paths := c.Args().Slice()
+			if len(paths) == 0 {
+				paths = []string{"alr.sh"}
+			}
+
+			scripts, err := findScripts(paths)
+			if err != nil {
+				return cliutils.FormatCliExit(gotext.Get("Error finding scripts"), err)
+			}
+
+			info, err := distro.ParseOSRelease(c.Context)
+			if err != nil {
+				return cliutils.FormatCliExit(gotext.Get("Error parsing os-release file"), err)
+			}
+
+			opts := updsums.Options{
+				Info:      info,
+				Distros:   repos.CheckDistros(c.StringSlice("distro")),
+				Algorithm: c.String("algo"),
+				Progress:  os.Stderr,
+			}
+			for _, script := range scripts {
+				if err := updsums.Update(c.Context, script, opts); err != nil {
+					return cliutils.FormatCliExit(gotext.Get("Error updating checksums of %s", script), err)
+				}
+			}
+			return nil
+		},
+	}
+}
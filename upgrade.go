@@ -107,6 +107,7 @@ func UpgradeCmd() *cli.Command {
 				deps.Repos,
 				scripter,
 				installer,
+				deps.DB,
 			)
 			if err != nil {
 				return err
@@ -237,14 +238,7 @@ func checkForUpdates(
 
 			pkg := pkgs[0]
 
-			repoVer := pkg.Version
-			releaseStr := overrides.ReleasePlatformSpecific(pkg.Release, info)
-
-			if pkg.Release != 0 && pkg.Epoch == 0 {
-				repoVer = fmt.Sprintf("%s-%s", pkg.Version, releaseStr)
-			} else if pkg.Release != 0 && pkg.Epoch != 0 {
-				repoVer = fmt.Sprintf("%d:%s-%s", pkg.Epoch, pkg.Version, releaseStr)
-			}
+			repoVer := overrides.VersionPlatformSpecific(pkg.Version, pkg.Release, pkg.Epoch, info)
 
 			c := vercmp.Compare(repoVer, installed[pkgName])
 